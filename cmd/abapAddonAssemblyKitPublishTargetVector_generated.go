@@ -248,13 +248,14 @@ func abapAddonAssemblyKitPublishTargetVectorMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_password"),
 					},
 					{
-						Name:        "targetVectorScope",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `T`,
+						Name:           "targetVectorScope",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `T`,
+						PossibleValues: []interface{}{"T", "P"},
 					},
 					{
 						Name:        "maxRuntimeInMinutes",
@@ -258,13 +258,14 @@ func abapEnvironmentCheckoutBranchMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_repositories"),
 					},
 					{
-						Name:        "logOutput",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `STANDARD`,
+						Name:           "logOutput",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `STANDARD`,
+						PossibleValues: []interface{}{"ZIP", "STANDARD"},
 					},
 					{
 						Name:        "cfApiEndpoint",
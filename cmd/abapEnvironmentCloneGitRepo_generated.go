@@ -263,13 +263,14 @@ func abapEnvironmentCloneGitRepoMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_byogPassword"),
 					},
 					{
-						Name:        "byogAuthMethod",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `TOKEN`,
+						Name:           "byogAuthMethod",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `TOKEN`,
+						PossibleValues: []interface{}{"TOKEN", "BASIC"},
 					},
 					{
 						Name:        "repositories",
@@ -308,13 +309,14 @@ func abapEnvironmentCloneGitRepoMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_host"),
 					},
 					{
-						Name:        "logOutput",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `STANDARD`,
+						Name:           "logOutput",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `STANDARD`,
+						PossibleValues: []interface{}{"ZIP", "STANDARD"},
 					},
 					{
 						Name:        "cfApiEndpoint",
@@ -271,13 +271,14 @@ func abapEnvironmentPullGitRepoMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_host"),
 					},
 					{
-						Name:        "logOutput",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `STANDARD`,
+						Name:           "logOutput",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS", "GENERAL"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `STANDARD`,
+						PossibleValues: []interface{}{"ZIP", "STANDARD"},
 					},
 					{
 						Name:        "cfApiEndpoint",
@@ -206,22 +206,24 @@ func ansSendEventMetadata() config.StepData {
 						Default:     `Piper`,
 					},
 					{
-						Name:        "severity",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `INFO`,
+						Name:           "severity",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `INFO`,
+						PossibleValues: []interface{}{"INFO", "NOTICE", "WARNING", "ERROR", "FATAL"},
 					},
 					{
-						Name:        "category",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `NOTIFICATION`,
+						Name:           "category",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `NOTIFICATION`,
+						PossibleValues: []interface{}{"NOTIFICATION", "ALERT", "EXCEPTION"},
 					},
 					{
 						Name:        "subject",
@@ -317,13 +317,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 				},
 				Parameters: []config.StepParameters{
 					{
-						Name:        "additionalTargetTools",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "[]string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     []string{},
+						Name:           "additionalTargetTools",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "[]string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        []string{},
+						PossibleValues: []interface{}{"custom", "docker", "dub", "golang", "gradle", "helm", "maven", "mta", "npm", "pip", "sbt", "yarn"},
 					},
 					{
 						Name:        "additionalTargetDescriptors",
@@ -335,13 +336,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     []string{},
 					},
 					{
-						Name:        "buildTool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_buildTool"),
+						Name:           "buildTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_buildTool"),
+						PossibleValues: []interface{}{"custom", "docker", "dub", "golang", "gradle", "helm", "maven", "mta", "npm", "pip", "sbt", "yarn", "CAP"},
 					},
 					{
 						Name:        "commitUserName",
@@ -371,13 +373,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_customVersionSection"),
 					},
 					{
-						Name:        "customVersioningScheme",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `maven`,
+						Name:           "customVersioningScheme",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `maven`,
+						PossibleValues: []interface{}{"docker", "maven", "pep440", "semver2"},
 					},
 					{
 						Name:        "dockerVersionSource",
@@ -407,13 +410,15 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_filePath"),
 					},
 					{
-						Name:        "CAPVersioningPreference",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `maven`,
+						Name:           "CAPVersioningPreference",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `maven`,
+						PossibleValues: []interface{}{"maven", "npm"},
+						MandatoryIf:    []config.ParameterDependence{{Name: "buildTool", Value: "CAP"}},
 					},
 					{
 						Name:        "globalSettingsFile",
@@ -544,13 +549,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_versioningTemplate"),
 					},
 					{
-						Name:        "versioningType",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `cloud`,
+						Name:           "versioningType",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `cloud`,
+						PossibleValues: []interface{}{"cloud", "cloud_noTag", "library"},
 					},
 					{
 						Name:        "customTlsCertificateLinks",
@@ -204,13 +204,14 @@ func batsExecuteTestsMetadata() config.StepData {
 				},
 				Parameters: []config.StepParameters{
 					{
-						Name:        "outputFormat",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `junit`,
+						Name:           "outputFormat",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `junit`,
+						PossibleValues: []interface{}{"tap", "junit"},
 					},
 					{
 						Name:        "repository",
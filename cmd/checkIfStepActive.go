@@ -66,16 +66,21 @@ func checkIfStepActive(utils piperutils.FileUtils) error {
 	if checkStepActiveOptions.v1Active {
 		log.Entry().Warning("Please do not use --useV1 flag since it is deprecated and will be removed in future releases")
 	}
+	verification, err := signatureVerification()
+	if err != nil {
+		return err
+	}
 	var pConfig config.Config
+	pConfig.SetSignatureVerification(verification)
 
 	// load project config and defaults
-	projectConfig, err := initializeConfig(&pConfig)
+	projectConfig, err := initializeConfig(&pConfig, verification, checkStepActiveOptions.openFile, checkStepActiveOptions.fileExists)
 	if err != nil {
 		log.Entry().Errorf("Failed to load project config: %v", err)
 		return errors.Wrap(err, "Failed to load project config failed")
 	}
 
-	stageConfigFile, err := checkStepActiveOptions.openFile(checkStepActiveOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+	stageConfigFile, err := verification.OpenFile(checkStepActiveOptions.openFile)(checkStepActiveOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
 	if err != nil {
 		return errors.Wrapf(err, "config: open stage configuration file '%v' failed", checkStepActiveOptions.stageConfigFile)
 	}
@@ -142,14 +147,20 @@ func addCheckStepActiveFlags(cmd *cobra.Command) {
 	_ = cmd.MarkFlagRequired("step")
 }
 
-func initializeConfig(pConfig *config.Config) (*config.Config, error) {
+// initializeConfig loads the project configuration and the defaults, the signatures of the defaults and of a remote project configuration are verified
+func initializeConfig(pConfig *config.Config, verification *config.SignatureVerification, openFile func(s string, t map[string]string) (io.ReadCloser, error), fileExists func(filename string) (bool, error)) (*config.Config, error) {
 	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
+	openVerifiedFile := verification.OpenFile(openFile)
 	var customConfig io.ReadCloser
 	var err error
 	//accept that config file cannot be loaded as its not mandatory here
-	if exists, err := checkStepActiveOptions.fileExists(projectConfigFile); exists {
+	if exists, err := fileExists(projectConfigFile); exists {
 		log.Entry().Infof("Project config: '%s'", projectConfigFile)
-		customConfig, err = checkStepActiveOptions.openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+		if config.IsRemoteFile(projectConfigFile) {
+			customConfig, err = openVerifiedFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+		} else {
+			customConfig, err = openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
 		}
@@ -160,7 +171,7 @@ func initializeConfig(pConfig *config.Config) (*config.Config, error) {
 
 	defaultConfig := []io.ReadCloser{}
 	for _, f := range GeneralConfig.DefaultConfig {
-		fc, err := checkStepActiveOptions.openFile(f, GeneralConfig.GitHubAccessTokens)
+		fc, err := openVerifiedFile(f, GeneralConfig.GitHubAccessTokens)
 		// only create error for non-default values
 		if err != nil && f != ".pipeline/defaults.yaml" {
 			return nil, errors.Wrapf(err, "config: getting defaults failed: '%v'", f)
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
@@ -148,3 +149,23 @@ func TestFailIfNoConfigFound(t *testing.T) {
 	}
 	t.Fatalf("process ran with err %v, want exit status 1", err)
 }
+
+func TestCheckIfStepActiveSignatureVerification(t *testing.T) {
+	defer func() {
+		GeneralConfig.DefaultConfig = nil
+		GeneralConfig.SignaturePolicy = ""
+		GeneralConfig.SignaturePublicKeys = nil
+	}()
+	checkStepActiveOptions.openFile = checkStepActiveOpenFileMock
+	checkStepActiveOptions.fileExists = checkStepActiveFileExistsMock
+	checkStepActiveOptions.stageName = "testStage"
+	checkStepActiveOptions.stepName = "testStep"
+	checkStepActiveOptions.stageConfigFile = "stage-config.yml"
+	GeneralConfig.CustomConfig = ".pipeline/config.yml"
+	GeneralConfig.DefaultConfig = []string{"https://host/unsigned-defaults.yml"}
+	GeneralConfig.SignaturePolicy = "enforce"
+	GeneralConfig.SignaturePublicKeys = []string{"untrusted comment: minisign public key\nRWQBAgMEBQYHCNNvnj2EKy/k2Ozti2A/tZUR1f2CRiqm6XhzRHGDeLNR\n"}
+
+	err := checkIfStepActive(&mock.FilesMock{})
+	assert.ErrorContains(t, err, "signature verification of 'https://host/unsigned-defaults.yml' failed")
+}
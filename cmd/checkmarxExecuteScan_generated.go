@@ -715,13 +715,14 @@ func checkmarxExecuteScanMetadata() config.StepData {
 						Default:     10,
 					},
 					{
-						Name:        "vulnerabilityThresholdResult",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `FAILURE`,
+						Name:           "vulnerabilityThresholdResult",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `FAILURE`,
+						PossibleValues: []interface{}{"FAILURE"},
 					},
 					{
 						Name:        "vulnerabilityThresholdUnit",
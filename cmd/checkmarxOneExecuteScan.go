@@ -18,6 +18,7 @@ import (
 	"time"
 
 	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -38,6 +39,8 @@ type checkmarxOneExecuteScanUtils interface {
 	MkdirAll(path string, perm os.FileMode) error
 	PathMatch(pattern, name string) (bool, error)
 	GetWorkspace() string
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
 	GetIssueService() *github.IssuesService
 	GetSearchService() *github.SearchService
 }
@@ -75,7 +78,11 @@ func checkmarxOneExecuteScan(config checkmarxOneExecuteScanOptions, _ *telemetry
 }
 
 func runStep(config checkmarxOneExecuteScanOptions, influx *checkmarxOneExecuteScanInflux, cx1sh *checkmarxOneExecuteScanHelper) error {
-	err := error(nil)
+	suppressions, err := readSuppressionsFromFile(config.SuppressionFile, cx1sh.utils, time.Now())
+	if err != nil {
+		return err
+	}
+
 	cx1sh.Project, err = cx1sh.GetProjectByName()
 	if err != nil && err.Error() != "project not found" {
 		return fmt.Errorf("failed to get project: %s", err)
@@ -134,7 +141,7 @@ func runStep(config checkmarxOneExecuteScanOptions, influx *checkmarxOneExecuteS
 
 	if config.VerifyOnly {
 		if len(scans) > 0 {
-			results, err := cx1sh.ParseResults(&scans[0]) // incl report-gen
+			results, err := cx1sh.ParseResults(&scans[0], suppressions) // incl report-gen
 			if err != nil {
 				return fmt.Errorf("failed to get scan results: %s", err)
 			}
@@ -183,7 +190,7 @@ func runStep(config checkmarxOneExecuteScanOptions, influx *checkmarxOneExecuteS
 		return fmt.Errorf("failed while polling scan status: %s", err)
 	}
 
-	results, err := cx1sh.ParseResults(scan) // incl report-gen
+	results, err := cx1sh.ParseResults(scan, suppressions) // incl report-gen
 	if err != nil {
 		return fmt.Errorf("failed to get scan results: %s", err)
 	}
@@ -640,7 +647,7 @@ func (c *checkmarxOneExecuteScanHelper) GetHeaderReportJSON(detailedResults *map
 	return nil
 }
 
-func (c *checkmarxOneExecuteScanHelper) ParseResults(scan *checkmarxOne.Scan) (map[string]interface{}, error) {
+func (c *checkmarxOneExecuteScanHelper) ParseResults(scan *checkmarxOne.Scan, suppressions format.Suppressions) (map[string]interface{}, error) {
 	var detailedResults map[string]interface{}
 
 	scanmeta, err := c.sys.GetScanMetadata(scan.ScanID)
@@ -663,10 +670,15 @@ func (c *checkmarxOneExecuteScanHelper) ParseResults(scan *checkmarxOne.Scan) (m
 		return detailedResults, fmt.Errorf("Unable to fetch scan results for scan %v: %s", scan.ScanID, err)
 	}
 
-	detailedResults, err = c.getDetailedResults(scan, &scanmeta, &results)
+	findings := checkmarxOne.ToFindings(results)
+	if suppressed := suppressions.Apply(findings); suppressed > 0 {
+		log.Entry().Infof("%v results are suppressed by the suppression file and considered not exploitable", suppressed)
+	}
+	detailedResults, err = c.getDetailedResults(scan, &scanmeta, &results, findings)
 	if err != nil {
 		return detailedResults, fmt.Errorf("Unable to fetch detailed results for scan %v: %s", scan.ScanID, err)
 	}
+	detailedResults["Findings"] = findings
 
 	err = c.GetReportJSON(scan)
 	if err != nil {
@@ -751,8 +763,9 @@ func (c *checkmarxOneExecuteScanHelper) getNumCoherentIncrementalScans(scans []c
 	return count
 }
 
-func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Scan, scanmeta *checkmarxOne.ScanMetadata, results *[]checkmarxOne.ScanResult) (map[string]interface{}, error) {
+func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Scan, scanmeta *checkmarxOne.ScanMetadata, results *[]checkmarxOne.ScanResult, findings []format.Finding) (map[string]interface{}, error) {
 	// this converts the JSON format results from Cx1 into the "resultMap" structure used in other parts of this step (influx etc)
+	// results suppressed by the suppression file are counted like results audited as not exploitable
 
 	resultMap := map[string]interface{}{}
 	resultMap["InitiatorName"] = scan.Initiator
@@ -814,7 +827,7 @@ func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Sc
 	resultMap["Information"] = map[string]int{}
 
 	if len(*results) > 0 {
-		for _, result := range *results {
+		for i, result := range *results {
 			key := "Information"
 			switch result.Severity {
 			case "HIGH":
@@ -851,6 +864,9 @@ func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Sc
 			default:
 				auditState = "ToVerify"
 			}
+			if isSuppressedResult(findings, i) {
+				auditState = "NotExploitable"
+			}
 			submap[auditState]++
 
 			if auditState != "NotExploitable" {
@@ -863,7 +879,7 @@ func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Sc
 		if c.config.VulnerabilityThresholdLowPerQuery {
 			var lowPerQuery = map[string]map[string]int{}
 
-			for _, result := range *results {
+			for i, result := range *results {
 				if result.Severity != "LOW" {
 					continue
 				}
@@ -890,6 +906,9 @@ func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Sc
 				default:
 					auditState = "ToVerify"
 				}
+				if isSuppressedResult(findings, i) {
+					auditState = "NotExploitable"
+				}
 				submap[auditState]++
 
 				if auditState != "NotExploitable" {
@@ -903,6 +922,11 @@ func (c *checkmarxOneExecuteScanHelper) getDetailedResults(scan *checkmarxOne.Sc
 	return resultMap, nil
 }
 
+// isSuppressedResult checks whether the finding of the result with the index was suppressed by the suppression file
+func isSuppressedResult(findings []format.Finding, index int) bool {
+	return index < len(findings) && findings[index].Status == format.FindingStatusSuppressed
+}
+
 func (c *checkmarxOneExecuteScanHelper) zipWorkspaceFiles(filterPattern string, utils checkmarxOneExecuteScanUtils) (*os.File, error) {
 	zipFileName := filepath.Join(utils.GetWorkspace(), "workspace.zip")
 	patterns := piperutils.Trim(strings.Split(filterPattern, ","))
@@ -1270,6 +1294,14 @@ func (c *checkmarxOneExecuteScanUtilsBundle) Open(name string) (*os.File, error)
 	return os.Open(name)
 }
 
+func (c *checkmarxOneExecuteScanUtilsBundle) FileExists(filename string) (bool, error) {
+	return piperutils.FileExists(filename)
+}
+
+func (c *checkmarxOneExecuteScanUtilsBundle) FileRead(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
 func (c *checkmarxOneExecuteScanUtilsBundle) CreateIssue(ghCreateIssueOptions *piperGithub.CreateIssueOptions) error {
 	_, err := piperGithub.CreateIssue(ghCreateIssueOptions)
 	return err
@@ -64,6 +64,7 @@ type checkmarxOneExecuteScanOptions struct {
 	IsOptimizedAndScheduled              bool     `json:"isOptimizedAndScheduled,omitempty"`
 	CreateResultIssue                    bool     `json:"createResultIssue,omitempty"`
 	ConvertToSarif                       bool     `json:"convertToSarif,omitempty"`
+	SuppressionFile                      string   `json:"suppressionFile,omitempty"`
 }
 
 type checkmarxOneExecuteScanInflux struct {
@@ -410,6 +411,7 @@ func addCheckmarxOneExecuteScanFlags(cmd *cobra.Command, stepConfig *checkmarxOn
 	cmd.Flags().BoolVar(&stepConfig.IsOptimizedAndScheduled, "isOptimizedAndScheduled", false, "Whether the pipeline runs in optimized mode and the current execution is a scheduled one")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
 	cmd.Flags().BoolVar(&stepConfig.ConvertToSarif, "convertToSarif", true, "Convert the checkmarxOne XML scan results to the open SARIF standard.")
+	cmd.Flags().StringVar(&stepConfig.SuppressionFile, "suppressionFile", `.pipeline/suppressions.yml`, "Path to the repository-local suppression file. Findings matched by a suppression which is not expired do not count as violations, expired suppressions are reported as warnings. See [suppressing findings](../configuration.md#suppressing-findings).")
 
 	cmd.MarkFlagRequired("clientSecret")
 	cmd.MarkFlagRequired("APIKey")
@@ -828,13 +830,14 @@ func checkmarxOneExecuteScanMetadata() config.StepData {
 						Default:     10,
 					},
 					{
-						Name:        "vulnerabilityThresholdResult",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `FAILURE`,
+						Name:           "vulnerabilityThresholdResult",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `FAILURE`,
+						PossibleValues: []interface{}{"FAILURE"},
 					},
 					{
 						Name:        "vulnerabilityThresholdUnit",
@@ -882,6 +885,15 @@ func checkmarxOneExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "suppressionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/suppressions.yml`,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
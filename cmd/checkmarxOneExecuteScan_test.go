@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/pkg/errors"
 )
@@ -387,3 +388,26 @@ func TestUpdateProjectTags(t *testing.T) {
 		assert.Equal(t, project.Tags, oldTags) // project's tags must be merged
 	})
 }
+
+func TestGetDetailedResultsWithSuppressions(t *testing.T) {
+	t.Parallel()
+	sys := &checkmarxOneSystemMock{}
+	options := checkmarxOneExecuteScanOptions{ProjectName: "ssba", ServerURL: "testURL"}
+	project := checkmarxOne.Project{ProjectID: "3cb99ae5-5245-4cf7-83aa-9b517b8c1c57", Name: "ssba"}
+	cx1sh := checkmarxOneExecuteScanHelper{nil, options, sys, nil, nil, &project, nil, nil, nil}
+
+	results := []checkmarxOne.ScanResult{
+		{Severity: "HIGH", State: "TO_VERIFY", Data: checkmarxOne.ScanResultData{QueryName: "Reflected_XSS", Nodes: []checkmarxOne.ScanResultNodes{{FileName: "src/legacy/index.js", Line: 3}}}},
+		{Severity: "HIGH", State: "TO_VERIFY", Data: checkmarxOne.ScanResultData{QueryName: "SQL_Injection", Nodes: []checkmarxOne.ScanResultNodes{{FileName: "src/db.js", Line: 7}}}},
+	}
+	findings := checkmarxOne.ToFindings(results)
+	suppressions := format.Suppressions{{ID: "Reflected_XSS", Path: "src/legacy/**", Justification: "escaped", Approver: "someone"}}
+	assert.Equal(t, 1, suppressions.Apply(findings))
+
+	detailedResults, err := cx1sh.getDetailedResults(&checkmarxOne.Scan{ScanID: "scan"}, &checkmarxOne.ScanMetadata{}, &results, findings)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, detailedResults["High"].(map[string]int)["Issues"])
+	assert.Equal(t, 1, detailedResults["High"].(map[string]int)["NotExploitable"])
+	assert.Equal(t, 1, detailedResults["High"].(map[string]int)["NotFalsePositive"])
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/codeql"
 	"github.com/SAP/jenkins-library/pkg/command"
@@ -175,6 +176,11 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 		return reports, err
 	}
 
+	if err := writeCodeqlScanReport(config, repoInfo, utils); err != nil {
+		log.Entry().WithError(err).Error("failed to write scan report")
+		return reports, err
+	}
+
 	if len(config.TargetGithubRepoURL) > 0 {
 		err = uploadProjectToGitHub(config, repoInfo)
 		if err != nil {
@@ -200,7 +206,12 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 			return reports, err
 		}
 
+		suppressions, err := readSuppressionsFromFile(config.SuppressionFile, utils, time.Now())
+		if err != nil {
+			return reports, err
+		}
 		codeqlScanAuditInstance := codeql.NewCodeqlScanAuditInstance(repoInfo.ServerUrl, repoInfo.Owner, repoInfo.Repo, token, []string{})
+		codeqlScanAuditInstance.SetSuppressions(suppressions)
 		scanResults, err = codeqlScanAuditInstance.GetVulnerabilities(repoInfo.AnalyzedRef)
 		if err != nil {
 			log.Entry().WithError(err).Error("failed to get vulnerabilities")
@@ -255,6 +266,24 @@ func runDatabaseCreate(config *codeqlExecuteScanOptions, customFlags map[string]
 	return nil
 }
 
+// writeCodeqlScanReport writes the findings of the SARIF report for step pipelineCreateScanSummary
+func writeCodeqlScanReport(config *codeqlExecuteScanOptions, repoInfo *codeql.RepoInfo, utils codeqlExecuteScanUtils) error {
+	sarifReport := filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")
+	if exists, _ := utils.FileExists(sarifReport); !exists {
+		log.Entry().Debugf("no SARIF report found at '%v', no findings are reported", sarifReport)
+		return nil
+	}
+	content, err := utils.FileRead(sarifReport)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read SARIF report '%v'", sarifReport)
+	}
+	findings, err := codeql.ReadFindings(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read findings of '%v'", sarifReport)
+	}
+	return codeql.WriteCustomReports(codeql.CreateCustomReport(repoInfo, config.QuerySuite, findings), repoInfo, utils)
+}
+
 func runDatabaseAnalyze(config *codeqlExecuteScanOptions, customFlags map[string]string, utils codeqlExecuteScanUtils) ([]piperutils.Path, error) {
 	sarifReport, err := executeAnalysis("sarif-latest", "codeqlReport.sarif", customFlags, config, utils)
 	if err != nil {
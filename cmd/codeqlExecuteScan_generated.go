@@ -48,6 +48,7 @@ type codeqlExecuteScanOptions struct {
 	DatabaseAnalyzeFlags        string `json:"databaseAnalyzeFlags,omitempty"`
 	CustomCommand               string `json:"customCommand,omitempty"`
 	TransformQuerySuite         string `json:"transformQuerySuite,omitempty"`
+	SuppressionFile             string `json:"suppressionFile,omitempty"`
 }
 
 type codeqlExecuteScanInflux struct {
@@ -295,6 +296,7 @@ func addCodeqlExecuteScanFlags(cmd *cobra.Command, stepConfig *codeqlExecuteScan
 	cmd.Flags().StringVar(&stepConfig.DatabaseAnalyzeFlags, "databaseAnalyzeFlags", os.Getenv("PIPER_databaseAnalyzeFlags"), "A space-separated string of flags for the 'codeql database analyze' command.")
 	cmd.Flags().StringVar(&stepConfig.CustomCommand, "customCommand", os.Getenv("PIPER_customCommand"), "A custom user-defined command to run between codeql analysis and results upload.")
 	cmd.Flags().StringVar(&stepConfig.TransformQuerySuite, "transformQuerySuite", os.Getenv("PIPER_transformQuerySuite"), "A transform string that will be applied to the querySuite using the sed command.")
+	cmd.Flags().StringVar(&stepConfig.SuppressionFile, "suppressionFile", `.pipeline/suppressions.yml`, "Path to the repository-local suppression file. Findings matched by a suppression which is not expired do not count as violations, expired suppressions are reported as warnings. See [suppressing findings](../configuration.md#suppressing-findings).")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -339,13 +341,14 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_githubToken"),
 					},
 					{
-						Name:        "buildTool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `maven`,
+						Name:           "buildTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `maven`,
+						PossibleValues: []interface{}{"custom", "maven", "golang", "npm", "pip", "yarn"},
 					},
 					{
 						Name:        "buildCommand",
@@ -569,6 +572,15 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_transformQuerySuite"),
 					},
+					{
+						Name:        "suppressionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/suppressions.yml`,
+					},
 				},
 			},
 			Containers: []config.Container{
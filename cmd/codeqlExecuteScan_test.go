@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -12,7 +13,9 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/codeql"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -858,3 +861,37 @@ func TestCheckForCompliance(t *testing.T) {
 		assert.NoError(t, checkForCompliance(scanResults, config, repoInfo))
 	})
 }
+
+func TestWriteCodeqlScanReport(t *testing.T) {
+	t.Parallel()
+	config := &codeqlExecuteScanOptions{ModulePath: "./", QuerySuite: "security-extended"}
+	repoInfo := &codeql.RepoInfo{FullUrl: "https://github.hello.test/Testing/codeql", AnalyzedRef: "refs/heads/main"}
+
+	t.Run("findings of the SARIF report", func(t *testing.T) {
+		utils := newCodeqlExecuteScanTestsUtils()
+		utils.AddFile(filepath.Join("target", "codeqlReport.sarif"), []byte(`{"runs":[{"tool":{"driver":{"name":"CodeQL"}},"results":[{"ruleId":"js/xss","level":"error","locations":[{"physicalLocation":{"artifactLocation":{"uri":"src/app.js"},"region":{"startLine":12}}}]}]}]}`))
+
+		assert.NoError(t, writeCodeqlScanReport(config, repoInfo, utils))
+
+		reports, _ := utils.Glob(reporting.StepReportDirectory + "/codeqlExecuteScan_sast_*.json")
+		if assert.Len(t, reports, 1) {
+			content, _ := utils.FileRead(reports[0])
+			var scanReport reporting.ScanReport
+			assert.NoError(t, json.Unmarshal(content, &scanReport))
+			assert.False(t, scanReport.SuccessfulScan)
+			if assert.Len(t, scanReport.Findings, 1) {
+				assert.Equal(t, "CodeQL", scanReport.Findings[0].Tool)
+				assert.Equal(t, &format.FindingLocation{File: "src/app.js", Line: 12}, scanReport.Findings[0].Location)
+			}
+		}
+	})
+
+	t.Run("no SARIF report", func(t *testing.T) {
+		utils := newCodeqlExecuteScanTestsUtils()
+
+		assert.NoError(t, writeCodeqlScanReport(config, repoInfo, utils))
+
+		exists, _ := utils.DirExists(reporting.StepReportDirectory)
+		assert.False(t, exists)
+	})
+}
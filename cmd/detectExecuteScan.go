@@ -16,6 +16,7 @@ import (
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/SAP/jenkins-library/pkg/golang"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
@@ -740,6 +741,7 @@ func createVulnerabilityReport(config detectExecuteScanOptions, vulns *bd.Vulner
 	}
 
 	scanReport.DetailTable = detailTable
+	scanReport.Findings = bd.ToFindings(vulnItems)
 	return scanReport
 }
 
@@ -759,6 +761,14 @@ func isActiveVulnerability(v bd.Vulnerability) bool {
 	}
 }
 
+// suppressedBy returns the suppression which matches an active vulnerability or nil
+func suppressedBy(v bd.Vulnerability, suppressions format.Suppressions) *format.Suppression {
+	if len(suppressions) == 0 || !isActiveVulnerability(v) {
+		return nil
+	}
+	return suppressions.Match(bd.ToFindings([]bd.Vulnerability{v})[0])
+}
+
 func isMajorVulnerability(v bd.Vulnerability) bool {
 	if v.Ignored {
 		return false
@@ -802,7 +812,11 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 
 	errorsOccured := []string{}
-	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys)
+	suppressions, err := readSuppressionsFromFile(config.SuppressionFile, utils, time.Now())
+	if err != nil {
+		return err
+	}
+	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys, suppressions)
 	if err != nil {
 		if config.GenerateReportsForEmptyProjects &&
 			strings.Contains(err.Error(), "No Components found for project version") {
@@ -881,7 +895,8 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	return nil
 }
 
-func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem) (*bd.Vulnerabilities, error) {
+// getVulnerabilitiesWithComponents returns the vulnerabilities of the project version, vulnerabilities matched by a suppression are marked as ignored
+func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem, suppressions format.Suppressions) (*bd.Vulnerabilities, error) {
 	detectVersionName := getVersionName(config)
 	components, err := sys.Client.GetComponents(config.ProjectName, detectVersionName)
 	if err != nil {
@@ -902,18 +917,22 @@ func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *d
 	majorVulns := 0
 	activeVulns := 0
 	for index, vuln := range vulns.Items {
-		if isActiveVulnerability(vuln) {
-			activeVulns++
-			if isMajorVulnerability(vuln) {
-				majorVulns++
-			}
-		}
 		component := componentLookup[fmt.Sprintf(keyFormat, vuln.Name, vuln.Version)]
 		if component != nil && len(component.Name) > 0 {
 			vulns.Items[index].Component = component
 		} else {
 			vulns.Items[index].Component = &bd.Component{Name: vuln.Name, Version: vuln.Version}
 		}
+		if suppression := suppressedBy(vulns.Items[index], suppressions); suppression != nil {
+			log.Entry().Infof("vulnerability %v of %v is suppressed as %v approved by %v", vuln.VulnerabilityName, vuln.Name, suppression.ID, suppression.Approver)
+			vulns.Items[index].Ignored = true
+		}
+		if isActiveVulnerability(vulns.Items[index]) {
+			activeVulns++
+			if isMajorVulnerability(vulns.Items[index]) {
+				majorVulns++
+			}
+		}
 	}
 	influx.detect_data.fields.vulnerabilities = activeVulns
 	influx.detect_data.fields.major_vulnerabilities = majorVulns
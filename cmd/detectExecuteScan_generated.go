@@ -80,6 +80,7 @@ type detectExecuteScanOptions struct {
 	UseDetect8                      bool     `json:"useDetect8,omitempty"`
 	UseDetect9                      bool     `json:"useDetect9,omitempty"`
 	ContainerScan                   bool     `json:"containerScan,omitempty"`
+	SuppressionFile                 string   `json:"suppressionFile,omitempty"`
 }
 
 type detectExecuteScanInflux struct {
@@ -360,6 +361,7 @@ func addDetectExecuteScanFlags(cmd *cobra.Command, stepConfig *detectExecuteScan
 	cmd.Flags().BoolVar(&stepConfig.UseDetect8, "useDetect8", false, "This flag enables the use of the supported version 8 of the Detect script instead of default version 10")
 	cmd.Flags().BoolVar(&stepConfig.UseDetect9, "useDetect9", false, "This flag enables the use of the supported version 9 of the Detect script instead of default version 10")
 	cmd.Flags().BoolVar(&stepConfig.ContainerScan, "containerScan", false, "When set to true, Container Scanning will be used instead of Docker Inspector as the Detect tool for scanning images, and all other detect tools will be ignored in the scan")
+	cmd.Flags().StringVar(&stepConfig.SuppressionFile, "suppressionFile", `.pipeline/suppressions.yml`, "Path to the repository-local suppression file. Findings matched by a suppression which is not expired do not count as violations, expired suppressions are reported as warnings. See [suppressing findings](../configuration.md#suppressing-findings).")
 
 	cmd.MarkFlagRequired("token")
 	cmd.MarkFlagRequired("projectName")
@@ -425,13 +427,14 @@ func detectExecuteScanMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_projectName"),
 					},
 					{
-						Name:        "scanners",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "[]string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "detect/scanners"}},
-						Default:     []string{`signature`},
+						Name:           "scanners",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "[]string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "detect/scanners"}},
+						Default:        []string{`signature`},
+						PossibleValues: []interface{}{"signature", "source"},
 					},
 					{
 						Name:        "scanPaths",
@@ -488,22 +491,24 @@ func detectExecuteScanMetadata() config.StepData {
 						Default:     []string{},
 					},
 					{
-						Name:        "failOn",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "[]string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "detect/failOn"}},
-						Default:     []string{`BLOCKER`},
+						Name:           "failOn",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "[]string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "detect/failOn"}},
+						Default:        []string{`BLOCKER`},
+						PossibleValues: []interface{}{"ALL", "BLOCKER", "CRITICAL", "MAJOR", "MINOR", "NONE"},
 					},
 					{
-						Name:        "versioningModel",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `major`,
+						Name:           "versioningModel",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `major`,
+						PossibleValues: []interface{}{"major", "major-minor", "semantic", "full"},
 					},
 					{
 						Name: "version",
@@ -839,13 +844,14 @@ func detectExecuteScanMetadata() config.StepData {
 						Default:     []string{},
 					},
 					{
-						Name:        "npmDependencyTypesExcluded",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "[]string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "detect/npmDependencyTypesExcluded"}},
-						Default:     []string{},
+						Name:           "npmDependencyTypesExcluded",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "[]string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "detect/npmDependencyTypesExcluded"}},
+						Default:        []string{},
+						PossibleValues: []interface{}{"NONE", "DEV", "PEER"},
 					},
 					{
 						Name:        "npmArguments",
@@ -887,13 +893,14 @@ func detectExecuteScanMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_privateModulesGitToken"),
 					},
 					{
-						Name:        "scanContainerDistro",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_scanContainerDistro"),
+						Name:           "scanContainerDistro",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_scanContainerDistro"),
+						PossibleValues: []interface{}{"ubuntu", "centos", "alpine"},
 					},
 					{
 						Name: "imageNameTags",
@@ -903,11 +910,12 @@ func detectExecuteScanMetadata() config.StepData {
 								Param: "container/imageNameTags",
 							},
 						},
-						Scope:     []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:      "[]string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   []string{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+						MandatoryIf: []config.ParameterDependence{{Name: "scanContainerDistro", Value: "ubuntu"}, {Name: "scanContainerDistro", Value: "centos"}, {Name: "scanContainerDistro", Value: "alpine"}},
 					},
 					{
 						Name: "registryUrl",
@@ -917,11 +925,12 @@ func detectExecuteScanMetadata() config.StepData {
 								Param: "container/registryUrl",
 							},
 						},
-						Scope:     []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_registryUrl"),
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_registryUrl"),
+						MandatoryIf: []config.ParameterDependence{{Name: "scanContainerDistro", Value: "ubuntu"}, {Name: "scanContainerDistro", Value: "centos"}, {Name: "scanContainerDistro", Value: "alpine"}},
 					},
 					{
 						Name: "repositoryUsername",
@@ -931,11 +940,12 @@ func detectExecuteScanMetadata() config.StepData {
 								Param: "container/repositoryUsername",
 							},
 						},
-						Scope:     []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_repositoryUsername"),
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_repositoryUsername"),
+						MandatoryIf: []config.ParameterDependence{{Name: "scanContainerDistro", Value: "ubuntu"}, {Name: "scanContainerDistro", Value: "centos"}, {Name: "scanContainerDistro", Value: "alpine"}},
 					},
 					{
 						Name: "repositoryPassword",
@@ -945,11 +955,12 @@ func detectExecuteScanMetadata() config.StepData {
 								Param: "container/repositoryPassword",
 							},
 						},
-						Scope:     []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_repositoryPassword"),
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_repositoryPassword"),
+						MandatoryIf: []config.ParameterDependence{{Name: "scanContainerDistro", Value: "ubuntu"}, {Name: "scanContainerDistro", Value: "centos"}, {Name: "scanContainerDistro", Value: "alpine"}},
 					},
 					{
 						Name:        "useDetect8",
@@ -978,6 +989,15 @@ func detectExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "detect/containerScan"}},
 						Default:     false,
 					},
+					{
+						Name:        "suppressionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/suppressions.yml`,
+					},
 				},
 			},
 			Containers: []config.Container{
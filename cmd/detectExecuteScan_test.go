@@ -16,6 +16,7 @@ import (
 
 	bd "github.com/SAP/jenkins-library/pkg/blackduck"
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -858,6 +859,35 @@ func TestIsMajorVulnerability(t *testing.T) {
 	})
 }
 
+func TestSuppressedBy(t *testing.T) {
+	t.Parallel()
+	suppressions := format.Suppressions{{ID: "CVE-2021-44228", Justification: "JNDI lookups are disabled", Approver: "someone"}}
+	active := bd.Vulnerability{
+		Name: "Apache Log4j",
+		VulnerabilityWithRemediation: bd.VulnerabilityWithRemediation{
+			VulnerabilityName: "CVE-2021-44228",
+			Severity:          "CRITICAL",
+			RemediationStatus: "NEW",
+		},
+	}
+	t.Run("active vulnerability matched by suppression", func(t *testing.T) {
+		assert.Equal(t, &suppressions[0], suppressedBy(active, suppressions))
+	})
+	t.Run("other vulnerability", func(t *testing.T) {
+		other := active
+		other.VulnerabilityWithRemediation.VulnerabilityName = "CVE-2021-45046"
+		assert.Nil(t, suppressedBy(other, suppressions))
+	})
+	t.Run("inactive vulnerability", func(t *testing.T) {
+		ignored := active
+		ignored.VulnerabilityWithRemediation.RemediationStatus = "IGNORED"
+		assert.Nil(t, suppressedBy(ignored, suppressions))
+	})
+	t.Run("no suppressions", func(t *testing.T) {
+		assert.Nil(t, suppressedBy(active, nil))
+	})
+}
+
 func TestIsActiveVulnerability(t *testing.T) {
 	t.Parallel()
 	t.Run("Case true", func(t *testing.T) {
@@ -914,7 +944,7 @@ func TestGetVulnerabilitiesWithComponents(t *testing.T) {
 		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
 		sys := newBlackduckMockSystem(config)
 
-		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys)
+		vulns, err := getVulnerabilitiesWithComponents(config, &detectExecuteScanInflux{}, &sys, nil)
 		assert.NoError(t, err)
 		vulnerabilitySpring := bd.Vulnerability{}
 		vulnerabilityLog4j1 := bd.Vulnerability{}
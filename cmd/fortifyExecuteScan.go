@@ -26,6 +26,7 @@ import (
 	"github.com/piper-validation/fortify-client-go/models"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/gradle"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -348,6 +349,10 @@ func classifyErrorOnLookup(err error) {
 
 func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOptions, utils fortifyUtils, sys fortify.System, project *models.Project, projectVersion *models.ProjectVersion, filterSet *models.FilterSet, influx *fortifyExecuteScanInflux, auditStatus map[string]string) ([]piperutils.Path, error) {
 	reports := []piperutils.Path{}
+	suppressions, err := readSuppressionsFromFile(config.SuppressionFile, utils, time.Now())
+	if err != nil {
+		return reports, err
+	}
 	// Generate report
 	if config.Reporting {
 		resultURL := []byte(fmt.Sprintf("%v/html/ssc/version/%v/fix/null/", config.ServerURL, projectVersion.ID))
@@ -376,6 +381,19 @@ func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOpt
 	if err != nil {
 		return reports, errors.Wrap(err, "failed to analyze unaudited issues")
 	}
+	issues, err := sys.GetAllIssueDetails(projectVersion.ID)
+	if err != nil {
+		// the issues are only needed for the findings of the scan summary and the suppression file
+		log.Entry().WithError(err).Warnf("Failed to fetch issue details for project version ID %v, findings are not reported and the suppression file is not applied", projectVersion.ID)
+	}
+	findings := fortify.ToFindings(issues)
+	if len(suppressions) > 0 {
+		numberOfSuppressed := countSuppressedIssues(config, sys, issues, findings, issueFilterSelectorSet, suppressions)
+		if numberOfSuppressed > 0 {
+			numberOfViolations = max(numberOfViolations-numberOfSuppressed, 0)
+			auditStatus["Suppressed by file"] = fmt.Sprintf("%v unaudited issues are suppressed by %v", numberOfSuppressed, config.SuppressionFile)
+		}
+	}
 	numberOfSuspiciousExploitable, issueGroupsSuspiciousExploitable := analyseSuspiciousExploitable(config, sys, projectVersion, filterSet, issueFilterSelectorSet, influx, auditStatus)
 	numberOfViolations += numberOfSuspiciousExploitable
 	issueGroups = append(issueGroups, issueGroupsSuspiciousExploitable...)
@@ -390,7 +408,8 @@ func verifyFFProjectCompliance(ctx context.Context, config fortifyExecuteScanOpt
 
 	fortifyReportingData := prepareReportData(influx)
 	scanReport := fortify.CreateCustomReport(fortifyReportingData, issueGroups)
-	paths, err := fortify.WriteCustomReports(scanReport)
+	scanReport.Findings = findings
+	paths, err := fortify.WriteCustomReports(scanReport, *project.Name, *projectVersion.Name)
 	if err != nil {
 		return reports, errors.Wrap(err, "failed to write custom reports")
 	}
@@ -470,6 +489,32 @@ func analyseUnauditedIssues(config fortifyExecuteScanOptions, sys fortify.System
 	return overallViolations, fetchedIssueGroups, nil
 }
 
+// countSuppressedIssues returns the number of unaudited issues of the issue groups which must be audited that are matched by a suppression,
+// the findings are the ones of the issues in the same order
+func countSuppressedIssues(config fortifyExecuteScanOptions, sys fortify.System, issues []*models.ProjectVersionIssue, findings []format.Finding, issueFilterSelectorSet *models.IssueFilterSelectorSet, suppressions format.Suppressions) int {
+	folders := map[string]string{}
+	if folderSelector := sys.GetFilterSetByDisplayName(issueFilterSelectorSet, "Folder"); folderSelector != nil {
+		for _, option := range folderSelector.SelectorOptions {
+			folders[option.GUID] = option.DisplayName
+		}
+	}
+	suppressed := 0
+	for i, finding := range findings {
+		if issues[i].Audited || finding.Status != format.FindingStatusOpen || issues[i].FolderGUID == nil {
+			continue
+		}
+		folder := folders[*issues[i].FolderGUID]
+		if len(folder) == 0 || !strings.Contains(config.MustAuditIssueGroups, folder) {
+			continue
+		}
+		if suppression := suppressions.Match(finding); suppression != nil {
+			log.Entry().Debugf("issue %v of %v is suppressed as %v approved by %v", issues[i].ID, finding.Rule, suppression.ID, suppression.Approver)
+			suppressed++
+		}
+	}
+	return suppressed
+}
+
 func getIssueDeltaFor(config fortifyExecuteScanOptions, sys fortify.System, issueGroup *models.ProjectVersionIssueGroup, projectVersionID int64, filterSet *models.FilterSet, issueFilterSelectorSet *models.IssueFilterSelectorSet, influx *fortifyExecuteScanInflux, auditStatus map[string]string, spotChecksCountByCategory *[]fortify.SpotChecksAuditCount) (int, error) {
 	totalMinusAuditedDelta := 0
 	group := ""
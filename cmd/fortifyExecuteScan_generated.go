@@ -84,6 +84,7 @@ type fortifyExecuteScanOptions struct {
 	VerifyOnly                      bool     `json:"verifyOnly,omitempty"`
 	InstallArtifacts                bool     `json:"installArtifacts,omitempty"`
 	CreateResultIssue               bool     `json:"createResultIssue,omitempty"`
+	SuppressionFile                 string   `json:"suppressionFile,omitempty"`
 }
 
 type fortifyExecuteScanInflux struct {
@@ -392,6 +393,7 @@ func addFortifyExecuteScanFlags(cmd *cobra.Command, stepConfig *fortifyExecuteSc
 	cmd.Flags().BoolVar(&stepConfig.VerifyOnly, "verifyOnly", false, "Whether the step shall only apply verification checks or whether it does a full scan and check cycle")
 	cmd.Flags().BoolVar(&stepConfig.InstallArtifacts, "installArtifacts", false, "If enabled, it will install all artifacts to the local maven repository to make them available before running Fortify. This is required if any maven module has dependencies to other modules in the repository and they were not installed before.")
 	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
+	cmd.Flags().StringVar(&stepConfig.SuppressionFile, "suppressionFile", `.pipeline/suppressions.yml`, "Path to the repository-local suppression file. Findings matched by a suppression which is not expired do not count as violations, expired suppressions are reported as warnings. See [suppressing findings](../configuration.md#suppressing-findings).")
 
 	cmd.MarkFlagRequired("authToken")
 	cmd.Flags().MarkDeprecated("pythonAdditionalPath", "this is deprecated")
@@ -569,13 +571,14 @@ func fortifyExecuteScanMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_pythonRequirementsInstallSuffix"),
 					},
 					{
-						Name:        "pythonVersion",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `python3`,
+						Name:           "pythonVersion",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `python3`,
+						PossibleValues: []interface{}{"python3", "python2"},
 					},
 					{
 						Name:        "uploadResults",
@@ -886,13 +889,14 @@ func fortifyExecuteScanMetadata() config.StepData {
 						Default:     1,
 					},
 					{
-						Name:        "spotCheckMinimumUnit",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `number`,
+						Name:           "spotCheckMinimumUnit",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `number`,
+						PossibleValues: []interface{}{"number", "percentage"},
 					},
 					{
 						Name:        "spotCheckMaximum",
@@ -913,13 +917,14 @@ func fortifyExecuteScanMetadata() config.StepData {
 						Default:     `/download/currentStateFprDownload.html`,
 					},
 					{
-						Name:        "versioningModel",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "defaultVersioningModel", Deprecated: true}},
-						Default:     `major`,
+						Name:           "versioningModel",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "defaultVersioningModel", Deprecated: true}},
+						Default:        `major`,
+						PossibleValues: []interface{}{"major", "major-minor", "semantic", "full"},
 					},
 					{
 						Name:        "pythonInstallCommand",
@@ -1043,6 +1048,15 @@ func fortifyExecuteScanMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   false,
 					},
+					{
+						Name:        "suppressionFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/suppressions.yml`,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -20,6 +20,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/mock"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/fortify"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -643,6 +644,29 @@ func TestAnalyseUnauditedIssuesWithWrongConfig(t *testing.T) {
 	assert.Equal(t, "Invalid spotCheckMinimumUnit. Please set it as 'percentage' or 'number'.", err.Error())
 }
 
+func TestCountSuppressedIssues(t *testing.T) {
+	config := fortifyExecuteScanOptions{MustAuditIssueGroups: "Corporate Security Requirements, Audit All", SuppressionFile: ".pipeline/suppressions.yml"}
+	xss, sqlInjection := "Cross-Site Scripting: Reflected", "SQL Injection"
+	legacyFile, appFile := "src/legacy/View.java", "src/app/View.java"
+	auditAll, optional := "guid-audit-all", "guid-optional"
+	issueFilterSelectorSet := &models.IssueFilterSelectorSet{FilterBySet: []*models.IssueFilterSelector{{
+		DisplayName:     "Folder",
+		SelectorOptions: []*models.SelectorOption{{GUID: auditAll, DisplayName: "Audit All"}, {GUID: optional, DisplayName: "Optional"}},
+	}}}
+	issues := []*models.ProjectVersionIssue{
+		{ID: 1, IssueName: &xss, FullFileName: &legacyFile, FolderGUID: &auditAll},
+		{ID: 2, IssueName: &xss, FullFileName: &legacyFile, FolderGUID: &auditAll, Audited: true},
+		{ID: 3, IssueName: &xss, FullFileName: &legacyFile, FolderGUID: &optional},
+		{ID: 4, IssueName: &xss, FullFileName: &appFile, FolderGUID: &auditAll},
+		{ID: 5, IssueName: &sqlInjection, FullFileName: &legacyFile, FolderGUID: &auditAll},
+	}
+	suppressions := format.Suppressions{{ID: "Cross-Site Scripting*", Path: "src/legacy/**", Justification: "escaped", Approver: "someone"}}
+
+	suppressed := countSuppressedIssues(config, &fortifyMock{}, issues, fortify.ToFindings(issues), issueFilterSelectorSet, suppressions)
+
+	assert.Equal(t, 1, suppressed)
+}
+
 func TestTriggerFortifyScan(t *testing.T) {
 	t.Run("maven", func(t *testing.T) {
 		dir := t.TempDir()
@@ -252,13 +252,14 @@ func gctsCreateRepositoryMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_remoteRepositoryURL"),
 					},
 					{
-						Name:        "role",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `SOURCE`,
+						Name:           "role",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `SOURCE`,
+						PossibleValues: []interface{}{"SOURCE", "TARGET"},
 					},
 					{
 						Name:        "vSID",
@@ -270,13 +271,14 @@ func gctsCreateRepositoryMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_vSID"),
 					},
 					{
-						Name:        "type",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `GIT`,
+						Name:           "type",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `GIT`,
+						PossibleValues: []interface{}{"GIT"},
 					},
 					{
 						Name:        "queryParameters",
@@ -275,13 +275,14 @@ func gctsDeployMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_remoteRepositoryURL"),
 					},
 					{
-						Name:        "role",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `SOURCE`,
+						Name:           "role",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `SOURCE`,
+						PossibleValues: []interface{}{"SOURCE", "TARGET"},
 					},
 					{
 						Name:        "vSID",
@@ -293,13 +294,14 @@ func gctsDeployMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_vSID"),
 					},
 					{
-						Name:        "type",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `GIT`,
+						Name:           "type",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `GIT`,
+						PossibleValues: []interface{}{"GIT", "GITHUB", "GITLAB"},
 					},
 					{
 						Name:        "branch",
@@ -26,6 +26,7 @@ type ConfigCommandOptions struct {
 	StepMetadata                  string // metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	StepName                      string
 	ContextConfig                 bool
+	Explain                       bool // if set: output the provenance of every parameter value instead of the configuration
 	OpenFile                      func(s string, t map[string]string) (io.ReadCloser, error)
 }
 
@@ -33,6 +34,7 @@ var configOptions ConfigCommandOptions
 
 func SetConfigOptions(c ConfigCommandOptions) {
 	configOptions.ContextConfig = c.ContextConfig
+	configOptions.Explain = c.Explain
 	configOptions.OpenFile = c.OpenFile
 	configOptions.Output = c.Output
 	configOptions.OutputFile = c.OutputFile
@@ -125,10 +127,18 @@ func getBuildToolFromStageConfig(stepName string) (string, error) {
 // This allows steps to refer to configuration parameters which are not part of the step itself.
 func GetStageConfig() (config.StepConfig, error) {
 	myConfig := config.Config{}
+	if configOptions.Explain {
+		myConfig.TrackProvenance()
+	}
 	stepConfig := config.StepConfig{}
+	verification, err := signatureVerification()
+	if err != nil {
+		return stepConfig, err
+	}
+	myConfig.SetSignatureVerification(verification)
 	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
 
-	customConfig, err := configOptions.OpenFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+	customConfig, err := openProjectConfigFile(projectConfigFile, verification)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return stepConfig, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
@@ -141,7 +151,7 @@ func GetStageConfig() (config.StepConfig, error) {
 		if configOptions.OpenFile == nil {
 			return stepConfig, errors.New("config: open file function not set")
 		}
-		fc, err := configOptions.OpenFile(f, GeneralConfig.GitHubAccessTokens)
+		fc, err := verification.OpenFile(configOptions.OpenFile)(f, GeneralConfig.GitHubAccessTokens)
 		// only create error for non-default values
 		if err != nil && f != ".pipeline/defaults.yaml" {
 			return stepConfig, errors.Wrapf(err, "config: getting defaults failed: '%v'", f)
@@ -154,6 +164,14 @@ func GetStageConfig() (config.StepConfig, error) {
 	return myConfig.GetStageConfig(GeneralConfig.ParametersJSON, customConfig, defaultConfig, GeneralConfig.IgnoreCustomDefaults, configOptions.StageConfigAcceptedParameters, GeneralConfig.StageName)
 }
 
+// openProjectConfigFile opens the project configuration, the signature is verified if it is fetched from a remote location
+func openProjectConfigFile(name string, verification *config.SignatureVerification) (io.ReadCloser, error) {
+	if config.IsRemoteFile(name) {
+		return verification.OpenFile(configOptions.OpenFile)(name, GeneralConfig.GitHubAccessTokens)
+	}
+	return configOptions.OpenFile(name, GeneralConfig.GitHubAccessTokens)
+}
+
 func getConfig() (config.StepConfig, error) {
 	return getConfigWithFlagValues(nil)
 }
@@ -163,6 +181,16 @@ func getConfigWithFlagValues(cmd *cobra.Command) (config.StepConfig, error) {
 	var stepConfig config.StepConfig
 	var err error
 
+	if configOptions.Explain {
+		myConfig.TrackProvenance()
+	}
+
+	verification, err := signatureVerification()
+	if err != nil {
+		return stepConfig, err
+	}
+	myConfig.SetSignatureVerification(verification)
+
 	if configOptions.StageConfig {
 		stepConfig, err = GetStageConfig()
 		if err != nil {
@@ -170,6 +198,7 @@ func getConfigWithFlagValues(cmd *cobra.Command) (config.StepConfig, error) {
 		}
 		// add hooks (defaults + custom defaults) to stage-config.json output
 		stepConfig.Config["hooks"] = stepConfig.HookConfig
+		stepConfig.Provenance.Record(config.ValueSource{Layer: config.SourceDefaults, Section: "hooks"}, map[string]interface{}{"hooks": stepConfig.HookConfig})
 	} else {
 		log.Entry().Infof("Printing stepName %s", configOptions.StepName)
 		if GeneralConfig.MetaDataResolver == nil {
@@ -193,7 +222,7 @@ func getConfigWithFlagValues(cmd *cobra.Command) (config.StepConfig, error) {
 
 		projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
 
-		customConfig, err := configOptions.OpenFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+		customConfig, err := openProjectConfigFile(projectConfigFile, verification)
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return stepConfig, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
@@ -207,7 +236,7 @@ func getConfigWithFlagValues(cmd *cobra.Command) (config.StepConfig, error) {
 		}
 
 		for _, f := range GeneralConfig.DefaultConfig {
-			fc, err := configOptions.OpenFile(f, GeneralConfig.GitHubAccessTokens)
+			fc, err := verification.OpenFile(configOptions.OpenFile)(f, GeneralConfig.GitHubAccessTokens)
 			// only create error for non-default values
 			if err != nil && f != ".pipeline/defaults.yaml" {
 				return stepConfig, errors.Wrapf(err, "config: getting defaults failed: '%v'", f)
@@ -260,7 +289,14 @@ func GenerateConfig(cmd *cobra.Command, formatter func(interface{}) (string, err
 		return err
 	}
 
-	myConfig, err := formatter(stepConfig.Config)
+	var myConfig string
+	if configOptions.Explain && strings.ToLower(configOptions.Output) == "text" {
+		myConfig = stepConfig.Provenance.ExplainText(stepConfig.Config)
+	} else if configOptions.Explain {
+		myConfig, err = formatter(stepConfig.Provenance.Explain(stepConfig.Config))
+	} else {
+		myConfig, err = formatter(stepConfig.Config)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -278,7 +314,7 @@ func GenerateConfig(cmd *cobra.Command, formatter func(interface{}) (string, err
 
 func addConfigFlags(cmd *cobra.Command) {
 	// ToDo: support more output options, like https://kubernetes.io/docs/reference/kubectl/overview/#formatting-output
-	cmd.Flags().StringVar(&configOptions.Output, "output", "json", "Defines the output format")
+	cmd.Flags().StringVar(&configOptions.Output, "output", "json", "Defines the output format (json, yaml; text is only available together with explain)")
 	cmd.Flags().StringVar(&configOptions.OutputFile, "outputFile", "", "Defines a file path. f set, the output will be written to the defines file")
 
 	cmd.Flags().StringVar(&configOptions.ParametersJSON, "parametersJSON", os.Getenv("PIPER_parametersJSON"), "Parameters to be considered in JSON format")
@@ -287,6 +323,7 @@ func addConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&configOptions.StepMetadata, "stepMetadata", "", "Step metadata, passed as path to yaml")
 	cmd.Flags().StringVar(&configOptions.StepName, "stepName", "", "Step name, used to get step metadata if yaml path is not set")
 	cmd.Flags().BoolVar(&configOptions.ContextConfig, "contextConfig", false, "Defines if step context configuration should be loaded instead of step config")
+	cmd.Flags().BoolVar(&configOptions.Explain, "explain", false, "Outputs for every parameter the source of its value (defaults, config file and line, environment, flags, ...) and the values it overrides")
 }
 
 func defaultsAndFilters(metadata *config.StepData, stepName string) ([]io.ReadCloser, config.StepFilters, error) {
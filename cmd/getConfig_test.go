@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func configOpenFileMock(name string, tokens map[string]string) (io.ReadCloser, error) {
@@ -50,7 +52,7 @@ func TestConfigCommand(t *testing.T) {
 	})
 
 	t.Run("Optional flags", func(t *testing.T) {
-		exp := []string{"contextConfig", "output", "outputFile", "parametersJSON", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
+		exp := []string{"contextConfig", "explain", "output", "outputFile", "parametersJSON", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
 		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
 	})
 
@@ -308,3 +310,63 @@ func TestPrepareOutputEnvironment(t *testing.T) {
 	assert.NoDirExists(t, filepath.Join(dir, "influx", "measurement0", "influx0_0"))
 	assert.NoDirExists(t, filepath.Join(dir, "influx", "measurement1", "influx0_1"))
 }
+
+func TestGetConfigExplain(t *testing.T) {
+	optionsBak, generalConfigBak := configOptions, GeneralConfig
+	defer func() { configOptions, GeneralConfig = optionsBak, generalConfigBak }()
+
+	openFile := func(name string, tokens map[string]string) (io.ReadCloser, error) {
+		if name == "testConfig.yml" {
+			return io.NopCloser(strings.NewReader("general:\n  buildTool: maven\nstages:\n  Build:\n    buildTool: npm\n")), nil
+		}
+		return nil, fmt.Errorf("file %v: %w", name, os.ErrNotExist)
+	}
+
+	t.Run("stage configuration", func(t *testing.T) {
+		configOptions = ConfigCommandOptions{OpenFile: openFile, StageConfig: true, Explain: true, StageConfigAcceptedParameters: []string{"buildTool"}}
+		GeneralConfig = GeneralConfigOptions{CustomConfig: "testConfig.yml", StageName: "Build"}
+
+		stepConfig, err := getConfigWithFlagValues(nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, stepConfig.Provenance)
+		explanation := map[string]config.ParameterProvenance{}
+		for _, entry := range stepConfig.Provenance.Explain(stepConfig.Config) {
+			explanation[entry.Parameter] = entry
+		}
+		require.NotNil(t, explanation["buildTool"].Source)
+		assert.Equal(t, "npm", explanation["buildTool"].Value)
+		assert.Equal(t, config.SourceConfig, explanation["buildTool"].Source.Layer)
+		assert.Equal(t, "stages/Build", explanation["buildTool"].Source.Section)
+		assert.Equal(t, 5, explanation["buildTool"].Source.Line)
+		assert.Len(t, explanation["buildTool"].Overridden, 1)
+		require.NotNil(t, explanation["hooks"].Source)
+		assert.Equal(t, config.SourceDefaults, explanation["hooks"].Source.Layer)
+
+		configOptions.OutputFile = filepath.Join(t.TempDir(), "explain.json")
+		require.NoError(t, GenerateConfig(nil, config.GetJSON))
+		output, err := os.ReadFile(configOptions.OutputFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(output), `{"parameter":"buildTool","value":"npm","source":{"layer":"config","section":"stages/Build","line":5,"value":"npm"}`)
+	})
+
+	t.Run("step configuration", func(t *testing.T) {
+		configOptions = ConfigCommandOptions{OpenFile: openFile, StepName: "githubCreateIssue", Explain: true}
+		GeneralConfig = GeneralConfigOptions{CustomConfig: "testConfig.yml", StageName: "Build"}
+
+		stepConfig, err := getConfigWithFlagValues(nil)
+
+		require.NoError(t, err)
+		require.NotNil(t, stepConfig.Provenance)
+		text := stepConfig.Provenance.ExplainText(stepConfig.Config)
+		assert.Contains(t, text, "apiUrl: \"https://api.github.com\"\n  set by:     stepDefault githubCreateIssue")
+		assert.NotContains(t, text, "set by:     unknown")
+
+		configOptions.Output = "text"
+		configOptions.OutputFile = filepath.Join(t.TempDir(), "explain.txt")
+		require.NoError(t, GenerateConfig(nil, config.GetJSON))
+		output, err := os.ReadFile(configOptions.OutputFile)
+		require.NoError(t, err)
+		assert.Equal(t, text, string(output))
+	})
+}
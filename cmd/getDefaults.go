@@ -73,8 +73,14 @@ func getDefaults() ([]map[string]string, error) {
 
 	var yamlDefaults []map[string]string
 
+	verification, err := signatureVerification()
+	if err != nil {
+		return yamlDefaults, err
+	}
+	openFile := verification.OpenFile(defaultsOptions.openFile)
+
 	for _, f := range defaultsOptions.defaultsFiles {
-		fc, err := defaultsOptions.openFile(f, GeneralConfig.GitHubAccessTokens)
+		fc, err := openFile(f, GeneralConfig.GitHubAccessTokens)
 		if err != nil {
 			return yamlDefaults, errors.Wrapf(err, "defaults: retrieving defaults file failed: '%v'", f)
 		}
@@ -130,3 +130,26 @@ func TestGenerateDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDefaultsSignatureVerification(t *testing.T) {
+	defer func() {
+		GeneralConfig.SignaturePolicy = ""
+		GeneralConfig.SignaturePublicKeys = nil
+	}()
+	defaultsOptions.openFile = defaultsOpenFileMock
+	defaultsOptions.defaultsFiles = []string{"stage_conditions.yaml"}
+	defaultsOptions.useV1 = true
+
+	t.Run("enforce without signature", func(t *testing.T) {
+		GeneralConfig.SignaturePolicy = "enforce"
+		GeneralConfig.SignaturePublicKeys = []string{"untrusted comment: minisign public key\nRWQBAgMEBQYHCNNvnj2EKy/k2Ozti2A/tZUR1f2CRiqm6XhzRHGDeLNR\n"}
+		_, err := getDefaults()
+		assert.EqualError(t, err, "defaults: retrieving defaults file failed: 'stage_conditions.yaml': signature verification of 'stage_conditions.yaml' failed: no valid signature found (.minisig: invalid minisign signature format)")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		GeneralConfig.SignaturePolicy = "strict"
+		_, err := getDefaults()
+		assert.EqualError(t, err, "invalid signature verification settings: invalid signature policy 'strict', possible values: off, warn, enforce")
+	})
+}
@@ -261,13 +261,14 @@ func githubSetCommitStatusMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_repository"),
 					},
 					{
-						Name:        "status",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_status"),
+						Name:           "status",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_status"),
+						PossibleValues: []interface{}{"failure", "pending", "success"},
 					},
 					{
 						Name:        "targetUrl",
@@ -359,13 +359,14 @@ func gitopsUpdateDeploymentMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_deploymentName"),
 					},
 					{
-						Name:        "tool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `kubectl`,
+						Name:           "tool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `kubectl`,
+						PossibleValues: []interface{}{"kubectl", "helm", "kustomize"},
 					},
 					{
 						Name:        "customTlsCertificateLinks",
@@ -326,13 +326,14 @@ func golangBuildMetadata() config.StepData {
 						Default:     false,
 					},
 					{
-						Name:        "coverageFormat",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `html`,
+						Name:           "coverageFormat",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `html`,
+						PossibleValues: []interface{}{"cobertura", "html"},
 					},
 					{
 						Name:        "createBOM",
@@ -518,13 +519,14 @@ func golangBuildMetadata() config.StepData {
 						Default:     []string{},
 					},
 					{
-						Name:        "testResultFormat",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `junit`,
+						Name:           "testResultFormat",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `junit`,
+						PossibleValues: []interface{}{"junit", "standard"},
 					},
 					{
 						Name:        "privateModules",
@@ -552,13 +552,14 @@ func helmExecuteMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_dockerConfigJSON"),
 					},
 					{
-						Name:        "helmCommand",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_helmCommand"),
+						Name:           "helmCommand",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_helmCommand"),
+						PossibleValues: []interface{}{"upgrade", "lint", "install", "test", "uninstall", "dependency", "publish"},
 					},
 					{
 						Name:        "appVersion",
@@ -570,13 +571,14 @@ func helmExecuteMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_appVersion"),
 					},
 					{
-						Name:        "dependency",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_dependency"),
+						Name:           "dependency",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_dependency"),
+						PossibleValues: []interface{}{"build", "list", "update"},
 					},
 					{
 						Name:        "packageDependencyUpdate",
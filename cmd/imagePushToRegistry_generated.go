@@ -220,11 +220,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Param: "container/imageNames",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "[]string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   []string{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "false"}},
 					},
 					{
 						Name: "sourceImageTag",
@@ -234,11 +235,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Param: "artifactVersion",
 							},
 						},
-						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{{Name: "artifactVersion"}, {Name: "containerImageTag"}},
-						Default:   os.Getenv("PIPER_sourceImageTag"),
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "artifactVersion"}, {Name: "containerImageTag"}},
+						Default:     os.Getenv("PIPER_sourceImageTag"),
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "false"}},
 					},
 					{
 						Name: "sourceRegistryUrl",
@@ -248,11 +250,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Param: "container/registryUrl",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_sourceRegistryUrl"),
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sourceRegistryUrl"),
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "false"}},
 					},
 					{
 						Name: "sourceRegistryUser",
@@ -268,11 +271,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Default: "docker-registry",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_sourceRegistryUser"),
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sourceRegistryUser"),
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "false"}},
 					},
 					{
 						Name: "sourceRegistryPassword",
@@ -288,11 +292,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Default: "docker-registry",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   os.Getenv("PIPER_sourceRegistryPassword"),
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_sourceRegistryPassword"),
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "false"}},
 					},
 					{
 						Name:        "targetRegistryUrl",
@@ -341,11 +346,12 @@ func imagePushToRegistryMetadata() config.StepData {
 								Param: "artifactVersion",
 							},
 						},
-						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{{Name: "artifactVersion"}, {Name: "containerImageTag"}},
-						Default:   os.Getenv("PIPER_targetImageTag"),
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "artifactVersion"}, {Name: "containerImageTag"}},
+						Default:     os.Getenv("PIPER_targetImageTag"),
+						MandatoryIf: []config.ParameterDependence{{Name: "tagLatest", Value: "false"}},
 					},
 					{
 						Name:        "useImageNameTags",
@@ -420,6 +426,7 @@ func imagePushToRegistryMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_localDockerImagePath"),
+						MandatoryIf: []config.ParameterDependence{{Name: "pushLocalDockerImage", Value: "true"}},
 					},
 					{
 						Name:        "targetArchitecture",
@@ -194,13 +194,14 @@ func integrationArtifactResourceMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_integrationFlowId"),
 					},
 					{
-						Name:        "operation",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     os.Getenv("PIPER_operation"),
+						Name:           "operation",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        os.Getenv("PIPER_operation"),
+						PossibleValues: []interface{}{"create", "update", "delete"},
 					},
 					{
 						Name:        "resourcePath",
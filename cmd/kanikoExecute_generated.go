@@ -401,6 +401,7 @@ func kanikoExecuteMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "dockerImageName"}},
 						Default:     os.Getenv("PIPER_containerImageName"),
+						MandatoryIf: []config.ParameterDependence{{Name: "containerMultiImageBuild", Value: "true"}},
 					},
 					{
 						Name: "containerImageTag",
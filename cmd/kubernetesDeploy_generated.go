@@ -422,13 +422,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_deploymentName"),
 					},
 					{
-						Name:        "deployTool",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `kubectl`,
+						Name:           "deployTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `kubectl`,
+						PossibleValues: []interface{}{"kubectl", "helm", "helm3"},
 					},
 					{
 						Name:        "forceUpdates",
@@ -678,13 +679,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Default:   `.pipeline/docker/config.json`,
 					},
 					{
-						Name:        "deployCommand",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `apply`,
+						Name:           "deployCommand",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `apply`,
+						PossibleValues: []interface{}{"apply", "replace"},
 					},
 					{
 						Name:        "setupScript",
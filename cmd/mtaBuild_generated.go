@@ -338,13 +338,14 @@ func mtaBuildMetadata() config.StepData {
 						Default:     0,
 					},
 					{
-						Name:        "platform",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `CF`,
+						Name:           "platform",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `CF`,
+						PossibleValues: []interface{}{"CF", "NEO", "XSA"},
 					},
 					{
 						Name:        "applicationName",
@@ -205,13 +205,14 @@ func nexusUploadMetadata() config.StepData {
 				},
 				Parameters: []config.StepParameters{
 					{
-						Name:        "version",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "nexus/version"}},
-						Default:     `nexus3`,
+						Name:           "version",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "nexus/version"}},
+						Default:        `nexus3`,
+						PossibleValues: []interface{}{"nexus2", "nexus3"},
 					},
 					{
 						Name: "format",
@@ -221,11 +222,12 @@ func nexusUploadMetadata() config.StepData {
 								Param: "custom/repositoryFormat",
 							},
 						},
-						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{},
-						Default:   `maven`,
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `maven`,
+						PossibleValues: []interface{}{"maven", "npm"},
 					},
 					{
 						Name: "url",
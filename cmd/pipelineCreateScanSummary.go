@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
@@ -62,13 +63,23 @@ func runPipelineCreateScanSummary(config *pipelineCreateScanSummaryOptions, tele
 	if len(config.PipelineLink) > 0 {
 		output = []byte(fmt.Sprintf("## Pipeline Source for Details\n\nAs listed results might be incomplete, it is crucial that you check the detailed [pipeline](%v) status.\n\n", config.PipelineLink))
 	}
+	findings := []format.Finding{}
 	for _, scanReport := range scanReports {
+		findings = append(findings, scanReport.Findings...)
 		if (config.FailedOnly && !scanReport.SuccessfulScan) || !config.FailedOnly {
 			mdReport, _ := scanReport.ToMarkdown()
 			output = append(output, mdReport...)
 		}
 	}
 
+	// findings of tools scanning the same code or dependencies, e.g. Black Duck and Mend, are listed once
+	if len(findings) > 0 {
+		findings = format.DeduplicateFindings(findings)
+		format.SortFindings(findings)
+		mdFindings, _ := reporting.FindingsToMarkdown(findings)
+		output = append(output, mdFindings...)
+	}
+
 	if err := utils.FileWrite(config.OutputFilePath, output, 0666); err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		return errors.Wrapf(err, "failed to write %v", config.OutputFilePath)
@@ -38,7 +38,9 @@ func PipelineCreateScanSummaryCommand() *cobra.Command {
 		Short: "Collect scan result information anc create a summary report",
 		Long: `This step allows you to create a summary report of your scan results.
 
-It is for example used to create a markdown file which can be used to create a GitHub issue.`,
+It is for example used to create a markdown file which can be used to create a GitHub issue.
+
+Findings reported by several scan steps, e.g. the same CVE in the same package found by Black Duck and Mend or the same CWE at the same location found by two SAST tools, are listed once in a consolidated section. Fortify findings are not consolidated with the ones of other SAST tools since the issues provided by Fortify do not contain the CWE of their category.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
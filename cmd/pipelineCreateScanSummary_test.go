@@ -49,6 +49,49 @@ func TestRunPipelineCreateScanSummary(t *testing.T) {
 		assert.Contains(t, fileContentString, "Title Scan 3")
 	})
 
+	t.Run("success - consolidated findings", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.md",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/step1.json", []byte(`{"title":"Title Scan 1","findings":[{"tool":"BlackDuck","cve":"CVE-2021-44228","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1","severity":"critical","status":"open"}]}`))
+		utils.AddFile(".pipeline/stepReports/step2.json", []byte(`{"title":"Title Scan 2","findings":[{"tool":"Mend","cve":"CVE-2021-44228","purl":"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1","severity":"high","status":"open"}]}`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		fileContentString := string(fileContent)
+		assert.Contains(t, fileContentString, "## Consolidated Findings")
+		assert.Contains(t, fileContentString, "Total number of findings after deduplication across tools: 1 (open: 1)")
+		assert.Contains(t, fileContentString, "<td>BlackDuck, Mend</td>")
+	})
+
+	t.Run("success - consolidated findings of SAST tools", func(t *testing.T) {
+		t.Parallel()
+
+		config := pipelineCreateScanSummaryOptions{
+			OutputFilePath: "scanSummary.md",
+		}
+
+		utils := newPipelineCreateScanSummaryTestsUtils()
+		utils.AddFile(".pipeline/stepReports/checkmarxOneExecuteScan_sast_1.json", []byte(`{"title":"CheckmarxOne SAST Report","findings":[{"tool":"CheckmarxOne","rule":"Reflected_XSS","cwe":"79","location":{"file":"/src/app.js","line":12},"severity":"high","status":"open"}]}`))
+		utils.AddFile(".pipeline/stepReports/codeqlExecuteScan_sast_1.json", []byte(`{"title":"CodeQL SAST Report","findings":[{"tool":"CodeQL","rule":"js/xss","cwe":"79","location":{"file":"src/app.js","line":12},"severity":"medium","status":"open"}]}`))
+		utils.AddFile(".pipeline/stepReports/sonarExecuteScan_1.json", []byte(`{"title":"SonarQube Report","findings":[{"tool":"SonarQube","rule":"javascript:S1481","location":{"file":"src/app.js","line":3},"severity":"low","status":"open"}]}`))
+
+		err := runPipelineCreateScanSummary(&config, nil, utils)
+
+		assert.NoError(t, err)
+		fileContent, _ := utils.FileRead("scanSummary.md")
+		fileContentString := string(fileContent)
+		assert.Contains(t, fileContentString, "Total number of findings after deduplication across tools: 2 (open: 2)")
+		assert.Contains(t, fileContentString, "<td>CheckmarxOne, CodeQL</td>")
+		assert.Contains(t, fileContentString, "<td>SonarQube</td>")
+	})
+
 	t.Run("success - failed only", func(t *testing.T) {
 		t.Parallel()
 
@@ -9,11 +9,18 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/SAP/jenkins-library/pkg/errorpatterns"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -34,6 +41,12 @@ type GeneralConfigOptions struct {
 	StepMetadata         string // metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	StepName             string
 	Verbose              bool
+	StrictConfig         bool     // if set: validate the project configuration against the step metadata at step start
+	SignaturePolicy      string   // verification of signatures of custom defaults and stage conditions: off, warn or enforce
+	SignaturePublicKeys  []string // public keys (or paths to key files) trusted for the verification of signatures
+	DryRun               bool     // if set: commands, modifying HTTP requests and file writes are only recorded in a plan
+	DryRunFormat         string   // format of the plan printed at the end of a dry run: text or json
+	DryRunPlan           string   // path of a file the plan of a dry run is written to in JSON format
 	LogFormat            string
 	VaultRoleID          string
 	VaultRoleSecretID    string
@@ -52,11 +65,13 @@ type GeneralConfigOptions struct {
 
 // HookConfiguration contains the configuration for supported hooks, so far Sentry and Splunk are supported.
 type HookConfiguration struct {
-	GCPPubSubConfig   GCPPubSubConfiguration   `json:"gcpPubSub,omitempty"`
-	SentryConfig      SentryConfiguration      `json:"sentry,omitempty"`
-	SplunkConfig      SplunkConfiguration      `json:"splunk,omitempty"`
-	OIDCConfig        OIDCConfiguration        `json:"oidc,omitempty"`
-	SystemTrustConfig SystemTrustConfiguration `json:"systemtrust,omitempty"`
+	GCPPubSubConfig     GCPPubSubConfiguration     `json:"gcpPubSub,omitempty"`
+	SentryConfig        SentryConfiguration        `json:"sentry,omitempty"`
+	SplunkConfig        SplunkConfiguration        `json:"splunk,omitempty"`
+	OIDCConfig          OIDCConfiguration          `json:"oidc,omitempty"`
+	SystemTrustConfig   SystemTrustConfiguration   `json:"systemtrust,omitempty"`
+	OpenTelemetryConfig OpenTelemetryConfiguration `json:"openTelemetry,omitempty"`
+	ErrorPatternsConfig ErrorPatternsConfiguration `json:"errorPatterns,omitempty"`
 }
 
 type GCPPubSubConfiguration struct {
@@ -88,6 +103,19 @@ type OIDCConfiguration struct {
 	RoleID string `json:",roleID,omitempty"`
 }
 
+// OpenTelemetryConfiguration defines the OTLP receiver traces and metrics of the steps are exported to
+type OpenTelemetryConfiguration struct {
+	Endpoint string            `json:"endpoint,omitempty"`
+	Protocol string            `json:"protocol,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Insecure bool              `json:"insecure,omitempty"`
+}
+
+// ErrorPatternsConfiguration defines additional error pattern catalogues which provide hints for errors detected in the output of tools
+type ErrorPatternsConfiguration struct {
+	Catalogues []string `json:"catalogues,omitempty"`
+}
+
 type SystemTrustConfiguration struct {
 	ServerURL           string `json:"baseURL,omitempty"`
 	TokenEndPoint       string `json:"tokenEndPoint,omitempty"`
@@ -210,6 +238,8 @@ func Execute() {
 	rootCmd.AddCommand(InfluxWriteDataCommand())
 	rootCmd.AddCommand(AbapEnvironmentRunAUnitTestCommand())
 	rootCmd.AddCommand(CheckStepActiveCommand())
+	rootCmd.AddCommand(ValidateConfigCommand())
+	rootCmd.AddCommand(RunCommand())
 	rootCmd.AddCommand(GolangBuildCommand())
 	rootCmd.AddCommand(ShellExecuteCommand())
 	rootCmd.AddCommand(ApiProxyDownloadCommand())
@@ -230,13 +260,20 @@ func Execute() {
 	rootCmd.AddCommand(AscAppUploadCommand())
 	rootCmd.AddCommand(AbapLandscapePortalUpdateAddOnProductCommand())
 	rootCmd.AddCommand(ImagePushToRegistryCommand())
+	rootCmd.AddCommand(SarifCheckQualityGateCommand())
 
 	addRootFlags(rootCmd)
 
+	cobra.OnInitialize(initDryRun)
+	log.DeferExitHandler(writeDryRunPlan)
+	log.DeferExitHandler(func() { tracing.Finish(true, log.GetErrorCategory().String()) })
+
 	if err := rootCmd.Execute(); err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
 		log.Entry().WithError(err).Fatal("configuration error")
 	}
+	writeDryRunPlan()
+	tracing.Finish(false, log.GetErrorCategory().String())
 }
 
 func addRootFlags(rootCmd *cobra.Command) {
@@ -257,7 +294,13 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StepConfigJSON, "stepConfigJSON", os.Getenv("PIPER_stepConfigJSON"), "Step configuration in JSON format")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoTelemetry, "noTelemetry", true, "Deprecated flag. Has no effect. Please don't use it.")
 	rootCmd.PersistentFlags().BoolVarP(&GeneralConfig.Verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full.")
+	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.StrictConfig, "strictConfig", os.Getenv("PIPER_strictConfig") == "true", "Fail at step start if the project configuration contains errors according to the step metadata")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.SignaturePolicy, "signaturePolicy", os.Getenv("PIPER_signaturePolicy"), "Verification of detached signatures of custom defaults and stage conditions: off, warn or enforce")
+	rootCmd.PersistentFlags().StringArrayVar(&GeneralConfig.SignaturePublicKeys, "signaturePublicKey", signaturePublicKeysFromEnv(), "Public key (cosign PEM or minisign) or path to a key file trusted for the verification of signatures, can be provided multiple times")
+	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.DryRun, "dryRun", os.Getenv("PIPER_dryRun") == "true", "Only record the commands, modifying HTTP requests and file writes of the step and print them as plan instead of executing them")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.DryRunFormat, "dryRunFormat", "text", "Format of the plan printed at the end of a dry run. Options: text, json.")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.DryRunPlan, "dryRunPlan", "", "Path of a file the plan of a dry run is written to in JSON format")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full, json.")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultServerURL, "vaultServerUrl", "", "The Vault server which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultNamespace, "vaultNamespace", "", "The Vault namespace which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultPath, "vaultPath", "", "The path which should be used to fetch credentials")
@@ -267,6 +310,38 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSSubFolder, "gcsSubFolder", "", "Used to logically separate results of the same step result type")
 }
 
+func initDryRun() {
+	if GeneralConfig.DryRun {
+		dryrun.Enable()
+		log.Entry().Info("Dry run: commands, modifying HTTP requests and file writes are recorded but not executed")
+	}
+}
+
+// writeDryRunPlan prints the plan recorded during a dry run and writes it to the plan file if requested.
+// It is called at the end of the execution as well as via the exit handler in case the step fails.
+func writeDryRunPlan() {
+	if !dryrun.Enabled() {
+		return
+	}
+	plan := dryrun.GetPlan()
+	content, err := plan.JSON()
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to serialize the dry run plan")
+		return
+	}
+	if GeneralConfig.DryRunFormat == "json" {
+		fmt.Println(string(content))
+	} else {
+		fmt.Print(plan.Text())
+	}
+	if len(GeneralConfig.DryRunPlan) > 0 {
+		// the plan is written directly since file writes via piperutils.Files are suppressed in dry run mode
+		if err := os.WriteFile(GeneralConfig.DryRunPlan, content, 0o644); err != nil {
+			log.Entry().WithError(err).Warnf("failed to write the dry run plan to '%v'", GeneralConfig.DryRunPlan)
+		}
+	}
+}
+
 // ResolveAccessTokens reads a list of tokens in format host:token passed via command line
 // and transfers this into a map as a more consumable format.
 func ResolveAccessTokens(tokenList []string) map[string]string {
@@ -349,8 +424,12 @@ func initStageName(outputToLog bool) {
 // PrepareConfig reads step configuration from various sources and merges it (defaults, config file, flags, ...)
 func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName string, options interface{}, openFile func(s string, t map[string]string) (io.ReadCloser, error)) error {
 	log.SetFormatter(GeneralConfig.LogFormat)
+	dryrun.SetStepName(stepName)
+	piperenv.SetJournalStep(metadata.Metadata.Name)
 
 	initStageName(true)
+	log.SetStageName(GeneralConfig.StageName)
+	log.SetCorrelationID(GeneralConfig.CorrelationID)
 
 	filters := metadata.GetParameterFilters()
 
@@ -359,6 +438,14 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	filters.General = append(filters.General, "collectTelemetryData")
 	filters.Parameters = append(filters.Parameters, "collectTelemetryData")
 
+	// add the parameters of executed commands and HTTP clients to all filters
+	generalParameters := append(append([]string{}, config.CommandParameters...), config.HTTPParameters...)
+	filters.All = append(filters.All, generalParameters...)
+	filters.General = append(filters.General, generalParameters...)
+	filters.Steps = append(filters.Steps, generalParameters...)
+	filters.Stages = append(filters.Stages, generalParameters...)
+	filters.Parameters = append(filters.Parameters, generalParameters...)
+
 	envParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	reportingEnvParams := config.ReportingParameters.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	resourceParams := mergeResourceParameters(envParams, reportingEnvParams)
@@ -383,6 +470,12 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	GeneralConfig.SystemTrustToken = os.Getenv("PIPER_systemTrustToken")
 	myConfig.SetSystemTrustToken(GeneralConfig.SystemTrustToken)
 
+	verification, err := signatureVerification()
+	if err != nil {
+		return err
+	}
+	myConfig.SetSignatureVerification(verification)
+
 	if len(GeneralConfig.StepConfigJSON) != 0 {
 		// ignore config & defaults in favor of passed stepConfigJSON
 		stepConfig = config.GetStepConfigWithJSON(flagValues, GeneralConfig.StepConfigJSON, filters)
@@ -397,6 +490,11 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 			projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
 			if exists, err := piperutils.FileExists(projectConfigFile); exists {
 				log.Entry().Debugf("Project config: '%s'", projectConfigFile)
+				if GeneralConfig.StrictConfig {
+					if err := failOnConfigIssues(projectConfigFile, metadata, stepName, openFile); err != nil {
+						return err
+					}
+				}
 				if customConfig, err = openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens); err != nil {
 					return errors.Wrapf(err, "Cannot read '%s'", projectConfigFile)
 				}
@@ -409,8 +507,9 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 		if len(GeneralConfig.DefaultConfig) == 0 {
 			log.Entry().Info("Project defaults: NONE")
 		}
+		openDefaultFile := verification.OpenFile(openFile)
 		for _, projectDefaultFile := range GeneralConfig.DefaultConfig {
-			fc, err := openFile(projectDefaultFile, GeneralConfig.GitHubAccessTokens)
+			fc, err := openDefaultFile(projectDefaultFile, GeneralConfig.GitHubAccessTokens)
 			// only create error for non-default values
 			if err != nil {
 				if projectDefaultFile != ".pipeline/defaults.yaml" {
@@ -440,7 +539,16 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 
 	config.MarkFlagsWithValue(cmd, stepConfig)
 
+	if err := setCommandDefaults(stepConfig.Config); err != nil {
+		return err
+	}
+	if err := setHTTPClientDefaults(stepConfig.Config); err != nil {
+		return err
+	}
 	retrieveHookConfig(stepConfig.HookConfig, &GeneralConfig.HookConfig)
+	piperhttp.SetHostTLSConfigs(stepConfig.TLSConfig)
+	startTracing(stepName)
+	loadErrorPatterns(verification.OpenFile(openFile))
 
 	if GeneralConfig.GCPJsonKeyFilePath == "" {
 		GeneralConfig.GCPJsonKeyFilePath, _ = stepConfig.Config["gcpJsonKeyFilePath"].(string)
@@ -457,6 +565,125 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	return nil
 }
 
+// setCommandDefaults applies the timeout and the retry policy of the configuration to all commands executed by the step
+func setCommandDefaults(stepConfig map[string]interface{}) error {
+	timeout, err := durationParameter(stepConfig, "commandTimeout")
+	if err != nil {
+		return err
+	}
+	retry := command.RetryPolicy{}
+	switch patterns := stepConfig["retryOnPatterns"].(type) {
+	case string:
+		retry.Patterns = []string{patterns}
+	case []interface{}:
+		for _, pattern := range patterns {
+			retry.Patterns = append(retry.Patterns, fmt.Sprint(pattern))
+		}
+	}
+	if len(retry.Patterns) > 0 {
+		retry.MaxAttempts = 3
+		retry.Delay = 10 * time.Second
+		if attempts, ok := stepConfig["retryAttempts"]; ok {
+			if retry.MaxAttempts, err = strconv.Atoi(fmt.Sprint(attempts)); err != nil || retry.MaxAttempts < 1 {
+				return errors.Errorf("invalid value for parameter retryAttempts: '%v', expected a positive number", attempts)
+			}
+		}
+		if _, ok := stepConfig["retryDelay"]; ok {
+			if retry.Delay, err = durationParameter(stepConfig, "retryDelay"); err != nil {
+				return err
+			}
+		}
+	}
+	command.SetDefaults(timeout, retry)
+	return nil
+}
+
+// setHTTPClientDefaults applies the circuit breaker and the response cache of the configuration to all HTTP clients of the step
+func setHTTPClientDefaults(stepConfig map[string]interface{}) error {
+	threshold := 0
+	if value, ok := stepConfig["httpCircuitBreakerThreshold"]; ok && value != nil {
+		var err error
+		if threshold, err = strconv.Atoi(fmt.Sprint(value)); err != nil || threshold < 0 {
+			return errors.Errorf("invalid value for parameter httpCircuitBreakerThreshold: '%v', expected a number", value)
+		}
+	}
+	cooldown, err := durationParameter(stepConfig, "httpCircuitBreakerCooldown")
+	if err != nil {
+		return err
+	}
+	cacheDir, _ := stepConfig["httpCacheDir"].(string)
+	piperhttp.SetClientDefaults(threshold, cooldown, cacheDir)
+	return nil
+}
+
+// durationParameter reads a duration like '30m' or a number of seconds from the configuration
+func durationParameter(stepConfig map[string]interface{}, name string) (time.Duration, error) {
+	value, ok := stepConfig[name]
+	if !ok || value == nil {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(fmt.Sprint(value), 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	duration, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || duration < 0 {
+		return 0, errors.Errorf("invalid value for parameter %v: '%v', expected a duration like '30m' or a number of seconds", name, value)
+	}
+	return duration, nil
+}
+
+// startTracing sets up the export of traces and metrics, the hook configuration takes precedence over the OTEL_* environment variables
+func startTracing(stepName string) {
+	tracingConfig := tracing.ConfigFromEnv()
+	hookConfig := GeneralConfig.HookConfig.OpenTelemetryConfig
+	if len(hookConfig.Endpoint) > 0 {
+		tracingConfig.Endpoint = hookConfig.Endpoint
+		tracingConfig.Protocol = hookConfig.Protocol
+		tracingConfig.Insecure = hookConfig.Insecure
+		for key, value := range hookConfig.Headers {
+			tracingConfig.Headers[key] = value
+		}
+	}
+	for _, value := range tracingConfig.Headers {
+		log.RegisterSecret(value)
+	}
+	tracingConfig.ServiceVersion = GitCommit
+	if err := tracing.Start(tracingConfig, stepName, GeneralConfig.StageName, GeneralConfig.CorrelationID); err != nil {
+		log.Entry().WithError(err).Warn("failed to set up the export of traces and metrics")
+	}
+}
+
+// loadErrorPatterns activates the default error pattern catalogue and the catalogues configured via hooks.errorPatterns,
+// a catalogue which cannot be loaded or verified is skipped since the patterns only provide additional guidance
+func loadErrorPatterns(openFile func(s string, t map[string]string) (io.ReadCloser, error)) {
+	errorpatterns.Reset()
+	if err := errorpatterns.LoadDefault(); err != nil {
+		log.Entry().WithError(err).Warn("failed to load error patterns")
+	}
+	for _, source := range GeneralConfig.HookConfig.ErrorPatternsConfig.Catalogues {
+		catalogue, err := readErrorPatternCatalogue(source, openFile)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to load error pattern catalogue '%v'", source)
+			continue
+		}
+		log.Entry().Debugf("Using %v from '%v'", catalogue, source)
+		errorpatterns.Register(catalogue)
+	}
+}
+
+func readErrorPatternCatalogue(source string, openFile func(s string, t map[string]string) (io.ReadCloser, error)) (*errorpatterns.Catalogue, error) {
+	file, err := openFile(source, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return errorpatterns.Parse(content)
+}
+
 func retrieveHookConfig(source map[string]interface{}, target *HookConfiguration) {
 	if source != nil {
 		log.Entry().Debug("Retrieving hook configuration")
@@ -613,6 +840,27 @@ func getStepOptionsStructType(stepOptions interface{}) reflect.Type {
 	return typedOptions.Type()
 }
 
+// signatureVerification provides the verification of signatures of shared configuration files as defined via the general options
+func signatureVerification() (*config.SignatureVerification, error) {
+	verification := &config.SignatureVerification{Policy: GeneralConfig.SignaturePolicy, PublicKeys: GeneralConfig.SignaturePublicKeys}
+	if err := verification.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid signature verification settings")
+	}
+	return verification, nil
+}
+
+// signaturePublicKeysFromEnv reads the trusted public keys from PIPER_signaturePublicKey.
+// Multiple keys are separated by empty lines since PEM encoded keys span multiple lines.
+func signaturePublicKeysFromEnv() []string {
+	keys := []string{}
+	for _, key := range strings.Split(strings.ReplaceAll(os.Getenv("PIPER_signaturePublicKey"), "\r\n", "\n"), "\n\n") {
+		if key = strings.TrimSpace(key); len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 func getProjectConfigFile(name string) string {
 	var altName string
 	if ext := filepath.Ext(name); ext == ".yml" {
@@ -5,9 +5,17 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,7 +27,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/SAP/jenkins-library/pkg/errorpatterns"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
 )
@@ -121,6 +133,65 @@ func TestPrepareConfig(t *testing.T) {
 		assert.Equal(t, "testValueJSON", testOptions.TestParam, "wrong value retrieved from config")
 	})
 
+	t.Run("command and HTTP client defaults", func(t *testing.T) {
+		stepConfigJSONBak := GeneralConfig.StepConfigJSON
+		defer func() { GeneralConfig.StepConfigJSON = stepConfigJSONBak }()
+		defer command.SetDefaults(0, command.RetryPolicy{})
+		defer piperhttp.SetClientDefaults(0, 0, "")
+		metadata := config.StepData{}
+
+		t.Run("shell script retried", func(t *testing.T) {
+			GeneralConfig.StepConfigJSON = `{"commandTimeout": "1m", "retryOnPatterns": ["ECONNRESET"], "retryDelay": "1ms"}`
+			require.NoError(t, PrepareConfig(&cobra.Command{Use: "test"}, &metadata, "shellExecute", &shellExecuteOptions{}, mock.OpenFileMock))
+
+			dir := t.TempDir()
+			counter := filepath.Join(dir, "counter")
+			script := filepath.Join(dir, "flaky.sh")
+			require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho . >> "+counter+"\nif [ $(wc -l < "+counter+") -lt 3 ]; then echo 'read ECONNRESET' >&2; exit 1; fi\n"), 0o755))
+
+			err := runShellExecute(&shellExecuteOptions{Sources: []string{script}}, nil, newShellExecuteUtils())
+			assert.NoError(t, err)
+			content, _ := os.ReadFile(counter)
+			assert.Equal(t, 3, strings.Count(string(content), "."), "the script is executed until it succeeds in the third attempt")
+		})
+
+		t.Run("circuit breaker of HTTP clients", func(t *testing.T) {
+			GeneralConfig.StepConfigJSON = `{"httpCircuitBreakerThreshold": 1, "httpCircuitBreakerCooldown": "1h"}`
+			require.NoError(t, PrepareConfig(&cobra.Command{Use: "test"}, &metadata, "shellExecute", &shellExecuteOptions{}, mock.OpenFileMock))
+			count := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				count++
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer server.Close()
+
+			client := piperhttp.Client{}
+			client.SetOptions(piperhttp.ClientOptions{MaxRetries: -1})
+			for i := 0; i < 3; i++ {
+				client.SendRequest(http.MethodGet, server.URL, nil, nil, nil)
+			}
+			assert.Equal(t, 1, count)
+		})
+
+		t.Run("invalid timeout", func(t *testing.T) {
+			GeneralConfig.StepConfigJSON = `{"commandTimeout": "forever"}`
+			err := PrepareConfig(&cobra.Command{Use: "test"}, &metadata, "shellExecute", &shellExecuteOptions{}, mock.OpenFileMock)
+			assert.EqualError(t, err, "invalid value for parameter commandTimeout: 'forever', expected a duration like '30m' or a number of seconds")
+		})
+
+		t.Run("invalid circuit breaker threshold", func(t *testing.T) {
+			GeneralConfig.StepConfigJSON = `{"httpCircuitBreakerThreshold": "often"}`
+			err := PrepareConfig(&cobra.Command{Use: "test"}, &metadata, "shellExecute", &shellExecuteOptions{}, mock.OpenFileMock)
+			assert.EqualError(t, err, "invalid value for parameter httpCircuitBreakerThreshold: 'often', expected a number")
+		})
+
+		t.Run("invalid attempts", func(t *testing.T) {
+			GeneralConfig.StepConfigJSON = `{"retryOnPatterns": "ECONNRESET", "retryAttempts": 0}`
+			err := PrepareConfig(&cobra.Command{Use: "test"}, &metadata, "shellExecute", &shellExecuteOptions{}, mock.OpenFileMock)
+			assert.EqualError(t, err, "invalid value for parameter retryAttempts: '0', expected a positive number")
+		})
+	})
+
 	t.Run("using config files", func(t *testing.T) {
 		t.Run("success case", func(t *testing.T) {
 			testOptions := mock.StepOptions{}
@@ -159,6 +230,26 @@ func TestPrepareConfig(t *testing.T) {
 			err := PrepareConfig(testCmd, &metadata, "testStep", &testOptions, mock.OpenFileMock)
 			assert.Error(t, err, "error expected but none occurred")
 		})
+
+		t.Run("unsigned defaults rejected", func(t *testing.T) {
+			GeneralConfig.DefaultConfig = []string{"testDefaults.yml"}
+			policyBak, publicKeysBak := GeneralConfig.SignaturePolicy, GeneralConfig.SignaturePublicKeys
+			defer func() { GeneralConfig.SignaturePolicy, GeneralConfig.SignaturePublicKeys = policyBak, publicKeysBak }()
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+			publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			require.NoError(t, err)
+			GeneralConfig.SignaturePolicy = config.SignaturePolicyEnforce
+			GeneralConfig.SignaturePublicKeys = []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))}
+			testOptions := mock.StepOptions{}
+			var testCmd = &cobra.Command{Use: "test", Short: "This is just a test"}
+			testCmd.Flags().StringVar(&testOptions.TestParam, "testParam", "", "test usage")
+			metadata := config.StepData{}
+
+			err = PrepareConfig(testCmd, &metadata, "testStep", &testOptions, mock.OpenFileMock)
+			assert.ErrorContains(t, err, "signature verification of 'testDefaults.yml' failed")
+			assert.Empty(t, testOptions.TestParam)
+		})
 	})
 }
 
@@ -501,6 +592,86 @@ bar: 42
 	})
 }
 
+func TestWriteDryRunPlan(t *testing.T) {
+	t.Cleanup(func() {
+		dryrun.Reset()
+		GeneralConfig.DryRun = false
+		GeneralConfig.DryRunPlan = ""
+	})
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	GeneralConfig.DryRun = true
+	GeneralConfig.DryRunPlan = planFile
+
+	initDryRun()
+	require.True(t, dryrun.Enabled())
+	dryrun.SetStepName("nexusUpload")
+	dryrun.RecordCommand("", "mvn", "deploy")
+	writeDryRunPlan()
+
+	content, err := os.ReadFile(planFile)
+	require.NoError(t, err)
+	var plan dryrun.Plan
+	require.NoError(t, json.Unmarshal(content, &plan))
+	assert.Equal(t, dryrun.Plan{Step: "nexusUpload", Actions: []dryrun.Action{{Type: dryrun.ActionCommand, Command: "mvn deploy"}}}, plan)
+}
+
+func TestLoadErrorPatterns(t *testing.T) {
+	t.Cleanup(func() {
+		errorpatterns.Reset()
+		GeneralConfig.HookConfig.ErrorPatternsConfig = ErrorPatternsConfiguration{}
+	})
+	catalogue := `apiVersion: v1
+version: "acme-3"
+tools:
+  npm:
+    - id: acme-registry
+      pattern: 'npm.acme.corp'
+      category: service
+      hint: The ACME registry is down.
+`
+	openFile := func(name string, tokens map[string]string) (io.ReadCloser, error) {
+		switch name {
+		case "https://github.acme.corp/raw/piper/errors.yaml":
+			return io.NopCloser(strings.NewReader(catalogue)), nil
+		case "invalid.yaml":
+			return io.NopCloser(strings.NewReader("apiVersion: v0")), nil
+		}
+		return nil, fmt.Errorf("file '%v' not found", name)
+	}
+	GeneralConfig.HookConfig.ErrorPatternsConfig.Catalogues = []string{"https://github.acme.corp/raw/piper/errors.yaml", "invalid.yaml", "missing.yaml"}
+
+	loadErrorPatterns(openFile)
+
+	match, ok := errorpatterns.Find("npm", "npm ERR! request to https://npm.acme.corp/lodash failed")
+	if assert.True(t, ok) {
+		assert.Equal(t, "acme-registry", match.Pattern.ID)
+		assert.Equal(t, "acme-3", match.CatalogueVersion)
+	}
+	// the default catalogue is still active
+	match, ok = errorpatterns.Find("mvn", "[ERROR] Tests run: 3, Failures: 1, Errors: 0, Skipped: 0")
+	if assert.True(t, ok) {
+		assert.Equal(t, "mvn-test-failures", match.Pattern.ID)
+	}
+
+	t.Run("unsigned catalogue skipped", func(t *testing.T) {
+		policyBak, publicKeysBak := GeneralConfig.SignaturePolicy, GeneralConfig.SignaturePublicKeys
+		defer func() { GeneralConfig.SignaturePolicy, GeneralConfig.SignaturePublicKeys = policyBak, publicKeysBak }()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+		GeneralConfig.SignaturePolicy = config.SignaturePolicyEnforce
+		GeneralConfig.SignaturePublicKeys = []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))}
+		verification, err := signatureVerification()
+		require.NoError(t, err)
+
+		loadErrorPatterns(verification.OpenFile(openFile))
+
+		_, ok := errorpatterns.Find("npm", "npm ERR! request to https://npm.acme.corp/lodash failed")
+		assert.False(t, ok)
+	})
+}
+
 func TestResolveAccessTokens(t *testing.T) {
 	tt := []struct {
 		description      string
@@ -257,6 +257,7 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 
 	// write custom report
 	scanReport := protecode.CreateCustomReport(fileName, productID, parsedResult, vulns)
+	scanReport.Findings = protecode.ToFindings(result.Result, config.ExcludeCVEs)
 	paths, err := protecode.WriteCustomReports(scanReport, fileName, fmt.Sprint(productID), utils)
 	if err != nil {
 		// do not fail - consider failing later on
@@ -428,13 +428,14 @@ func protecodeExecuteScanMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_dockerConfigJSON"),
 					},
 					{
-						Name:        "cleanupMode",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `binary`,
+						Name:           "cleanupMode",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `binary`,
+						PossibleValues: []interface{}{"none", "binary", "complete"},
 					},
 					{
 						Name:        "filePath",
@@ -595,13 +596,14 @@ func protecodeExecuteScanMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_customScanVersion"),
 					},
 					{
-						Name:        "versioningModel",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `major`,
+						Name:           "versioningModel",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `major`,
+						PossibleValues: []interface{}{"major", "major-minor", "semantic", "full"},
 					},
 					{
 						Name:        "pullRequestName",
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"slices"
+	"sort"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/encryption"
@@ -17,6 +21,7 @@ import (
 func ReadPipelineEnv() *cobra.Command {
 	var stepConfig artifactPrepareVersionOptions
 	var encryptedCPE bool
+	var options readPipelineEnvOptions
 	metadata := artifactPrepareVersionMetadata()
 
 	readPipelineEnvCmd := &cobra.Command{
@@ -37,7 +42,15 @@ func ReadPipelineEnv() *cobra.Command {
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
-			err := runReadPipelineEnv(stepConfig.Password, encryptedCPE)
+			var err error
+			switch {
+			case options.history || options.diff:
+				err = runReadPipelineEnvJournal(options, stepConfig.Password, encryptedCPE, os.Stdout)
+			case options.schema:
+				err = writeJSON(os.Stdout, cpeSchema())
+			default:
+				err = runReadPipelineEnv(stepConfig.Password, encryptedCPE)
+			}
 			if err != nil {
 				log.Entry().Fatalf("error when writing reading Pipeline environment: %v", err)
 			}
@@ -45,6 +58,11 @@ func ReadPipelineEnv() *cobra.Command {
 	}
 
 	readPipelineEnvCmd.Flags().BoolVar(&encryptedCPE, "encryptedCPE", false, "Bool to use encryption in CPE")
+	readPipelineEnvCmd.Flags().BoolVar(&options.history, "history", false, "Outputs the recorded changes of the commonPipelineEnvironment in the order they happened")
+	readPipelineEnvCmd.Flags().BoolVar(&options.diff, "diff", false, "Outputs the initial and the current value of each changed key together with the steps which changed it")
+	readPipelineEnvCmd.Flags().StringSliceVar(&options.keys, "key", nil, "Restricts --history and --diff to the given keys, e.g. custom/buildSettingsInfo")
+	readPipelineEnvCmd.Flags().BoolVar(&options.schema, "schema", false, "Outputs the known keys of the commonPipelineEnvironment with their types and the steps writing them")
+	readPipelineEnvCmd.MarkFlagsMutuallyExclusive("history", "diff", "schema")
 	return readPipelineEnvCmd
 }
 
@@ -59,26 +77,99 @@ func runReadPipelineEnv(stepConfigPassword string, encryptedCPE bool) error {
 	// try to encrypt
 	if encryptedCPE {
 		log.Entry().Debug("trying to encrypt CPE")
-		if stepConfigPassword == "" {
-			return fmt.Errorf("empty stepConfigPassword")
+		return writeEncryptedJSON(os.Stdout, cpe, stepConfigPassword)
+	}
+
+	// fallback
+	return writeJSON(os.Stdout, cpe)
+}
+
+type readPipelineEnvOptions struct {
+	history bool
+	diff    bool
+	schema  bool
+	keys    []string
+}
+
+// runReadPipelineEnvJournal outputs the journal of the commonPipelineEnvironment, either as is or condensed to a diff.
+// Like the commonPipelineEnvironment itself the output is encrypted if requested, otherwise known secrets are masked.
+func runReadPipelineEnvJournal(options readPipelineEnvOptions, stepConfigPassword string, encryptedCPE bool, out io.Writer) error {
+	entries, err := piperenv.ReadJournal(path.Join(GeneralConfig.EnvRootPath, piperenv.CommonPipelineEnvironment))
+	if err != nil {
+		return err
+	}
+	if len(options.keys) > 0 {
+		filtered := []piperenv.JournalEntry{}
+		for _, entry := range entries {
+			if slices.Contains(options.keys, entry.Key) {
+				filtered = append(filtered, entry)
+			}
 		}
+		entries = filtered
+	}
+	var value interface{} = entries
+	if options.diff {
+		value = piperenv.Diff(entries)
+	}
+	if encryptedCPE {
+		log.Entry().Debug("trying to encrypt CPE journal")
+		return writeEncryptedJSON(out, value, stepConfigPassword)
+	}
+	var buffer bytes.Buffer
+	if err := writeJSON(&buffer, value); err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, log.MaskSecrets(buffer.String()))
+	return err
+}
 
-		cpeJsonBytes, _ := json.Marshal(cpe)
-		encryptedCPEBytes, err := encryption.Encrypt([]byte(stepConfigPassword), cpeJsonBytes)
-		if err != nil {
-			log.Entry().Fatal(err)
+// cpeSchema derives the known keys of the commonPipelineEnvironment from the outputs of all steps
+func cpeSchema() piperenv.Schema {
+	metadata := GetAllStepMetadata()
+	stepNames := make([]string, 0, len(metadata))
+	for stepName := range metadata {
+		stepNames = append(stepNames, stepName)
+	}
+	sort.Strings(stepNames)
+
+	schema := piperenv.Schema{}
+	for _, stepName := range stepNames {
+		for _, resource := range metadata[stepName].Spec.Outputs.Resources {
+			if resource.Type != "piperEnvironment" || resource.Name != piperenv.CommonPipelineEnvironment {
+				continue
+			}
+			for _, param := range resource.Parameters {
+				key, _ := param["name"].(string)
+				valueType, _ := param["type"].(string)
+				if len(key) > 0 {
+					schema.Add(key, valueType, stepName)
+				}
+			}
 		}
+	}
+	return schema
+}
 
-		os.Stdout.Write(encryptedCPEBytes)
-		return nil
+func writeEncryptedJSON(out io.Writer, value interface{}, stepConfigPassword string) error {
+	if stepConfigPassword == "" {
+		return fmt.Errorf("empty stepConfigPassword")
 	}
 
-	// fallback
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "\t")
-	if err := encoder.Encode(cpe); err != nil {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	encryptedBytes, err := encryption.Encrypt([]byte(stepConfigPassword), jsonBytes)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = out.Write(encryptedBytes)
+	return err
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(value)
 }
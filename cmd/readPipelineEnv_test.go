@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/encryption"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCpeEncryption(t *testing.T) {
@@ -20,3 +26,86 @@ func TestCpeEncryption(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, decrypted, payload)
 }
+
+func TestCpeSchema(t *testing.T) {
+	schema := cpeSchema()
+
+	assert.Equal(t, "string", schema["artifactVersion"].Type)
+	assert.Contains(t, schema["artifactVersion"].Steps, "artifactPrepareVersion")
+	assert.Equal(t, "[]string", schema["container/imageNameTags"].Type)
+}
+
+func TestRunReadPipelineEnvJournal(t *testing.T) {
+	envRootPath := GeneralConfig.EnvRootPath
+	defer func() {
+		GeneralConfig.EnvRootPath = envRootPath
+		piperenv.SetJournalStep("")
+	}()
+	GeneralConfig.EnvRootPath = t.TempDir()
+	cpePath := filepath.Join(GeneralConfig.EnvRootPath, piperenv.CommonPipelineEnvironment)
+
+	piperenv.SetJournalStep("artifactPrepareVersion")
+	require.NoError(t, piperenv.CPEMap{"artifactVersion": "1.0.0", "git/branch": "main"}.WriteToDisk(cpePath))
+	piperenv.SetJournalStep("kanikoExecute")
+	require.NoError(t, piperenv.CPEMap{"artifactVersion": "1.0.1", "custom/token": "journalSecret"}.WriteToDisk(cpePath))
+
+	t.Run("history", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runReadPipelineEnvJournal(readPipelineEnvOptions{history: true, keys: []string{"artifactVersion"}}, "", false, &out))
+
+		var entries []piperenv.JournalEntry
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "artifactPrepareVersion", entries[0].Step)
+		assert.Equal(t, "kanikoExecute", entries[1].Step)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runReadPipelineEnvJournal(readPipelineEnvOptions{diff: true}, "", false, &out))
+
+		var diff []piperenv.KeyChange
+		require.NoError(t, json.Unmarshal(out.Bytes(), &diff))
+		assert.Equal(t, []piperenv.KeyChange{
+			{Key: "artifactVersion", NewValue: "1.0.1", Steps: []string{"artifactPrepareVersion", "kanikoExecute"}},
+			{Key: "custom/token", NewValue: "journalSecret", Steps: []string{"kanikoExecute"}},
+			{Key: "git/branch", NewValue: "main", Steps: []string{"artifactPrepareVersion"}},
+		}, diff)
+	})
+
+	t.Run("secrets masked", func(t *testing.T) {
+		log.RegisterSecret("journalSecret")
+		var out bytes.Buffer
+		require.NoError(t, runReadPipelineEnvJournal(readPipelineEnvOptions{history: true}, "", false, &out))
+
+		assert.NotContains(t, out.String(), "journalSecret")
+		assert.Contains(t, out.String(), "****")
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runReadPipelineEnvJournal(readPipelineEnvOptions{diff: true, keys: []string{"git/branch"}}, "testKey!", true, &out))
+
+		decrypted, err := encryption.Decrypt([]byte("testKey!"), out.Bytes())
+		require.NoError(t, err)
+		var diff []piperenv.KeyChange
+		require.NoError(t, json.Unmarshal(decrypted, &diff))
+		assert.Equal(t, []piperenv.KeyChange{{Key: "git/branch", NewValue: "main", Steps: []string{"artifactPrepareVersion"}}}, diff)
+	})
+
+	t.Run("encrypted without password", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runReadPipelineEnvJournal(readPipelineEnvOptions{history: true}, "", true, &out)
+
+		assert.EqualError(t, err, "empty stepConfigPassword")
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("journal stashed with the environment", func(t *testing.T) {
+		assert.FileExists(t, filepath.Join(cpePath, piperenv.JournalFileName))
+
+		cpe := piperenv.CPEMap{}
+		require.NoError(t, cpe.LoadFromDisk(cpePath))
+		assert.NotContains(t, cpe, piperenv.JournalFileName)
+	})
+}
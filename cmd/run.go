@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	runStatusSuccess  = "SUCCESS"
+	runStatusFailure  = "FAILURE"
+	runStatusInactive = "INACTIVE"
+	runStatusSkipped  = "SKIPPED"
+	runStatusNotRun   = "NOT RUN"
+)
+
+type runCommandOptions struct {
+	openFile        func(s string, t map[string]string) (io.ReadCloser, error)
+	stageConfigFile string
+	stageName       string
+	containers      bool
+	continueOnError bool
+	// containerExecutable is the Linux build of piper which is mounted into the step containers
+	containerExecutable string
+	// generalFlags contains the general flags which are passed on to the steps
+	generalFlags []string
+	executable   string
+}
+
+// piperReleaseURL is the location of the Linux build of piper attached to the releases
+const piperReleaseURL = "https://github.com/SAP/jenkins-library/releases"
+
+var runOptions runCommandOptions
+
+type runUtils interface {
+	command.ExecRunner
+	piperutils.FileUtils
+
+	DownloadFile(url, filename string, header http.Header, cookies []*http.Cookie) error
+}
+
+type runUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+	httpClient *piperhttp.Client
+}
+
+func (r *runUtilsBundle) DownloadFile(url, filename string, header http.Header, cookies []*http.Cookie) error {
+	return r.httpClient.DownloadFile(url, filename, header, cookies)
+}
+
+func newRunUtils() runUtils {
+	utils := runUtilsBundle{
+		Command:    &command.Command{},
+		Files:      &piperutils.Files{},
+		httpClient: &piperhttp.Client{},
+	}
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+// stepRunResult contains the outcome of a step executed via 'piper run'
+type stepRunResult struct {
+	Step     string
+	Status   string
+	Duration time.Duration
+	Message  string
+	// Category is the error category reported by a failed step
+	Category log.ErrorCategory
+}
+
+// RunCommand is the entry command for running the active steps of a stage locally
+func RunCommand() *cobra.Command {
+	runOptions.openFile = config.OpenPiperFile
+	var runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Runs the active steps of a pipeline stage locally.",
+		Long: `Reads the pipeline definition (stage conditions), evaluates the step conditions of the given stage
+and runs the active steps in the declared order. The steps share the common pipeline environment,
+so values written by one step are available to the subsequent steps.
+
+By default the steps are executed as sub-commands of the current piper binary.
+With --containers each step is executed in the container declared in its metadata (requires docker).
+The containers run a Linux build of piper, see --containerExecutable.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+			runOptions.generalFlags = changedGeneralFlags(cmd.Root().PersistentFlags())
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := newRunUtils()
+			results, err := runStage(utils)
+			logRunSummary(runOptions.stageName, results)
+			if err != nil {
+				log.SetErrorCategory(runErrorCategory(results))
+				log.Entry().WithError(err).Fatal("Running the stage failed")
+			}
+		},
+	}
+	addRunFlags(runCmd)
+	return runCmd
+}
+
+func runStage(utils runUtils) ([]stepRunResult, error) {
+	verification, err := signatureVerification()
+	if err != nil {
+		return nil, err
+	}
+	var pConfig config.Config
+	pConfig.SetSignatureVerification(verification)
+
+	projectConfig, err := initializeConfig(&pConfig, verification, runOptions.openFile, utils.FileExists)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load project config")
+	}
+
+	stageConfigFile, err := verification.OpenFile(runOptions.openFile)(runOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open stage configuration file '%v'", runOptions.stageConfigFile)
+	}
+	defer stageConfigFile.Close()
+
+	runConfigV1 := &config.RunConfigV1{RunConfig: config.RunConfig{StageConfigFile: stageConfigFile}}
+	if err := runConfigV1.InitRunConfigV1(projectConfig, utils, GeneralConfig.EnvRootPath); err != nil {
+		return nil, err
+	}
+
+	stage, err := findStage(runConfigV1.PipelineConfig.Spec.Stages, runOptions.stageName)
+	if err != nil {
+		return nil, err
+	}
+
+	if GeneralConfig.MetaDataResolver == nil {
+		GeneralConfig.MetaDataResolver = GetAllStepMetadata
+	}
+	metadata := GeneralConfig.MetaDataResolver()
+
+	results := []stepRunResult{}
+	var failed error
+	for _, step := range stage.Steps {
+		result := stepRunResult{Step: step.Name}
+		stepMetadata, available := metadata[step.Name]
+		switch {
+		case failed != nil:
+			result.Status = runStatusNotRun
+		case !runConfigV1.RunSteps[stage.DisplayName][step.Name]:
+			result.Status = runStatusInactive
+		case !available:
+			result.Status = runStatusSkipped
+			result.Message = "not available as piper command"
+		default:
+			// the fatal hook of a step does not overwrite the error details of a previous run
+			errorDetailsFile := step.Name + "_errorDetails.json"
+			if exists, _ := utils.FileExists(errorDetailsFile); exists {
+				if err := utils.FileRemove(errorDetailsFile); err != nil {
+					log.Entry().WithError(err).Warnf("failed to remove error details of a previous run of step %v", step.Name)
+				}
+			}
+			start := time.Now()
+			err := runStageStep(utils, projectConfig, stage.DisplayName, stepMetadata)
+			result.Duration = time.Since(start).Round(time.Millisecond)
+			result.Status = runStatusSuccess
+			if err != nil {
+				result.Status = runStatusFailure
+				result.Message = err.Error()
+				result.Category = stepErrorCategory(utils, errorDetailsFile)
+				if !runOptions.continueOnError {
+					failed = err
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	for _, result := range results {
+		if result.Status == runStatusFailure {
+			return results, errors.Errorf("step '%v' of stage '%v' failed", result.Step, stage.DisplayName)
+		}
+	}
+	return results, nil
+}
+
+// stepErrorCategory reads the error category from the error details written by the fatal hook of a failed step
+func stepErrorCategory(utils runUtils, errorDetailsFile string) log.ErrorCategory {
+	content, err := utils.FileRead(errorDetailsFile)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("no error details available in %v", errorDetailsFile)
+		return log.ErrorUndefined
+	}
+	var details struct {
+		Category string `json:"category"`
+	}
+	if err := json.Unmarshal(content, &details); err != nil {
+		log.Entry().WithError(err).Debugf("failed to parse error details in %v", errorDetailsFile)
+		return log.ErrorUndefined
+	}
+	return log.ErrorCategoryByString(details.Category)
+}
+
+// runErrorCategory returns the error category of the first failed step, a stage which could not be run at all is a configuration error
+func runErrorCategory(results []stepRunResult) log.ErrorCategory {
+	for _, result := range results {
+		if result.Status == runStatusFailure {
+			return result.Category
+		}
+	}
+	return log.ErrorConfiguration
+}
+
+func findStage(stages []config.Stage, name string) (config.Stage, error) {
+	names := []string{}
+	for _, stage := range stages {
+		if stage.DisplayName == name || stage.Name == name {
+			return stage, nil
+		}
+		names = append(names, stage.DisplayName)
+	}
+	return config.Stage{}, errors.Errorf("stage '%v' not found, available stages: %v", name, strings.Join(names, ", "))
+}
+
+func runStageStep(utils runUtils, projectConfig *config.Config, stageName string, metadata config.StepData) error {
+	stepName := metadata.Metadata.Name
+	args := append([]string{stepName, "--stageName", stageName}, runOptions.generalFlags...)
+	executable := runOptions.executable
+	if len(executable) == 0 {
+		var err error
+		if executable, err = os.Executable(); err != nil {
+			return errors.Wrap(err, "failed to determine the piper executable")
+		}
+	}
+
+	if !runOptions.containers || len(metadata.Spec.Containers) == 0 {
+		log.Entry().Infof("Running step %v", stepName)
+		return utils.RunExecutable(executable, args...)
+	}
+
+	// container related parameters like dockerImage are part of the context configuration
+	filters := metadata.GetParameterFilters()
+	contextFilters := metadata.GetContextParameterFilters()
+	filters.Stages = append(filters.Stages, contextFilters.Stages...)
+	filters.Steps = append(filters.Steps, contextFilters.Steps...)
+	stepConfig, err := projectConfig.GetStepConfig(map[string]interface{}{}, "", nil, nil, GeneralConfig.IgnoreCustomDefaults, filters, metadata, map[string]interface{}{}, stageName, stepName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine configuration of step '%v'", stepName)
+	}
+	image := stepContainerImage(metadata.Spec.Containers, stepConfig.Config)
+	if len(image) == 0 {
+		log.Entry().Infof("Running step %v (no container image applicable)", stepName)
+		return utils.RunExecutable(executable, args...)
+	}
+
+	workspace, err := os.Getwd()
+	if err != nil {
+		return errors.Wrap(err, "failed to determine the workspace")
+	}
+	containerExecutable, err := containerExecutable(utils, executable, workspace, runtime.GOOS)
+	if err != nil {
+		return err
+	}
+	log.Entry().Infof("Running step %v in container %v", stepName, image)
+	dockerArgs := []string{"run", "--rm",
+		"--volume", fmt.Sprintf("%v:%v", workspace, workspace),
+		"--volume", fmt.Sprintf("%v:/piper/piper:ro", containerExecutable),
+		"--workdir", workspace,
+		"--entrypoint", "/piper/piper",
+	}
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, "PIPER_") {
+			dockerArgs = append(dockerArgs, "--env", strings.SplitN(env, "=", 2)[0])
+		}
+	}
+	dockerArgs = append(dockerArgs, image)
+	return utils.RunExecutable("docker", append(dockerArgs, args...)...)
+}
+
+// containerExecutable returns the piper binary which is mounted into the step containers. The containers require a Linux build,
+// so on other operating systems the Linux build of the same release is downloaded unless a binary is configured explicitly.
+// The download is verified against the checksum published with the release and cached per release.
+func containerExecutable(utils runUtils, executable, workspace, goos string) (string, error) {
+	if len(runOptions.containerExecutable) > 0 {
+		return filepath.Abs(runOptions.containerExecutable)
+	}
+	if goos == "linux" {
+		return executable, nil
+	}
+	if len(GitTag) == 0 {
+		return "", errors.New("the release of this piper binary is unknown, provide the Linux build of piper via --containerExecutable")
+	}
+
+	target := filepath.Join(workspace, ".pipeline", "run", GitTag, "piper")
+	checksumFile := target + ".sha256"
+	if exists, _ := utils.FileExists(target); exists {
+		if err := verifyChecksum(utils, target, checksumFile); err == nil {
+			return target, nil
+		}
+		log.Entry().Warnf("Cached Linux build of piper %v does not match its checksum, downloading it again", GitTag)
+	}
+
+	url := piperReleaseURL + "/download/" + GitTag + "/piper"
+	log.Entry().Infof("Downloading the Linux build of piper from %v", url)
+	if err := utils.DownloadFile(url+".sha256", checksumFile, nil, nil); err != nil {
+		return "", errors.Wrapf(err, "failed to download the checksum of the Linux build of piper %v, provide one via --containerExecutable", GitTag)
+	}
+	if err := utils.DownloadFile(url, target, nil, nil); err != nil {
+		return "", errors.Wrap(err, "failed to download the Linux build of piper, provide one via --containerExecutable")
+	}
+	if err := verifyChecksum(utils, target, checksumFile); err != nil {
+		_ = utils.FileRemove(target)
+		return "", errors.Wrapf(err, "failed to verify the Linux build of piper %v, provide one via --containerExecutable", GitTag)
+	}
+	if err := utils.Chmod(target, 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to make %v executable", target)
+	}
+	return target, nil
+}
+
+// verifyChecksum compares the SHA256 of a file with the checksum file in the format of sha256sum
+func verifyChecksum(utils runUtils, file, checksumFile string) error {
+	checksum, err := utils.FileRead(checksumFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read checksum file %v", checksumFile)
+	}
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+		return errors.Errorf("checksum file %v is empty", checksumFile)
+	}
+	content, err := utils.FileRead(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %v", file)
+	}
+	if sum := sha256.Sum256(content); !strings.EqualFold(hex.EncodeToString(sum[:]), fields[0]) {
+		return errors.Errorf("checksum of %v does not match %v", file, fields[0])
+	}
+	return nil
+}
+
+// stepContainerImage determines the container image of a step: an explicitly configured dockerImage
+// or the first container declared in the step metadata whose conditions match the step configuration.
+func stepContainerImage(containers []config.Container, stepConfig map[string]interface{}) string {
+	if image, ok := stepConfig["dockerImage"].(string); ok && len(image) > 0 {
+		return image
+	}
+	for _, container := range containers {
+		if len(container.Conditions) == 0 {
+			return container.Image
+		}
+		for _, condition := range container.Conditions {
+			matches := true
+			for _, param := range condition.Params {
+				if fmt.Sprint(stepConfig[param.Name]) != param.Value {
+					matches = false
+				}
+			}
+			if matches {
+				return container.Image
+			}
+		}
+	}
+	return ""
+}
+
+// changedGeneralFlags returns the general flags which have been set explicitly so that they can be passed on to the steps
+func changedGeneralFlags(flags *pflag.FlagSet) []string {
+	args := []string{}
+	flags.Visit(func(flag *pflag.Flag) {
+		if flag.Name == "stageName" {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				args = append(args, "--"+flag.Name, value)
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%v=%v", flag.Name, flag.Value.String()))
+	})
+	return args
+}
+
+func logRunSummary(stageName string, results []stepRunResult) {
+	if len(results) == 0 {
+		return
+	}
+	width := len("Step")
+	for _, result := range results {
+		width = max(width, len(result.Step))
+	}
+	lines := []string{fmt.Sprintf("Summary of stage %v:", stageName), fmt.Sprintf("  %-*v  %-8v  %8v  %v", width, "Step", "Status", "Duration", "Message")}
+	for _, result := range results {
+		duration := ""
+		if result.Duration > 0 {
+			duration = result.Duration.String()
+		}
+		lines = append(lines, fmt.Sprintf("  %-*v  %-8v  %8v  %v", width, result.Step, result.Status, duration, result.Message))
+	}
+	log.Entry().Info(strings.Join(lines, "\n"))
+}
+
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&runOptions.stageConfigFile, "stageConfig", ".resources/piper-stage-config.yml", "Pipeline definition containing the stage conditions")
+	cmd.Flags().StringVar(&runOptions.stageName, "stage", "", "Name of the stage to run")
+	cmd.Flags().BoolVar(&runOptions.containers, "containers", false, "Run the steps in the containers declared in their metadata (requires docker)")
+	cmd.Flags().StringVar(&runOptions.containerExecutable, "containerExecutable", "", "Linux build of piper mounted into the step containers, by default the current binary on Linux and the checksum verified build of the same release downloaded from GitHub otherwise")
+	cmd.Flags().BoolVar(&runOptions.continueOnError, "continueOnError", false, "Continue with the next step if a step fails")
+	cmd.MarkFlagRequired("stage")
+}
@@ -0,0 +1,266 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+type runUtilsMock struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+	downloads []string
+	// checksum is the content of downloaded checksum files
+	checksum string
+}
+
+func (r *runUtilsMock) DownloadFile(url, filename string, _ http.Header, _ []*http.Cookie) error {
+	r.downloads = append(r.downloads, url)
+	if strings.HasSuffix(url, ".sha256") {
+		r.AddFile(filename, []byte(r.checksum))
+		return nil
+	}
+	r.AddFile(filename, []byte("piper"))
+	return nil
+}
+
+// piperChecksum is the SHA256 of the binary provided by runUtilsMock
+const piperChecksum = "f4946d1234689b077c017045d050ca33dd89091567740df9d55b3e669766f866"
+
+const runStageConfig = `spec:
+  stages:
+  - name: build
+    displayName: Build
+    steps:
+    - name: mavenBuild
+    - name: npmExecuteScripts
+      conditions:
+      - configKey: npmScript
+    - name: groovyOnlyStep
+  - name: integration
+    displayName: Integration
+    steps:
+    - name: mavenExecuteIntegration
+      conditions:
+      - configKey: integrationScript
+`
+
+func runMetadataMock() map[string]config.StepData {
+	return map[string]config.StepData{
+		"mavenBuild": {
+			Metadata: config.StepMetadata{Name: "mavenBuild"},
+			Spec: config.StepSpec{Containers: []config.Container{
+				{Image: "maven:3-jdk-17", Conditions: []config.Condition{{Params: []config.Param{{Name: "jdk", Value: "17"}}}}},
+				{Image: "maven:3"},
+			}},
+		},
+		"npmExecuteScripts":       {Metadata: config.StepMetadata{Name: "npmExecuteScripts"}},
+		"mavenExecuteIntegration": {Metadata: config.StepMetadata{Name: "mavenExecuteIntegration"}},
+	}
+}
+
+func runOpenFileMock(name string, _ map[string]string) (io.ReadCloser, error) {
+	if name == "stage-config.yml" {
+		return io.NopCloser(strings.NewReader(runStageConfig)), nil
+	}
+	return nil, errors.New("file not found")
+}
+
+func TestRunStage(t *testing.T) {
+	defer func() {
+		GeneralConfig.MetaDataResolver = nil
+		runOptions = runCommandOptions{}
+	}()
+	GeneralConfig.MetaDataResolver = runMetadataMock
+
+	newOptions := func(stage string) runCommandOptions {
+		return runCommandOptions{
+			openFile:        runOpenFileMock,
+			stageConfigFile: "stage-config.yml",
+			stageName:       stage,
+			generalFlags:    []string{"--verbose=true"},
+			executable:      "/usr/bin/piper",
+		}
+	}
+
+	t.Run("active steps are executed in order", func(t *testing.T) {
+		runOptions = newOptions("Build")
+		utils := runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+
+		results, err := runStage(&utils)
+		require.NoError(t, err)
+		require.Len(t, utils.Calls, 1)
+		assert.Equal(t, mock.ExecCall{Exec: "/usr/bin/piper", Params: []string{"mavenBuild", "--stageName", "Build", "--verbose=true"}}, utils.Calls[0])
+		assert.Equal(t, []string{runStatusSuccess, runStatusInactive, runStatusSkipped}, runStatuses(results))
+	})
+
+	t.Run("failing step stops the stage", func(t *testing.T) {
+		runOptions = newOptions("build")
+		utils := runUtilsMock{
+			ExecMockRunner: &mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"/usr/bin/piper mavenBuild": errors.New("exit status 1")}},
+			FilesMock:      &mock.FilesMock{},
+		}
+
+		results, err := runStage(&utils)
+		assert.EqualError(t, err, "step 'mavenBuild' of stage 'Build' failed")
+		assert.Equal(t, []string{runStatusFailure, runStatusNotRun, runStatusNotRun}, runStatuses(results))
+		assert.Equal(t, "exit status 1", results[0].Message)
+		assert.Equal(t, log.ErrorUndefined, runErrorCategory(results))
+	})
+
+	t.Run("failing step reports its error category", func(t *testing.T) {
+		runOptions = newOptions("build")
+		files := &mock.FilesMock{}
+		// details of a previous run are not taken into account
+		files.AddFile("mavenBuild_errorDetails.json", []byte(`{"category":"test"}`))
+		utils := runUtilsMock{
+			ExecMockRunner: &mock.ExecMockRunner{Stub: func(call string, _ map[string]string, _ map[string]error, _ io.Writer) error {
+				files.AddFile("mavenBuild_errorDetails.json", []byte(`{"category":"build","message":"compilation failed"}`))
+				return errors.New("exit status 1")
+			}},
+			FilesMock: files,
+		}
+
+		results, err := runStage(&utils)
+		assert.Error(t, err)
+		assert.Equal(t, log.ErrorBuild, results[0].Category)
+		assert.Equal(t, log.ErrorBuild, runErrorCategory(results))
+	})
+
+	t.Run("stage which cannot be run is a configuration error", func(t *testing.T) {
+		assert.Equal(t, log.ErrorConfiguration, runErrorCategory(nil))
+	})
+
+	t.Run("steps in containers", func(t *testing.T) {
+		runOptions = newOptions("Build")
+		runOptions.containers = true
+		utils := runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+
+		_, err := runStage(&utils)
+		require.NoError(t, err)
+		require.Len(t, utils.Calls, 1)
+		assert.Equal(t, "docker", utils.Calls[0].Exec)
+		assert.Contains(t, strings.Join(utils.Calls[0].Params, " "), "--entrypoint /piper/piper maven:3 mavenBuild --stageName Build")
+	})
+
+	t.Run("unknown stage", func(t *testing.T) {
+		runOptions = newOptions("Deploy")
+		_, err := runStage(&runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}})
+		assert.EqualError(t, err, "stage 'Deploy' not found, available stages: Build, Integration")
+	})
+}
+
+func runStatuses(results []stepRunResult) []string {
+	statuses := []string{}
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func TestStepContainerImage(t *testing.T) {
+	containers := runMetadataMock()["mavenBuild"].Spec.Containers
+	assert.Equal(t, "maven:3-jdk-17", stepContainerImage(containers, map[string]interface{}{"jdk": "17"}))
+	assert.Equal(t, "maven:3", stepContainerImage(containers, map[string]interface{}{"jdk": "11"}))
+	assert.Equal(t, "custom:1", stepContainerImage(containers, map[string]interface{}{"dockerImage": "custom:1"}))
+	assert.Equal(t, "", stepContainerImage(nil, map[string]interface{}{}))
+}
+
+func TestChangedGeneralFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("verbose", false, "")
+	flags.StringSlice("defaultConfig", nil, "")
+	flags.String("stageName", "", "")
+	flags.String("envRootPath", ".pipeline", "")
+	require.NoError(t, flags.Parse([]string{"--verbose", "--defaultConfig", "a.yml,b.yml", "--stageName", "Build"}))
+
+	assert.Equal(t, []string{"--defaultConfig", "a.yml", "--defaultConfig", "b.yml", "--verbose=true"}, changedGeneralFlags(flags))
+}
+
+func TestContainerExecutable(t *testing.T) {
+	defer func() {
+		runOptions = runCommandOptions{}
+		GitTag = ""
+	}()
+
+	t.Run("current binary on Linux", func(t *testing.T) {
+		runOptions = runCommandOptions{}
+		utils := &runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+
+		executable, err := containerExecutable(utils, "/usr/bin/piper", "/workspace", "linux")
+		require.NoError(t, err)
+		assert.Equal(t, "/usr/bin/piper", executable)
+		assert.Empty(t, utils.downloads)
+	})
+
+	t.Run("Linux build of the release downloaded on other systems", func(t *testing.T) {
+		runOptions = runCommandOptions{}
+		GitTag = "v1.400.0"
+		utils := &runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}, checksum: piperChecksum + "  piper\n"}
+
+		executable, err := containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/workspace", ".pipeline", "run", "v1.400.0", "piper"), executable)
+		assert.Equal(t, []string{
+			"https://github.com/SAP/jenkins-library/releases/download/v1.400.0/piper.sha256",
+			"https://github.com/SAP/jenkins-library/releases/download/v1.400.0/piper",
+		}, utils.downloads)
+
+		// the binary is downloaded only once
+		_, err = containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		require.NoError(t, err)
+		assert.Len(t, utils.downloads, 2)
+
+		// the binary of another release is downloaded again
+		GitTag = "v1.401.0"
+		executable, err = containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/workspace", ".pipeline", "run", "v1.401.0", "piper"), executable)
+		assert.Len(t, utils.downloads, 4)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		runOptions = runCommandOptions{}
+		GitTag = "v1.400.0"
+		utils := &runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}, checksum: strings.Repeat("0", 64) + "  piper\n"}
+
+		_, err := containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		assert.ErrorContains(t, err, "failed to verify the Linux build of piper v1.400.0, provide one via --containerExecutable")
+		exists, _ := utils.FileExists(filepath.Join("/workspace", ".pipeline", "run", "v1.400.0", "piper"))
+		assert.False(t, exists)
+	})
+
+	t.Run("unknown release", func(t *testing.T) {
+		runOptions = runCommandOptions{}
+		GitTag = ""
+		utils := &runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+
+		_, err := containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		assert.EqualError(t, err, "the release of this piper binary is unknown, provide the Linux build of piper via --containerExecutable")
+		assert.Empty(t, utils.downloads)
+	})
+
+	t.Run("configured binary", func(t *testing.T) {
+		runOptions = runCommandOptions{containerExecutable: "/opt/piper-linux"}
+		utils := &runUtilsMock{ExecMockRunner: &mock.ExecMockRunner{}, FilesMock: &mock.FilesMock{}}
+
+		executable, err := containerExecutable(utils, "/usr/local/bin/piper", "/workspace", "darwin")
+		require.NoError(t, err)
+		assert.Equal(t, "/opt/piper-linux", executable)
+		assert.Empty(t, utils.downloads)
+	})
+}
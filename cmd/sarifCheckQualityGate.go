@@ -0,0 +1,480 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+type sarifCheckQualityGateUtils interface {
+	command.ExecRunner
+	piperutils.FileUtils
+}
+
+type sarifCheckQualityGateUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newSarifCheckQualityGateUtils() sarifCheckQualityGateUtils {
+	utils := sarifCheckQualityGateUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+// sarifLevels are the levels of results which can count towards the quality gate, ordered by severity
+var sarifLevels = []string{"error", "warning", "note"}
+
+// qualityGateResult is a result of a SARIF run which counts towards the quality gate
+type qualityGateResult struct {
+	tool   string
+	level  string
+	denied bool
+	result format.Results
+}
+
+// qualityGateEvaluation is the outcome of checking the results against the policy
+type qualityGateEvaluation struct {
+	results    []qualityGateResult
+	runs       []format.Runs
+	counts     map[string]int
+	allowed    int
+	baseline   int
+	unchanged  int
+	violations []string
+}
+
+func sarifCheckQualityGate(config sarifCheckQualityGateOptions, telemetryData *telemetry.CustomData) {
+	utils := newSarifCheckQualityGateUtils()
+
+	if config.NewFindingsOnly && len(config.PullRequestBase) == 0 {
+		detectPullRequestBase(&config, utils)
+	}
+
+	err := runSarifCheckQualityGate(&config, telemetryData, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+// detectPullRequestBase infers the base of a pull request from the CI environment, if it cannot be inferred all results
+// are evaluated
+func detectPullRequestBase(config *sarifCheckQualityGateOptions, utils sarifCheckQualityGateUtils) {
+	provider, err := orchestrator.GetOrchestratorConfigProvider(nil)
+	if err != nil {
+		log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
+		return
+	}
+	if !provider.IsPullRequest() {
+		return
+	}
+	base, err := fetchPullRequestBase(provider.PullRequestConfig().Base, utils)
+	if err != nil {
+		log.Entry().WithError(err).Warning("Cannot infer parameter pullRequestBase from environment")
+		return
+	}
+	log.Entry().Info("Inferring parameter pullRequestBase from environment: " + base)
+	config.PullRequestBase = base
+}
+
+// fetchPullRequestBase fetches the branch a pull request is merged into since CI checkouts often contain only the
+// pull request itself, and returns the remote-tracking branch of it, e.g. origin/main
+func fetchPullRequestBase(branch string, utils sarifCheckQualityGateUtils) (string, error) {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if len(branch) == 0 {
+		return "", nil
+	}
+	base := "origin/" + branch
+	if err := utils.RunExecutable("git", "fetch", "--no-tags", "origin", fmt.Sprintf("+refs/heads/%v:refs/remotes/%v", branch, base)); err != nil {
+		return "", errors.Wrapf(err, "failed to fetch the pull request base %v, please make it available in the checkout or set the parameter pullRequestBase", base)
+	}
+	return base, nil
+}
+
+func runSarifCheckQualityGate(config *sarifCheckQualityGateOptions, telemetryData *telemetry.CustomData, utils sarifCheckQualityGateUtils) error {
+	files, err := findSarifFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	combined := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{},
+	}
+	for _, file := range files {
+		sarif, err := readSarifFile(file, utils)
+		if err != nil {
+			return err
+		}
+		combined.Runs = append(combined.Runs, sarif.Runs...)
+	}
+
+	baseline := map[string]bool{}
+	if len(config.BaselineFile) > 0 {
+		sarif, err := readSarifFile(config.BaselineFile, utils)
+		if err != nil {
+			return err
+		}
+		for _, run := range sarif.Runs {
+			for _, result := range run.Results {
+				baseline[sarifResultFingerprint(result)] = true
+			}
+		}
+	}
+
+	var changedLines map[string][][2]int
+	if config.NewFindingsOnly {
+		if len(config.PullRequestBase) == 0 {
+			log.Entry().Info("No pull request base available, all results are evaluated")
+		} else if changedLines, err = gitChangedLines(config.PullRequestBase, utils); err != nil {
+			return err
+		}
+	}
+
+	evaluation := evaluateQualityGate(config, combined.Runs, baseline, changedLines)
+
+	reports := []piperutils.Path{}
+	combinedContent, err := json.MarshalIndent(combined, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize combined SARIF")
+	}
+	if err := utils.FileWrite(config.CombinedSarifFile, combinedContent, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to write %v", config.CombinedSarifFile)
+	}
+	reports = append(reports, piperutils.Path{Name: "Combined SARIF", Target: config.CombinedSarifFile})
+
+	scanReport := qualityGateReport(config, files, evaluation)
+	summary, _ := scanReport.ToMarkdown()
+	if err := utils.FileWrite(config.SummaryFile, summary, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to write %v", config.SummaryFile)
+	}
+	reports = append(reports, piperutils.Path{Name: "SARIF Quality Gate Summary", Target: config.SummaryFile})
+
+	// JSON reports are used by step pipelineCreateScanSummary
+	jsonReport, _ := scanReport.ToJSON()
+	if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+		return errors.Wrap(err, "failed to create reporting directory")
+	}
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, "sarifCheckQualityGate.json"), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	piperutils.PersistReportsAndLinks("sarifCheckQualityGate", "", utils, reports, nil)
+
+	for _, level := range sarifLevels {
+		log.Entry().Infof("%v results with level %v", evaluation.counts[level], level)
+	}
+	if len(evaluation.violations) > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("the quality gate failed: %v", strings.Join(evaluation.violations, ", "))
+	}
+	log.Entry().Info("the quality gate passed")
+	return nil
+}
+
+// findSarifFiles returns the files matching the patterns, without the files written or read by the step itself
+func findSarifFiles(config *sarifCheckQualityGateOptions, utils sarifCheckQualityGateUtils) ([]string, error) {
+	excluded := map[string]bool{
+		filepath.Clean(config.CombinedSarifFile): true,
+		filepath.Clean(config.BaselineFile):      len(config.BaselineFile) > 0,
+	}
+	files := []string{}
+	found := map[string]bool{}
+	for _, pattern := range config.SarifFiles {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "invalid pattern %v", pattern)
+		}
+		for _, match := range matches {
+			if clean := filepath.Clean(match); !found[clean] && !excluded[clean] {
+				found[clean] = true
+				files = append(files, match)
+			}
+		}
+	}
+	if len(files) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no SARIF files found matching %v", config.SarifFiles)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func readSarifFile(file string, utils sarifCheckQualityGateUtils) (format.SARIF, error) {
+	sarif := format.SARIF{}
+	content, err := utils.FileRead(file)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return sarif, errors.Wrapf(err, "failed to read %v", file)
+	}
+	if err := json.Unmarshal(content, &sarif); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return sarif, errors.Wrapf(err, "failed to parse SARIF file %v", file)
+	}
+	log.Entry().Debugf("read %v runs from %v", len(sarif.Runs), file)
+	return sarif, nil
+}
+
+func evaluateQualityGate(config *sarifCheckQualityGateOptions, runs []format.Runs, baseline map[string]bool, changedLines map[string][][2]int) qualityGateEvaluation {
+	evaluation := qualityGateEvaluation{counts: map[string]int{}}
+	denied := 0
+	for _, run := range runs {
+		countedRun := run
+		countedRun.Results = []format.Results{}
+		for _, result := range run.Results {
+			// results of other kinds like pass or informational are no findings
+			if len(result.Kind) > 0 && result.Kind != "fail" {
+				continue
+			}
+			level := sarifResultLevel(run, result)
+			if level == "none" {
+				continue
+			}
+			if matchesRule(config.AllowRules, result.RuleID) {
+				evaluation.allowed++
+				continue
+			}
+			if baseline[sarifResultFingerprint(result)] {
+				evaluation.baseline++
+				continue
+			}
+			if changedLines != nil && !isChangedLine(changedLines, result) {
+				evaluation.unchanged++
+				continue
+			}
+			counted := qualityGateResult{tool: run.Tool.Driver.Name, level: level, denied: matchesRule(config.DenyRules, result.RuleID), result: result}
+			if counted.denied {
+				denied++
+			}
+			evaluation.counts[level]++
+			evaluation.results = append(evaluation.results, counted)
+			countedRun.Results = append(countedRun.Results, result)
+		}
+		evaluation.runs = append(evaluation.runs, countedRun)
+	}
+
+	limits := map[string]int{"error": config.MaxErrors, "warning": config.MaxWarnings, "note": config.MaxNotes}
+	for _, level := range sarifLevels {
+		if limits[level] >= 0 && evaluation.counts[level] > limits[level] {
+			evaluation.violations = append(evaluation.violations, fmt.Sprintf("%v results with level %v exceed the maximum of %v", evaluation.counts[level], level, limits[level]))
+		}
+	}
+	if denied > 0 {
+		evaluation.violations = append(evaluation.violations, fmt.Sprintf("%v results of denied rules", denied))
+	}
+	sort.SliceStable(evaluation.results, func(i, j int) bool {
+		if evaluation.results[i].denied != evaluation.results[j].denied {
+			return evaluation.results[i].denied
+		}
+		return levelIndex(evaluation.results[i].level) < levelIndex(evaluation.results[j].level)
+	})
+	return evaluation
+}
+
+// sarifResultLevel returns the level of a result, which defaults to the level configured for its rule and finally to warning
+func sarifResultLevel(run format.Runs, result format.Results) string {
+	if len(result.Level) > 0 {
+		return result.Level
+	}
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID == result.RuleID && rule.DefaultConfiguration != nil && len(rule.DefaultConfiguration.Level) > 0 {
+			return rule.DefaultConfiguration.Level
+		}
+	}
+	return "warning"
+}
+
+func levelIndex(level string) int {
+	for i, l := range sarifLevels {
+		if l == level {
+			return i
+		}
+	}
+	return len(sarifLevels)
+}
+
+// matchesRule checks whether the rule matches one of the patterns, * matches any characters
+func matchesRule(patterns []string, ruleID string) bool {
+	for _, pattern := range patterns {
+		expression := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, _ := regexp.MatchString(expression, ruleID); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sarifResultFingerprint identifies a result independent of its line, since lines change with unrelated edits
+func sarifResultFingerprint(result format.Results) string {
+	message := ""
+	if result.Message != nil {
+		message = result.Message.Text
+	}
+	return strings.Join([]string{result.RuleID, sarifResultFile(result), message}, "|")
+}
+
+func sarifResultFile(result format.Results) string {
+	if len(result.Locations) == 0 {
+		return ""
+	}
+	file := strings.TrimPrefix(result.Locations[0].PhysicalLocation.ArtifactLocation.URI, "file://")
+	if filepath.IsAbs(file) {
+		if workspace, err := os.Getwd(); err == nil {
+			if relative, err := filepath.Rel(workspace, file); err == nil && !strings.HasPrefix(relative, "..") {
+				file = relative
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+func isChangedLine(changedLines map[string][][2]int, result format.Results) bool {
+	// results without location cannot be attributed to a change and are considered new
+	if len(result.Locations) == 0 {
+		return true
+	}
+	line := result.Locations[0].PhysicalLocation.Region.StartLine
+	for _, lines := range changedLines[sarifResultFile(result)] {
+		if line == 0 || (line >= lines[0] && line <= lines[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+var diffHunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// gitChangedLines returns the ranges of lines per file which were added or changed since the merge base with the base
+func gitChangedLines(base string, utils sarifCheckQualityGateUtils) (map[string][][2]int, error) {
+	var diff bytes.Buffer
+	utils.Stdout(&diff)
+	defer utils.Stdout(log.Writer())
+	if err := utils.RunExecutable("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", base+"...HEAD"); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to determine the changes compared to %v", base)
+	}
+
+	changedLines := map[string][][2]int{}
+	file := ""
+	scanner := bufio.NewScanner(&diff)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "+++ ") {
+			file = ""
+			if target := strings.TrimPrefix(line, "+++ "); target != "/dev/null" {
+				file = strings.TrimPrefix(target, "b/")
+			}
+			continue
+		}
+		matches := diffHunkPattern.FindStringSubmatch(line)
+		if matches == nil || len(file) == 0 {
+			continue
+		}
+		start, _ := strconv.Atoi(matches[1])
+		count := 1
+		if len(matches[2]) > 0 {
+			count, _ = strconv.Atoi(matches[2])
+		}
+		if count > 0 {
+			changedLines[file] = append(changedLines[file], [2]int{start, start + count - 1})
+		}
+	}
+	return changedLines, nil
+}
+
+func qualityGateReport(config *sarifCheckQualityGateOptions, files []string, evaluation qualityGateEvaluation) reporting.ScanReport {
+	limits := map[string]int{"error": config.MaxErrors, "warning": config.MaxWarnings, "note": config.MaxNotes}
+	scanReport := reporting.ScanReport{
+		ReportTitle: "SARIF Quality Gate",
+		Subheaders: []reporting.Subheader{
+			{Description: "SARIF files", Details: strings.Join(files, ", ")},
+		},
+		Overview:       []reporting.OverviewRow{},
+		SuccessfulScan: len(evaluation.violations) == 0,
+		ReportTime:     time.Now(),
+		Findings:       format.FindingsFromSARIF(format.SARIF{Runs: evaluation.runs}),
+	}
+	if config.NewFindingsOnly && len(config.PullRequestBase) > 0 {
+		scanReport.Subheaders = append(scanReport.Subheaders, reporting.Subheader{Description: "Pull request base", Details: config.PullRequestBase})
+	}
+	for _, level := range sarifLevels {
+		details := fmt.Sprint(evaluation.counts[level])
+		if limits[level] >= 0 {
+			details = fmt.Sprintf("%v (maximum %v)", evaluation.counts[level], limits[level])
+		}
+		row := reporting.OverviewRow{Description: fmt.Sprintf("Results with level %v", level), Details: details}
+		if limits[level] >= 0 && evaluation.counts[level] > limits[level] {
+			row.Style = reporting.Red
+		}
+		scanReport.Overview = append(scanReport.Overview, row)
+	}
+	scanReport.Overview = append(scanReport.Overview,
+		reporting.OverviewRow{Description: "Results of allowed rules", Details: fmt.Sprint(evaluation.allowed)},
+		reporting.OverviewRow{Description: "Results in the baseline", Details: fmt.Sprint(evaluation.baseline)},
+	)
+	if config.NewFindingsOnly && len(config.PullRequestBase) > 0 {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: "Results in unchanged lines", Details: fmt.Sprint(evaluation.unchanged)})
+	}
+	for _, violation := range evaluation.violations {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: violation, Style: reporting.Red})
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No results count towards the quality gate",
+		Headers:       []string{"Level", "Tool", "Rule", "Location", "Message"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, counted := range evaluation.results {
+		levelStyle := reporting.ColumnStyle(reporting.Yellow)
+		if counted.denied || counted.level == "error" {
+			levelStyle = reporting.Red
+		}
+		location := sarifResultFile(counted.result)
+		if len(counted.result.Locations) > 0 && counted.result.Locations[0].PhysicalLocation.Region.StartLine > 0 {
+			location = fmt.Sprintf("%v:%v", location, counted.result.Locations[0].PhysicalLocation.Region.StartLine)
+		}
+		message := ""
+		if counted.result.Message != nil {
+			message = counted.result.Message.Text
+		}
+		rule := counted.result.RuleID
+		if counted.denied {
+			rule += " (denied)"
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(counted.level, levelStyle)
+		row.AddColumn(counted.tool, 0)
+		row.AddColumn(rule, 0)
+		row.AddColumn(location, 0)
+		row.AddColumn(message, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
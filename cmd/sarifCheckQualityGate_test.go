@@ -0,0 +1,283 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sarifCheckQualityGateMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+func newSarifCheckQualityGateTestsUtils() sarifCheckQualityGateMockUtils {
+	utils := sarifCheckQualityGateMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+	return utils
+}
+
+func sarifResult(ruleID, level, file string, line int, message string) format.Results {
+	return format.Results{
+		RuleID:    ruleID,
+		Level:     level,
+		Message:   &format.Message{Text: message},
+		Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: file}, Region: format.Region{StartLine: line}}}},
+	}
+}
+
+func sarifContent(t *testing.T, tool string, rules []format.SarifRule, results ...format.Results) []byte {
+	sarif := format.SARIF{Version: "2.1.0", Runs: []format.Runs{{Tool: format.Tool{Driver: format.Driver{Name: tool, Rules: rules}}, Results: results}}}
+	content, err := json.Marshal(sarif)
+	require.NoError(t, err)
+	return content
+}
+
+func defaultSarifCheckQualityGateOptions() sarifCheckQualityGateOptions {
+	return sarifCheckQualityGateOptions{
+		SarifFiles:        []string{"**/*.sarif"},
+		MaxErrors:         0,
+		MaxWarnings:       -1,
+		MaxNotes:          -1,
+		CombinedSarifFile: "sarifQualityGate.sarif",
+		SummaryFile:       "sarifQualityGate.md",
+	}
+}
+
+func TestRunSarifCheckQualityGate(t *testing.T) {
+	t.Run("passes and combines the SARIF files", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.AddFile("codeql/results.sarif", sarifContent(t, "CodeQL", nil, sarifResult("js/xss", "warning", "src/app.js", 12, "Cross-site scripting")))
+		utils.AddFile("gosec.sarif", sarifContent(t, "gosec", nil, sarifResult("G101", "note", "main.go", 3, "Hardcoded credentials")))
+		// results of a previous run are not read again
+		utils.AddFile("sarifQualityGate.sarif", sarifContent(t, "CodeQL", nil, sarifResult("js/xss", "error", "src/app.js", 12, "Cross-site scripting")))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.NoError(t, err)
+		combinedContent, err := utils.FileRead("sarifQualityGate.sarif")
+		require.NoError(t, err)
+		combined := format.SARIF{}
+		require.NoError(t, json.Unmarshal(combinedContent, &combined))
+		assert.Equal(t, "2.1.0", combined.Version)
+		require.Len(t, combined.Runs, 2)
+		assert.Equal(t, "CodeQL", combined.Runs[0].Tool.Driver.Name)
+		assert.Equal(t, "gosec", combined.Runs[1].Tool.Driver.Name)
+
+		summary, err := utils.FileRead("sarifQualityGate.md")
+		require.NoError(t, err)
+		assert.Contains(t, string(summary), "SARIF Quality Gate")
+		assert.Contains(t, string(summary), "js/xss")
+		assert.Contains(t, string(summary), "src/app.js:12")
+		assert.True(t, utils.HasFile(filepath.Join(reporting.StepReportDirectory, "sarifCheckQualityGate.json")))
+	})
+
+	t.Run("fails if the maximum number of errors is exceeded", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		utils := newSarifCheckQualityGateTestsUtils()
+		rules := []format.SarifRule{{ID: "go/sql-injection", DefaultConfiguration: &format.DefaultConfiguration{Level: "error"}}}
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", rules, sarifResult("go/sql-injection", "", "db.go", 20, "SQL injection")))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.EqualError(t, err, "the quality gate failed: 1 results with level error exceed the maximum of 0")
+		// the reports are written also if the quality gate fails
+		assert.True(t, utils.HasFile("sarifQualityGate.md"))
+		assert.True(t, utils.HasFile("sarifQualityGate.sarif"))
+	})
+
+	t.Run("ignores allowed rules and fails for denied rules", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		config.AllowRules = []string{"js/*"}
+		config.DenyRules = []string{"G1*"}
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", nil, sarifResult("js/xss", "error", "src/app.js", 12, "Cross-site scripting")))
+		utils.AddFile("gosec.sarif", sarifContent(t, "gosec", nil, sarifResult("G101", "note", "main.go", 3, "Hardcoded credentials")))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.EqualError(t, err, "the quality gate failed: 1 results of denied rules")
+	})
+
+	t.Run("ignores results of the baseline independent of their line", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		config.BaselineFile = "baseline/accepted.sarif"
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", nil, sarifResult("js/xss", "error", "src/app.js", 15, "Cross-site scripting")))
+		utils.AddFile("baseline/accepted.sarif", sarifContent(t, "CodeQL", nil, sarifResult("js/xss", "error", "./src/app.js", 12, "Cross-site scripting")))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("counts only results in changed lines", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		config.NewFindingsOnly = true
+		config.PullRequestBase = "origin/main"
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.StdoutReturn = map[string]string{
+			"git diff --unified=0 --no-color --no-ext-diff origin/main...HEAD": "diff --git a/src/app.js b/src/app.js\n--- a/src/app.js\n+++ b/src/app.js\n@@ -10,0 +11,2 @@ function\n+a\n+b\n",
+		}
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", nil,
+			sarifResult("js/xss", "error", "src/app.js", 30, "Cross-site scripting"),
+			sarifResult("js/xss", "warning", "src/app.js", 12, "Cross-site scripting"),
+			sarifResult("js/xss", "error", "src/other.js", 12, "Cross-site scripting"),
+		))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.NoError(t, err)
+		summary, err := utils.FileRead("sarifQualityGate.md")
+		require.NoError(t, err)
+		assert.Contains(t, string(summary), "src/app.js:12")
+		assert.NotContains(t, string(summary), "src/app.js:30")
+	})
+
+	t.Run("counts all results without pull request base", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		config.NewFindingsOnly = true
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", nil,
+			sarifResult("js/xss", "warning", "src/app.js", 12, "Cross-site scripting"),
+		))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, utils.Calls)
+		summary, err := utils.FileRead("sarifQualityGate.md")
+		require.NoError(t, err)
+		assert.Contains(t, string(summary), "src/app.js:12")
+		assert.NotContains(t, string(summary), "Results in unchanged lines")
+	})
+
+	t.Run("fails if the configured pull request base cannot be diffed", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		config.NewFindingsOnly = true
+		config.PullRequestBase = "origin/main"
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"git diff": errors.New("unknown revision origin/main")}
+		utils.AddFile("codeql.sarif", sarifContent(t, "CodeQL", nil))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.ErrorContains(t, err, "unknown revision origin/main")
+	})
+
+	t.Run("fails without SARIF files", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		utils := newSarifCheckQualityGateTestsUtils()
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.EqualError(t, err, "no SARIF files found matching [**/*.sarif]")
+	})
+
+	t.Run("fails for invalid SARIF files", func(t *testing.T) {
+		config := defaultSarifCheckQualityGateOptions()
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.AddFile("broken.sarif", []byte("{"))
+
+		err := runSarifCheckQualityGate(&config, nil, utils)
+
+		assert.ErrorContains(t, err, "failed to parse SARIF file broken.sarif")
+	})
+}
+
+func TestSarifResultLevel(t *testing.T) {
+	run := format.Runs{Tool: format.Tool{Driver: format.Driver{Rules: []format.SarifRule{{ID: "rule", DefaultConfiguration: &format.DefaultConfiguration{Level: "note"}}}}}}
+	assert.Equal(t, "error", sarifResultLevel(run, format.Results{RuleID: "rule", Level: "error"}))
+	assert.Equal(t, "note", sarifResultLevel(run, format.Results{RuleID: "rule"}))
+	assert.Equal(t, "warning", sarifResultLevel(run, format.Results{RuleID: "other"}))
+}
+
+func TestMatchesRule(t *testing.T) {
+	assert.True(t, matchesRule([]string{"js/*"}, "js/xss"))
+	assert.True(t, matchesRule([]string{"*"}, "go/sql-injection"))
+	assert.True(t, matchesRule([]string{"G101"}, "G101"))
+	assert.False(t, matchesRule([]string{"G10"}, "G101"))
+	assert.False(t, matchesRule([]string{"js.xss"}, "jsaxss"))
+	assert.False(t, matchesRule(nil, "G101"))
+}
+
+func TestDetectPullRequestBase(t *testing.T) {
+	t.Setenv("GITHUB_ACTION", "sarifCheckQualityGate")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REPOSITORY", "SAP/jenkins-library")
+	orchestrator.ResetConfigProvider()
+	defer orchestrator.ResetConfigProvider()
+
+	t.Run("no pull request", func(t *testing.T) {
+		t.Setenv("GITHUB_HEAD_REF", "")
+		config := defaultSarifCheckQualityGateOptions()
+		config.NewFindingsOnly = true
+		utils := newSarifCheckQualityGateTestsUtils()
+
+		detectPullRequestBase(&config, utils)
+
+		assert.Empty(t, config.PullRequestBase)
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("base of the pull request cannot be fetched", func(t *testing.T) {
+		t.Setenv("GITHUB_HEAD_REF", "feature")
+		t.Setenv("GITHUB_BASE_REF", "main")
+		config := defaultSarifCheckQualityGateOptions()
+		config.NewFindingsOnly = true
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"git fetch": errors.New("could not read from remote repository")}
+
+		detectPullRequestBase(&config, utils)
+
+		assert.Empty(t, config.PullRequestBase)
+		assert.Len(t, utils.Calls, 1)
+	})
+}
+
+func TestFetchPullRequestBase(t *testing.T) {
+	t.Run("fetches the base branch", func(t *testing.T) {
+		utils := newSarifCheckQualityGateTestsUtils()
+
+		base, err := fetchPullRequestBase("refs/heads/main", utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "origin/main", base)
+		if assert.Len(t, utils.Calls, 1) {
+			assert.Equal(t, mock.ExecCall{Exec: "git", Params: []string{"fetch", "--no-tags", "origin", "+refs/heads/main:refs/remotes/origin/main"}}, utils.Calls[0])
+		}
+	})
+
+	t.Run("no base branch", func(t *testing.T) {
+		utils := newSarifCheckQualityGateTestsUtils()
+
+		base, err := fetchPullRequestBase("", utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, base)
+		assert.Empty(t, utils.Calls)
+	})
+
+	t.Run("fails if the base branch cannot be fetched", func(t *testing.T) {
+		utils := newSarifCheckQualityGateTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"git fetch": errors.New("could not read from remote repository")}
+
+		_, err := fetchPullRequestBase("main", utils)
+
+		assert.EqualError(t, err, "failed to fetch the pull request base origin/main, please make it available in the checkout or set the parameter pullRequestBase: could not read from remote repository")
+	})
+}
@@ -310,7 +310,12 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 	if err != nil {
 		return err
 	}
-	return nil
+
+	issues, err := issueService.GetIssues()
+	if err != nil {
+		log.Entry().Warnf("failed to retrieve sonar issues: %v", err)
+	}
+	return SonarUtils.WriteCustomReports(SonarUtils.CreateCustomReport(reportData, issues), taskReport.ProjectKey, utils)
 }
 
 // isInOptions returns true, if the given property is already provided in config.Options.
@@ -392,13 +392,14 @@ func sonarExecuteScanMetadata() config.StepData {
 						Default:     `https://binaries.sonarsource.com/Distribution/sonar-scanner-cli/sonar-scanner-cli-7.1.0.4889-linux-x64.zip`,
 					},
 					{
-						Name:        "versioningModel",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"GENERAL", "STAGES", "STEPS", "PARAMETERS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `major`,
+						Name:           "versioningModel",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "STAGES", "STEPS", "PARAMETERS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `major`,
+						PossibleValues: []interface{}{"major", "major-minor", "semantic", "full"},
 					},
 					{
 						Name: "version",
@@ -523,13 +524,14 @@ func sonarExecuteScanMetadata() config.StepData {
 						Default:     os.Getenv("PIPER_changeTarget"),
 					},
 					{
-						Name:        "pullRequestProvider",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `GitHub`,
+						Name:           "pullRequestProvider",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `GitHub`,
+						PossibleValues: []interface{}{"GitHub"},
 					},
 					{
 						Name: "owner",
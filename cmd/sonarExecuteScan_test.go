@@ -20,6 +20,7 @@ import (
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	SonarUtils "github.com/SAP/jenkins-library/pkg/sonar"
 )
 
@@ -178,10 +179,13 @@ func TestRunSonar(t *testing.T) {
 		fileUtilsExists = mockFileUtilsExists(true)
 		os.Setenv("SONAR_SCANNER_OPTS", "-Xmx42m")
 		defer os.Setenv("SONAR_SCANNER_OPTS", "")
+		fileUtils := &mock.FilesMock{}
 		// test
-		err := runSonar(options, &mockDownloadClient, &mockRunner, apiClient, &mock.FilesMock{}, &sonarExecuteScanInflux{})
+		err := runSonar(options, &mockDownloadClient, &mockRunner, apiClient, fileUtils, &sonarExecuteScanInflux{})
 		// assert
 		assert.NoError(t, err)
+		stepReports, _ := fileUtils.Glob(reporting.StepReportDirectory + "/sonarExecuteScan_*.json")
+		assert.Len(t, stepReports, 1)
 		assert.Contains(t, sonar.options, "-Dsonar.projectVersion=1")
 		assert.Contains(t, sonar.options, "-Dsonar.organization=SAP")
 		assert.Contains(t, sonar.environment, "SONAR_HOST_URL="+sonarServerURL)
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type suppressionFileUtils interface {
+	FileExists(filename string) (bool, error)
+	FileRead(path string) ([]byte, error)
+}
+
+// readSuppressionsFromFile reads the suppressions of the repository-local suppression file which are not expired.
+// A missing file results in no suppressions, expired suppressions are reported as warnings.
+func readSuppressionsFromFile(suppressionFilePath string, utils suppressionFileUtils, now time.Time) (format.Suppressions, error) {
+	if len(suppressionFilePath) == 0 {
+		return format.Suppressions{}, nil
+	}
+	exists, err := utils.FileExists(suppressionFilePath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to check existence of suppression file at '%s'", suppressionFilePath)
+	}
+	if !exists {
+		log.Entry().Debugf("no suppression file found at '%s'", suppressionFilePath)
+		return format.Suppressions{}, nil
+	}
+	content, err := utils.FileRead(suppressionFilePath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to read suppression file at '%s'", suppressionFilePath)
+	}
+	suppressions, err := format.ReadSuppressions(io.NopCloser(bytes.NewReader(content)), suppressionFilePath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to parse suppression file at '%s'", suppressionFilePath)
+	}
+
+	for _, suppression := range suppressions.Expired(now) {
+		log.Entry().Warnf("suppression of %v for '%v' approved by %v expired on %v and is ignored: %v", suppression.ID, suppression.Path, suppression.Approver, suppression.Expires, suppression.Justification)
+	}
+	active := suppressions.Active(now)
+	log.Entry().Infof("using %v suppressions of '%s'", len(active), suppressionFilePath)
+	return active, nil
+}
@@ -0,0 +1,44 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSuppressionsFromFile(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("active suppressions", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/suppressions.yml", []byte("suppressions:\n  - id: G101\n    expires: \"2026-01-31\"\n    justification: test data\n    approver: someone\n  - id: G102\n    justification: test data\n    approver: someone\n"))
+
+		suppressions, err := readSuppressionsFromFile(".pipeline/suppressions.yml", utils, now)
+
+		assert.NoError(t, err)
+		if assert.Len(t, suppressions, 1) {
+			assert.Equal(t, "G102", suppressions[0].ID)
+		}
+	})
+
+	t.Run("no suppression file", func(t *testing.T) {
+		suppressions, err := readSuppressionsFromFile(".pipeline/suppressions.yml", &mock.FilesMock{}, now)
+
+		assert.NoError(t, err)
+		assert.Empty(t, suppressions)
+	})
+
+	t.Run("invalid suppression file", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/suppressions.yml", []byte("suppressions:\n  - id: G101\n"))
+
+		_, err := readSuppressionsFromFile(".pipeline/suppressions.yml", utils, now)
+
+		assert.EqualError(t, err, "unable to parse suppression file at '.pipeline/suppressions.yml': suppression 1 is invalid: missing justification, approver")
+	})
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+type validateConfigCommandOptions struct {
+	openFile       func(s string, t map[string]string) (io.ReadCloser, error)
+	output         string
+	outputFile     string
+	failOnWarnings bool
+}
+
+var validateConfigOptions validateConfigCommandOptions
+
+// ValidateConfigCommand is the entry command for validating the project configuration against the step metadata
+func ValidateConfigCommand() *cobra.Command {
+	validateConfigOptions.openFile = config.OpenPiperFile
+	var validateConfigCmd = &cobra.Command{
+		Use:   "validateConfig",
+		Short: "Validates the project 'Piper' configuration against the metadata of the steps.",
+		Long: `Checks all entries of the general, stages and steps sections of the project configuration.
+Unknown keys are reported together with suggestions, values are checked against the type and the possible values of the parameters.
+Keys of the general and stages sections might be used by other tools, thus unknown keys are reported as warnings there.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			if err := validateConfig(utils); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("validation of the configuration failed")
+			}
+		},
+	}
+	addValidateConfigFlags(validateConfigCmd)
+	return validateConfigCmd
+}
+
+func validateConfig(utils piperutils.FileUtils) error {
+	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
+	issues, err := validateProjectConfig(projectConfigFile, validateConfigOptions.openFile)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	switch validateConfigOptions.output {
+	case "json":
+		if output, err = json.MarshalIndent(issues, "", "  "); err != nil {
+			return errors.Wrap(err, "failed to marshal issues")
+		}
+	case "text":
+		lines := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			lines = append(lines, issue.String())
+		}
+		output = []byte(strings.Join(lines, "\n"))
+	default:
+		return errors.Errorf("output format '%s' not supported, supported formats: json, text", validateConfigOptions.output)
+	}
+
+	if len(validateConfigOptions.outputFile) > 0 {
+		if err := utils.FileWrite(validateConfigOptions.outputFile, output, 0666); err != nil {
+			return fmt.Errorf("error writing file '%v': %w", validateConfigOptions.outputFile, err)
+		}
+	} else if len(output) > 0 {
+		fmt.Println(string(output))
+	}
+
+	errorCount, warningCount := countConfigIssues(issues)
+	if errorCount > 0 || (validateConfigOptions.failOnWarnings && warningCount > 0) {
+		return errors.Errorf("configuration '%s' contains %d error(s) and %d warning(s)", projectConfigFile, errorCount, warningCount)
+	}
+	log.Entry().Infof("configuration '%s' is valid (%d warning(s))", projectConfigFile, warningCount)
+	return nil
+}
+
+func addValidateConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&validateConfigOptions.output, "output", "text", "Defines the output format (text, json)")
+	cmd.Flags().StringVar(&validateConfigOptions.outputFile, "outputFile", "", "Defines a file path. If set, the issues will be written to the defined file")
+	cmd.Flags().BoolVar(&validateConfigOptions.failOnWarnings, "failOnWarnings", false, "Also fail if only warnings are found")
+}
+
+// validateProjectConfig validates the project configuration file against the metadata of all steps
+func validateProjectConfig(projectConfigFile string, openFile func(s string, t map[string]string) (io.ReadCloser, error)) ([]config.ConfigIssue, error) {
+	customConfig, err := openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
+	}
+
+	var projectConfig config.Config
+	if err := projectConfig.ReadConfig(customConfig); err != nil {
+		return nil, errors.Wrapf(err, "config: reading configuration file '%v' failed", projectConfigFile)
+	}
+
+	if GeneralConfig.MetaDataResolver == nil {
+		GeneralConfig.MetaDataResolver = GetAllStepMetadata
+	}
+	return projectConfig.Validate(GeneralConfig.MetaDataResolver()), nil
+}
+
+// failOnConfigIssues validates the project configuration at the start of a step if strict configuration is enabled.
+// Only issues in the sections relevant for the step are considered.
+func failOnConfigIssues(projectConfigFile string, metadata *config.StepData, stepName string, openFile func(s string, t map[string]string) (io.ReadCloser, error)) error {
+	issues, err := validateProjectConfig(projectConfigFile, openFile)
+	if err != nil {
+		return err
+	}
+
+	sections := []string{"general", "steps/" + stepName}
+	if len(GeneralConfig.StageName) > 0 {
+		sections = append(sections, "stages/"+GeneralConfig.StageName)
+	}
+	for _, alias := range metadata.Metadata.Aliases {
+		sections = append(sections, "steps/"+alias.Name)
+	}
+
+	relevant := []config.ConfigIssue{}
+	for _, issue := range issues {
+		if !slices.Contains(sections, issue.Section) {
+			continue
+		}
+		if issue.Severity == config.IssueError {
+			log.Entry().Error(issue.String())
+		} else {
+			log.Entry().Warning(issue.String())
+		}
+		relevant = append(relevant, issue)
+	}
+
+	if config.ConfigIssuesContainErrors(relevant) {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Errorf("configuration '%s' is invalid, run 'piper validateConfig' for details", projectConfigFile)
+	}
+	return nil
+}
+
+func countConfigIssues(issues []config.ConfigIssue) (errorCount, warningCount int) {
+	for _, issue := range issues {
+		if issue.Severity == config.IssueError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+	return
+}
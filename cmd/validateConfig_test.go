@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func validateConfigMetadataMock() map[string]config.StepData {
+	return map[string]config.StepData{
+		"testStep": {
+			Metadata: config.StepMetadata{Name: "testStep"},
+			Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+				{Name: "testParam", Type: "string", Scope: []string{"GENERAL", "STEPS"}},
+			}}},
+		},
+	}
+}
+
+func validateConfigOpenFileMock(content string) func(name string, tokens map[string]string) (io.ReadCloser, error) {
+	return func(name string, tokens map[string]string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	defer func() {
+		GeneralConfig.MetaDataResolver = nil
+		validateConfigOptions = validateConfigCommandOptions{}
+	}()
+	GeneralConfig.MetaDataResolver = validateConfigMetadataMock
+
+	t.Run("valid configuration", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{
+			openFile:   validateConfigOpenFileMock("steps:\n  testStep:\n    testParam: value\n"),
+			output:     "json",
+			outputFile: "issues.json",
+		}
+		utils := &mock.FilesMock{}
+
+		require.NoError(t, validateConfig(utils))
+		content, err := utils.FileRead("issues.json")
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(content))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{
+			openFile:   validateConfigOpenFileMock("steps:\n  testStep:\n    testParm: value\n"),
+			output:     "text",
+			outputFile: "issues.txt",
+		}
+		utils := &mock.FilesMock{}
+
+		err := validateConfig(utils)
+		assert.EqualError(t, err, "configuration '.pipeline/config.yml' contains 1 error(s) and 0 warning(s)")
+		content, _ := utils.FileRead("issues.txt")
+		assert.Equal(t, "error: steps/testStep: unknown parameter 'testParm' (did you mean 'testParam'?)", string(content))
+	})
+
+	t.Run("fail on warnings", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{
+			openFile:       validateConfigOpenFileMock("steps:\n  otherStep:\n    testParam: value\n"),
+			output:         "text",
+			outputFile:     "issues.txt",
+			failOnWarnings: true,
+		}
+
+		err := validateConfig(&mock.FilesMock{})
+		assert.EqualError(t, err, "configuration '.pipeline/config.yml' contains 0 error(s) and 1 warning(s)")
+	})
+}
+
+func TestFailOnConfigIssues(t *testing.T) {
+	defer func() {
+		GeneralConfig.MetaDataResolver = nil
+		GeneralConfig.StageName = ""
+	}()
+	GeneralConfig.MetaDataResolver = validateConfigMetadataMock
+	metadata := validateConfigMetadataMock()["testStep"]
+	openFile := validateConfigOpenFileMock("steps:\n  testStep:\n    testParm: value\n  otherStep:\n    testParm: value\n")
+
+	t.Run("issues of the step", func(t *testing.T) {
+		err := failOnConfigIssues(".pipeline/config.yml", &metadata, "testStep", openFile)
+		assert.EqualError(t, err, "configuration '.pipeline/config.yml' is invalid, run 'piper validateConfig' for details")
+	})
+
+	t.Run("issues of other steps are ignored", func(t *testing.T) {
+		otherMetadata := config.StepData{Metadata: config.StepMetadata{Name: "anotherStep"}}
+		assert.NoError(t, failOnConfigIssues(".pipeline/config.yml", &otherMetadata, "anotherStep", openFile))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/ado"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
@@ -42,6 +43,13 @@ func (v vaultRotateSecretIDUtilsBundle) UpdateSecretInStore(config *vaultRotateS
 }
 
 func vaultRotateSecretId(config vaultRotateSecretIdOptions, telemetryData *telemetry.CustomData) {
+	if skip, err := skipSecretIDRotation(config.VaultAuthMethod, GeneralConfig.VaultRoleSecretID); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		log.Entry().WithError(err).Fatal("step execution failed")
+	} else if skip {
+		log.Entry().Infof("Vault login via the %v auth method does not use an AppRole secret ID, skipping rotation", config.VaultAuthMethod)
+		return
+	}
 
 	vaultConfig := &vault.ClientConfig{
 		Config: &api.Config{
@@ -186,3 +194,18 @@ func writeVaultSecretIDToStore(config *vaultRotateSecretIdOptions, secretID stri
 	}
 	return nil
 }
+
+// skipSecretIDRotation reports whether there is no secret ID to rotate since the auth method does not use a long-lived secret,
+// a missing secret ID is an error for the AppRole auth method, which is also used if no auth method is configured
+func skipSecretIDRotation(authMethod, secretID string) (bool, error) {
+	switch authMethod {
+	case vault.AuthMethodKubernetes, vault.AuthMethodJWT, vault.AuthMethodCert:
+		return len(secretID) == 0, nil
+	case "", vault.AuthMethodAppRole:
+		if len(secretID) == 0 {
+			return false, errors.New("no Vault AppRole secret ID configured")
+		}
+		return false, nil
+	}
+	return false, errors.Errorf("unsupported Vault auth method '%v', supported auth methods: approle, kubernetes, jwt, cert", authMethod)
+}
@@ -25,6 +25,7 @@ type vaultRotateSecretIdOptions struct {
 	VaultAppRoleSecretTokenCredentialsID string `json:"vaultAppRoleSecretTokenCredentialsId,omitempty"`
 	VaultServerURL                       string `json:"vaultServerUrl,omitempty"`
 	VaultNamespace                       string `json:"vaultNamespace,omitempty"`
+	VaultAuthMethod                      string `json:"vaultAuthMethod,omitempty"`
 	DaysBeforeExpiry                     int    `json:"daysBeforeExpiry,omitempty"`
 	AdoOrganization                      string `json:"adoOrganization,omitempty"`
 	AdoPersonalAccessToken               string `json:"adoPersonalAccessToken,omitempty"`
@@ -169,6 +170,7 @@ func addVaultRotateSecretIdFlags(cmd *cobra.Command, stepConfig *vaultRotateSecr
 	cmd.Flags().StringVar(&stepConfig.VaultAppRoleSecretTokenCredentialsID, "vaultAppRoleSecretTokenCredentialsId", os.Getenv("PIPER_vaultAppRoleSecretTokenCredentialsId"), "The Jenkins credential ID, Azure DevOps variable name, or GitHub Actions secret name for the Vault AppRole Secret ID credential")
 	cmd.Flags().StringVar(&stepConfig.VaultServerURL, "vaultServerUrl", os.Getenv("PIPER_vaultServerUrl"), "The URL for the Vault server to use")
 	cmd.Flags().StringVar(&stepConfig.VaultNamespace, "vaultNamespace", os.Getenv("PIPER_vaultNamespace"), "The Vault namespace that should be used (optional)")
+	cmd.Flags().StringVar(&stepConfig.VaultAuthMethod, "vaultAuthMethod", os.Getenv("PIPER_vaultAuthMethod"), "The auth method of the Vault login: approle (default), kubernetes, jwt or cert. The rotation is skipped for the auth methods which do not use an AppRole secret ID.")
 	cmd.Flags().IntVar(&stepConfig.DaysBeforeExpiry, "daysBeforeExpiry", 15, "The amount of days before expiry until the secret ID gets rotated")
 	cmd.Flags().StringVar(&stepConfig.AdoOrganization, "adoOrganization", os.Getenv("PIPER_adoOrganization"), "The Azure DevOps organization name")
 	cmd.Flags().StringVar(&stepConfig.AdoPersonalAccessToken, "adoPersonalAccessToken", os.Getenv("PIPER_adoPersonalAccessToken"), "The Azure DevOps personal access token")
@@ -195,13 +197,14 @@ func vaultRotateSecretIdMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Parameters: []config.StepParameters{
 					{
-						Name:        "secretStore",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `jenkins`,
+						Name:           "secretStore",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `jenkins`,
+						PossibleValues: []interface{}{"jenkins", "ado", "github"},
 					},
 					{
 						Name: "jenkinsUrl",
@@ -284,6 +287,15 @@ func vaultRotateSecretIdMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_vaultNamespace"),
 					},
+					{
+						Name:        "vaultAuthMethod",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_vaultAuthMethod"),
+					},
 					{
 						Name:        "daysBeforeExpiry",
 						ResourceRef: []config.ResourceReference{},
@@ -344,11 +356,12 @@ func vaultRotateSecretIdMetadata() config.StepData {
 								Default: "github",
 							},
 						},
-						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
-						Type:      "string",
-						Mandatory: false,
-						Aliases:   []config.Alias{{Name: "access_token"}, {Name: "token"}},
-						Default:   os.Getenv("PIPER_githubToken"),
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "access_token"}, {Name: "token"}},
+						Default:     os.Getenv("PIPER_githubToken"),
+						MandatoryIf: []config.ParameterDependence{{Name: "secretStore", Value: "github"}},
 					},
 					{
 						Name:        "githubApiUrl",
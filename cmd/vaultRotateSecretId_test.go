@@ -152,3 +152,24 @@ func getTestConfig() *vaultRotateSecretIdOptions {
 		DaysBeforeExpiry: 5,
 	}
 }
+
+func TestSkipSecretIDRotation(t *testing.T) {
+	t.Parallel()
+
+	for _, authMethod := range []string{"kubernetes", "jwt", "cert"} {
+		skip, err := skipSecretIDRotation(authMethod, "")
+		assert.NoError(t, err)
+		assert.True(t, skip, authMethod)
+	}
+
+	skip, err := skipSecretIDRotation("approle", "secret-id")
+	assert.NoError(t, err)
+	assert.False(t, skip)
+
+	_, err = skipSecretIDRotation("approle", "")
+	assert.EqualError(t, err, "no Vault AppRole secret ID configured")
+	_, err = skipSecretIDRotation("", "")
+	assert.EqualError(t, err, "no Vault AppRole secret ID configured")
+	_, err = skipSecretIDRotation("ldap", "")
+	assert.EqualError(t, err, "unsupported Vault auth method 'ldap', supported auth methods: approle, kubernetes, jwt, cert")
+}
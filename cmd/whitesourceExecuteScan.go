@@ -678,6 +678,10 @@ func checkSecurityViolations(ctx context.Context, config *ScanOptions, scan *ws.
 
 	// inhale assessments from file system
 	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
+	suppressions, err := readSuppressionsFromFile(config.SuppressionFile, utils, utils.Now())
+	if err != nil {
+		return []piperutils.Path{}, err
+	}
 
 	vulnerabilitiesCount := 0
 	var allOccurredErrors []string
@@ -696,6 +700,7 @@ func checkSecurityViolations(ctx context.Context, config *ScanOptions, scan *ws.
 			project,
 			sys,
 			assessments,
+			suppressions,
 			influx,
 		)
 
@@ -710,6 +715,7 @@ func checkSecurityViolations(ctx context.Context, config *ScanOptions, scan *ws.
 				project,
 				sys,
 				assessments,
+				suppressions,
 				influx,
 			)
 			if len(occurredErrors) != 0 {
@@ -754,6 +760,7 @@ func collectVulnsAndLibsForProject(
 	project ws.Project,
 	sys whitesource,
 	assessments *[]format.Assessment,
+	suppressions format.Suppressions,
 	influx *whitesourceExecuteScanInflux,
 ) (
 	int,
@@ -763,7 +770,7 @@ func collectVulnsAndLibsForProject(
 	[]string,
 ) {
 	var errorsOccurred []string
-	vulCount, alerts, assessedAlerts, err := checkProjectSecurityViolations(config, cvssSeverityLimit, project, sys, assessments, influx)
+	vulCount, alerts, assessedAlerts, err := checkProjectSecurityViolations(config, cvssSeverityLimit, project, sys, assessments, suppressions, influx)
 	if err != nil {
 		errorsOccurred = append(errorsOccurred, fmt.Sprint(err))
 	}
@@ -869,7 +876,7 @@ func readAssessmentsFromFile(assessmentFilePath string, utils whitesourceUtils)
 }
 
 // checkSecurityViolations checks security violations and returns an error if the configured severity limit is crossed. Besides the potential error the list of unassessed and assessed alerts are being returned to allow generating reports and issues from the data.
-func checkProjectSecurityViolations(config *ScanOptions, cvssSeverityLimit float64, project ws.Project, sys whitesource, assessments *[]format.Assessment, influx *whitesourceExecuteScanInflux) (int, []ws.Alert, []ws.Alert, error) {
+func checkProjectSecurityViolations(config *ScanOptions, cvssSeverityLimit float64, project ws.Project, sys whitesource, assessments *[]format.Assessment, suppressions format.Suppressions, influx *whitesourceExecuteScanInflux) (int, []ws.Alert, []ws.Alert, error) {
 	// get project alerts (vulnerabilities)
 	alerts, err := sys.GetProjectAlertsByType(project.Token, "SECURITY_VULNERABILITY")
 	if err != nil {
@@ -896,6 +903,20 @@ func checkProjectSecurityViolations(config *ScanOptions, cvssSeverityLimit float
 		alerts = filteredAlerts
 	}
 
+	// suppressed alerts are treated like assessed ones
+	if len(suppressions) > 0 {
+		unsuppressedAlerts := []ws.Alert{}
+		for i, finding := range ws.ToFindings(alerts) {
+			if suppression := suppressions.Match(finding); suppression != nil {
+				log.Entry().Debugf("Matched suppression %v approved by %v to vulnerability %v affecting package %v", suppression.ID, suppression.Approver, alerts[i].Vulnerability.Name, finding.PackageURL)
+				assessedAlerts = append(assessedAlerts, alerts[i])
+			} else {
+				unsuppressedAlerts = append(unsuppressedAlerts, alerts[i])
+			}
+		}
+		alerts = unsuppressedAlerts
+	}
+
 	severeVulnerabilities, nonSevereVulnerabilities := ws.CountSecurityVulnerabilities(&alerts, cvssSeverityLimit)
 	influx.whitesource_data.fields.minor_vulnerabilities = nonSevereVulnerabilities
 	influx.whitesource_data.fields.major_vulnerabilities = severeVulnerabilities
@@ -41,6 +41,8 @@ func WritePipelineEnv() *cobra.Command {
 			}
 			log.RegisterSecret(stepConfig.Password)
 			log.RegisterSecret(stepConfig.Username)
+			// the metadata is borrowed from artifactPrepareVersion, changes are recorded for this command
+			piperenv.SetJournalStep("writePipelineEnv")
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
@@ -83,6 +85,10 @@ func runWritePipelineEnv(stepConfigPassword string, encryptedCPE bool) error {
 		return fmt.Errorf("failed to parse input: %w", err)
 	}
 
+	for _, err := range cpeSchema().Check(commonPipelineEnv) {
+		log.Entry().Warnf("unexpected value in common pipeline environment: %v", err)
+	}
+
 	if _, err := writeOutput(commonPipelineEnv); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
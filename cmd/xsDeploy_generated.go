@@ -255,22 +255,24 @@ func xsDeployMetadata() config.StepData {
 						Default:   os.Getenv("PIPER_mtaPath"),
 					},
 					{
-						Name:        "action",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{},
-						Default:     `NONE`,
+						Name:           "action",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						Default:        `NONE`,
+						PossibleValues: []interface{}{"NONE", "Resume", "Abort", "Retry"},
 					},
 					{
-						Name:        "mode",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
-						Aliases:     []config.Alias{},
-						Default:     `DEPLOY`,
+						Name:           "mode",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						Default:        `DEPLOY`,
+						PossibleValues: []interface{}{"NONE", "DEPLOY", "BG_DEPLOY"},
 					},
 					{
 						Name: "operationId",
@@ -0,0 +1,40 @@
+package blackduck
+
+import (
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ToFindings converts the vulnerabilities of a project version into scanner independent findings
+func ToFindings(vulns []Vulnerability) []format.Finding {
+	findings := []format.Finding{}
+	for _, v := range vulns {
+		finding := format.Finding{
+			Tool:       "BlackDuck",
+			Rule:       v.VulnerabilityName,
+			CWE:        format.NormalizeCWE(v.CweID),
+			CVE:        format.FindCVE(v.VulnerabilityName, v.RelatedVulnerability),
+			Severity:   format.NormalizeSeverity(v.Severity),
+			Status:     remediationStatus(v),
+			AuditState: v.RemediationStatus,
+			Message:    v.Description,
+		}
+		if v.Component != nil {
+			finding.PackageURL = v.Component.ToPackageUrl().ToString()
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func remediationStatus(v Vulnerability) string {
+	if v.Ignored {
+		return format.FindingStatusSuppressed
+	}
+	switch v.RemediationStatus {
+	case "REMEDIATION_COMPLETE", "PATCHED":
+		return format.FindingStatusFixed
+	case "IGNORED", "MITIGATED", "DUPLICATE":
+		return format.FindingStatusSuppressed
+	}
+	return format.FindingStatusOpen
+}
@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package blackduck
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	vulns := []Vulnerability{
+		{
+			Component: &Component{Name: "log4j-core", Version: "2.14.1", Origins: []ComponentOrigin{{ExternalNamespace: "maven", ExternalID: "org.apache.logging.log4j:log4j-core:2.14.1"}}},
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{
+				VulnerabilityName:    "BDSA-2021-3779",
+				RelatedVulnerability: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+				CweID:                "CWE-502",
+				Severity:             "CRITICAL",
+				RemediationStatus:    "NEW",
+			},
+		},
+		{
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2020-1234", Severity: "LOW", RemediationStatus: "PATCHED"},
+		},
+		{
+			Ignored:                      true,
+			VulnerabilityWithRemediation: VulnerabilityWithRemediation{VulnerabilityName: "CVE-2020-5678", Severity: "MEDIUM", RemediationStatus: "NEW"},
+		},
+	}
+
+	findings := ToFindings(vulns)
+
+	if assert.Len(t, findings, 3) {
+		assert.Equal(t, "BlackDuck", findings[0].Tool)
+		assert.Equal(t, "CVE-2021-44228", findings[0].CVE)
+		assert.Equal(t, "502", findings[0].CWE)
+		assert.Equal(t, vulns[0].Component.ToPackageUrl().ToString(), findings[0].PackageURL)
+		assert.Equal(t, format.SeverityCritical, findings[0].Severity)
+		assert.Equal(t, format.FindingStatusOpen, findings[0].Status)
+		assert.Empty(t, findings[1].PackageURL)
+		assert.Equal(t, format.FindingStatusFixed, findings[1].Status)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[2].Status)
+	}
+}
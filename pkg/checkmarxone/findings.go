@@ -0,0 +1,35 @@
+package checkmarxOne
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ToFindings converts the results of a scan into scanner independent findings, the location of a
+// finding is its first node, i.e. the source like in the Checkmarx One web view
+func ToFindings(results []ScanResult) []format.Finding {
+	findings := []format.Finding{}
+	for _, r := range results {
+		finding := format.Finding{
+			Tool:       "CheckmarxOne",
+			Rule:       r.Data.QueryName,
+			CWE:        format.NormalizeCWE(fmt.Sprint(r.VulnerabilityDetails.CweId)),
+			Severity:   format.NormalizeSeverity(r.Severity),
+			Status:     format.FindingStatusOpen,
+			AuditState: r.State,
+			Message:    r.Description,
+		}
+		if len(r.Data.Nodes) > 0 {
+			finding.Location = &format.FindingLocation{File: r.Data.Nodes[0].FileName, Line: r.Data.Nodes[0].Line}
+		}
+		switch {
+		case r.Status == "FIXED":
+			finding.Status = format.FindingStatusFixed
+		case r.State == "NOT_EXPLOITABLE":
+			finding.Status = format.FindingStatusSuppressed
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
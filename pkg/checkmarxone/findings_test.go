@@ -0,0 +1,41 @@
+package checkmarxOne
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	results := []ScanResult{
+		{
+			Severity: "HIGH",
+			State:    "TO_VERIFY",
+			Status:   "NEW",
+			Data: ScanResultData{QueryName: "Reflected_XSS", Nodes: []ScanResultNodes{
+				{FileName: "/src/main/App.java", Line: 12},
+				{FileName: "/src/main/View.java", Line: 40},
+			}},
+			VulnerabilityDetails: ScanResultDetails{CweId: 79},
+		},
+		{Severity: "LOW", State: "NOT_EXPLOITABLE", Status: "RECURRENT"},
+	}
+
+	findings := ToFindings(results)
+
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, format.Finding{
+			Tool:       "CheckmarxOne",
+			Rule:       "Reflected_XSS",
+			CWE:        "79",
+			Location:   &format.FindingLocation{File: "/src/main/App.java", Line: 12},
+			Severity:   format.SeverityHigh,
+			Status:     format.FindingStatusOpen,
+			AuditState: "TO_VERIFY",
+		}, findings[0])
+		assert.Empty(t, findings[1].CWE)
+		assert.Nil(t, findings[1].Location)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[1].Status)
+	}
+}
@@ -71,6 +71,9 @@ func CreateCustomReport(data *map[string]interface{}, insecure, neutral []string
 		Overview:   []reporting.OverviewRow{},
 		ReportTime: time.Now(),
 	}
+	if findings, ok := (*data)["Findings"].([]format.Finding); ok {
+		scanReport.Findings = findings
+	}
 
 	for _, issue := range insecure {
 		row := reporting.OverviewRow{}
@@ -2,7 +2,9 @@ package codeql
 
 import (
 	"context"
+	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/google/go-github/v68/github"
 )
@@ -33,6 +35,12 @@ type CodeqlScanAuditInstance struct {
 	token            string
 	trustedCerts     []string
 	alertListoptions github.AlertListOptions
+	suppressions     format.Suppressions
+}
+
+// SetSuppressions sets the suppressions of the repository, open alerts matched by them are counted as audited
+func (codeqlScanAudit *CodeqlScanAuditInstance) SetSuppressions(suppressions format.Suppressions) {
+	codeqlScanAudit.suppressions = suppressions
 }
 
 func (codeqlScanAudit *CodeqlScanAuditInstance) GetVulnerabilities(analyzedRef string) ([]CodeqlFindings, error) {
@@ -53,6 +61,8 @@ func getVulnerabilitiesFromClient(ctx context.Context, codeScanning githubCodeql
 	totalAlerts := 0
 	optionalAudited := 0
 	totalOptionalAlerts := 0
+	suppressed := 0
+	optionalSuppressed := 0
 
 	for page != 0 {
 		alertOptions := github.AlertListOptions{
@@ -91,6 +101,10 @@ func getVulnerabilitiesFromClient(ctx context.Context, codeScanning githubCodeql
 
 				if *alert.State == auditStateOpen {
 					totalAlerts += 1
+					if codeqlScanAudit.isSuppressed(alert) {
+						audited += 1
+						suppressed += 1
+					}
 				}
 			} else {
 				if *alert.State == auditStateDismissed {
@@ -100,6 +114,10 @@ func getVulnerabilitiesFromClient(ctx context.Context, codeScanning githubCodeql
 
 				if *alert.State == auditStateOpen {
 					totalOptionalAlerts += 1
+					if codeqlScanAudit.isSuppressed(alert) {
+						optionalAudited += 1
+						optionalSuppressed += 1
+					}
 				}
 			}
 		}
@@ -109,17 +127,42 @@ func getVulnerabilitiesFromClient(ctx context.Context, codeScanning githubCodeql
 		ClassificationName: AuditAll,
 		Total:              totalAlerts,
 		Audited:            audited,
+		Suppressed:         suppressed,
 	}
 	optionalIssues := CodeqlFindings{
 		ClassificationName: Optional,
 		Total:              totalOptionalAlerts,
 		Audited:            optionalAudited,
+		Suppressed:         optionalSuppressed,
 	}
 	codeqlScanning := []CodeqlFindings{auditAll, optionalIssues}
 
 	return codeqlScanning, nil
 }
 
+func (codeqlScanAudit *CodeqlScanAuditInstance) isSuppressed(alert *github.Alert) bool {
+	return len(codeqlScanAudit.suppressions) > 0 && codeqlScanAudit.suppressions.Match(alertToFinding(alert)) != nil
+}
+
+func alertToFinding(alert *github.Alert) format.Finding {
+	finding := format.Finding{
+		Tool:   codeqlToolName,
+		Rule:   alert.GetRule().GetID(),
+		Status: format.FindingStatusOpen,
+	}
+	for _, tag := range alert.GetRule().Tags {
+		if strings.HasPrefix(tag, "external/cwe/") {
+			finding.CWE = format.NormalizeCWE(tag)
+			break
+		}
+	}
+	location := alert.GetMostRecentInstance().GetLocation()
+	if len(location.GetPath()) > 0 {
+		finding.Location = &format.FindingLocation{File: location.GetPath(), Line: location.GetStartLine()}
+	}
+	return finding
+}
+
 func getApiUrl(serverUrl string) string {
 	if serverUrl == "https://github.com" {
 		return "https://api.github.com"
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/google/go-github/v68/github"
 	"github.com/stretchr/testify/assert"
 )
@@ -59,6 +60,16 @@ func (g *githubCodeqlScanningMock) ListAlertsForRepo(ctx context.Context, owner,
 		}
 	}
 
+	if repo == "testRepo3" {
+		xssRule, sqlRule := "js/xss", "js/sql-injection"
+		legacyFile, appFile := "src/legacy/index.js", "src/app.js"
+		line := 3
+		alerts = append(alerts, &github.Alert{State: &openState, Tool: &github.Tool{Name: &codeqlToolName}, Rule: &github.Rule{ID: &xssRule, Tags: []string{"security", "external/cwe/cwe-079"}}, MostRecentInstance: &github.MostRecentInstance{Location: &github.Location{Path: &legacyFile, StartLine: &line}}})
+		alerts = append(alerts, &github.Alert{State: &openState, Tool: &github.Tool{Name: &codeqlToolName}, Rule: &github.Rule{ID: &xssRule, Tags: []string{"security", "external/cwe/cwe-079"}}, MostRecentInstance: &github.MostRecentInstance{Location: &github.Location{Path: &appFile, StartLine: &line}}})
+		alerts = append(alerts, &github.Alert{State: &openState, Tool: &github.Tool{Name: &codeqlToolName}, Rule: &github.Rule{ID: &sqlRule, Tags: []string{"security"}}, MostRecentInstance: &github.MostRecentInstance{Location: &github.Location{Path: &legacyFile, StartLine: &line}}})
+		response.NextPage = 0
+	}
+
 	return alerts, &response, nil
 }
 
@@ -94,6 +105,17 @@ func TestGetVulnerabilitiesFromClient(t *testing.T) {
 		assert.Equal(t, 80, codeScanning[0].Audited)
 	})
 
+	t.Run("Success with suppressions", func(t *testing.T) {
+		ghCodeqlScanningMock := githubCodeqlScanningMock{}
+		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "", "testRepo3", "", []string{})
+		codeqlScanAuditInstance.SetSuppressions(format.Suppressions{{ID: "CWE-79", Path: "src/legacy/**", Justification: "escaped", Approver: "someone"}})
+		codeScanning, err := getVulnerabilitiesFromClient(ctx, &ghCodeqlScanningMock, "ref", &codeqlScanAuditInstance)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, codeScanning[0].Total)
+		assert.Equal(t, 1, codeScanning[0].Audited)
+		assert.Equal(t, 1, codeScanning[0].Suppressed)
+	})
+
 	t.Run("Error", func(t *testing.T) {
 		ghCodeqlScanningErrorMock := githubCodeqlScanningErrorMock{}
 		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "", "", "", []string{})
@@ -0,0 +1,26 @@
+package codeql
+
+import (
+	"encoding/json"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/pkg/errors"
+)
+
+// ToFindings converts the results of a SARIF report created by CodeQL into scanner independent findings
+func ToFindings(sarif format.SARIF) []format.Finding {
+	findings := format.FindingsFromSARIF(sarif)
+	for i := range findings {
+		findings[i].Tool = "CodeQL"
+	}
+	return findings
+}
+
+// ReadFindings reads the findings of a SARIF report created by CodeQL
+func ReadFindings(sarifReport []byte) ([]format.Finding, error) {
+	var sarif format.SARIF
+	if err := json.Unmarshal(sarifReport, &sarif); err != nil {
+		return nil, errors.Wrap(err, "failed to parse SARIF report")
+	}
+	return ToFindings(sarif), nil
+}
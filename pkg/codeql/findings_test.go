@@ -0,0 +1,48 @@
+package codeql
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codeqlSarif = `{
+	"runs": [{
+		"tool": {"driver": {"name": "CodeQL", "rules": [{
+			"id": "js/xss",
+			"properties": {"tags": ["security", "external/cwe/cwe-079"], "security-severity": "6.1"}
+		}]}},
+		"results": [{
+			"ruleId": "js/xss",
+			"ruleIndex": 0,
+			"level": "error",
+			"message": {"text": "Cross-site scripting vulnerability due to user-provided value."},
+			"locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/app.js"}, "region": {"startLine": 12}}}]
+		}]
+	}]
+}`
+
+func TestReadFindings(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		findings, err := ReadFindings([]byte(codeqlSarif))
+
+		require.NoError(t, err)
+		assert.Equal(t, []format.Finding{{
+			Tool:     "CodeQL",
+			Rule:     "js/xss",
+			CWE:      "79",
+			Location: &format.FindingLocation{File: "src/app.js", Line: 12},
+			Severity: format.SeverityMedium,
+			Status:   format.FindingStatusOpen,
+			Message:  "Cross-site scripting vulnerability due to user-provided value.",
+		}}, findings)
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		_, err := ReadFindings([]byte("{"))
+
+		assert.ErrorContains(t, err, "failed to parse SARIF report")
+	})
+}
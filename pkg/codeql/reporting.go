@@ -1,12 +1,17 @@
 package codeql
 
 import (
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/pkg/errors"
 )
@@ -24,6 +29,7 @@ type CodeqlFindings struct {
 	ClassificationName string `json:"classificationName"`
 	Total              int    `json:"total"`
 	Audited            int    `json:"audited"`
+	Suppressed         int    `json:"suppressed,omitempty"`
 }
 
 func WriteJSONReport(jsonReport CodeqlAudit, modulePath string) ([]piperutils.Path, error) {
@@ -113,3 +119,44 @@ func persistToolRecord(toolRecord *toolrecord.Toolrecord) (string, error) {
 	}
 	return toolRecord.GetFileName(), nil
 }
+
+// CreateCustomReport creates the report of the findings of a scan which is consolidated by step pipelineCreateScanSummary
+func CreateCustomReport(repoInfo *RepoInfo, querySuite string, findings []format.Finding) reporting.ScanReport {
+	open := 0
+	for _, finding := range findings {
+		if finding.Status == format.FindingStatusOpen {
+			open++
+		}
+	}
+	return reporting.ScanReport{
+		ReportTitle: "CodeQL SAST Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Repository", Details: repoInfo.FullUrl},
+			{Description: "Analyzed reference", Details: repoInfo.AnalyzedRef},
+			{Description: "Query suite", Details: querySuite},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Number of findings", Details: fmt.Sprint(len(findings))},
+			{Description: "Number of open findings", Details: fmt.Sprint(open)},
+		},
+		Findings:       findings,
+		SuccessfulScan: open == 0,
+		ReportTime:     time.Now(),
+	}
+}
+
+// WriteCustomReports writes the JSON report which is used by step pipelineCreateScanSummary
+func WriteCustomReports(scanReport reporting.ScanReport, repoInfo *RepoInfo, utils piperutils.FileUtils) error {
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	reportSha := fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join([]string{repoInfo.FullUrl, repoInfo.AnalyzedRef}, ","))))
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("codeqlExecuteScan_sast_%v.json", reportSha)), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return nil
+}
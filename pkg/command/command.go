@@ -3,28 +3,80 @@ package command
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/SAP/jenkins-library/pkg/errorpatterns"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/pkg/errors"
 )
 
+// defaultGracePeriod is the time a terminated process gets for shutting down before it is killed
+const defaultGracePeriod = 10 * time.Second
+
 // Command defines the information required for executing a call to any executable
 type Command struct {
 	ErrorCategoryMapping map[string][]string
 	StepName             string
-	dir                  string
-	stdin                io.Reader
-	stdout               io.Writer
-	stderr               io.Writer
-	env                  []string
-	exitCode             int
+	// Timeout limits the duration of a single execution attempt, there is no limit if it is zero
+	Timeout time.Duration
+	// GracePeriod is the time between SIGTERM and SIGKILL when a timed out or cancelled execution is terminated, defaults to 10s
+	GracePeriod time.Duration
+	// Retry defines which failed executions are repeated
+	Retry    RetryPolicy
+	dir      string
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	env      []string
+	exitCode int
+	usage    Usage
+	tool     string
+}
+
+// RetryPolicy defines when a failed execution is repeated
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of executions including the first one
+	MaxAttempts int
+	// Patterns are matched against the stderr lines of a failed execution, '*' acts as wildcard like in ErrorCategoryMapping
+	Patterns []string
+	// Delay is the time to wait between two attempts
+	Delay time.Duration
+}
+
+var (
+	defaultTimeout time.Duration
+	defaultRetry   RetryPolicy
+)
+
+// SetDefaults defines the timeout and the retry policy of commands which do not define their own, e.g. from the general configuration
+func SetDefaults(timeout time.Duration, retry RetryPolicy) {
+	defaultTimeout = timeout
+	defaultRetry = retry
+}
+
+func (c *Command) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c *Command) retryPolicy() RetryPolicy {
+	if c.Retry.MaxAttempts > 0 {
+		return c.Retry
+	}
+	return defaultRetry
 }
 
 type runner interface {
@@ -52,6 +104,18 @@ type ShellRunner interface {
 	RunShell(shell string, command string) error
 }
 
+// ContextExecRunner mock for intercepting calls to executables which can be cancelled
+type ContextExecRunner interface {
+	ExecRunner
+	RunExecutableWithContext(ctx context.Context, executable string, params ...string) error
+}
+
+// ContextShellRunner mock for intercepting shell calls which can be cancelled
+type ContextShellRunner interface {
+	ShellRunner
+	RunShellWithContext(ctx context.Context, shell string, command string) error
+}
+
 // SetDir sets the working directory for the execution
 func (c *Command) SetDir(dir string) {
 	c.dir = dir
@@ -101,25 +165,41 @@ var ExecCommand = exec.Command
 
 // RunShell runs the specified command on the shell
 func (c *Command) RunShell(shell, script string) error {
+	return c.RunShellWithContext(context.Background(), shell, script)
+}
+
+// RunShellWithContext runs the specified command on the shell and terminates it when the context is done
+func (c *Command) RunShellWithContext(ctx context.Context, shell, script string) error {
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionCommand, Command: shell, Script: script, Dir: c.dir})
+		return nil
+	}
 	c.prepareOut()
+	c.tool = errorpatterns.ToolName(shell)
 
-	cmd := ExecCommand(shell)
+	newCmd := func() *exec.Cmd {
+		cmd := ExecCommand(shell)
 
-	if len(c.dir) > 0 {
-		cmd.Dir = c.dir
-	}
+		if len(c.dir) > 0 {
+			cmd.Dir = c.dir
+		}
 
-	appendEnvironment(cmd, c.env)
+		appendEnvironment(cmd, c.env)
 
-	in := bytes.Buffer{}
-	in.Write([]byte(script))
-	cmd.Stdin = &in
+		in := bytes.Buffer{}
+		in.Write([]byte(script))
+		cmd.Stdin = &in
+		return cmd
+	}
 
 	log.Entry().Infof("running shell script: %v %v", shell, script)
 
-	if err := c.runCmd(cmd); err != nil {
+	span := tracing.StartSpan("shell "+filepath.Base(shell), tracing.SpanKindInternal, tracing.Attr("process.executable.name", filepath.Base(shell)))
+	if err := c.run(ctx, newCmd, false); err != nil {
+		c.endSpan(span, err)
 		return errors.Wrapf(err, "running shell script failed with %v", shell)
 	}
+	c.endSpan(span, nil)
 	return nil
 }
 
@@ -136,26 +216,52 @@ func (c *Command) RunExecutable(executable string, params ...string) error {
 //
 //	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
 func (c *Command) RunExecutableWithAttrs(executable string, sysProcAttr *syscall.SysProcAttr, params ...string) error {
-	c.prepareOut()
+	return c.runExecutable(context.Background(), executable, sysProcAttr, params...)
+}
 
-	cmd := ExecCommand(executable, params...)
-	cmd.SysProcAttr = sysProcAttr
+// RunExecutableWithContext runs the specified executable with parameters and terminates it when the context is done
+// !! While the cmd.Env is applied during command execution, it is NOT involved when the actual executable is resolved.
+//
+//	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
+func (c *Command) RunExecutableWithContext(ctx context.Context, executable string, params ...string) error {
+	return c.runExecutable(ctx, executable, nil, params...)
+}
 
-	if len(c.dir) > 0 {
-		cmd.Dir = c.dir
+func (c *Command) runExecutable(ctx context.Context, executable string, sysProcAttr *syscall.SysProcAttr, params ...string) error {
+	if dryrun.Enabled() {
+		dryrun.RecordCommand(c.dir, executable, params...)
+		return nil
 	}
+	c.prepareOut()
+	c.tool = errorpatterns.ToolName(executable)
 
-	log.Entry().Infof("running command: %v %v", executable, strings.Join(params, (" ")))
+	newCmd := func() *exec.Cmd {
+		cmd := ExecCommand(executable, params...)
+		cmd.SysProcAttr = sysProcAttr
 
-	appendEnvironment(cmd, c.env)
+		if len(c.dir) > 0 {
+			cmd.Dir = c.dir
+		}
 
-	if c.stdin != nil {
-		cmd.Stdin = c.stdin
+		appendEnvironment(cmd, c.env)
+
+		if c.stdin != nil {
+			cmd.Stdin = c.stdin
+		}
+		return cmd
 	}
 
-	if err := c.runCmd(cmd); err != nil {
+	log.Entry().Infof("running command: %v %v", executable, strings.Join(params, (" ")))
+
+	span := tracing.StartSpan("exec "+filepath.Base(executable), tracing.SpanKindInternal,
+		tracing.Attr("process.executable.name", filepath.Base(executable)),
+		tracing.Attr("process.command_args", maskedArgs(executable, params)),
+	)
+	if err := c.run(ctx, newCmd, c.stdin != nil); err != nil {
+		c.endSpan(span, err)
 		return errors.Wrapf(err, "running command '%v' failed", executable)
 	}
+	c.endSpan(span, nil)
 	return nil
 }
 
@@ -164,7 +270,12 @@ func (c *Command) RunExecutableWithAttrs(executable string, sysProcAttr *syscall
 //
 //	Thus the executable needs to be on the PATH of the current process and it is not sufficient to alter the PATH on cmd.Env.
 func (c *Command) RunExecutableInBackground(executable string, params ...string) (Execution, error) {
+	if dryrun.Enabled() {
+		dryrun.RecordCommand(c.dir, executable, params...)
+		return &dryRunExecution{}, nil
+	}
 	c.prepareOut()
+	c.tool = errorpatterns.ToolName(executable)
 
 	cmd := ExecCommand(executable, params...)
 
@@ -188,11 +299,34 @@ func (c *Command) RunExecutableInBackground(executable string, params ...string)
 	return execution, nil
 }
 
+func (c *Command) endSpan(span *tracing.Span, err error) {
+	span.SetAttributes(
+		tracing.Attr("process.exit.code", c.exitCode),
+		tracing.Attr("process.cpu.time", c.usage.CPUTime.Seconds()),
+		tracing.Attr("process.max_rss", c.usage.MaxRSS),
+		tracing.Attr("piper.command.attempts", c.usage.Executions),
+	)
+	span.End(err)
+}
+
+func maskedArgs(executable string, params []string) []string {
+	args := []string{log.MaskSecrets(executable)}
+	for _, param := range params {
+		args = append(args, log.MaskSecrets(param))
+	}
+	return args
+}
+
 // GetExitCode allows to retrieve the exit code of a command execution
 func (c *Command) GetExitCode() int {
 	return c.exitCode
 }
 
+// GetUsage returns the resources consumed by the last command execution including its retries
+func (c *Command) GetUsage() Usage {
+	return c.usage
+}
+
 func appendEnvironment(cmd *exec.Cmd, env []string) {
 	if len(env) > 0 {
 
@@ -236,7 +370,7 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 	srcOut := stdout
 	srcErr := stderr
 
-	if c.ErrorCategoryMapping != nil {
+	if c.ErrorCategoryMapping != nil || errorpatterns.HasPatterns(c.tool) {
 		prOut, pwOut := io.Pipe()
 		trOut := io.TeeReader(stdout, pwOut)
 		srcOut = prOut
@@ -260,6 +394,20 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		}()
 	}
 
+	if len(c.retryPolicy().Patterns) > 0 {
+		prRetry, pwRetry := io.Pipe()
+		trRetry := io.TeeReader(srcErr, pwRetry)
+		srcErr = prRetry
+
+		execution.wg.Add(1)
+
+		go func() {
+			defer execution.wg.Done()
+			defer pwRetry.Close()
+			execution.retryable = c.scanRetryPatterns(trRetry)
+		}()
+	}
+
 	go func() {
 		if c.StepName != "" {
 			var buf bytes.Buffer
@@ -301,6 +449,33 @@ func (c *Command) scanLog(in io.Reader) {
 	}
 }
 
+// scanRetryPatterns reports whether any line matches one of the retry patterns, it consumes the complete input
+func (c *Command) scanRetryPatterns(in io.Reader) bool {
+	matched := false
+	scanner := bufio.NewScanner(in)
+	scanner.Split(scanShortLines)
+	for scanner.Scan() {
+		if !matched {
+			matched = matchAnyPattern(scanner.Text(), c.retryPolicy().Patterns)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Entry().WithError(err).Info("failed to scan log for retry patterns")
+	}
+	// keep the output flowing in case scanning stopped early
+	_, _ = io.Copy(io.Discard, in)
+	return matched
+}
+
+func matchAnyPattern(text string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) > 0 && matchPattern(text, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 func scanShortLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	lenData := len(data)
 	if atEOF && lenData == 0 {
@@ -327,6 +502,8 @@ func scanShortLines(data []byte, atEOF bool) (advance int, token []byte, err err
 }
 
 func (c *Command) parseConsoleErrors(logLine string) {
+	// step specific patterns take precedence over the category of the error pattern catalogue
+	errorpatterns.Apply(c.tool, logLine)
 	for category, categoryErrors := range c.ErrorCategoryMapping {
 		for _, errorPart := range categoryErrors {
 			if matchPattern(logLine, errorPart) {
@@ -350,16 +527,89 @@ func matchPattern(text, pattern string) bool {
 	return true
 }
 
-func (c *Command) runCmd(cmd *exec.Cmd) error {
+// run executes the commands created by newCmd until one succeeds or the retry policy does not allow another attempt
+func (c *Command) run(ctx context.Context, newCmd func() *exec.Cmd, hasStdin bool) error {
+	c.usage = Usage{}
+	defer func() { addTotalUsage(c.usage) }()
+
+	// a hint matched in the output of the command only applies in case the command finally fails
+	hint := log.GetErrorHint()
+	retry := c.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		retryable, err := c.runCmd(ctx, newCmd())
+		if err == nil {
+			log.SetErrorHint(hint)
+			return nil
+		}
+		if !retryable || attempt >= retry.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if hasStdin && !c.rewindStdin() {
+			log.Entry().Warn("not retrying the command since its stdin cannot be replayed")
+			return err
+		}
+		log.Entry().WithError(err).Infof("command failed with a retryable error in attempt %v of %v, retrying in %v", attempt, retry.MaxAttempts, retry.Delay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retry.Delay):
+		}
+		c.usage.Retries++
+	}
+}
+
+func (c *Command) rewindStdin() bool {
+	seeker, ok := c.stdin.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err == nil
+}
+
+// runCmd executes the command once and reports whether a failure matched the retry patterns
+func (c *Command) runCmd(ctx context.Context, cmd *exec.Cmd) (bool, error) {
+	if timeout := c.timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if ctx.Done() != nil {
+		setProcessGroup(cmd)
+	}
+
+	start := time.Now()
 	execution, err := c.startCmd(cmd)
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	exited := make(chan struct{})
+	watched := make(chan struct{})
+	terminated := false
+	go func() {
+		defer close(watched)
+		select {
+		case <-ctx.Done():
+			log.Entry().Warnf("terminating command: %v", ctx.Err())
+			terminated = true
+			terminate(cmd.Process, c.gracePeriod(), exited)
+		case <-exited:
+		}
+	}()
+
 	err = execution.Wait()
+	close(exited)
+	<-watched
+
+	usage := usageOf(cmd.ProcessState, time.Since(start))
+	if terminated && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		usage.Timeouts = 1
+	}
+	c.usage.add(usage)
 
 	if execution.errCopyStdout != nil || execution.errCopyStderr != nil {
-		return fmt.Errorf("failed to capture stdout/stderr: '%v'/'%v'", execution.errCopyStdout, execution.errCopyStderr)
+		return false, fmt.Errorf("failed to capture stdout/stderr: '%v'/'%v'", execution.errCopyStdout, execution.errCopyStderr)
 	}
 
 	if err != nil {
@@ -371,10 +621,20 @@ func (c *Command) runCmd(cmd *exec.Cmd) error {
 				c.exitCode = status.ExitStatus()
 			}
 		}
-		return errors.Wrap(err, "cmd.Run() failed")
+		if terminated {
+			return false, errors.Wrapf(ctx.Err(), "cmd.Run() failed: process terminated (%v)", err)
+		}
+		return execution.retryable, errors.Wrap(err, "cmd.Run() failed")
 	}
 	c.exitCode = 0
-	return nil
+	return false, nil
+}
+
+func (c *Command) gracePeriod() time.Duration {
+	if c.GracePeriod > 0 {
+		return c.GracePeriod
+	}
+	return defaultGracePeriod
 }
 
 func (c *Command) prepareOut() {
@@ -5,14 +5,24 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/SAP/jenkins-library/pkg/errorpatterns"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -99,6 +109,29 @@ func TestExecutableRun(t *testing.T) {
 	})
 }
 
+func TestDryRun(t *testing.T) {
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+	ExecCommand = func(string, ...string) *exec.Cmd {
+		t.Fatal("no command must be executed in dry run mode")
+		return nil
+	}
+	defer func() { ExecCommand = exec.Command }()
+
+	ex := Command{dir: "app"}
+	assert.NoError(t, ex.RunExecutable("cf", "push", "myApp"))
+	assert.NoError(t, ex.RunShell("/bin/bash", "echo hello"))
+	execution, err := ex.RunExecutableInBackground("sleep", "10")
+	assert.NoError(t, err)
+	assert.NoError(t, execution.Wait())
+
+	assert.Equal(t, []dryrun.Action{
+		{Type: dryrun.ActionCommand, Command: "cf push myApp", Dir: "app"},
+		{Type: dryrun.ActionCommand, Command: "/bin/bash", Script: "echo hello", Dir: "app"},
+		{Type: dryrun.ActionCommand, Command: "sleep 10", Dir: "app"},
+	}, dryrun.GetPlan().Actions)
+}
+
 func TestEnvironmentVariables(t *testing.T) {
 
 	ExecCommand = helperCommand
@@ -187,6 +220,84 @@ func TestParseConsoleErrors(t *testing.T) {
 	log.SetErrorCategory(log.ErrorUndefined)
 }
 
+func TestParseConsoleErrorsWithCatalogue(t *testing.T) {
+	errorpatterns.Reset()
+	defer errorpatterns.Reset()
+	defer log.SetErrorHint(nil)
+	assert.NoError(t, errorpatterns.LoadDefault())
+
+	t.Run("catalogue pattern", func(t *testing.T) {
+		log.SetErrorCategory(log.ErrorUndefined)
+		cmd := Command{tool: "npm"}
+		cmd.parseConsoleErrors("npm ERR! code E401")
+		assert.Equal(t, log.ErrorConfiguration, log.GetErrorCategory())
+		if assert.NotNil(t, log.GetErrorHint()) {
+			assert.Equal(t, "npm-unauthorized", log.GetErrorHint().PatternID)
+		}
+	})
+
+	t.Run("step specific category takes precedence", func(t *testing.T) {
+		log.SetErrorCategory(log.ErrorUndefined)
+		cmd := Command{tool: "npm", ErrorCategoryMapping: map[string][]string{"service": {"code E401"}}}
+		cmd.parseConsoleErrors("npm ERR! code E401")
+		assert.Equal(t, log.ErrorService, log.GetErrorCategory())
+	})
+	log.SetErrorCategory(log.ErrorUndefined)
+}
+
+func TestErrorHintOfSuccessfulExecution(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	errorpatterns.Reset()
+	defer errorpatterns.Reset()
+	defer log.SetErrorHint(nil)
+	defer log.SetErrorCategory(log.ErrorUndefined)
+	catalogue, err := errorpatterns.Parse([]byte(`apiVersion: v1
+version: 1.0.0
+tools:
+  flaky:
+    - id: flaky-connection-reset
+      pattern: ECONNRESET
+      category: infrastructure
+      hint: check the connection to the registry
+`))
+	assert.NoError(t, err)
+	errorpatterns.Register(catalogue)
+
+	run := func(t *testing.T, maxAttempts int) error {
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), Retry: RetryPolicy{MaxAttempts: maxAttempts, Patterns: []string{"ECONNRESET"}}}
+		ex.SetEnv([]string{"COUNTER_FILE=" + filepath.Join(t.TempDir(), "counter")})
+		return ex.RunExecutable("flaky", "2")
+	}
+
+	t.Run("hint of a failed attempt is discarded after a successful retry", func(t *testing.T) {
+		log.SetErrorHint(nil)
+		assert.NoError(t, run(t, 2))
+		assert.Nil(t, log.GetErrorHint())
+
+		hook := log.FatalHook{Path: t.TempDir()}
+		assert.NoError(t, hook.Fire(&logrus.Entry{Message: "unrelated error"}))
+		fileContent, err := os.ReadFile(filepath.Join(hook.Path, "errorDetails.json"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(fileContent), `"hint"`)
+	})
+
+	t.Run("hint of a failed execution is kept", func(t *testing.T) {
+		log.SetErrorHint(nil)
+		assert.Error(t, run(t, 1))
+		if assert.NotNil(t, log.GetErrorHint()) {
+			assert.Equal(t, "flaky-connection-reset", log.GetErrorHint().PatternID)
+		}
+	})
+
+	t.Run("hint of an earlier execution is kept", func(t *testing.T) {
+		earlier := &log.ErrorHint{PatternID: "earlier"}
+		log.SetErrorHint(earlier)
+		assert.NoError(t, run(t, 2))
+		assert.Same(t, earlier, log.GetErrorHint())
+	})
+}
+
 func TestMatchPattern(t *testing.T) {
 	tt := []struct {
 		text     string
@@ -235,6 +346,141 @@ func TestCmdPipes(t *testing.T) {
 	})
 }
 
+func TestRetry(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+
+	run := func(t *testing.T, retry RetryPolicy) (Command, error) {
+		counterFile := filepath.Join(t.TempDir(), "counter")
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), Retry: retry}
+		ex.SetEnv([]string{"COUNTER_FILE=" + counterFile})
+		err := ex.RunExecutable("flaky", "3")
+		return ex, err
+	}
+
+	t.Run("retry on matching stderr", func(t *testing.T) {
+		ex, err := run(t, RetryPolicy{MaxAttempts: 3, Patterns: []string{"ECONNRESET*registry"}, Delay: time.Millisecond})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, ex.GetExitCode())
+		assert.Equal(t, 3, ex.GetUsage().Executions)
+		assert.Equal(t, 2, ex.GetUsage().Retries)
+	})
+
+	t.Run("max attempts reached", func(t *testing.T) {
+		ex, err := run(t, RetryPolicy{MaxAttempts: 2, Patterns: []string{"ECONNRESET"}})
+		assert.EqualError(t, err, "running command 'flaky' failed: cmd.Run() failed: exit status 1")
+		assert.Equal(t, 1, ex.GetExitCode())
+		assert.Equal(t, 2, ex.GetUsage().Executions)
+	})
+
+	t.Run("no retry on other errors", func(t *testing.T) {
+		ex, err := run(t, RetryPolicy{MaxAttempts: 3, Patterns: []string{"ETIMEDOUT"}})
+		assert.Error(t, err)
+		assert.Equal(t, 1, ex.GetUsage().Executions)
+		assert.Equal(t, 0, ex.GetUsage().Retries)
+	})
+
+	t.Run("no retry with stdin which cannot be replayed", func(t *testing.T) {
+		counterFile := filepath.Join(t.TempDir(), "counter")
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), Retry: RetryPolicy{MaxAttempts: 3, Patterns: []string{"ECONNRESET"}}}
+		ex.SetEnv([]string{"COUNTER_FILE=" + counterFile})
+		ex.Stdin(io.MultiReader(strings.NewReader("input")))
+		assert.Error(t, ex.RunExecutable("flaky", "3"))
+		assert.Equal(t, 1, ex.GetUsage().Executions)
+	})
+
+	t.Run("default retry policy", func(t *testing.T) {
+		SetDefaults(0, RetryPolicy{MaxAttempts: 3, Patterns: []string{"ECONNRESET"}, Delay: time.Millisecond})
+		defer SetDefaults(0, RetryPolicy{})
+		ex, err := run(t, RetryPolicy{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, ex.GetUsage().Retries)
+
+		ex, err = run(t, RetryPolicy{MaxAttempts: 1})
+		assert.Error(t, err, "the retry policy of the command takes precedence")
+		assert.Equal(t, 0, ex.GetUsage().Retries)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+
+	t.Run("terminated after timeout", func(t *testing.T) {
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), Timeout: 200 * time.Millisecond}
+		start := time.Now()
+		err := ex.RunExecutable("sleep", "30")
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+		assert.Contains(t, err.Error(), "process terminated")
+		assert.NotEqual(t, 0, ex.GetExitCode())
+		assert.Equal(t, 1, ex.GetUsage().Timeouts)
+	})
+
+	t.Run("killed after grace period", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer), Timeout: 500 * time.Millisecond, GracePeriod: 200 * time.Millisecond}
+		start := time.Now()
+		err := ex.RunShell("ignoreterm", "")
+		assert.Less(t, time.Since(start), 10*time.Second)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+		assert.Contains(t, stdout.String(), "ignoring SIGTERM")
+	})
+
+	t.Run("not retried after timeout", func(t *testing.T) {
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer), Timeout: 200 * time.Millisecond, Retry: RetryPolicy{MaxAttempts: 3, Patterns: []string{"sleeping"}}}
+		assert.Error(t, ex.RunExecutable("sleep", "30"))
+		assert.Equal(t, 1, ex.GetUsage().Executions)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(200*time.Millisecond, cancel)
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+		err := ex.RunExecutableWithContext(ctx, "sleep", "30")
+		assert.True(t, errors.Is(err, context.Canceled), "unexpected error: %v", err)
+		assert.Equal(t, 0, ex.GetUsage().Timeouts)
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		SetDefaults(200*time.Millisecond, RetryPolicy{})
+		defer SetDefaults(0, RetryPolicy{})
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+		err := ex.RunExecutable("sleep", "30")
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+		assert.Equal(t, 1, ex.GetUsage().Timeouts)
+	})
+
+	t.Run("finished before timeout", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer), Timeout: time.Minute}
+		assert.NoError(t, ex.RunShellWithContext(context.Background(), "/bin/bash", "myScript"))
+		assert.Equal(t, "Stdout: command /bin/bash - Stdin: myScript\n", stdout.String())
+	})
+}
+
+func TestUsage(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	ResetTotalUsage()
+	defer ResetTotalUsage()
+
+	ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	assert.NoError(t, ex.RunExecutable("echo", "foo"))
+	assert.NoError(t, ex.RunExecutable("echo", "bar"))
+
+	usage := ex.GetUsage()
+	assert.Equal(t, 1, usage.Executions)
+	assert.Greater(t, usage.WallTime, time.Duration(0))
+	assert.Greater(t, usage.CPUTime, time.Duration(0))
+	assert.Greater(t, usage.MaxRSS, int64(0))
+
+	total := TotalUsage()
+	assert.Equal(t, 2, total.Executions)
+	assert.GreaterOrEqual(t, total.WallTime, usage.WallTime)
+	assert.GreaterOrEqual(t, total.MaxRSS, usage.MaxRSS)
+}
+
 // based on https://golang.org/src/os/exec/exec_test.go
 // this is not directly executed
 func TestHelperProcess(*testing.T) {
@@ -274,6 +520,26 @@ func TestHelperProcess(*testing.T) {
 		for _, e := range os.Environ() {
 			fmt.Println(e)
 		}
+	case "flaky":
+		// fails with a retryable error until it was called the given number of times
+		attempts, _ := strconv.Atoi(args[0])
+		counter, _ := os.ReadFile(os.Getenv("COUNTER_FILE"))
+		count, _ := strconv.Atoi(string(counter))
+		count++
+		os.WriteFile(os.Getenv("COUNTER_FILE"), []byte(strconv.Itoa(count)), 0644)
+		if count < attempts {
+			fmt.Fprintf(os.Stderr, "npm ERR! network read ECONNRESET from registry\n")
+			os.Exit(1)
+		}
+		fmt.Println("success")
+	case "sleep":
+		seconds, _ := strconv.Atoi(args[0])
+		fmt.Fprintln(os.Stderr, "sleeping")
+		time.Sleep(time.Duration(seconds) * time.Second)
+	case "ignoreterm":
+		signal.Ignore(syscall.SIGTERM)
+		fmt.Println("ignoring SIGTERM")
+		time.Sleep(30 * time.Second)
 	case "long":
 		b := []byte("a")
 		size := 64000
@@ -6,13 +6,14 @@ import (
 	"sync"
 )
 
-// errCopyStdout and errCopyStderr are filled after the command execution after Wait() terminates
+// errCopyStdout, errCopyStderr and retryable are filled after the command execution after Wait() terminates
 type execution struct {
 	cmd           *exec.Cmd
 	wg            sync.WaitGroup
 	errCopyStdout error
 	errCopyStderr error
 	ul            *log.URLLogger
+	retryable     bool
 }
 
 func (execution *execution) Kill() error {
@@ -30,3 +31,14 @@ type Execution interface {
 	Kill() error
 	Wait() error
 }
+
+// dryRunExecution is returned by RunExecutableInBackground in dry run mode where no process is started
+type dryRunExecution struct{}
+
+func (execution *dryRunExecution) Kill() error {
+	return nil
+}
+
+func (execution *dryRunExecution) Wait() error {
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build !windows
+// +build !windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts the command in its own process group so that it can be terminated including its children
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	} else {
+		// do not modify the attributes provided by the caller
+		attrs := *cmd.SysProcAttr
+		cmd.SysProcAttr = &attrs
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminate sends SIGTERM to the process group and SIGKILL after the grace period unless done is closed before
+func terminate(process *os.Process, gracePeriod time.Duration, done <-chan struct{}) {
+	if process == nil {
+		return
+	}
+	_ = syscall.Kill(-process.Pid, syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(gracePeriod):
+		_ = syscall.Kill(-process.Pid, syscall.SIGKILL)
+	}
+}
+
+// maxRSS returns the maximum resident set size of the process in bytes
+func maxRSS(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return 0
+	}
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss)
+	}
+	// Linux reports kilobytes
+	return int64(rusage.Maxrss) * 1024
+}
@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// setProcessGroup is not supported on Windows, only the process itself is terminated
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminate kills the process immediately since Windows does not support SIGTERM
+func terminate(process *os.Process, gracePeriod time.Duration, done <-chan struct{}) {
+	if process == nil {
+		return
+	}
+	_ = process.Kill()
+}
+
+// maxRSS is not available on Windows
+func maxRSS(state *os.ProcessState) int64 {
+	return 0
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Usage contains the resources consumed by command executions
+type Usage struct {
+	// Executions is the number of started processes including retries
+	Executions int
+	// Retries is the number of executions which repeated a failed attempt
+	Retries int
+	// Timeouts is the number of executions which were terminated since they exceeded their timeout
+	Timeouts int
+	// WallTime is the elapsed real time of the executions
+	WallTime time.Duration
+	// CPUTime is the user and system CPU time of the executions
+	CPUTime time.Duration
+	// MaxRSS is the maximum resident set size in bytes of any of the executions
+	MaxRSS int64
+}
+
+func (u *Usage) add(other Usage) {
+	u.Executions += other.Executions
+	u.Retries += other.Retries
+	u.Timeouts += other.Timeouts
+	u.WallTime += other.WallTime
+	u.CPUTime += other.CPUTime
+	if other.MaxRSS > u.MaxRSS {
+		u.MaxRSS = other.MaxRSS
+	}
+}
+
+// usageOf collects the resource usage of a finished process
+func usageOf(state *os.ProcessState, wallTime time.Duration) Usage {
+	usage := Usage{Executions: 1, WallTime: wallTime}
+	if state != nil {
+		usage.CPUTime = state.UserTime() + state.SystemTime()
+		usage.MaxRSS = maxRSS(state)
+	}
+	return usage
+}
+
+var (
+	totalUsage      Usage
+	totalUsageMutex sync.Mutex
+)
+
+func addTotalUsage(usage Usage) {
+	totalUsageMutex.Lock()
+	defer totalUsageMutex.Unlock()
+	totalUsage.add(usage)
+}
+
+// TotalUsage returns the resources consumed by all command executions of the current process, e.g. for step telemetry
+func TotalUsage() Usage {
+	totalUsageMutex.Lock()
+	defer totalUsageMutex.Unlock()
+	return totalUsage
+}
+
+// ResetTotalUsage discards the collected resource usage
+func ResetTotalUsage() {
+	totalUsageMutex.Lock()
+	defer totalUsageMutex.Unlock()
+	totalUsage = Usage{}
+}
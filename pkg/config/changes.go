@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+)
+
+// ChangedFilesResolver provides the list of files (relative to the repository root) which have been changed in the current pipeline run.
+type ChangedFilesResolver func() ([]string, error)
+
+const (
+	// changedFilesBaseEnv allows to explicitly define the git revision the changes are compared against.
+	changedFilesBaseEnv = "PIPER_changedFilesBase"
+	// previousSuccessfulCommitEnv is provided by the Jenkins git plugin.
+	previousSuccessfulCommitEnv = "GIT_PREVIOUS_SUCCESSFUL_COMMIT"
+	// commitBeforeShaEnv is provided by GitLab CI for push pipelines.
+	commitBeforeShaEnv = "CI_COMMIT_BEFORE_SHA"
+)
+
+// memoizeChangedFiles makes sure the changed files are determined only once per evaluation.
+func memoizeChangedFiles(resolve ChangedFilesResolver) ChangedFilesResolver {
+	var files []string
+	var err error
+	resolved := false
+	return func() ([]string, error) {
+		if !resolved {
+			files, err = resolve()
+			resolved = true
+		}
+		return files, err
+	}
+}
+
+// gitChangedFiles determines the changed files based on the git repository in the current working directory.
+// The changes are calculated between HEAD and
+// - the revision defined via environment variable PIPER_changedFilesBase (if set),
+// - the merge base with the target branch in case of a pull request,
+// - the previous successful commit (Jenkins) or the commit before the push (GitLab),
+// - the parent of the oldest commit of the change sets provided by the orchestrator.
+func gitChangedFiles() ([]string, error) {
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open git repository")
+	}
+	head, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := changesBaseCommit(repo, head)
+	if err != nil {
+		return nil, err
+	}
+	log.Entry().Debugf("Determining changed files between %v and %v", base.Hash, head.Hash)
+	return diffCommits(base, head)
+}
+
+func changesBaseCommit(repo *git.Repository, head *object.Commit) (*object.Commit, error) {
+	if base := os.Getenv(changedFilesBaseEnv); len(base) > 0 {
+		return resolveCommit(repo, base)
+	}
+
+	provider, err := orchestrator.GetOrchestratorConfigProvider(nil)
+	if err != nil {
+		log.Entry().WithError(err).Debug("Orchestrator not detected, changes are determined based on git only")
+	}
+
+	if provider != nil && provider.IsPullRequest() {
+		targetBranch := provider.PullRequestConfig().Base
+		for _, ref := range []string{"refs/remotes/origin/" + targetBranch, targetBranch} {
+			target, err := resolveCommit(repo, ref)
+			if err != nil {
+				continue
+			}
+			mergeBase, err := head.MergeBase(target)
+			if err != nil || len(mergeBase) == 0 {
+				return nil, errors.Errorf("failed to determine merge base with target branch '%v'", targetBranch)
+			}
+			return mergeBase[0], nil
+		}
+		return nil, errors.Errorf("target branch '%v' of the pull request is not available", targetBranch)
+	}
+
+	for _, env := range []string{previousSuccessfulCommitEnv, commitBeforeShaEnv} {
+		// GitLab provides an all-zero sha for the first push of a branch
+		if sha := os.Getenv(env); len(strings.Trim(sha, "0")) > 0 {
+			return resolveCommit(repo, sha)
+		}
+	}
+
+	if provider != nil {
+		if base := changeSetsBaseCommit(repo, provider.ChangeSets()); base != nil {
+			return base, nil
+		}
+	}
+
+	return nil, errors.New("no base revision available to compare the changes against")
+}
+
+// changeSetsBaseCommit returns the merge base of the parents of the commits which start the change sets, i.e. of the
+// commits whose parent is not part of the change sets. Change sets containing merged branches start with several commits.
+func changeSetsBaseCommit(repo *git.Repository, changeSets []orchestrator.ChangeSet) *object.Commit {
+	commits := map[plumbing.Hash]bool{}
+	for _, changeSet := range changeSets {
+		commits[plumbing.NewHash(changeSet.CommitId)] = true
+	}
+	var base *object.Commit
+	for _, changeSet := range changeSets {
+		commit, err := repo.CommitObject(plumbing.NewHash(changeSet.CommitId))
+		if err != nil || commit.NumParents() == 0 {
+			continue
+		}
+		parent, err := commit.Parent(0)
+		if err != nil || commits[parent.Hash] {
+			continue
+		}
+		if base == nil {
+			base = parent
+			continue
+		}
+		mergeBase, err := base.MergeBase(parent)
+		if err != nil || len(mergeBase) == 0 {
+			log.Entry().Debugf("No common ancestor of %v and %v, changes are determined since %v", base.Hash, parent.Hash, base.Hash)
+			continue
+		}
+		base = mergeBase[0]
+	}
+	return base
+}
+
+func resolveCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve revision '%v'", revision)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read commit '%v'", revision)
+	}
+	return commit, nil
+}
+
+func diffCommits(from, to *object.Commit) ([]string, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read tree of commit %v", from.Hash)
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read tree of commit %v", to.Hash)
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate changes")
+	}
+
+	files := []string{}
+	for _, change := range changes {
+		// consider both names in order to cover renamed, added and deleted files
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if len(name) > 0 && (len(files) == 0 || files[len(files)-1] != name) {
+				files = append(files, name)
+			}
+		}
+	}
+	return files, nil
+}
+
+// errChangedFilesUnknown indicates that the changed files cannot be determined, e.g. in case of a shallow clone
+var errChangedFilesUnknown = errors.New("changed files cannot be determined")
+
+// anyFileChanged checks whether one of the changed files matches one of the patterns.
+// In case the changed files cannot be determined, the fallback is returned. It needs to be chosen by the caller
+// such that the step stays active, i.e. true for conditions and false for notActiveConditions.
+func anyFileChanged(patterns []string, changedFiles ChangedFilesResolver, fallback bool) (bool, error) {
+	changed, err := matchChangedFiles(patterns, changedFiles)
+	if errors.Is(err, errChangedFilesUnknown) {
+		log.Entry().WithError(err).Warnf("Could not determine changed files, considering the change of files matching %v as %v in order to keep the step active", patterns, fallback)
+		return fallback, nil
+	}
+	return changed, err
+}
+
+// matchChangedFiles checks whether one of the changed files matches one of the patterns.
+// In case the changed files cannot be determined, an error wrapping errChangedFilesUnknown is returned.
+func matchChangedFiles(patterns []string, changedFiles ChangedFilesResolver) (bool, error) {
+	if changedFiles == nil {
+		changedFiles = gitChangedFiles
+	}
+	files, err := changedFiles()
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", errChangedFilesUnknown, err)
+	}
+	for _, pattern := range patterns {
+		for _, file := range files {
+			matched, err := doublestar.Match(pattern, filepath.ToSlash(file))
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid file pattern '%v'", pattern)
+			}
+			if matched {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
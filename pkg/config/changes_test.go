@@ -0,0 +1,173 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+)
+
+func commitFiles(t *testing.T, worktree *git.Worktree, dir string, files ...string) string {
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(time.Now().String()), 0600))
+		_, err := worktree.Add(file)
+		require.NoError(t, err)
+	}
+	hash, err := worktree.Commit("change", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	return hash.String()
+}
+
+func TestGitChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	base := commitFiles(t, worktree, dir, "backend/main.go", "frontend/app.js")
+	commitFiles(t, worktree, dir, "frontend/app.js")
+	commitFiles(t, worktree, dir, "frontend/package.json")
+
+	oldWd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(oldWd)
+
+	t.Run("changes since base revision", func(t *testing.T) {
+		t.Setenv(changedFilesBaseEnv, base)
+		files, err := gitChangedFiles()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"frontend/app.js", "frontend/package.json"}, files)
+	})
+
+	t.Run("changes since previous successful commit", func(t *testing.T) {
+		t.Setenv(changedFilesBaseEnv, "")
+		t.Setenv(previousSuccessfulCommitEnv, "HEAD~1")
+		files, err := gitChangedFiles()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"frontend/package.json"}, files)
+	})
+
+	t.Run("unknown base revision", func(t *testing.T) {
+		t.Setenv(changedFilesBaseEnv, "doesNotExist")
+		_, err := gitChangedFiles()
+		assert.ErrorContains(t, err, "failed to resolve revision 'doesNotExist'")
+	})
+}
+
+func storeCommit(t *testing.T, repo *git.Repository, message string, parents ...plumbing.Hash) plumbing.Hash {
+	tree := repo.Storer.NewEncodedObject()
+	require.NoError(t, (&object.Tree{}).Encode(tree))
+	treeHash, err := repo.Storer.SetEncodedObject(tree)
+	require.NoError(t, err)
+	signature := object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	commit := repo.Storer.NewEncodedObject()
+	require.NoError(t, (&object.Commit{Author: signature, Committer: signature, Message: message, TreeHash: treeHash, ParentHashes: parents}).Encode(commit))
+	hash, err := repo.Storer.SetEncodedObject(commit)
+	require.NoError(t, err)
+	return hash
+}
+
+func TestChangeSetsBaseCommit(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), nil)
+	require.NoError(t, err)
+
+	t.Run("merge base of the commits starting the change sets", func(t *testing.T) {
+		root := storeCommit(t, repo, "root")
+		main := storeCommit(t, repo, "main", root)
+		feature := storeCommit(t, repo, "feature", root)
+		onMain := storeCommit(t, repo, "on main", main)
+		merge := storeCommit(t, repo, "merge", onMain, feature)
+
+		for i := 0; i < 10; i++ {
+			for _, changeSets := range [][]orchestrator.ChangeSet{
+				{{CommitId: onMain.String()}, {CommitId: feature.String()}, {CommitId: merge.String()}},
+				{{CommitId: merge.String()}, {CommitId: feature.String()}, {CommitId: onMain.String()}},
+			} {
+				base := changeSetsBaseCommit(repo, changeSets)
+				require.NotNil(t, base)
+				assert.Equal(t, root, base.Hash)
+			}
+		}
+	})
+
+	t.Run("independent root commits", func(t *testing.T) {
+		first := storeCommit(t, repo, "first root")
+		second := storeCommit(t, repo, "second root")
+		onFirst := storeCommit(t, repo, "on first", first)
+		onSecond := storeCommit(t, repo, "on second", second)
+
+		for i := 0; i < 10; i++ {
+			base := changeSetsBaseCommit(repo, []orchestrator.ChangeSet{{CommitId: onFirst.String()}, {CommitId: onSecond.String()}})
+			require.NotNil(t, base)
+			assert.Equal(t, first, base.Hash)
+		}
+	})
+
+	t.Run("no commit with a parent outside of the change sets", func(t *testing.T) {
+		root := storeCommit(t, repo, "only root")
+		assert.Nil(t, changeSetsBaseCommit(repo, []orchestrator.ChangeSet{{CommitId: root.String()}}))
+	})
+}
+
+func TestAnyFileChanged(t *testing.T) {
+	changedFiles := func() ([]string, error) {
+		return []string{"frontend/app.js", "README.md"}, nil
+	}
+
+	t.Run("matching pattern", func(t *testing.T) {
+		changed, err := anyFileChanged([]string{"backend/**", "frontend/**"}, changedFiles, true)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	})
+
+	t.Run("no matching pattern", func(t *testing.T) {
+		changed, err := anyFileChanged([]string{"backend/**", "*.go"}, changedFiles, true)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := anyFileChanged([]string{"[frontend"}, changedFiles, true)
+		assert.EqualError(t, err, "invalid file pattern '[frontend': syntax error in pattern")
+	})
+
+	t.Run("changes not available", func(t *testing.T) {
+		notAvailable := func() ([]string, error) {
+			return nil, errors.New("shallow clone")
+		}
+		changed, err := anyFileChanged([]string{"backend/**"}, notAvailable, true)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		changed, err = anyFileChanged([]string{"backend/**"}, notAvailable, false)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+}
+
+func TestMemoizeChangedFiles(t *testing.T) {
+	calls := 0
+	changedFiles := memoizeChangedFiles(func() ([]string, error) {
+		calls++
+		return []string{"file"}, nil
+	})
+	changedFiles()
+	files, _ := changedFiles()
+	assert.Equal(t, []string{"file"}, files)
+	assert.Equal(t, 1, calls)
+}
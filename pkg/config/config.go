@@ -28,18 +28,26 @@ type Config struct {
 	Stages                   map[string]map[string]interface{} `json:"stages"`
 	Steps                    map[string]map[string]interface{} `json:"steps"`
 	Hooks                    map[string]interface{}            `json:"hooks,omitempty"`
+	TLS                      []piperhttp.HostTLSConfig         `json:"tls,omitempty"`
 	defaults                 PipelineDefaults
 	initialized              bool
 	accessTokens             map[string]string
 	openFile                 func(s string, t map[string]string) (io.ReadCloser, error)
 	vaultCredentials         VaultCredentials
 	systemTrustConfiguration systemtrust.Configuration
+	provenance               bool
+	source                   *configSource
+	signatureVerification    *SignatureVerification
 }
 
 // StepConfig defines the structure for merged step configuration
 type StepConfig struct {
 	Config     map[string]interface{}
 	HookConfig map[string]interface{}
+	// TLSConfig contains the TLS settings per host of the defaults and the configuration, later entries take precedence
+	TLSConfig []piperhttp.HostTLSConfig
+	// Provenance is only available if provenance tracking has been enabled via Config.TrackProvenance
+	Provenance *Provenance
 }
 
 // ReadConfig loads config and returns its content
@@ -51,6 +59,7 @@ func (c *Config) ReadConfig(configuration io.ReadCloser) error {
 		return errors.Wrapf(err, "error reading %v", configuration)
 	}
 
+	c.source = &configSource{name: readerName(configuration), content: content}
 	err = yaml.Unmarshal(content, &c)
 	if err != nil {
 		return NewParseError(fmt.Sprintf("format of configuration is invalid %q: %v", content, err))
@@ -160,11 +169,15 @@ func (c *Config) InitializeConfig(configuration io.ReadCloser, defaults []io.Rea
 		if c.openFile == nil {
 			c.openFile = OpenPiperFile
 		}
+		openFile := c.signatureVerification.OpenFile(c.openFile)
 		for _, f := range c.CustomDefaults {
-			fc, err := c.openFile(f, c.accessTokens)
+			fc, err := openFile(f, c.accessTokens)
 			if err != nil {
 				return errors.Wrapf(err, "getting default '%v' failed", f)
 			}
+			if len(readerName(fc)) == 0 {
+				fc = &namedReadCloser{ReadCloser: fc, name: f}
+			}
 			defaults = append(defaults, fc)
 		}
 	}
@@ -192,21 +205,27 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		}
 	}
 
+	if c.provenance {
+		stepConfig.Provenance = &Provenance{}
+	}
+
 	c.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
 
 	// initialize with defaults from step.yaml
 	stepConfig.mixInStepDefaults(parameters)
+	stepConfig.Provenance.record(ValueSource{Layer: SourceStepDefault, File: metadata.Metadata.Name}, stepConfig.Config)
 
 	// merge parameters provided by Piper environment
-	stepConfig.mixIn(envParameters, filters.All, metadata)
-	stepConfig.mixIn(envParameters, ReportingParameters.getReportingFilter(), metadata)
+	stepConfig.mixInFrom(ValueSource{Layer: SourceCommonPipelineEnvironment}, envParameters, filters.All, metadata)
+	stepConfig.mixInFrom(ValueSource{Layer: SourceCommonPipelineEnvironment}, envParameters, ReportingParameters.getReportingFilter(), metadata)
 
 	// read defaults & merge general -> steps (-> general -> steps ...)
 	for _, def := range c.defaults.Defaults {
 		def.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
-		stepConfig.mixIn(def.General, filters.General, metadata)
-		stepConfig.mixIn(def.Steps[stepName], filters.Steps, metadata)
-		stepConfig.mixIn(def.Stages[stageName], filters.Steps, metadata)
+		stepConfig.mixInSection(&def, SourceDefaults, []string{"general"}, def.General, filters.General, metadata)
+		stepConfig.mixInSection(&def, SourceDefaults, []string{"steps", stepName}, def.Steps[stepName], filters.Steps, metadata)
+		stepConfig.mixInSection(&def, SourceDefaults, []string{"stages", stageName}, def.Stages[stageName], filters.Steps, metadata)
+		before := stepConfig.Provenance.snapshot(stepConfig.Config)
 		stepConfig.mixinVaultConfig(parameters, def.General, def.Steps[stepName], def.Stages[stageName])
 		reportingConfig, err := cloneConfig(&def)
 		if err != nil {
@@ -214,17 +233,20 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		}
 		reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
 		stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+		stepConfig.Provenance.recordChanges(def.sourceDescription(SourceDefaults), before, stepConfig.Config)
 
 		stepConfig.mixInHookConfig(def.Hooks, metadata)
+		stepConfig.TLSConfig = append(stepConfig.TLSConfig, def.TLS...)
 	}
 
 	// read config & merge - general -> steps -> stages
-	stepConfig.mixIn(c.General, filters.General, metadata)
-	stepConfig.mixIn(c.Steps[stepName], filters.Steps, metadata)
-	stepConfig.mixIn(c.Stages[stageName], filters.Stages, metadata)
+	stepConfig.mixInSection(c, SourceConfig, []string{"general"}, c.General, filters.General, metadata)
+	stepConfig.mixInSection(c, SourceConfig, []string{"steps", stepName}, c.Steps[stepName], filters.Steps, metadata)
+	stepConfig.mixInSection(c, SourceConfig, []string{"stages", stageName}, c.Stages[stageName], filters.Stages, metadata)
+	stepConfig.TLSConfig = append(stepConfig.TLSConfig, c.TLS...)
 
 	// merge parameters provided via env vars
-	stepConfig.mixIn(envValues(filters.All), filters.All, metadata)
+	stepConfig.mixInFrom(ValueSource{Layer: SourceEnvironment}, envValues(filters.All), filters.All, metadata)
 
 	vaultParams := map[string]interface{}{}
 
@@ -250,13 +272,13 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 				}
 			}
 
-			stepConfig.mixIn(params, filters.Parameters, metadata)
+			stepConfig.mixInFrom(ValueSource{Layer: SourceParametersJSON}, params, filters.Parameters, metadata)
 		}
 	}
 
 	// merge command line flags
 	if flagValues != nil {
-		stepConfig.mixIn(flagValues, filters.Parameters, metadata)
+		stepConfig.mixInFrom(ValueSource{Layer: SourceFlags}, flagValues, filters.Parameters, metadata)
 		// retrieve Vault config from flags if provided
 		for _, v := range vaultFilter {
 			if flagValues[v] != nil {
@@ -271,6 +293,7 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		log.Entry().Warnf("invalid value for parameter verbose: '%v'", stepConfig.Config["verbose"])
 	}
 
+	before := stepConfig.Provenance.snapshot(stepConfig.Config)
 	stepConfig.mixinVaultConfig(parameters, c.General, c.Steps[stepName], c.Stages[stageName], vaultParams)
 
 	reportingConfig, err := cloneConfig(c)
@@ -279,6 +302,7 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 	}
 	reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
 	stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+	stepConfig.Provenance.recordChanges(c.sourceDescription(SourceConfig), before, stepConfig.Config)
 
 	// check whether vault should be skipped
 	if skip, ok := stepConfig.Config["skipVault"].(bool); !ok || !skip {
@@ -288,10 +312,16 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 			return StepConfig{}, err
 		}
 		if vaultClient != nil {
+			before := stepConfig.Provenance.snapshot(stepConfig.Config)
 			resolveAllVaultReferences(&stepConfig, vaultClient, append(parameters, ReportingParameters.Parameters...))
 			resolveVaultTestCredentialsWrapper(&stepConfig, vaultClient)
 			resolveVaultCredentialsWrapper(&stepConfig, vaultClient)
+			resolveVaultTLSCertificates(&stepConfig, vaultClient)
+			stepConfig.Provenance.recordChanges(ValueSource{Layer: SourceVault}, before, stepConfig.Config)
 		}
+		before := stepConfig.Provenance.snapshot(stepConfig.Config)
+		resolveAllSecretProviderReferences(&stepConfig, append(parameters, ReportingParameters.Parameters...))
+		stepConfig.Provenance.recordChanges(ValueSource{Layer: SourceSecretProvider}, before, stepConfig.Config)
 	}
 
 	// hooks need to have been loaded from the defaults before the server URL is known
@@ -300,10 +330,13 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		log.Entry().WithError(err).Debug("System Trust lookup skipped due to missing or incorrect configuration")
 	} else {
 		systemTrustClient := systemtrust.PrepareClient(&piperhttp.Client{}, c.systemTrustConfiguration)
+		before := stepConfig.Provenance.snapshot(stepConfig.Config)
 		resolveAllSystemTrustReferences(&stepConfig, append(parameters, ReportingParameters.Parameters...), c.systemTrustConfiguration, systemTrustClient)
+		stepConfig.Provenance.recordChanges(ValueSource{Layer: SourceSystemTrust}, before, stepConfig.Config)
 	}
 
 	// finally do the condition evaluation post processing
+	before = stepConfig.Provenance.snapshot(stepConfig.Config)
 	for _, p := range parameters {
 		if len(p.Conditions) > 0 {
 			for _, cond := range p.Conditions {
@@ -323,9 +356,15 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 			}
 		}
 	}
+	stepConfig.Provenance.recordChanges(ValueSource{Layer: SourceCondition, File: metadata.Metadata.Name}, before, stepConfig.Config)
 	return stepConfig, nil
 }
 
+// SetSignatureVerification enables the verification of signatures of custom defaults
+func (c *Config) SetSignatureVerification(verification *SignatureVerification) {
+	c.signatureVerification = verification
+}
+
 // SetVaultCredentials sets the appRoleID and the appRoleSecretID or the vaultTokento load additional
 // configuration from vault
 // Either appRoleID and appRoleSecretID or vaultToken must be specified.
@@ -393,11 +432,11 @@ func OpenPiperFile(name string, accessTokens map[string]string) (io.ReadCloser,
 		return nil, errors.Wrap(os.ErrNotExist, "no filename provided")
 	}
 
-	if !strings.HasPrefix(name, "http://") && !strings.HasPrefix(name, "https://") {
+	if !IsRemoteFile(name) {
 		return os.Open(name)
 	}
 
-	return httpReadFile(name, accessTokens)
+	return readRemoteFile(name, accessTokens)
 }
 
 func httpReadFile(name string, accessTokens map[string]string) (io.ReadCloser, error) {
@@ -425,7 +464,7 @@ func httpReadFile(name string, accessTokens map[string]string) (io.ReadCloser, e
 	if err != nil {
 		return nil, err
 	}
-	return response.Body, nil
+	return &namedReadCloser{ReadCloser: response.Body, name: name}, nil
 }
 
 func envValues(filter []string) map[string]interface{} {
@@ -446,6 +485,18 @@ func (s *StepConfig) mixIn(mergeData map[string]interface{}, filter []string, me
 	s.Config = merge(s.Config, filterMap(mergeData, filter), metadata)
 }
 
+// mixInFrom merges the given data and records it as coming from source, if provenance is tracked
+func (s *StepConfig) mixInFrom(source ValueSource, mergeData map[string]interface{}, filter []string, metadata StepData) {
+	s.Provenance.record(source, filterMap(mergeData, filter))
+	s.mixIn(mergeData, filter, metadata)
+}
+
+// mixInSection merges a section of a configuration file and records it including file name and line numbers, if provenance is tracked
+func (s *StepConfig) mixInSection(c *Config, layer string, section []string, mergeData map[string]interface{}, filter []string, metadata StepData) {
+	s.Provenance.recordSection(c, layer, section, filterMap(mergeData, filter), metadata)
+	s.mixIn(mergeData, filter, metadata)
+}
+
 func (s *StepConfig) mixInHookConfig(mergeData map[string]interface{}, metadata StepData) {
 	if s.HookConfig == nil {
 		s.HookConfig = map[string]interface{}{}
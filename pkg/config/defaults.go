@@ -36,6 +36,7 @@ func (d *PipelineDefaults) ReadPipelineDefaults(defaultSources []io.ReadCloser)
 			return NewParseError(fmt.Sprintf("error unmarshalling %q: %v", content, err))
 		}
 
+		c.source = &configSource{name: readerName(def), content: content}
 		d.Defaults = append(d.Defaults, c)
 	}
 	return nil
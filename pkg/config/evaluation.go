@@ -36,6 +36,11 @@ func (r *RunConfigV1) evaluateConditionsV1(config *Config, utils piperutils.File
 	}
 
 	currentOrchestrator := orchestrator.DetectOrchestrator().String()
+	changedFiles := r.ChangedFiles
+	if changedFiles == nil {
+		changedFiles = gitChangedFiles
+	}
+	changedFiles = memoizeChangedFiles(changedFiles)
 	for _, stage := range r.PipelineConfig.Spec.Stages {
 		// Currently, the displayName is being used, but it may be necessary
 		// to also consider using the technical name.
@@ -70,7 +75,7 @@ func (r *RunConfigV1) evaluateConditionsV1(config *Config, utils piperutils.File
 			// If no condition is available, the step will be active by default.
 			stepActive := true
 			for _, condition := range step.Conditions {
-				stepActive, err = condition.evaluateV1(stepConfig, utils, step.Name, envRootPath, runStep)
+				stepActive, err = condition.evaluateV1(stepConfig, utils, step.Name, envRootPath, runStep, changedFiles, true)
 				if err != nil {
 					return fmt.Errorf("failed to evaluate step conditions: %w", err)
 				}
@@ -95,7 +100,7 @@ func (r *RunConfigV1) evaluateConditionsV1(config *Config, utils piperutils.File
 			}
 
 			for _, condition := range step.NotActiveConditions {
-				stepNotActive, err := condition.evaluateV1(stepConfig, utils, step.Name, envRootPath, runStep)
+				stepNotActive, err := condition.evaluateV1(stepConfig, utils, step.Name, envRootPath, runStep, changedFiles, false)
 				if err != nil {
 					return fmt.Errorf("failed to evaluate not active step conditions: %w", err)
 				}
@@ -128,10 +133,28 @@ func (s *StepCondition) evaluateV1(
 	stepName string,
 	envRootPath string,
 	runSteps map[string]bool,
+	changedFiles ChangedFilesResolver,
+	changedFallback bool,
 ) (bool, error) {
 
 	// only the first condition will be evaluated.
 	// if multiple conditions should be checked they need to provided via the Conditions list
+	if len(s.If) > 0 {
+		active, err := evaluateExpression(s.If, &expressionContext{
+			config:       config.Config,
+			stepName:     stepName,
+			envRootPath:  envRootPath,
+			utils:        utils,
+			changedFiles: changedFiles,
+		})
+		// the fallback applies to the whole expression, e.g. !changed('docs/**') must not deactivate the step either
+		if errors.Is(err, errChangedFilesUnknown) {
+			log.Entry().WithError(err).Warnf("Could not determine changed files, considering the condition '%v' as %v in order to keep the step active", s.If, changedFallback)
+			return changedFallback, nil
+		}
+		return active, err
+	}
+
 	if s.Config != nil {
 
 		if len(s.Config) > 1 {
@@ -181,6 +204,14 @@ func (s *StepCondition) evaluateV1(
 		return false, nil
 	}
 
+	if len(s.FilePatternChanged) > 0 {
+		return anyFileChanged([]string{s.FilePatternChanged}, changedFiles, changedFallback)
+	}
+
+	if len(s.OnlyIfChanged) > 0 {
+		return anyFileChanged(s.OnlyIfChanged, changedFiles, changedFallback)
+	}
+
 	if len(s.NpmScript) > 0 {
 		return checkForNpmScriptsInPackagesV1(s.NpmScript, config, utils)
 	}
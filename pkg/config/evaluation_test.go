@@ -220,10 +220,32 @@ func TestRunConfigV1EvaluateConditionsV1(t *testing.T) {
 				}},
 			wantRunStages: map[string]bool{"Test Stage 1": true},
 		},
+		{
+			name: "Changed files: stage without relevant changes is skipped",
+			pipelineConfig: PipelineDefinitionV1{Spec: Spec{Stages: []Stage{{DisplayName: "Frontend",
+				Steps: []Step{{
+					Name:       "step1",
+					Conditions: []StepCondition{{OnlyIfChanged: []string{"frontend/**"}}},
+				}},
+			}, {DisplayName: "Backend",
+				Steps: []Step{{
+					Name:       "step2",
+					Conditions: []StepCondition{{FilePatternChanged: "backend/**"}},
+				}},
+			},
+			}}},
+			wantRunSteps: map[string]map[string]bool{
+				"Frontend": {"step1": false},
+				"Backend":  {"step2": true},
+			},
+			wantRunStages: map[string]bool{"Frontend": false, "Backend": true},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := &RunConfigV1{PipelineConfig: tt.pipelineConfig}
+			r := &RunConfigV1{PipelineConfig: tt.pipelineConfig, ChangedFiles: func() ([]string, error) {
+				return []string{"backend/src/main.go"}, nil
+			}}
 			assert.NoError(t, r.evaluateConditionsV1(&config, &filesMock, envRootPath),
 				fmt.Sprintf("evaluateConditionsV1() err, pipelineConfig = %v", tt.pipelineConfig),
 			)
@@ -232,6 +254,50 @@ func TestRunConfigV1EvaluateConditionsV1(t *testing.T) {
 			assert.Equal(t, tt.wantRunStages, r.RunStages, "RunStages mismatch")
 		})
 	}
+
+	t.Run("Changed files: steps stay active if changes are not available", func(t *testing.T) {
+		pipelineConfig := PipelineDefinitionV1{Spec: Spec{Stages: []Stage{{DisplayName: "Frontend",
+			Steps: []Step{{
+				Name:       "step1",
+				Conditions: []StepCondition{{OnlyIfChanged: []string{"frontend/**"}}},
+			}, {
+				Name:                "step2",
+				NotActiveConditions: []StepCondition{{OnlyIfChanged: []string{"docs/**"}}},
+			}, {
+				Name:                "step3",
+				NotActiveConditions: []StepCondition{{If: "changed('docs/**')"}},
+			}, {
+				Name:       "step4",
+				Conditions: []StepCondition{{If: "!changed('docs/**')"}},
+			}, {
+				Name:       "step5",
+				Conditions: []StepCondition{{If: "changed('frontend/**') && !changed('docs/**')"}},
+			}, {
+				Name:       "step6",
+				Conditions: []StepCondition{{If: "false || changed('frontend/**')"}},
+			}, {
+				Name:                "step7",
+				NotActiveConditions: []StepCondition{{If: "!changed('frontend/**')"}},
+			}, {
+				Name:                "step8",
+				NotActiveConditions: []StepCondition{{If: "true && !changed('frontend/**')"}},
+			}, {
+				Name:                "step9",
+				NotActiveConditions: []StepCondition{{If: "changed('docs/**') || !changed('frontend/**')"}},
+			}},
+		}}}}
+		r := &RunConfigV1{PipelineConfig: pipelineConfig, ChangedFiles: func() ([]string, error) {
+			return nil, fmt.Errorf("shallow clone")
+		}}
+		assert.NoError(t, r.evaluateConditionsV1(&config, &filesMock, envRootPath))
+
+		assert.Equal(t, map[string]map[string]bool{"Frontend": {
+			"step1": true, "step2": true, "step3": true,
+			"step4": true, "step5": true, "step6": true,
+			"step7": true, "step8": true, "step9": true,
+		}}, r.RunSteps)
+		assert.Equal(t, map[string]bool{"Frontend": true}, r.RunStages)
+	})
 }
 
 func TestEvaluateV1(t *testing.T) {
@@ -317,6 +383,54 @@ func TestEvaluateV1(t *testing.T) {
 			stepCondition: StepCondition{FilePattern: "**/confx.js"},
 			expected:      false,
 		},
+		{
+			name:          "FilePatternChanged condition - true",
+			config:        StepConfig{Config: map[string]interface{}{}},
+			stepCondition: StepCondition{FilePatternChanged: "frontend/**"},
+			expected:      true,
+		},
+		{
+			name:          "FilePatternChanged condition - false",
+			config:        StepConfig{Config: map[string]interface{}{}},
+			stepCondition: StepCondition{FilePatternChanged: "backend/**"},
+			expected:      false,
+		},
+		{
+			name:          "If condition - true",
+			config:        StepConfig{Config: map[string]interface{}{"buildTool": "npm"}},
+			stepCondition: StepCondition{If: "config.buildTool == 'npm' && fileExists('package.json') && changed('frontend/**')"},
+			expected:      true,
+		},
+		{
+			name:          "If condition - false",
+			config:        StepConfig{Config: map[string]interface{}{"buildTool": "npm"}},
+			stepCondition: StepCondition{If: "config.buildTool == 'maven' || fileExists('pom.xml')"},
+			expected:      false,
+		},
+		{
+			name:          "If condition - list parameter",
+			config:        StepConfig{Config: map[string]interface{}{"mavenProfiles": []string{"release", "docs"}}},
+			stepCondition: StepCondition{If: "'release' in config.mavenProfiles && config.mavenProfiles.contains('docs') && size(config.mavenProfiles) == 2"},
+			expected:      true,
+		},
+		{
+			name:          "If condition - error",
+			config:        StepConfig{Config: map[string]interface{}{}},
+			stepCondition: StepCondition{If: "config.buildTool =="},
+			expectedError: fmt.Errorf("invalid expression 'config.buildTool ==': unexpected end of expression"),
+		},
+		{
+			name:          "OnlyIfChanged condition - true",
+			config:        StepConfig{Config: map[string]interface{}{}},
+			stepCondition: StepCondition{OnlyIfChanged: []string{"backend/**", "**/*.json"}},
+			expected:      true,
+		},
+		{
+			name:          "OnlyIfChanged condition - false",
+			config:        StepConfig{Config: map[string]interface{}{}},
+			stepCondition: StepCondition{OnlyIfChanged: []string{"backend/**", "**/*.java"}},
+			expected:      false,
+		},
 		{
 			name: "FilePatternFromConfig condition - true",
 			config: StepConfig{Config: map[string]interface{}{
@@ -431,9 +545,13 @@ func TestEvaluateV1(t *testing.T) {
 	os.WriteFile(filepath.Join(cpeDir, "myCpeTrueFile"), []byte("myTrueValue"), 0700)
 	os.WriteFile(filepath.Join(cpeDir, "custom", "myCpeTrueFile"), []byte("myTrueValue"), 0700)
 
+	changedFiles := func() ([]string, error) {
+		return []string{"frontend/src/app.js", "frontend/package.json"}, nil
+	}
+
 	for _, test := range tt {
 		t.Run(test.name, func(t *testing.T) {
-			active, err := test.stepCondition.evaluateV1(test.config, &filesMock, "dummy", dir, test.runSteps)
+			active, err := test.stepCondition.evaluateV1(test.config, &filesMock, "dummy", dir, test.runSteps, changedFiles, true)
 			if test.expectedError == nil {
 				assert.NoError(t, err)
 			} else {
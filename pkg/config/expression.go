@@ -0,0 +1,813 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Expressions used in the 'if' step condition follow a small subset of the Common Expression Language (CEL).
+// They are evaluated in a sandbox: only the variables and functions listed below are available,
+// expressions cannot have side effects and their size and nesting depth are limited.
+//
+// Variables:
+//   - config:        step configuration, e.g. config.buildTool or config["cloudFoundry"].space
+//   - branch:        branch of the current pipeline run
+//   - buildReason:   reason of the pipeline run as provided by the orchestrator, e.g. PullRequest
+//   - isPullRequest: true if the pipeline runs for a pull request
+//   - orchestrator:  type of the orchestrator, e.g. Jenkins
+//
+// Functions:
+//   - cpe('custom/key'):      value from the common pipeline environment, null if not available
+//   - fileExists('**/*.js'):  true if a file matching the pattern exists
+//   - changed('frontend/**'): true if a file matching the pattern has been changed
+//   - s.startsWith(x), s.endsWith(x), s.contains(x), s.matches(regex), list.contains(x), size(x)
+//
+// Operators: ||, &&, !, ==, !=, <, <=, >, >=, in, parentheses and list literals [a, b].
+// Accessing a field which does not exist results in null.
+
+const (
+	maxExpressionLength = 2048
+	maxExpressionDepth  = 32
+)
+
+// expressionContext provides the data an expression can access.
+type expressionContext struct {
+	config       map[string]interface{}
+	stepName     string
+	envRootPath  string
+	utils        piperutils.FileUtils
+	changedFiles ChangedFilesResolver
+	// pipeline provides the variables describing the pipeline run, it is only called if such a variable is used
+	pipeline func() map[string]interface{}
+}
+
+// pipelineVariables returns the variables describing the current pipeline run based on the orchestrator.
+func pipelineVariables() map[string]interface{} {
+	provider, _ := orchestrator.GetOrchestratorConfigProvider(nil)
+	return map[string]interface{}{
+		"branch":        provider.Branch(),
+		"buildReason":   provider.BuildReason(),
+		"isPullRequest": provider.IsPullRequest(),
+		"orchestrator":  orchestrator.DetectOrchestrator().String(),
+	}
+}
+
+// evaluateExpression parses and evaluates a condition expression, the result has to be a boolean.
+func evaluateExpression(expression string, ctx *expressionContext) (bool, error) {
+	node, err := parseExpression(expression)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid expression '%v'", expression)
+	}
+	result, err := node.eval(ctx)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate expression '%v'", expression)
+	}
+	active, ok := result.(bool)
+	if !ok {
+		return false, errors.Errorf("expression '%v' evaluates to %v instead of bool", expression, exprTypeName(result))
+	}
+	return active, nil
+}
+
+// ---- lexer ----
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%v' at position %v", t.value, t.pos+1)
+}
+
+var exprOperators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func tokenize(expression string) ([]token, error) {
+	tokens := []token{}
+	for pos := 0; pos < len(expression); {
+		c := rune(expression[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '\'' || c == '"':
+			value, end, err := readStringLiteral(expression, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			pos = end
+		case unicode.IsDigit(c):
+			end := pos
+			for end < len(expression) && (unicode.IsDigit(rune(expression[end])) || expression[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: expression[pos:end], pos: pos})
+			pos = end
+		case unicode.IsLetter(c) || c == '_':
+			end := pos
+			for end < len(expression) && (unicode.IsLetter(rune(expression[end])) || unicode.IsDigit(rune(expression[end])) || expression[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: expression[pos:end], pos: pos})
+			pos = end
+		default:
+			found := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(expression[pos:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, value: op, pos: pos})
+					pos += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, errors.Errorf("unexpected character '%c' at position %v", c, pos+1)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expression)}), nil
+}
+
+func readStringLiteral(expression string, start int) (string, int, error) {
+	quote := expression[start]
+	var value strings.Builder
+	for pos := start + 1; pos < len(expression); pos++ {
+		switch expression[pos] {
+		case quote:
+			return value.String(), pos + 1, nil
+		case '\\':
+			if pos+1 < len(expression) {
+				pos++
+			}
+		}
+		value.WriteByte(expression[pos])
+	}
+	return "", 0, errors.Errorf("unterminated string starting at position %v", start+1)
+}
+
+// ---- parser ----
+
+type exprNode interface {
+	eval(ctx *expressionContext) (interface{}, error)
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parseExpression(expression string) (exprNode, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+	if len(expression) > maxExpressionLength {
+		return nil, errors.Errorf("expression exceeds the maximum length of %v characters", maxExpressionLength)
+	}
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, errors.Errorf("unexpected %v", next)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) isOperator(values ...string) bool {
+	t := p.peek()
+	if t.kind == tokenOperator || (t.kind == tokenIdent && t.value == "in") {
+		for _, value := range values {
+			if t.value == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *exprParser) expect(value string) error {
+	if t := p.next(); t.kind != tokenOperator || t.value != value {
+		return errors.Errorf("expected '%v' but found %v", value, t)
+	}
+	return nil
+}
+
+func (p *exprParser) enter() error {
+	p.depth++
+	if p.depth > maxExpressionDepth {
+		return errors.Errorf("expression exceeds the maximum nesting depth of %v", maxExpressionDepth)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{operator: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{operator: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseRelation() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("==", "!=", "<", "<=", ">", ">=", "in") {
+		operator := p.next().value
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &relationNode{operator: operator, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	if p.isOperator("!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, errors.Errorf("expected field or function name but found %v", name)
+			}
+			if p.isOperator("(") {
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+				node = &callNode{name: name.value, target: node, args: args, pos: name.pos}
+			} else {
+				node = &indexNode{target: node, index: &literalNode{value: name.value}}
+			}
+		case p.isOperator("["):
+			p.next()
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = &indexNode{target: node, index: index}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literalNode{value: t.value}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %v", t)
+		}
+		return &literalNode{value: value}, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.isOperator("(") {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+			return &callNode{name: t.value, args: args, pos: t.pos}, nil
+		}
+		if !isExpressionVariable(t.value) {
+			return nil, errors.Errorf("unknown variable %v", t)
+		}
+		return &variableNode{name: t.value}, nil
+	case tokenOperator:
+		switch t.value {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		case "[":
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{items: items}, nil
+		}
+	}
+	return nil, errors.Errorf("unexpected %v", t)
+}
+
+func (p *exprParser) parseArguments() ([]exprNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	return p.parseList(")")
+}
+
+func (p *exprParser) parseList(closing string) ([]exprNode, error) {
+	items := []exprNode{}
+	if p.isOperator(closing) {
+		p.next()
+		return items, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.isOperator(",") {
+			p.next()
+			continue
+		}
+		return items, p.expect(closing)
+	}
+}
+
+// ---- evaluation ----
+
+func isExpressionVariable(name string) bool {
+	switch name {
+	case "config", "branch", "buildReason", "isPullRequest", "orchestrator":
+		return true
+	}
+	return false
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(*expressionContext) (interface{}, error) {
+	return n.value, nil
+}
+
+type listNode struct {
+	items []exprNode
+}
+
+func (n *listNode) eval(ctx *expressionContext) (interface{}, error) {
+	values := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n *variableNode) eval(ctx *expressionContext) (interface{}, error) {
+	if n.name == "config" {
+		return ctx.config, nil
+	}
+	if ctx.pipeline == nil {
+		ctx.pipeline = pipelineVariables
+	}
+	variables := ctx.pipeline()
+	// cache the variables for further usages within the same expression
+	ctx.pipeline = func() map[string]interface{} { return variables }
+	return normalizeExprValue(variables[n.name]), nil
+}
+
+type indexNode struct {
+	target exprNode
+	index  exprNode
+}
+
+func (n *indexNode) eval(ctx *expressionContext) (interface{}, error) {
+	target, err := n.target.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch t := normalizeExprValue(target).(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, errors.Errorf("map key must be a string but is %v", exprTypeName(index))
+		}
+		// values of the configuration may have any slice or map type, e.g. []string
+		return normalizeExprValue(t[key]), nil
+	case []interface{}:
+		i, ok := toFloat(index)
+		if !ok || i != float64(int(i)) {
+			return nil, errors.Errorf("list index must be an integer but is %v", exprTypeName(index))
+		}
+		if int(i) < 0 || int(i) >= len(t) {
+			return nil, nil
+		}
+		return normalizeExprValue(t[int(i)]), nil
+	}
+	return nil, errors.Errorf("cannot access field '%v' of %v", index, exprTypeName(target))
+}
+
+type notNode struct {
+	operand exprNode
+}
+
+func (n *notNode) eval(ctx *expressionContext) (interface{}, error) {
+	value, err := evalBool(n.operand, ctx, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !value, nil
+}
+
+type logicalNode struct {
+	operator    string
+	left, right exprNode
+}
+
+func (n *logicalNode) eval(ctx *expressionContext) (interface{}, error) {
+	left, err := evalBool(n.left, ctx, n.operator)
+	if err != nil {
+		return nil, err
+	}
+	// short-circuit evaluation, e.g. to avoid determining changed files if not required
+	if (n.operator == "&&" && !left) || (n.operator == "||" && left) {
+		return left, nil
+	}
+	return evalBool(n.right, ctx, n.operator)
+}
+
+func evalBool(node exprNode, ctx *expressionContext, operator string) (bool, error) {
+	value, err := node.eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, errors.Errorf("operator '%v' requires bool operands but got %v", operator, exprTypeName(value))
+	}
+	return b, nil
+}
+
+type relationNode struct {
+	operator    string
+	left, right exprNode
+}
+
+func (n *relationNode) eval(ctx *expressionContext) (interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "==":
+		return exprEqual(left, right), nil
+	case "!=":
+		return !exprEqual(left, right), nil
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("operator 'in' requires a list on the right side but got %v", exprTypeName(right))
+		}
+		return listContains(list, left), nil
+	}
+
+	if l, ok := toFloat(left); ok {
+		if r, ok := toFloat(right); ok {
+			return compare(n.operator, l, r), nil
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compare(n.operator, l, r), nil
+		}
+	}
+	return nil, errors.Errorf("operator '%v' cannot compare %v with %v", n.operator, exprTypeName(left), exprTypeName(right))
+}
+
+func compare[T float64 | string](operator string, left, right T) bool {
+	switch operator {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	}
+	return left >= right
+}
+
+type callNode struct {
+	name   string
+	target exprNode
+	args   []exprNode
+	pos    int
+}
+
+func (n *callNode) eval(ctx *expressionContext) (interface{}, error) {
+	args := []interface{}{}
+	if n.target != nil {
+		target, err := n.target.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, target)
+	}
+	for _, arg := range n.args {
+		value, err := arg.eval(ctx)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	switch n.name {
+	case "size":
+		if err := n.checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		switch v := args[0].(type) {
+		case string:
+			return float64(len(v)), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, errors.Errorf("function 'size' is not supported for %v", exprTypeName(args[0]))
+	case "contains":
+		if err := n.checkArgs(args, 2); err != nil {
+			return nil, err
+		}
+		if list, ok := args[0].([]interface{}); ok {
+			return listContains(list, args[1]), nil
+		}
+		return n.stringFunction(args, strings.Contains)
+	case "startsWith":
+		return n.stringFunction(args, strings.HasPrefix)
+	case "endsWith":
+		return n.stringFunction(args, strings.HasSuffix)
+	case "matches":
+		return n.stringFunction(args, func(s, pattern string) bool {
+			matched, _ := regexp.MatchString(pattern, s)
+			return matched
+		})
+	case "cpe":
+		path, err := n.stringArg(args)
+		if err != nil {
+			return nil, err
+		}
+		var metadata StepData
+		entry := getCPEEntry(path, "", &metadata, ctx.stepName, ctx.envRootPath)
+		if len(entry) == 0 {
+			entry = getCPEEntry(path, nil, &metadata, ctx.stepName, ctx.envRootPath)
+		}
+		return normalizeExprValue(entry[ctx.stepName]), nil
+	case "fileExists":
+		pattern, err := n.stringArg(args)
+		if err != nil {
+			return nil, err
+		}
+		files, err := ctx.utils.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check files matching '%v'", pattern)
+		}
+		return len(files) > 0, nil
+	case "changed":
+		pattern, err := n.stringArg(args)
+		if err != nil {
+			return nil, err
+		}
+		return matchChangedFiles([]string{pattern}, ctx.changedFiles)
+	}
+	return nil, errors.Errorf("unknown function '%v' at position %v", n.name, n.pos+1)
+}
+
+func (n *callNode) checkArgs(args []interface{}, count int) error {
+	if len(args) != count {
+		return errors.Errorf("function '%v' expects %v argument(s) but got %v", n.name, count, len(args))
+	}
+	return nil
+}
+
+func (n *callNode) stringArg(args []interface{}) (string, error) {
+	if n.target != nil {
+		return "", errors.Errorf("function '%v' cannot be called as a method", n.name)
+	}
+	if err := n.checkArgs(args, 1); err != nil {
+		return "", err
+	}
+	value, ok := args[0].(string)
+	if !ok {
+		return "", errors.Errorf("function '%v' expects a string argument but got %v", n.name, exprTypeName(args[0]))
+	}
+	return value, nil
+}
+
+func (n *callNode) stringFunction(args []interface{}, f func(s, arg string) bool) (interface{}, error) {
+	if err := n.checkArgs(args, 2); err != nil {
+		return nil, err
+	}
+	s, ok1 := args[0].(string)
+	arg, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil, errors.Errorf("function '%v' expects string arguments but got %v and %v", n.name, exprTypeName(args[0]), exprTypeName(args[1]))
+	}
+	if n.name == "matches" {
+		if _, err := regexp.Compile(arg); err != nil {
+			return nil, errors.Wrapf(err, "invalid regular expression '%v'", arg)
+		}
+	}
+	return f(s, arg), nil
+}
+
+func listContains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if exprEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func exprEqual(left, right interface{}) bool {
+	left, right = normalizeExprValue(left), normalizeExprValue(right)
+	if l, ok := toFloat(left); ok {
+		r, ok := toFloat(right)
+		return ok && l == r
+	}
+	return fmt.Sprintf("%T%v", left, left) == fmt.Sprintf("%T%v", right, right)
+}
+
+// normalizeExprValue converts values from the configuration into the types used within expressions,
+// i.e. numbers into float64, slices like []string into []interface{} and maps into map[string]interface{}.
+func normalizeExprValue(value interface{}) interface{} {
+	if f, ok := toFloat(value); ok {
+		return f
+	}
+	switch value.(type) {
+	case nil, bool, string, []interface{}, map[string]interface{}:
+		return value
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i).Interface())
+		}
+		return values
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		values := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			values[iter.Key().String()] = iter.Value().Interface()
+		}
+		return values
+	}
+	return value
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func exprTypeName(value interface{}) string {
+	switch normalizeExprValue(value).(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
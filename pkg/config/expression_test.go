@@ -0,0 +1,143 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	envRootPath := t.TempDir()
+	cpeDir := filepath.Join(envRootPath, "commonPipelineEnvironment", "custom")
+	require.NoError(t, os.MkdirAll(cpeDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(cpeDir, "buildTool"), []byte("npm"), 0700))
+
+	filesMock := mock.FilesMock{}
+	filesMock.AddFile("package.json", []byte("{}"))
+
+	pipelineCalls := 0
+	newContext := func() *expressionContext {
+		return &expressionContext{
+			config: map[string]interface{}{
+				"buildTool":    "maven",
+				"retries":      3,
+				"profiles":     []interface{}{"release", "docs"},
+				"goals":        []string{"install", "deploy"},
+				"ports":        []int{8080, 8443},
+				"labels":       map[string]string{"team": "piper"},
+				"cloudFoundry": map[string]interface{}{"space": "dev"},
+			},
+			stepName:    "testStep",
+			envRootPath: envRootPath,
+			utils:       &filesMock,
+			changedFiles: func() ([]string, error) {
+				return []string{"frontend/app.js"}, nil
+			},
+			pipeline: func() map[string]interface{} {
+				pipelineCalls++
+				return map[string]interface{}{"branch": "feature/x", "buildReason": "PullRequest", "isPullRequest": true, "orchestrator": "Jenkins"}
+			},
+		}
+	}
+
+	tt := []struct {
+		expression string
+		expected   bool
+	}{
+		{expression: "config.buildTool == 'maven'", expected: true},
+		{expression: `config["buildTool"] != "maven"`, expected: false},
+		{expression: "config.cloudFoundry.space == 'dev' && config.retries >= 3", expected: true},
+		{expression: "config.notThere.nested == null", expected: true},
+		{expression: "'release' in config.profiles && config.profiles.contains('docs') && size(config.profiles) == 2", expected: true},
+		{expression: "config.buildTool in ['npm', 'yarn']", expected: false},
+		{expression: "branch.startsWith('feature/') && isPullRequest", expected: true},
+		{expression: "!(buildReason == 'PullRequest') || orchestrator == 'Azure'", expected: false},
+		{expression: "branch.matches('^feature/[a-z]+$') && branch.endsWith('x')", expected: true},
+		{expression: "cpe('custom/buildTool') == 'npm' && cpe('custom/missing') == null", expected: true},
+		{expression: "fileExists('package.json') && !fileExists('**/pom.xml')", expected: true},
+		{expression: "changed('frontend/**') && !changed('backend/**')", expected: true},
+		{expression: "false && unknownFunction()", expected: false},
+		{expression: "'deploy' in config.goals && config.goals.contains('install') && config.goals[0] == 'install'", expected: true},
+		{expression: "8443 in config.ports && size(config.ports) == 2", expected: true},
+		{expression: "config.labels.team == 'piper' && size(config.labels) == 1", expected: true},
+	}
+
+	for _, test := range tt {
+		t.Run(test.expression, func(t *testing.T) {
+			active, err := evaluateExpression(test.expression, newContext())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, active)
+		})
+	}
+
+	t.Run("pipeline variables are determined once", func(t *testing.T) {
+		pipelineCalls = 0
+		_, err := evaluateExpression("branch != '' && buildReason != '' && isPullRequest", newContext())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, pipelineCalls)
+	})
+}
+
+func TestEvaluateExpressionErrors(t *testing.T) {
+	ctx := &expressionContext{config: map[string]interface{}{"buildTool": "maven"}, utils: &mock.FilesMock{}}
+
+	tt := []struct {
+		expression    string
+		expectedError string
+	}{
+		{expression: "", expectedError: "invalid expression '': expression is empty"},
+		{expression: "config.buildTool == 'maven", expectedError: "invalid expression 'config.buildTool == 'maven': unterminated string starting at position 21"},
+		{expression: "config.buildTool = 'maven'", expectedError: "invalid expression 'config.buildTool = 'maven'': unexpected character '=' at position 18"},
+		{expression: "(config.buildTool == 'maven'", expectedError: "invalid expression '(config.buildTool == 'maven'': expected ')' but found end of expression"},
+		{expression: "config.buildTool == 'maven' 'npm'", expectedError: "invalid expression 'config.buildTool == 'maven' 'npm'': unexpected 'npm' at position 29"},
+		{expression: "env.HOME == ''", expectedError: "invalid expression 'env.HOME == ''': unknown variable 'env' at position 1"},
+		{expression: strings.Repeat("!", 40) + "true", expectedError: "invalid expression '" + strings.Repeat("!", 40) + "true': expression exceeds the maximum nesting depth of 32"},
+		{expression: "exec('rm -rf /')", expectedError: "failed to evaluate expression 'exec('rm -rf /')': unknown function 'exec' at position 1"},
+		{expression: "config.buildTool && true", expectedError: "failed to evaluate expression 'config.buildTool && true': operator '&&' requires bool operands but got string"},
+		{expression: "config.buildTool > 1", expectedError: "failed to evaluate expression 'config.buildTool > 1': operator '>' cannot compare string with number"},
+		{expression: "config.buildTool.matches('[')", expectedError: "failed to evaluate expression 'config.buildTool.matches('[')': invalid regular expression '[': error parsing regexp: missing closing ]: `[`"},
+		{expression: "fileExists(1)", expectedError: "failed to evaluate expression 'fileExists(1)': function 'fileExists' expects a string argument but got number"},
+		{expression: "config.buildTool", expectedError: "expression 'config.buildTool' evaluates to string instead of bool"},
+	}
+
+	for _, test := range tt {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := evaluateExpression(test.expression, ctx)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestEvaluateExpressionChangedFilesUnknown(t *testing.T) {
+	ctx := &expressionContext{config: map[string]interface{}{"buildTool": "maven"}, utils: &mock.FilesMock{}, changedFiles: func() ([]string, error) {
+		return nil, fmt.Errorf("shallow clone")
+	}}
+
+	for _, expression := range []string{
+		"changed('docs/**')",
+		"!changed('docs/**')",
+		"config.buildTool == 'maven' && changed('docs/**')",
+		"config.buildTool == 'npm' || !changed('docs/**')",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := evaluateExpression(expression, ctx)
+			assert.ErrorIs(t, err, errChangedFilesUnknown)
+		})
+	}
+
+	t.Run("short circuit", func(t *testing.T) {
+		active, err := evaluateExpression("config.buildTool == 'npm' && changed('docs/**')", ctx)
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+}
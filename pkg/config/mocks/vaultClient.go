@@ -17,6 +17,65 @@ func (_m *VaultClient) EXPECT() *VaultClient_Expecter {
 	return &VaultClient_Expecter{mock: &_m.Mock}
 }
 
+// GetDynamicSecret provides a mock function with given fields: _a0, _a1
+func (_m *VaultClient) GetDynamicSecret(_a0 string, _a1 map[string]interface{}) (map[string]string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDynamicSecret")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, map[string]interface{}) (map[string]string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, map[string]interface{}) map[string]string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, map[string]interface{}) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VaultClient_GetDynamicSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDynamicSecret'
+type VaultClient_GetDynamicSecret_Call struct {
+	*mock.Call
+}
+
+// GetDynamicSecret is a helper method to define mock.On call
+//   - _a0 string
+//   - _a1 map[string]interface{}
+func (_e *VaultClient_Expecter) GetDynamicSecret(_a0 interface{}, _a1 interface{}) *VaultClient_GetDynamicSecret_Call {
+	return &VaultClient_GetDynamicSecret_Call{Call: _e.mock.On("GetDynamicSecret", _a0, _a1)}
+}
+
+func (_c *VaultClient_GetDynamicSecret_Call) Run(run func(_a0 string, _a1 map[string]interface{})) *VaultClient_GetDynamicSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(map[string]interface{}))
+	})
+	return _c
+}
+
+func (_c *VaultClient_GetDynamicSecret_Call) Return(_a0 map[string]string, _a1 error) *VaultClient_GetDynamicSecret_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *VaultClient_GetDynamicSecret_Call) RunAndReturn(run func(string, map[string]interface{}) (map[string]string, error)) *VaultClient_GetDynamicSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetKvSecret provides a mock function with given fields: _a0
 func (_m *VaultClient) GetKvSecret(_a0 string) (map[string]string, error) {
 	ret := _m.Called(_a0)
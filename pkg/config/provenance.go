@@ -0,0 +1,298 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Layers of the configuration merge a value can originate from
+const (
+	SourceStepDefault               = "stepDefault"
+	SourceCommonPipelineEnvironment = "commonPipelineEnvironment"
+	SourceDefaults                  = "defaults"
+	SourceConfig                    = "config"
+	SourceEnvironment               = "environment"
+	SourceParametersJSON            = "parametersJSON"
+	SourceFlags                     = "flags"
+	SourceVault                     = "vault"
+	SourceSecretProvider            = "secretProvider"
+	SourceSystemTrust               = "systemTrust"
+	SourceCondition                 = "condition"
+)
+
+// ValueSource describes where a configuration value originates from
+type ValueSource struct {
+	Layer string `json:"layer"`
+	// File is the name of the configuration file, e.g. .pipeline/config.yml
+	File string `json:"file,omitempty"`
+	// Section is the part of the configuration file, e.g. general, steps/mavenBuild or stages/Build
+	Section string `json:"section,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	// Alias is set if the value was provided using an alias or a deprecated name of the parameter
+	Alias      string      `json:"alias,omitempty"`
+	Deprecated bool        `json:"deprecated,omitempty"`
+	Value      interface{} `json:"value"`
+}
+
+// ParameterProvenance contains the source of the effective value of a parameter and the values it overrode
+type ParameterProvenance struct {
+	Parameter  string        `json:"parameter"`
+	Value      interface{}   `json:"value"`
+	Source     *ValueSource  `json:"source,omitempty"`
+	Overridden []ValueSource `json:"overridden,omitempty"`
+}
+
+// Provenance records all values set for the parameters of a step configuration during the merge of the configuration layers
+type Provenance struct {
+	history map[string][]ValueSource
+}
+
+// configSource holds the raw content of a configuration file, which is used to determine line numbers
+type configSource struct {
+	name    string
+	content []byte
+	root    *yaml.Node
+	parsed  bool
+}
+
+// TrackProvenance enables recording of the provenance of all parameter values in the step configuration
+func (c *Config) TrackProvenance() {
+	c.provenance = true
+}
+
+// Explain returns the provenance of all parameters of the given configuration, sorted by parameter name
+func (p *Provenance) Explain(config map[string]interface{}) []ParameterProvenance {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]ParameterProvenance, 0, len(names))
+	for _, name := range names {
+		entry := ParameterProvenance{Parameter: name, Value: config[name]}
+		if p != nil {
+			if history := p.history[name]; len(history) > 0 {
+				winner := history[len(history)-1]
+				entry.Source = &winner
+				for i := len(history) - 2; i >= 0; i-- {
+					entry.Overridden = append(entry.Overridden, history[i])
+				}
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// ExplainText returns a human-readable representation of the provenance of the given configuration
+func (p *Provenance) ExplainText(config map[string]interface{}) string {
+	var b strings.Builder
+	for _, entry := range p.Explain(config) {
+		fmt.Fprintf(&b, "%s: %v\n", entry.Parameter, formatValue(entry.Value))
+		if entry.Source == nil {
+			b.WriteString("  set by:     unknown\n")
+			continue
+		}
+		fmt.Fprintf(&b, "  set by:     %s\n", entry.Source)
+		for _, overridden := range entry.Overridden {
+			fmt.Fprintf(&b, "  overrides:  %s = %v\n", overridden, formatValue(overridden.Value))
+		}
+	}
+	return b.String()
+}
+
+// String returns a short description of the source, e.g. config .pipeline/config.yml:12 (steps/mavenBuild)
+func (s ValueSource) String() string {
+	description := s.Layer
+	if len(s.File) > 0 {
+		description += " " + s.File
+		if s.Line > 0 {
+			description += fmt.Sprintf(":%d", s.Line)
+		}
+	}
+	if len(s.Section) > 0 {
+		description += " (" + s.Section + ")"
+	}
+	if len(s.Alias) > 0 {
+		if s.Deprecated {
+			description += fmt.Sprintf(" via deprecated name '%s'", s.Alias)
+		} else {
+			description += fmt.Sprintf(" via alias '%s'", s.Alias)
+		}
+	}
+	return description
+}
+
+func formatValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Record adds values which are set outside of the configuration merge, e.g. the hooks of the stage configuration,
+// to the history of the respective parameters
+func (p *Provenance) Record(source ValueSource, values map[string]interface{}) {
+	p.record(source, values)
+}
+
+func (p *Provenance) record(source ValueSource, values map[string]interface{}) {
+	if p == nil {
+		return
+	}
+	if p.history == nil {
+		p.history = map[string][]ValueSource{}
+	}
+	for key, value := range values {
+		s := source
+		s.Value = value
+		p.history[key] = append(p.history[key], s)
+	}
+}
+
+// recordChanges adds all values which differ between before and after to the history of the respective parameters
+func (p *Provenance) recordChanges(source ValueSource, before, after map[string]interface{}) {
+	if p == nil {
+		return
+	}
+	changed := map[string]interface{}{}
+	for key, value := range after {
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changed[key] = value
+		}
+	}
+	p.record(source, changed)
+}
+
+// recordSection adds the values of a section of a configuration file to the history of the respective parameters.
+// The line numbers as well as usages of aliases and deprecated names are determined from the raw file content.
+func (p *Provenance) recordSection(c *Config, layer string, section []string, values map[string]interface{}, metadata StepData) {
+	if p == nil {
+		return
+	}
+	for key, value := range values {
+		source := ValueSource{Layer: layer, Section: strings.Join(section, "/"), Value: value}
+		if c.source != nil {
+			source.File = c.source.name
+			source.Line = c.source.line(append(section, key)...)
+			if source.Line == 0 {
+				c.source.resolveAlias(&source, section, key, metadata)
+			}
+		}
+		p.record(source, map[string]interface{}{key: value})
+	}
+}
+
+// snapshot returns a shallow copy of the current configuration, if provenance is tracked
+func (p *Provenance) snapshot(config map[string]interface{}) map[string]interface{} {
+	if p == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		result[key] = value
+	}
+	return result
+}
+
+// resolveAlias checks whether the key was provided using one of its aliases or in the section of a step alias
+func (s *configSource) resolveAlias(source *ValueSource, section []string, key string, metadata StepData) {
+	for _, param := range metadata.Spec.Inputs.Parameters {
+		if param.Name != key {
+			continue
+		}
+		for _, alias := range param.Aliases {
+			if line := s.line(append(append([]string{}, section...), strings.Split(alias.Name, "/")...)...); line > 0 {
+				source.Line = line
+				source.Alias = alias.Name
+				source.Deprecated = alias.Deprecated
+				return
+			}
+		}
+	}
+	if len(section) == 2 && section[0] == "steps" {
+		for _, stepAlias := range metadata.Metadata.Aliases {
+			if line := s.line("steps", stepAlias.Name, key); line > 0 {
+				source.Section = "steps/" + stepAlias.Name
+				source.Line = line
+				source.Alias = stepAlias.Name
+				source.Deprecated = stepAlias.Deprecated
+				return
+			}
+		}
+	}
+}
+
+// line returns the line number of the key at the given path or 0 if the key does not exist
+func (s *configSource) line(path ...string) int {
+	if !s.parsed {
+		s.parsed = true
+		var root yaml.Node
+		if err := yaml.Unmarshal(s.content, &root); err != nil {
+			log.Entry().WithError(err).Debugf("failed to determine line numbers of %s", s.name)
+			return 0
+		}
+		s.root = &root
+	}
+	if s.root == nil || len(s.root.Content) == 0 {
+		return 0
+	}
+
+	node := s.root.Content[0]
+	line := 0
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return 0
+		}
+		found := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				line = node.Content[i].Line
+				node = node.Content[i+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+	return line
+}
+
+// readerName returns the name of the file behind a reader, if available
+func readerName(r io.Reader) string {
+	if named, ok := r.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return ""
+}
+
+// namedReadCloser attaches the name of the origin to the content of a remote file
+type namedReadCloser struct {
+	io.ReadCloser
+	name string
+	// stale marks cached content which is used since the remote location could not be reached
+	stale bool
+}
+
+// Name returns the name of the origin, e.g. the URL of the file
+func (n *namedReadCloser) Name() string {
+	return n.name
+}
+
+// sourceDescription returns the source of values which are merged from the whole configuration file
+func (c *Config) sourceDescription(layer string) ValueSource {
+	source := ValueSource{Layer: layer}
+	if c.source != nil {
+		source.File = c.source.name
+	}
+	return source
+}
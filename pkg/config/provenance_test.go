@@ -0,0 +1,140 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type namedStringReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedStringReader) Name() string { return n.name }
+func (n namedStringReader) Close() error { return nil }
+
+func TestGetStepConfigProvenance(t *testing.T) {
+	projectConfig := `general:
+  buildTool: maven
+steps:
+  mavenBuild:
+    goals: install
+    oldFlag: true
+  mavenBuildOld:
+    profiles: release
+stages:
+  Build:
+    goals: verify
+`
+	defaults := `general:
+  buildTool: npm
+steps:
+  mavenBuild:
+    goals: package
+`
+	metadata := StepData{
+		Metadata: StepMetadata{Name: "mavenBuild", Aliases: []Alias{{Name: "mavenBuildOld", Deprecated: true}}},
+		Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+			{Name: "buildTool", Scope: []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"}, Type: "string"},
+			{Name: "goals", Scope: []string{"STEPS", "STAGES", "PARAMETERS"}, Type: "string", Default: "clean"},
+			{Name: "newFlag", Scope: []string{"STEPS", "PARAMETERS"}, Type: "bool", Aliases: []Alias{{Name: "oldFlag", Deprecated: true}}},
+			{Name: "profiles", Scope: []string{"STEPS", "PARAMETERS"}, Type: "string"},
+			{Name: "verbose", Scope: []string{"PARAMETERS"}, Type: "bool"},
+		}}},
+	}
+
+	t.Run("provenance tracked", func(t *testing.T) {
+		c := Config{}
+		c.TrackProvenance()
+
+		stepConfig, err := c.GetStepConfig(
+			map[string]interface{}{"verbose": false},
+			`{"buildTool":"gradle"}`,
+			namedStringReader{Reader: strings.NewReader(projectConfig), name: ".pipeline/config.yml"},
+			[]io.ReadCloser{namedStringReader{Reader: strings.NewReader(defaults), name: "defaults.yml"}},
+			false, metadata.GetParameterFilters(), metadata, nil, "Build", "mavenBuild",
+		)
+		require.NoError(t, err)
+		require.NotNil(t, stepConfig.Provenance)
+
+		explained := map[string]ParameterProvenance{}
+		for _, p := range stepConfig.Provenance.Explain(stepConfig.Config) {
+			explained[p.Parameter] = p
+		}
+
+		buildTool := explained["buildTool"]
+		assert.Equal(t, "gradle", buildTool.Value)
+		assert.Equal(t, SourceParametersJSON, buildTool.Source.Layer)
+		if assert.Len(t, buildTool.Overridden, 2) {
+			assert.Equal(t, ValueSource{Layer: SourceConfig, File: ".pipeline/config.yml", Section: "general", Line: 2, Value: "maven"}, buildTool.Overridden[0])
+			assert.Equal(t, ValueSource{Layer: SourceDefaults, File: "defaults.yml", Section: "general", Line: 2, Value: "npm"}, buildTool.Overridden[1])
+		}
+
+		goals := explained["goals"]
+		assert.Equal(t, "verify", goals.Value)
+		assert.Equal(t, ValueSource{Layer: SourceConfig, File: ".pipeline/config.yml", Section: "stages/Build", Line: 11, Value: "verify"}, *goals.Source)
+		if assert.Len(t, goals.Overridden, 3) {
+			assert.Equal(t, 5, goals.Overridden[0].Line)
+			assert.Equal(t, SourceDefaults, goals.Overridden[1].Layer)
+			assert.Equal(t, ValueSource{Layer: SourceStepDefault, File: "mavenBuild", Value: "clean"}, goals.Overridden[2])
+		}
+
+		newFlag := explained["newFlag"]
+		assert.Equal(t, ValueSource{Layer: SourceConfig, File: ".pipeline/config.yml", Section: "steps/mavenBuild", Line: 6, Alias: "oldFlag", Deprecated: true, Value: true}, *newFlag.Source)
+
+		profiles := explained["profiles"]
+		assert.Equal(t, ValueSource{Layer: SourceConfig, File: ".pipeline/config.yml", Section: "steps/mavenBuildOld", Line: 8, Alias: "mavenBuildOld", Deprecated: true, Value: "release"}, *profiles.Source)
+
+		assert.Equal(t, SourceFlags, explained["verbose"].Source.Layer)
+
+		text := stepConfig.Provenance.ExplainText(stepConfig.Config)
+		assert.Contains(t, text, "buildTool: \"gradle\"\n  set by:     parametersJSON\n  overrides:  config .pipeline/config.yml:2 (general) = \"maven\"\n  overrides:  defaults defaults.yml:2 (general) = \"npm\"\n")
+		assert.Contains(t, text, "newFlag: true\n  set by:     config .pipeline/config.yml:6 (steps/mavenBuild) via deprecated name 'oldFlag'\n")
+	})
+
+	t.Run("environment variables", func(t *testing.T) {
+		defer os.Unsetenv("PIPER_profiles")
+		os.Setenv("PIPER_profiles", "env")
+		c := Config{}
+		c.TrackProvenance()
+
+		stepConfig, err := c.GetStepConfig(nil, "", nil, nil, false, metadata.GetParameterFilters(), metadata, nil, "Build", "mavenBuild")
+		require.NoError(t, err)
+
+		explained := stepConfig.Provenance.Explain(stepConfig.Config)
+		assert.Equal(t, []ParameterProvenance{
+			{Parameter: "goals", Value: "clean", Source: &ValueSource{Layer: SourceStepDefault, File: "mavenBuild", Value: "clean"}},
+			{Parameter: "profiles", Value: "env", Source: &ValueSource{Layer: SourceEnvironment, Value: "env"}},
+		}, explained)
+	})
+
+	t.Run("provenance not tracked", func(t *testing.T) {
+		c := Config{}
+
+		stepConfig, err := c.GetStepConfig(nil, "", nil, nil, false, metadata.GetParameterFilters(), metadata, nil, "Build", "mavenBuild")
+		require.NoError(t, err)
+
+		assert.Nil(t, stepConfig.Provenance)
+		assert.Equal(t, []ParameterProvenance{{Parameter: "goals", Value: "clean"}}, stepConfig.Provenance.Explain(stepConfig.Config))
+	})
+}
+
+func TestConfigSourceLine(t *testing.T) {
+	s := configSource{content: []byte("general:\n  a: 1\nsteps:\n  s1:\n    b:\n      c: 2\n")}
+
+	assert.Equal(t, 2, s.line("general", "a"))
+	assert.Equal(t, 6, s.line("steps", "s1", "b", "c"))
+	assert.Equal(t, 0, s.line("steps", "s2"))
+	assert.Equal(t, 0, s.line("general", "a", "b"))
+
+	invalid := configSource{content: []byte("general:\n\ta: 1")}
+	assert.Equal(t, 0, invalid.line("general", "a"))
+}
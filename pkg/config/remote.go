@@ -0,0 +1,453 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// remoteConfigCacheDirEnv allows to define the directory used for caching remote configuration files.
+	remoteConfigCacheDirEnv = "PIPER_remoteConfigCacheDir"
+	// remoteConfigCacheTTLEnv allows to define for how long cached remote configuration files are used without fetching them again, e.g. 10m.
+	remoteConfigCacheTTLEnv = "PIPER_remoteConfigCacheTTL"
+	// remoteConfigOfflineFallbackEnv allows to disable the use of cached remote configuration files in case the remote location cannot be reached.
+	remoteConfigOfflineFallbackEnv = "PIPER_remoteConfigOfflineFallback"
+
+	remoteFetchTimeout = 2 * time.Minute
+	ociTitleAnnotation = "org.opencontainers.image.title"
+)
+
+// remoteFetcher downloads the content of a remote configuration file.
+type remoteFetcher func(ctx context.Context, source *url.URL, accessTokens map[string]string) ([]byte, error)
+
+// remoteFetchers contains the supported remote locations by URL scheme.
+var remoteFetchers = map[string]remoteFetcher{
+	"oci":       fetchOCIArtifact,
+	"s3":        fetchS3Object,
+	"gs":        fetchGCSObject,
+	"git+https": fetchGitFile,
+	"git+http":  fetchGitFile,
+	"git+file":  fetchGitFile,
+	"http":      fetchHTTPFile,
+	"https":     fetchHTTPFile,
+}
+
+// IsRemoteFile checks whether a configuration file needs to be fetched from a remote location.
+func IsRemoteFile(name string) bool {
+	scheme, _, found := strings.Cut(name, "://")
+	if !found {
+		return false
+	}
+	_, ok := remoteFetchers[scheme]
+	return ok
+}
+
+// remoteConfigCache caches remote configuration files on disk. The files are stored by the digest of their content
+// and an index maps the source location to the digest of the last successfully fetched content.
+type remoteConfigCache struct {
+	dir string
+	ttl time.Duration
+	// offlineFallback defines whether the cached content is used in case the remote location cannot be reached
+	offlineFallback bool
+}
+
+type remoteConfigCacheEntry struct {
+	Digest  string    `json:"digest"`
+	Fetched time.Time `json:"fetched"`
+	// Pinned is the digest the source was pinned to when the content was fetched, e.g. the manifest digest of an OCI artifact
+	Pinned string `json:"pinned,omitempty"`
+}
+
+func newRemoteConfigCache() *remoteConfigCache {
+	dir := os.Getenv(remoteConfigCacheDirEnv)
+	if len(dir) == 0 {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			cacheDir = os.TempDir()
+		}
+		dir = filepath.Join(cacheDir, "piper", "config")
+	}
+	var ttl time.Duration
+	if value := os.Getenv(remoteConfigCacheTTLEnv); len(value) > 0 {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil {
+			log.Entry().WithError(err).Warnf("Invalid value '%v' of %v, cached remote configuration will not be used without fetching it", value, remoteConfigCacheTTLEnv)
+		}
+	}
+	offlineFallback := true
+	if value := os.Getenv(remoteConfigOfflineFallbackEnv); len(value) > 0 {
+		var err error
+		if offlineFallback, err = strconv.ParseBool(value); err != nil {
+			log.Entry().WithError(err).Warnf("Invalid value '%v' of %v, cached remote configuration will not be used in case the remote location cannot be reached", value, remoteConfigOfflineFallbackEnv)
+		}
+	}
+	return &remoteConfigCache{dir: dir, ttl: ttl, offlineFallback: offlineFallback}
+}
+
+func (c *remoteConfigCache) indexFile() string {
+	return filepath.Join(c.dir, "index.json")
+}
+
+func (c *remoteConfigCache) contentFile(digest string) string {
+	return filepath.Join(c.dir, strings.Replace(digest, ":", string(filepath.Separator), 1))
+}
+
+func (c *remoteConfigCache) readIndex() map[string]remoteConfigCacheEntry {
+	index := map[string]remoteConfigCacheEntry{}
+	content, err := os.ReadFile(c.indexFile())
+	if err == nil {
+		if err := json.Unmarshal(content, &index); err != nil {
+			log.Entry().WithError(err).Debug("Ignoring invalid remote configuration cache index")
+		}
+	}
+	return index
+}
+
+// get returns the cached content of a source, the content is only returned if it matches the recorded digest.
+func (c *remoteConfigCache) get(source string) ([]byte, remoteConfigCacheEntry, bool) {
+	entry, ok := c.readIndex()[source]
+	if !ok {
+		return nil, entry, false
+	}
+	content, err := os.ReadFile(c.contentFile(entry.Digest))
+	if err != nil || contentDigest(content) != entry.Digest {
+		return nil, entry, false
+	}
+	return content, entry, true
+}
+
+func (c *remoteConfigCache) put(source string, content []byte) error {
+	digest := contentDigest(content)
+	file := c.contentFile(digest)
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(file, content); err != nil {
+		return err
+	}
+
+	// the index is updated by all steps, the lock avoids losing entries written by steps of parallel stages
+	unlock, err := c.lockIndex()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	index := c.readIndex()
+	index[source] = remoteConfigCacheEntry{Digest: digest, Fetched: time.Now(), Pinned: pinnedDigest(source)}
+	indexContent, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.indexFile(), indexContent)
+}
+
+// lockIndex acquires an exclusive lock of the cache index, the returned function releases it
+func (c *remoteConfigCache) lockIndex() (func(), error) {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(c.dir, "index.lock"), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, errors.Wrap(err, "failed to lock remote configuration cache index")
+	}
+	return func() {
+		_ = unlockFile(file)
+		file.Close()
+	}, nil
+}
+
+// writeFileAtomic avoids corrupted cache files in case multiple steps are executed in parallel.
+func writeFileAtomic(file string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func contentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// pinnedDigest returns the digest a source is pinned to, e.g. sha256:... of oci://registry/repo@sha256:..., or an
+// empty string if the source is not pinned
+func pinnedDigest(source string) string {
+	if !strings.HasPrefix(source, "oci://") {
+		return ""
+	}
+	_, digest, found := strings.Cut(source, "@sha256:")
+	if !found {
+		return ""
+	}
+	digest, _, _ = strings.Cut(digest, "#")
+	return "sha256:" + digest
+}
+
+// redactedSource returns the url with masked password and query values, e.g. tokens of presigned URLs
+func redactedSource(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.Redacted()
+	}
+	redacted := *u
+	query := redacted.Query()
+	for _, values := range query {
+		for i := range values {
+			values[i] = "xxxxx"
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.Redacted()
+}
+
+// cacheKey identifies a source in the cache index. The query is part of the identity of a source but may contain
+// credentials, hence sources with a query are identified by the digest of the url.
+func cacheKey(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.Redacted()
+	}
+	return contentDigest([]byte(u.String()))
+}
+
+// readRemoteFile fetches a remote configuration file.
+// The content is cached. Sources pinned to a digest are served from the cache if the content was fetched for this
+// digest. Other sources are served from the cache within the configured TTL and in case the remote location cannot
+// be reached. The latter is marked as stale so that it is rejected if signatures are enforced, since a stale copy
+// could bypass an update of the configuration.
+func readRemoteFile(source string, accessTokens map[string]string) (io.ReadCloser, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read url: %w", err)
+	}
+	fetch := remoteFetchers[u.Scheme]
+	cache := newRemoteConfigCache()
+	// credentials which are part of the url must not end up in the cache index or in the log
+	source = redactedSource(u)
+	key := cacheKey(u)
+
+	if content, entry, ok := cache.get(key); ok {
+		pinned := len(entry.Pinned) > 0 && entry.Pinned == pinnedDigest(source)
+		if pinned || (cache.ttl > 0 && time.Since(entry.Fetched) < cache.ttl) {
+			log.Entry().Debugf("Using cached content of '%v' (%v)", source, entry.Digest)
+			return &namedReadCloser{ReadCloser: io.NopCloser(bytes.NewReader(content)), name: source}, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFetchTimeout)
+	defer cancel()
+	content, err := fetch(ctx, u, accessTokens)
+	if err != nil {
+		if cached, entry, ok := cache.get(key); ok && cache.offlineFallback {
+			log.Entry().WithError(err).Warnf("Failed to fetch '%v', using cached content fetched at %v (%v)", source, entry.Fetched.Format(time.RFC3339), entry.Digest)
+			return &namedReadCloser{ReadCloser: io.NopCloser(bytes.NewReader(cached)), name: source, stale: true}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to fetch '%v'", source)
+	}
+	if err := cache.put(key, content); err != nil {
+		log.Entry().WithError(err).Debugf("Failed to cache content of '%v'", source)
+	}
+	return &namedReadCloser{ReadCloser: io.NopCloser(bytes.NewReader(content)), name: source}, nil
+}
+
+func fetchHTTPFile(_ context.Context, source *url.URL, accessTokens map[string]string) ([]byte, error) {
+	response, err := httpReadFile(source.String(), accessTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Close()
+	return io.ReadAll(response)
+}
+
+// fetchOCIArtifact downloads a file from an OCI artifact, e.g. oci://ghcr.io/org/defaults:1.0.0#defaults.yml.
+// The file is identified by the title annotation of the layer, without a fragment the artifact needs to contain exactly one layer.
+// Registry credentials are taken from the docker configuration.
+func fetchOCIArtifact(ctx context.Context, source *url.URL, _ map[string]string) ([]byte, error) {
+	ref, err := name.ParseReference(source.Host + source.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid OCI reference '%v'", source.Host+source.Path)
+	}
+	options := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain)}
+	descriptor, err := remote.Get(ref, options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get manifest of '%v'", ref)
+	}
+	manifest, err := v1.ParseManifest(bytes.NewReader(descriptor.Manifest))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest of '%v'", ref)
+	}
+
+	layer, err := selectOCILayer(manifest.Layers, source.Fragment)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find file in '%v'", ref)
+	}
+	blob, err := remote.Layer(ref.Context().Digest(layer.Digest.String()), options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get layer %v of '%v'", layer.Digest, ref)
+	}
+	reader, err := blob.Compressed()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download layer %v of '%v'", layer.Digest, ref)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func selectOCILayer(layers []v1.Descriptor, fileName string) (v1.Descriptor, error) {
+	if len(fileName) == 0 {
+		if len(layers) != 1 {
+			return v1.Descriptor{}, errors.Errorf("artifact contains %v files, please specify the file name, e.g. #defaults.yml", len(layers))
+		}
+		return layers[0], nil
+	}
+	for _, layer := range layers {
+		if layer.Annotations[ociTitleAnnotation] == fileName {
+			return layer, nil
+		}
+	}
+	return v1.Descriptor{}, errors.Errorf("artifact does not contain file '%v'", fileName)
+}
+
+// fetchS3Object downloads an object from AWS S3, e.g. s3://bucket/path/defaults.yml.
+// The credentials are determined via the default AWS credential chain.
+func fetchS3Object(ctx context.Context, source *url.URL, _ map[string]string) ([]byte, error) {
+	cfg, err := awsConfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS configuration")
+	}
+	bucket, key := source.Host, strings.TrimPrefix(source.Path, "/")
+	object, err := s3.NewFromConfig(cfg).GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object '%v' from bucket '%v'", key, bucket)
+	}
+	defer object.Body.Close()
+	return io.ReadAll(object.Body)
+}
+
+// fetchGCSObject downloads an object from Google Cloud Storage, e.g. gs://bucket/path/defaults.yml.
+// The credentials are determined via the application default credentials.
+func fetchGCSObject(ctx context.Context, source *url.URL, _ map[string]string) ([]byte, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCS client")
+	}
+	defer client.Close()
+	bucket, object := source.Host, strings.TrimPrefix(source.Path, "/")
+	reader, err := client.Bucket(bucket).Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read object '%v' from bucket '%v'", object, bucket)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// gitFileSource describes a file within a git repository, e.g. git+https://github.com/org/repo.git//path/defaults.yml@v1.0.0
+type gitFileSource struct {
+	repository string
+	file       string
+	ref        string
+}
+
+func parseGitFileSource(source *url.URL) (gitFileSource, error) {
+	location, ref := source.Path, ""
+	if i := strings.LastIndex(location, "@"); i >= 0 {
+		location, ref = location[:i], location[i+1:]
+	}
+	repository, file, found := strings.Cut(location, "//")
+	if !found {
+		repository, file, found = strings.Cut(location, ".git/")
+		repository += ".git"
+	}
+	if !found || len(file) == 0 {
+		return gitFileSource{}, errors.Errorf("no file specified in '%v', expected format %v://<host>/<repository>//<file>@<ref>", source.Redacted(), source.Scheme)
+	}
+	return gitFileSource{
+		repository: strings.TrimPrefix(source.Scheme, "git+") + "://" + source.Host + repository,
+		file:       path.Clean(file),
+		ref:        ref,
+	}, nil
+}
+
+// fetchGitFile reads a file from a git repository at the given tag or branch (default branch if not specified).
+// In case an access token is available for the host it is used for authentication.
+func fetchGitFile(ctx context.Context, source *url.URL, accessTokens map[string]string) ([]byte, error) {
+	gitSource, err := parseGitFileSource(source)
+	if err != nil {
+		return nil, err
+	}
+	options := &git.CloneOptions{URL: gitSource.repository, Depth: 1, SingleBranch: true, Tags: git.NoTags}
+	if token := accessTokens[source.Host]; len(token) > 0 {
+		options.Auth = &githttp.BasicAuth{Username: "piper", Password: token}
+	}
+
+	var repo *git.Repository
+	if len(gitSource.ref) == 0 {
+		repo, err = git.CloneContext(ctx, memory.NewStorage(), memfs.New(), options)
+	} else {
+		for _, refName := range []plumbing.ReferenceName{plumbing.NewTagReferenceName(gitSource.ref), plumbing.NewBranchReferenceName(gitSource.ref)} {
+			options.ReferenceName = refName
+			repo, err = git.CloneContext(ctx, memory.NewStorage(), memfs.New(), options)
+			if err == nil || !isMissingReference(err) {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone '%v' at '%v'", gitSource.repository, gitSource.ref)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to access worktree")
+	}
+	file, err := worktree.Filesystem.Open(gitSource.file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read '%v' from '%v'", gitSource.file, gitSource.repository)
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func isMissingReference(err error) bool {
+	var noMatchingRefSpec git.NoMatchingRefSpecError
+	return errors.As(err, &noMatchingRefSpec) || errors.Is(err, plumbing.ErrReferenceNotFound) || strings.Contains(err.Error(), "couldn't find remote ref")
+}
@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+package config
+
+import (
+	"os"
+)
+
+// lockFile is not supported on Windows, the index is still replaced atomically but concurrent updates may be lost
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,316 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+func readAllAndClose(t *testing.T, r io.ReadCloser) string {
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestIsRemoteFile(t *testing.T) {
+	assert.True(t, IsRemoteFile("https://github.com/org/repo/raw/main/defaults.yml"))
+	assert.True(t, IsRemoteFile("oci://ghcr.io/org/defaults:1.0.0"))
+	assert.True(t, IsRemoteFile("s3://bucket/defaults.yml"))
+	assert.True(t, IsRemoteFile("gs://bucket/defaults.yml"))
+	assert.True(t, IsRemoteFile("git+https://github.com/org/repo.git//defaults.yml@v1.0.0"))
+	assert.False(t, IsRemoteFile(".pipeline/defaults.yaml"))
+	assert.False(t, IsRemoteFile("ftp://host/defaults.yml"))
+}
+
+func TestParseGitFileSource(t *testing.T) {
+	tt := []struct {
+		source   string
+		expected gitFileSource
+		err      string
+	}{
+		{source: "git+https://github.com/org/repo.git//config/defaults.yml@v1.0.0", expected: gitFileSource{repository: "https://github.com/org/repo.git", file: "config/defaults.yml", ref: "v1.0.0"}},
+		{source: "git+https://github.com/org/repo.git/defaults.yml", expected: gitFileSource{repository: "https://github.com/org/repo.git", file: "defaults.yml"}},
+		{source: "git+file:///tmp/repo//defaults.yml@main", expected: gitFileSource{repository: "file:///tmp/repo", file: "defaults.yml", ref: "main"}},
+		{source: "git+https://github.com/org/repo@v1", err: "no file specified in 'git+https://github.com/org/repo@v1', expected format git+https://<host>/<repository>//<file>@<ref>"},
+	}
+	for _, test := range tt {
+		t.Run(test.source, func(t *testing.T) {
+			u, err := url.Parse(test.source)
+			require.NoError(t, err)
+			source, err := parseGitFileSource(u)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, source)
+			}
+		})
+	}
+}
+
+func TestReadRemoteFile(t *testing.T) {
+	t.Setenv(remoteConfigCacheDirEnv, t.TempDir())
+
+	var fetchErr error
+	fetches := 0
+	original := remoteFetchers["s3"]
+	remoteFetchers["s3"] = func(ctx context.Context, source *url.URL, accessTokens map[string]string) ([]byte, error) {
+		fetches++
+		return []byte("general:\n  fetch: " + source.Path), fetchErr
+	}
+	defer func() { remoteFetchers["s3"] = original }()
+
+	t.Run("fetch and cache", func(t *testing.T) {
+		r, err := OpenPiperFile("s3://bucket/defaults.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "general:\n  fetch: /defaults.yml", readAllAndClose(t, r))
+		assert.Equal(t, "s3://bucket/defaults.yml", r.(*namedReadCloser).name)
+
+		content, entry, ok := newRemoteConfigCache().get("s3://bucket/defaults.yml")
+		assert.True(t, ok)
+		assert.Equal(t, "general:\n  fetch: /defaults.yml", string(content))
+		assert.Equal(t, contentDigest(content), entry.Digest)
+	})
+
+	t.Run("offline fallback", func(t *testing.T) {
+		fetchErr = errors.New("network unreachable")
+		defer func() { fetchErr = nil }()
+
+		r, err := OpenPiperFile("s3://bucket/defaults.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "general:\n  fetch: /defaults.yml", readAllAndClose(t, r))
+		assert.True(t, r.(*namedReadCloser).stale)
+
+		_, err = OpenPiperFile("s3://bucket/other.yml", nil)
+		assert.EqualError(t, err, "failed to fetch 's3://bucket/other.yml': network unreachable")
+	})
+
+	t.Run("offline fallback disabled", func(t *testing.T) {
+		t.Setenv(remoteConfigOfflineFallbackEnv, "false")
+		fetchErr = errors.New("network unreachable")
+		defer func() { fetchErr = nil }()
+
+		_, err := OpenPiperFile("s3://bucket/defaults.yml", nil)
+		assert.EqualError(t, err, "failed to fetch 's3://bucket/defaults.yml': network unreachable")
+	})
+
+	t.Run("corrupted cache is not used", func(t *testing.T) {
+		t.Setenv(remoteConfigCacheTTLEnv, "1h")
+		cache := newRemoteConfigCache()
+		_, entry, _ := cache.get("s3://bucket/defaults.yml")
+		require.NoError(t, os.WriteFile(cache.contentFile(entry.Digest), []byte("tampered"), 0o600))
+		fetchErr = errors.New("network unreachable")
+		defer func() { fetchErr = nil }()
+
+		_, err := OpenPiperFile("s3://bucket/defaults.yml", nil)
+		assert.EqualError(t, err, "failed to fetch 's3://bucket/defaults.yml': network unreachable")
+	})
+
+	t.Run("query is not exposed", func(t *testing.T) {
+		source := "s3://bucket/query.yml?X-Amz-Signature=secret"
+		r, err := OpenPiperFile(source, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "s3://bucket/query.yml?X-Amz-Signature=xxxxx", r.(*namedReadCloser).name)
+		index, err := os.ReadFile(newRemoteConfigCache().indexFile())
+		require.NoError(t, err)
+		assert.NotContains(t, string(index), "secret")
+
+		_, _, ok := newRemoteConfigCache().get(cacheKey(&url.URL{Scheme: "s3", Host: "bucket", Path: "/query.yml", RawQuery: "X-Amz-Signature=other"}))
+		assert.False(t, ok, "the query is part of the identity of the source")
+
+		fetchErr = errors.New("network unreachable")
+		defer func() { fetchErr = nil }()
+		logBuffer := new(bytes.Buffer)
+		oldLogOutput := log.Entry().Logger.Out
+		log.Entry().Logger.Out = logBuffer
+		defer func() { log.Entry().Logger.Out = oldLogOutput }()
+		r, err = OpenPiperFile(source, nil)
+		require.NoError(t, err)
+		assert.True(t, r.(*namedReadCloser).stale)
+		assert.Contains(t, logBuffer.String(), "Failed to fetch 's3://bucket/query.yml?X-Amz-Signature=xxxxx'")
+		assert.NotContains(t, logBuffer.String(), "secret")
+
+		_, err = OpenPiperFile("s3://bucket/other.yml?token=secret", nil)
+		assert.EqualError(t, err, "failed to fetch 's3://bucket/other.yml?token=xxxxx': network unreachable")
+	})
+
+	t.Run("cache ttl", func(t *testing.T) {
+		t.Setenv(remoteConfigCacheTTLEnv, "1h")
+		_, err := OpenPiperFile("s3://bucket/ttl.yml", nil)
+		require.NoError(t, err)
+		fetches = 0
+		_, err = OpenPiperFile("s3://bucket/ttl.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, fetches)
+	})
+}
+
+func TestReadPinnedRemoteFile(t *testing.T) {
+	t.Setenv(remoteConfigCacheDirEnv, t.TempDir())
+	source := "oci://ghcr.io/someorg/defaults@sha256:4711#defaults.yml"
+
+	fetches := 0
+	original := remoteFetchers["oci"]
+	remoteFetchers["oci"] = func(ctx context.Context, source *url.URL, accessTokens map[string]string) ([]byte, error) {
+		fetches++
+		if fetches > 1 {
+			return nil, errors.New("registry unreachable")
+		}
+		return []byte("general:\n  pinned: true"), nil
+	}
+	defer func() { remoteFetchers["oci"] = original }()
+
+	r, err := OpenPiperFile(source, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "general:\n  pinned: true", readAllAndClose(t, r))
+	_, entry, ok := newRemoteConfigCache().get(source)
+	require.True(t, ok)
+	assert.Equal(t, "sha256:4711", entry.Pinned)
+
+	r, err = OpenPiperFile(source, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "general:\n  pinned: true", readAllAndClose(t, r))
+	assert.Equal(t, 1, fetches)
+}
+
+func TestPinnedDigest(t *testing.T) {
+	assert.Equal(t, "sha256:4711", pinnedDigest("oci://ghcr.io/someorg/defaults@sha256:4711#defaults.yml"))
+	assert.Equal(t, "sha256:4711", pinnedDigest("oci://ghcr.io/someorg/defaults@sha256:4711"))
+	assert.Equal(t, "", pinnedDigest("oci://ghcr.io/someorg/defaults:1.0.0"))
+	assert.Equal(t, "", pinnedDigest("https://example.org/defaults.yml@sha256:4711"))
+}
+
+func TestRemoteConfigCacheConcurrentPut(t *testing.T) {
+	cache := &remoteConfigCache{dir: t.TempDir()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, cache.put(fmt.Sprintf("s3://bucket/defaults-%v.yml", i), []byte(fmt.Sprint(i))))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, cache.readIndex(), 20)
+}
+
+func TestFetchGitFile(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+
+	commit := func(content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "config"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "defaults.yml"), []byte(content), 0o600))
+		_, err := worktree.Add("config/defaults.yml")
+		require.NoError(t, err)
+		_, err = worktree.Commit("update", &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+	}
+	commit("version: 1")
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", head.Hash(), nil)
+	require.NoError(t, err)
+	commit("version: 2")
+
+	fetch := func(source string) (string, error) {
+		u, err := url.Parse(source)
+		require.NoError(t, err)
+		content, err := fetchGitFile(context.Background(), u, nil)
+		return string(content), err
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		content, err := fetch("git+file://" + filepath.ToSlash(dir) + "//config/defaults.yml@v1.0.0")
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 1", content)
+	})
+
+	t.Run("default branch", func(t *testing.T) {
+		content, err := fetch("git+file://" + filepath.ToSlash(dir) + "//config/defaults.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 2", content)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := fetch("git+file://" + filepath.ToSlash(dir) + "//missing.yml@v1.0.0")
+		assert.ErrorContains(t, err, "failed to read 'missing.yml'")
+	})
+
+	t.Run("missing ref", func(t *testing.T) {
+		_, err := fetch("git+file://" + filepath.ToSlash(dir) + "//config/defaults.yml@v9")
+		assert.ErrorContains(t, err, "failed to clone")
+	})
+}
+
+func TestFetchOCIArtifact(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	artifact, err := mutate.Append(empty.Image,
+		mutate.Addendum{Layer: static.NewLayer([]byte("general:\n  buildTool: npm\n"), types.MediaType("application/yaml")), Annotations: map[string]string{ociTitleAnnotation: "defaults.yml"}},
+		mutate.Addendum{Layer: static.NewLayer([]byte("stages: {}\n"), types.MediaType("application/yaml")), Annotations: map[string]string{ociTitleAnnotation: "stages.yml"}},
+	)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(host + "/piper/defaults:1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, artifact))
+
+	fetch := func(source string) (string, error) {
+		u, err := url.Parse(source)
+		require.NoError(t, err)
+		content, err := fetchOCIArtifact(context.Background(), u, nil)
+		return string(content), err
+	}
+
+	t.Run("file by title", func(t *testing.T) {
+		content, err := fetch("oci://" + host + "/piper/defaults:1.0.0#defaults.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, "general:\n  buildTool: npm\n", content)
+	})
+
+	t.Run("file not specified", func(t *testing.T) {
+		_, err := fetch("oci://" + host + "/piper/defaults:1.0.0")
+		assert.ErrorContains(t, err, "artifact contains 2 files, please specify the file name, e.g. #defaults.yml")
+	})
+
+	t.Run("unknown file", func(t *testing.T) {
+		_, err := fetch("oci://" + host + "/piper/defaults:1.0.0#other.yml")
+		assert.ErrorContains(t, err, "artifact does not contain file 'other.yml'")
+	})
+}
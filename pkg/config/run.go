@@ -22,6 +22,9 @@ type RunConfig struct {
 type RunConfigV1 struct {
 	RunConfig
 	PipelineConfig PipelineDefinitionV1
+	// ChangedFiles allows to overwrite how the changed files are determined for the change-based step conditions.
+	// If not set, the changes are determined via git.
+	ChangedFiles ChangedFilesResolver
 }
 
 type StageConfig struct {
@@ -76,6 +79,9 @@ type StepCondition struct {
 	NpmScript                 string                   `json:"npmScript,omitempty"`
 	CommonPipelineEnvironment map[string]interface{}   `json:"commonPipelineEnvironment,omitempty"`
 	PipelineEnvironmentFilled string                   `json:"pipelineEnvironmentFilled,omitempty"`
+	OnlyIfChanged             []string                 `json:"onlyIfChanged,omitempty"`
+	FilePatternChanged        string                   `json:"filePatternChanged,omitempty"`
+	If                        string                   `json:"if,omitempty"`
 }
 
 func (r *RunConfigV1) InitRunConfigV1(config *Config, utils piperutils.FileUtils, envRootPath string) error {
@@ -130,5 +136,19 @@ func (r *RunConfigV1) LoadConditionsV1() error {
 	if err != nil {
 		return errors.Errorf("format of configuration is invalid %q: %v", content, err)
 	}
+
+	// report invalid expressions early instead of at the time the respective condition is evaluated
+	for _, stage := range r.PipelineConfig.Spec.Stages {
+		for _, step := range stage.Steps {
+			for _, condition := range append(append([]StepCondition{}, step.Conditions...), step.NotActiveConditions...) {
+				if len(condition.If) == 0 {
+					continue
+				}
+				if _, err := parseExpression(condition.If); err != nil {
+					return errors.Wrapf(err, "invalid condition of step '%v' in stage '%v': expression '%v'", step.Name, stage.DisplayName, condition.If)
+				}
+			}
+		}
+	}
 	return nil
 }
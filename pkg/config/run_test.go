@@ -53,6 +53,12 @@ func TestInitRunConfigV1(t *testing.T) {
 			stageConfig:      "spec:\n  stages:\n  - name: testStage\n    displayName: testStage\n    steps:\n    - name: testStep\n      conditions:\n      - config:\n          configKey1:\n          - configVal1\n          configKey2:\n          - configVal2",
 			errorContains:    "failed to evaluate step conditions",
 		},
+		{
+			name:             "error - invalid expression",
+			runStepsExpected: map[string]map[string]bool{},
+			stageConfig:      "spec:\n  stages:\n  - name: testStage\n    displayName: testStage\n    steps:\n    - name: testStep\n      conditions:\n      - if: branch == 'main' &&",
+			errorContains:    "invalid condition of step 'testStep' in stage 'testStage': expression 'branch == 'main' &&': unexpected end of expression",
+		},
 	}
 
 	filesMock := mock.FilesMock{}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/config/interpolation"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/secretprovider"
+)
+
+// secretProviderPath defines the secret store used for secret references without scheme, e.g. awssm://piper
+const secretProviderPath = "secretProviderPath"
+
+// SecretProvider retrieves secrets from a secret store other than Vault
+type SecretProvider interface {
+	// GetSecret returns the fields of the secret at the address, i.e. the secret reference without its scheme
+	GetSecret(address string) (map[string]string, error)
+}
+
+// secretProviders creates the providers of secret references by scheme, e.g. awssm://piper/github.
+var secretProviders = map[string]func() (SecretProvider, error){
+	"awssm": func() (SecretProvider, error) {
+		provider, err := secretprovider.NewAWSSecretsManager()
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	},
+	"azkv": func() (SecretProvider, error) {
+		provider, err := secretprovider.NewAzureKeyVault()
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	},
+	"gcpsm": func() (SecretProvider, error) {
+		provider, err := secretprovider.NewGCPSecretManager()
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	},
+	"sops": func() (SecretProvider, error) {
+		return secretprovider.NewSOPS(&command.Command{}), nil
+	},
+}
+
+// secretAddress returns the scheme and the address of the secret a vaultSecret or vaultSecretFile reference points to.
+// An empty scheme means that the secret is read from Vault.
+func secretAddress(ref *ResourceReference, config map[string]interface{}) (string, string) {
+	secretName := ref.Default
+	if providedName, ok := config[ref.Name].(string); ok && providedName != "" {
+		secretName = providedName
+	}
+	if scheme, address, found := strings.Cut(secretName, "://"); found {
+		return scheme, address
+	}
+	rootPath, _ := config[secretProviderPath].(string)
+	scheme, address, found := strings.Cut(rootPath, "://")
+	if !found {
+		return "", secretName
+	}
+	// the secrets of a sops file are its top-level keys
+	separator := "/"
+	if scheme == "sops" {
+		separator = "#"
+	}
+	if address = strings.TrimSuffix(address, separator); len(address) == 0 {
+		return scheme, secretName
+	}
+	return scheme, address + separator + secretName
+}
+
+// resolveAllSecretProviderReferences retrieves the step's secrets whose references point to a secret store other than Vault
+func resolveAllSecretProviderReferences(config *StepConfig, params []StepParameters) {
+	providers := map[string]SecretProvider{}
+	for _, param := range params {
+		if ref := param.GetReference("vaultSecret"); ref != nil {
+			resolveSecretProviderReference(ref, config, param, providers)
+		}
+		if ref := param.GetReference("vaultSecretFile"); ref != nil {
+			resolveSecretProviderReference(ref, config, param, providers)
+		}
+	}
+}
+
+func resolveSecretProviderReference(ref *ResourceReference, config *StepConfig, param StepParameters, providers map[string]SecretProvider) {
+	scheme, address := secretAddress(ref, config.Config)
+	if len(scheme) == 0 {
+		return
+	}
+	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
+	if paramValue, _ := config.Config[param.Name].(string); vaultDisableOverwrite && paramValue != "" {
+		log.Entry().Debugf("Not fetching '%s' from %s since it has already been set", param.Name, scheme)
+		return
+	}
+
+	log.Entry().Infof("Resolving '%s' from %s", param.Name, scheme)
+	address, ok := interpolation.ResolveString(address, config.Config)
+	if !ok {
+		log.Entry().Warnf("  failed, could not resolve the variables of '%s'", address)
+		return
+	}
+	provider, ok := providers[scheme]
+	if !ok {
+		newProvider, known := secretProviders[scheme]
+		if !known {
+			log.Entry().Warnf("  failed, unknown secret provider '%s'", scheme)
+			return
+		}
+		var err error
+		if provider, err = newProvider(); err != nil {
+			log.Entry().WithError(err).Warnf("  failed to initialize secret provider '%s'", scheme)
+		}
+		// a provider which failed to initialize is not initialized again for the other parameters
+		providers[scheme] = provider
+	}
+	if provider == nil {
+		return
+	}
+
+	secret, err := provider.GetSecret(address)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Couldn't fetch secret '%s'", address)
+		return
+	}
+	secretValue := lookupSecretField(secret, &param)
+	if secretValue == nil {
+		log.Entry().Warn("  failed")
+		return
+	}
+	log.Entry().Infof("  succeeded with secret '%s'", address)
+	setSecretParameter(ref, config, param, *secretValue)
+}
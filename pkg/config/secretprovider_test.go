@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type secretProviderMock struct {
+	secrets   map[string]map[string]string
+	addresses []string
+}
+
+func (p *secretProviderMock) GetSecret(address string) (map[string]string, error) {
+	p.addresses = append(p.addresses, address)
+	if secret, ok := p.secrets[address]; ok {
+		return secret, nil
+	}
+	return nil, errors.New("secret not found")
+}
+
+func mockSecretProvider(t *testing.T, scheme string, provider SecretProvider, err error) {
+	original, registered := secretProviders[scheme]
+	secretProviders[scheme] = func() (SecretProvider, error) { return provider, err }
+	t.Cleanup(func() {
+		if registered {
+			secretProviders[scheme] = original
+		} else {
+			delete(secretProviders, scheme)
+		}
+	})
+}
+
+func TestSecretAddress(t *testing.T) {
+	t.Parallel()
+	ref := &ResourceReference{Name: "githubTokenVaultSecretName", Default: "github"}
+	tt := []struct {
+		name            string
+		config          map[string]interface{}
+		expectedScheme  string
+		expectedAddress string
+	}{
+		{name: "Vault", config: map[string]interface{}{}, expectedScheme: "", expectedAddress: "github"},
+		{name: "reference with scheme", config: map[string]interface{}{"githubTokenVaultSecretName": "azkv://my-vault/github"}, expectedScheme: "azkv", expectedAddress: "my-vault/github"},
+		{name: "reference with scheme and provider path", config: map[string]interface{}{"githubTokenVaultSecretName": "gcpsm://project/github", secretProviderPath: "awssm://piper"}, expectedScheme: "gcpsm", expectedAddress: "project/github"},
+		{name: "provider path", config: map[string]interface{}{secretProviderPath: "awssm://piper/"}, expectedScheme: "awssm", expectedAddress: "piper/github"},
+		{name: "provider path without location", config: map[string]interface{}{secretProviderPath: "awssm://"}, expectedScheme: "awssm", expectedAddress: "github"},
+		{name: "provider path of sops file", config: map[string]interface{}{secretProviderPath: "sops://.pipeline/secrets.enc.yaml"}, expectedScheme: "sops", expectedAddress: ".pipeline/secrets.enc.yaml#github"},
+		{name: "Vault with provider path without scheme", config: map[string]interface{}{secretProviderPath: "piper"}, expectedScheme: "", expectedAddress: "github"},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			scheme, address := secretAddress(ref, test.config)
+			assert.Equal(t, test.expectedScheme, scheme)
+			assert.Equal(t, test.expectedAddress, address)
+		})
+	}
+}
+
+func TestResolveAllSecretProviderReferences(t *testing.T) {
+	t.Run("secrets from provider", func(t *testing.T) {
+		provider := &secretProviderMock{secrets: map[string]map[string]string{
+			"piper/github": {"githubToken": "ghp_token"},
+			"piper/sonar":  {"sonarToken": "sonarToken"},
+		}}
+		mockSecretProvider(t, "awssm", provider, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{secretProviderPath: "awssm://piper"}}
+		tokenParam := stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")
+		sonarParam := stepParam("sonarToken", "vaultSecret", "sonarVaultSecretName", "sonar")
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{tokenParam, sonarParam})
+
+		assert.Equal(t, "ghp_token", stepConfig.Config["githubToken"])
+		assert.Equal(t, "sonarToken", stepConfig.Config["sonarToken"])
+	})
+
+	t.Run("secret file from provider per reference", func(t *testing.T) {
+		dir := t.TempDir()
+		VaultSecretFileDirectory = dir
+		defer func() { VaultSecretFileDirectory = "" }()
+		provider := &secretProviderMock{secrets: map[string]map[string]string{"my-vault/kube": {"kubeConfig": "apiVersion: v1"}}}
+		mockSecretProvider(t, "azkv", provider, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{"kubeVaultSecretName": "azkv://my-vault/kube"}}
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{stepParam("kubeConfig", "vaultSecretFile", "kubeVaultSecretName", "kube")})
+
+		if assert.Contains(t, stepConfig.Config["kubeConfig"], dir) {
+			content, err := os.ReadFile(stepConfig.Config["kubeConfig"].(string))
+			assert.NoError(t, err)
+			assert.Equal(t, "apiVersion: v1", string(content))
+		}
+	})
+
+	t.Run("Vault references are skipped", func(t *testing.T) {
+		provider := &secretProviderMock{}
+		mockSecretProvider(t, "awssm", provider, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")})
+
+		assert.Nil(t, stepConfig.Config["githubToken"])
+		assert.Empty(t, provider.addresses)
+	})
+
+	t.Run("overwrite disabled", func(t *testing.T) {
+		provider := &secretProviderMock{}
+		mockSecretProvider(t, "awssm", provider, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{secretProviderPath: "awssm://piper", "vaultDisableOverwrite": true, "githubToken": "configured"}}
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")})
+
+		assert.Equal(t, "configured", stepConfig.Config["githubToken"])
+		assert.Empty(t, provider.addresses)
+	})
+
+	t.Run("provider fails to initialize once", func(t *testing.T) {
+		calls := 0
+		secretProviders["failing"] = func() (SecretProvider, error) {
+			calls++
+			return nil, errors.New("no credentials")
+		}
+		defer delete(secretProviders, "failing")
+		stepConfig := StepConfig{Config: map[string]interface{}{secretProviderPath: "failing://piper"}}
+		tokenParam := stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")
+		sonarParam := stepParam("sonarToken", "vaultSecret", "sonarVaultSecretName", "sonar")
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{tokenParam, sonarParam})
+
+		assert.Equal(t, 1, calls)
+		assert.Nil(t, stepConfig.Config["githubToken"])
+	})
+
+	t.Run("unknown provider and missing secret", func(t *testing.T) {
+		provider := &secretProviderMock{}
+		mockSecretProvider(t, "awssm", provider, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{"githubVaultSecretName": "unknown://github", "sonarVaultSecretName": "awssm://sonar"}}
+		tokenParam := stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")
+		sonarParam := stepParam("sonarToken", "vaultSecret", "sonarVaultSecretName", "sonar")
+
+		resolveAllSecretProviderReferences(&stepConfig, []StepParameters{tokenParam, sonarParam})
+
+		assert.Nil(t, stepConfig.Config["githubToken"])
+		assert.Nil(t, stepConfig.Config["sonarToken"])
+		assert.Equal(t, []string{"sonar"}, provider.addresses)
+	})
+}
+
+func TestVaultSkipsSecretProviderReferences(t *testing.T) {
+	vaultMock := &mocks.VaultClient{}
+	stepConfig := StepConfig{Config: map[string]interface{}{"vaultPath": "team1", "githubVaultSecretName": "awssm://piper/github"}}
+
+	resolveAllVaultReferences(&stepConfig, vaultMock, []StepParameters{stepParam("githubToken", "vaultSecret", "githubVaultSecretName", "github")})
+
+	vaultMock.AssertNotCalled(t, "GetKvSecret", mock.Anything)
+}
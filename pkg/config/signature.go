@@ -0,0 +1,343 @@
+package config
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// SignaturePolicyOff disables the verification of signatures
+	SignaturePolicyOff = "off"
+	// SignaturePolicyWarn logs a warning in case a signature is missing or invalid
+	SignaturePolicyWarn = "warn"
+	// SignaturePolicyEnforce fails in case a signature is missing or invalid
+	SignaturePolicyEnforce = "enforce"
+)
+
+// SignatureVerification defines how detached signatures of shared configuration files (custom defaults, stage conditions) are verified.
+//
+// Supported are signatures created with
+//   - cosign using a key pair (cosign sign-blob --key), stored as <file>.sig or as bundle <file>.bundle
+//   - minisign, stored as <file>.minisig
+//
+// Keyless signatures are not supported since they require access to the sigstore transparency log.
+type SignatureVerification struct {
+	Policy string
+	// PublicKeys contains the trusted public keys, either a PEM encoded public key (cosign) or a minisign public key.
+	// Instead of the key itself a path to a file containing the key can be provided.
+	PublicKeys []string
+}
+
+type signatureVerifier interface {
+	// extensions returns the file extensions of the detached signatures supported by the verifier
+	extensions() []string
+	verify(content, signature []byte, extension string) error
+}
+
+// Enabled returns true if signatures need to be verified.
+func (v *SignatureVerification) Enabled() bool {
+	return v != nil && len(v.Policy) > 0 && v.Policy != SignaturePolicyOff
+}
+
+// Validate checks the policy and the public keys.
+func (v *SignatureVerification) Validate() error {
+	if !v.Enabled() {
+		return nil
+	}
+	if v.Policy != SignaturePolicyWarn && v.Policy != SignaturePolicyEnforce {
+		return errors.Errorf("invalid signature policy '%v', possible values: %v, %v, %v", v.Policy, SignaturePolicyOff, SignaturePolicyWarn, SignaturePolicyEnforce)
+	}
+	_, err := v.verifiers()
+	return err
+}
+
+func (v *SignatureVerification) verifiers() ([]signatureVerifier, error) {
+	if len(v.PublicKeys) == 0 {
+		return nil, errors.New("no public key configured for the verification of signatures")
+	}
+	verifiers := []signatureVerifier{}
+	for _, key := range v.PublicKeys {
+		content := []byte(key)
+		if !strings.Contains(key, "\n") {
+			if fileContent, err := os.ReadFile(key); err == nil {
+				content = fileContent
+			}
+		}
+		verifier, err := parsePublicKey(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid public key '%v'", abbreviate(key))
+		}
+		verifiers = append(verifiers, verifier)
+	}
+	return verifiers, nil
+}
+
+// OpenFile wraps the given function to open configuration files so that the detached signature of each file is verified.
+// Signatures are expected next to the file, e.g. https://host/defaults.yml.sig for https://host/defaults.yml.
+func (v *SignatureVerification) OpenFile(openFile func(string, map[string]string) (io.ReadCloser, error)) func(string, map[string]string) (io.ReadCloser, error) {
+	if !v.Enabled() {
+		return openFile
+	}
+	return func(name string, accessTokens map[string]string) (io.ReadCloser, error) {
+		file, err := openFile(name, accessTokens)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read '%v'", name)
+		}
+
+		if named, ok := file.(*namedReadCloser); ok && named.stale && v.Policy == SignaturePolicyEnforce {
+			return nil, errors.Errorf("cached content of '%v' is not accepted since the remote location could not be reached and signatures are enforced", name)
+		}
+		if err := v.verify(name, content, openFile, accessTokens); err != nil {
+			if v.Policy == SignaturePolicyEnforce {
+				return nil, errors.Wrapf(err, "signature verification of '%v' failed", name)
+			}
+			log.Entry().WithError(err).Warnf("Signature verification of '%v' failed", name)
+		} else {
+			log.Entry().Infof("Signature of '%v' verified", name)
+		}
+
+		fileName := readerName(file)
+		if len(fileName) == 0 {
+			fileName = name
+		}
+		return &namedReadCloser{ReadCloser: io.NopCloser(bytes.NewReader(content)), name: fileName}, nil
+	}
+}
+
+func (v *SignatureVerification) verify(name string, content []byte, openFile func(string, map[string]string) (io.ReadCloser, error), accessTokens map[string]string) error {
+	verifiers, err := v.verifiers()
+	if err != nil {
+		return err
+	}
+
+	signatures := map[string][]byte{}
+	var verificationErrors []string
+	for _, verifier := range verifiers {
+		for _, extension := range verifier.extensions() {
+			signature, ok := signatures[extension]
+			if !ok {
+				signature = readSignature(signatureLocation(name, extension), openFile, accessTokens)
+				signatures[extension] = signature
+			}
+			if signature == nil {
+				continue
+			}
+			err := verifier.verify(content, signature, extension)
+			if err == nil {
+				return nil
+			}
+			verificationErrors = append(verificationErrors, fmt.Sprintf("%v: %v", extension, err))
+		}
+	}
+	if len(verificationErrors) == 0 {
+		return errors.New("no signature found")
+	}
+	return errors.Errorf("no valid signature found (%v)", strings.Join(verificationErrors, "; "))
+}
+
+func readSignature(location string, openFile func(string, map[string]string) (io.ReadCloser, error), accessTokens map[string]string) []byte {
+	file, err := openFile(location, accessTokens)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("No signature available at '%v'", location)
+		return nil
+	}
+	defer file.Close()
+	signature, err := io.ReadAll(file)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("Failed to read signature '%v'", location)
+		return nil
+	}
+	return signature
+}
+
+// signatureLocation returns the location of the detached signature, for git sources the signature is expected at the same ref.
+func signatureLocation(name, extension string) string {
+	if strings.HasPrefix(name, "git+") {
+		if i := strings.LastIndex(name, "@"); i > strings.Index(name, "//") {
+			return name[:i] + extension + name[i:]
+		}
+	}
+	return name + extension
+}
+
+func parsePublicKey(content []byte) (signatureVerifier, error) {
+	if block, _ := pem.Decode(content); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse PEM public key")
+		}
+		return &cosignVerifier{publicKey: key}, nil
+	}
+	return parseMinisignPublicKey(content)
+}
+
+// cosignVerifier verifies signatures created via 'cosign sign-blob --key'.
+type cosignVerifier struct {
+	publicKey crypto.PublicKey
+}
+
+func (c *cosignVerifier) extensions() []string {
+	return []string{".sig", ".bundle"}
+}
+
+func (c *cosignVerifier) verify(content, signature []byte, extension string) error {
+	if extension == ".bundle" {
+		var err error
+		if signature, err = signatureFromBundle(signature); err != nil {
+			return err
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return errors.Wrap(err, "signature is not base64 encoded")
+	}
+
+	digest := sha256.Sum256(content)
+	switch key := c.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(key, digest[:], decoded) {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, content, decoded) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], decoded) == nil {
+			return nil
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", c.publicKey)
+	}
+	return errors.New("invalid signature")
+}
+
+// signatureFromBundle extracts the signature from a cosign bundle (cosign sign-blob --bundle) or a sigstore bundle.
+func signatureFromBundle(content []byte) ([]byte, error) {
+	var bundle struct {
+		Base64Signature  string `json:"base64Signature"`
+		MessageSignature struct {
+			Signature string `json:"signature"`
+		} `json:"messageSignature"`
+	}
+	if err := json.Unmarshal(content, &bundle); err != nil {
+		return nil, errors.Wrap(err, "failed to parse bundle")
+	}
+	if len(bundle.Base64Signature) > 0 {
+		return []byte(bundle.Base64Signature), nil
+	}
+	if len(bundle.MessageSignature.Signature) > 0 {
+		return []byte(bundle.MessageSignature.Signature), nil
+	}
+	return nil, errors.New("bundle does not contain a signature")
+}
+
+// minisignVerifier verifies signatures created via minisign, see https://jedisct1.github.io/minisign/
+type minisignVerifier struct {
+	keyID     []byte
+	publicKey ed25519.PublicKey
+}
+
+func parseMinisignPublicKey(content []byte) (*minisignVerifier, error) {
+	lines := nonCommentLines(string(content), "untrusted comment:")
+	if len(lines) != 1 {
+		return nil, errors.New("neither a PEM encoded nor a minisign public key")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(decoded) != 2+8+ed25519.PublicKeySize || string(decoded[:2]) != "Ed" {
+		return nil, errors.New("neither a PEM encoded nor a minisign public key")
+	}
+	return &minisignVerifier{keyID: decoded[2:10], publicKey: ed25519.PublicKey(decoded[10:])}, nil
+}
+
+func (m *minisignVerifier) extensions() []string {
+	return []string{".minisig"}
+}
+
+func (m *minisignVerifier) verify(content, signature []byte, _ string) error {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(string(signature)), "\r\n", "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("invalid minisign signature format")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(decoded) != 2+8+ed25519.SignatureSize {
+		return errors.New("invalid minisign signature format")
+	}
+	algorithm, keyID, sig := string(decoded[:2]), decoded[2:10], decoded[10:]
+	if !bytes.Equal(keyID, m.keyID) {
+		return errors.Errorf("signature was created with key %X but public key is %X", reverse(keyID), reverse(m.keyID))
+	}
+
+	message := content
+	switch algorithm {
+	case "ED":
+		hash := blake2b.Sum512(content)
+		message = hash[:]
+	case "Ed":
+	default:
+		return errors.Errorf("unsupported minisign signature algorithm '%v'", algorithm)
+	}
+	if !ed25519.Verify(m.publicKey, message, sig) {
+		return errors.New("invalid signature")
+	}
+
+	globalSignature, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return errors.New("invalid minisign signature format")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(m.publicKey, append(append([]byte{}, sig...), trustedComment...), globalSignature) {
+		return errors.New("invalid signature of trusted comment")
+	}
+	return nil
+}
+
+func nonCommentLines(content, commentPrefix string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 && !strings.HasPrefix(line, commentPrefix) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// reverse returns the key ID in the byte order minisign uses for display
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
+
+func abbreviate(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+	return s
+}
@@ -0,0 +1,172 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func cosignTestKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+}
+
+func cosignSign(t *testing.T, key *ecdsa.PrivateKey, content string) string {
+	digest := sha256.Sum256([]byte(content))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func minisignTestKey(t *testing.T) (ed25519.PrivateKey, []byte, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	encoded := base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), publicKey...))
+	return privateKey, keyID, "untrusted comment: minisign public key\n" + encoded + "\n"
+}
+
+func minisignSign(privateKey ed25519.PrivateKey, keyID []byte, content string) string {
+	hash := blake2b.Sum512([]byte(content))
+	signature := ed25519.Sign(privateKey, hash[:])
+	trustedComment := "timestamp:1700000000\tfile:defaults.yml\thashed"
+	globalSignature := ed25519.Sign(privateKey, append(append([]byte{}, signature...), trustedComment...))
+	return fmt.Sprintf("untrusted comment: signature from minisign secret key\n%v\ntrusted comment: %v\n%v\n",
+		base64.StdEncoding.EncodeToString(append(append([]byte("ED"), keyID...), signature...)),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(globalSignature))
+}
+
+func signatureOpenFileMock(files map[string]string) func(string, map[string]string) (io.ReadCloser, error) {
+	return func(name string, _ map[string]string) (io.ReadCloser, error) {
+		content, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+func TestSignatureVerification(t *testing.T) {
+	const defaults = "general:\n  buildTool: maven\n"
+	cosignKey, cosignPublicKey := cosignTestKey(t)
+	minisignKey, minisignKeyID, minisignPublicKey := minisignTestKey(t)
+	_, otherPublicKey := cosignTestKey(t)
+
+	files := map[string]string{
+		"https://host/cosign.yml":                 defaults,
+		"https://host/cosign.yml.sig":             cosignSign(t, cosignKey, defaults),
+		"https://host/bundle.yml":                 defaults,
+		"https://host/bundle.yml.bundle":          fmt.Sprintf(`{"base64Signature": %q}`, cosignSign(t, cosignKey, defaults)),
+		"https://host/minisign.yml":               defaults,
+		"https://host/minisign.yml.minisig":       minisignSign(minisignKey, minisignKeyID, defaults),
+		"https://host/tampered.yml":               defaults + "  verbose: true\n",
+		"https://host/tampered.yml.sig":           cosignSign(t, cosignKey, defaults),
+		"https://host/tampered.yml.minisig":       minisignSign(minisignKey, minisignKeyID, defaults),
+		"https://host/unsigned.yml":               defaults,
+		"git+https://host/repo.git//d.yml@v1":     defaults,
+		"git+https://host/repo.git//d.yml.sig@v1": cosignSign(t, cosignKey, defaults),
+	}
+	openFile := signatureOpenFileMock(files)
+
+	t.Run("valid signatures", func(t *testing.T) {
+		verification := &SignatureVerification{Policy: SignaturePolicyEnforce, PublicKeys: []string{otherPublicKey, cosignPublicKey, minisignPublicKey}}
+		require.NoError(t, verification.Validate())
+		for _, name := range []string{"https://host/cosign.yml", "https://host/bundle.yml", "https://host/minisign.yml", "git+https://host/repo.git//d.yml@v1"} {
+			file, err := verification.OpenFile(openFile)(name, nil)
+			if assert.NoError(t, err, name) {
+				assert.Equal(t, defaults, readAllAndClose(t, file))
+			}
+		}
+	})
+
+	t.Run("enforce", func(t *testing.T) {
+		verification := &SignatureVerification{Policy: SignaturePolicyEnforce, PublicKeys: []string{cosignPublicKey, minisignPublicKey}}
+		_, err := verification.OpenFile(openFile)("https://host/tampered.yml", nil)
+		assert.EqualError(t, err, "signature verification of 'https://host/tampered.yml' failed: no valid signature found (.sig: invalid signature; .minisig: invalid signature)")
+
+		_, err = verification.OpenFile(openFile)("https://host/unsigned.yml", nil)
+		assert.EqualError(t, err, "signature verification of 'https://host/unsigned.yml' failed: no signature found")
+	})
+
+	t.Run("stale content", func(t *testing.T) {
+		staleOpenFile := func(name string, accessTokens map[string]string) (io.ReadCloser, error) {
+			file, err := openFile(name, accessTokens)
+			if err != nil {
+				return nil, err
+			}
+			return &namedReadCloser{ReadCloser: file, name: name, stale: true}, nil
+		}
+
+		verification := &SignatureVerification{Policy: SignaturePolicyEnforce, PublicKeys: []string{cosignPublicKey}}
+		_, err := verification.OpenFile(staleOpenFile)("https://host/cosign.yml", nil)
+		assert.EqualError(t, err, "cached content of 'https://host/cosign.yml' is not accepted since the remote location could not be reached and signatures are enforced")
+
+		verification = &SignatureVerification{Policy: SignaturePolicyWarn, PublicKeys: []string{cosignPublicKey}}
+		file, err := verification.OpenFile(staleOpenFile)("https://host/cosign.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, readAllAndClose(t, file))
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		verification := &SignatureVerification{Policy: SignaturePolicyWarn, PublicKeys: []string{cosignPublicKey}}
+		file, err := verification.OpenFile(openFile)("https://host/tampered.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, files["https://host/tampered.yml"], readAllAndClose(t, file))
+	})
+
+	t.Run("off", func(t *testing.T) {
+		var verification *SignatureVerification
+		assert.False(t, verification.Enabled())
+		file, err := verification.OpenFile(openFile)("https://host/unsigned.yml", nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaults, readAllAndClose(t, file))
+	})
+
+	t.Run("public key from file", func(t *testing.T) {
+		keyFile := t.TempDir() + "/cosign.pub"
+		require.NoError(t, os.WriteFile(keyFile, []byte(cosignPublicKey), 0o600))
+		verification := &SignatureVerification{Policy: SignaturePolicyEnforce, PublicKeys: []string{keyFile}}
+		_, err := verification.OpenFile(openFile)("https://host/cosign.yml", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		assert.EqualError(t, (&SignatureVerification{Policy: "strict", PublicKeys: []string{cosignPublicKey}}).Validate(), "invalid signature policy 'strict', possible values: off, warn, enforce")
+		assert.EqualError(t, (&SignatureVerification{Policy: SignaturePolicyWarn}).Validate(), "no public key configured for the verification of signatures")
+		assert.EqualError(t, (&SignatureVerification{Policy: SignaturePolicyWarn, PublicKeys: []string{"not-a-key"}}).Validate(), "invalid public key 'not-a-key': neither a PEM encoded nor a minisign public key")
+	})
+}
+
+func TestInitializeConfigVerifiesCustomDefaults(t *testing.T) {
+	cosignKey, cosignPublicKey := cosignTestKey(t)
+	const defaults = "general:\n  buildTool: maven\n"
+	c := Config{openFile: signatureOpenFileMock(map[string]string{
+		"https://host/defaults.yml":     defaults,
+		"https://host/defaults.yml.sig": cosignSign(t, cosignKey, "general:\n  buildTool: npm\n"),
+	})}
+	c.SetSignatureVerification(&SignatureVerification{Policy: SignaturePolicyEnforce, PublicKeys: []string{cosignPublicKey}})
+
+	err := c.InitializeConfig(io.NopCloser(strings.NewReader("customDefaults: ['https://host/defaults.yml']")), nil, false)
+	assert.EqualError(t, err, "getting default 'https://host/defaults.yml' failed: signature verification of 'https://host/defaults.yml' failed: no valid signature found (.sig: invalid signature)")
+}
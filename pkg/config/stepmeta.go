@@ -176,6 +176,12 @@ func (m *StepData) ReadPipelineStepData(metadata io.ReadCloser) error {
 	return nil
 }
 
+// CommandParameters are general parameters defining the timeout and the retries of all commands executed by a step
+var CommandParameters = []string{"commandTimeout", "retryOnPatterns", "retryAttempts", "retryDelay"}
+
+// HTTPParameters are general parameters defining the circuit breaker and the response cache of all HTTP clients of a step
+var HTTPParameters = []string{"httpCircuitBreakerThreshold", "httpCircuitBreakerCooldown", "httpCacheDir"}
+
 // GetParameterFilters retrieves all scope dependent parameter filters
 func (m *StepData) GetParameterFilters() StepFilters {
 	filters := StepFilters{All: []string{"verbose"}, General: []string{"verbose"}, Steps: []string{"verbose"}, Stages: []string{"verbose"}, Parameters: []string{"verbose"}}
@@ -466,6 +472,9 @@ func getFilterForResourceReferences(params []StepParameters) []string {
 		if reference == nil {
 			reference = param.GetReference("vaultSecretFile")
 		}
+		if reference == nil {
+			reference = param.GetReference(RefTypeVaultDynamicSecret)
+		}
 		if reference == nil {
 			return filter
 		}
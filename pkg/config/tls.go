@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	vaultTLSCertificateKey = "certificate"
+	vaultTLSKeyKey         = "key"
+)
+
+// resolveVaultTLSCertificates retrieves the client certificates of the TLS configuration per host from Vault
+func resolveVaultTLSCertificates(config *StepConfig, client VaultClient) {
+	for i := range config.TLSConfig {
+		hostConfig := &config.TLSConfig[i]
+		if len(hostConfig.ClientCertificateVaultPath) == 0 {
+			continue
+		}
+		log.Entry().Infof("Resolving client certificate of host %v from Vault path '%v'", hostConfig.Host, hostConfig.ClientCertificateVaultPath)
+		secret, err := client.GetKvSecret(hostConfig.ClientCertificateVaultPath)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Couldn't fetch client certificate of host %v from Vault", hostConfig.Host)
+			continue
+		}
+		if len(secret[vaultTLSCertificateKey]) == 0 || len(secret[vaultTLSKeyKey]) == 0 {
+			log.Entry().Warnf("Vault secret '%v' does not contain the keys '%v' and '%v'", hostConfig.ClientCertificateVaultPath, vaultTLSCertificateKey, vaultTLSKeyKey)
+			continue
+		}
+		log.RegisterSecret(secret[vaultTLSKeyKey])
+		hostConfig.ClientCertificatePEM = []byte(secret[vaultTLSCertificateKey])
+		hostConfig.ClientKeyPEM = []byte(secret[vaultTLSKeyKey])
+	}
+}
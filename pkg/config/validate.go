@@ -0,0 +1,579 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Severities of issues found during the validation of the configuration
+const (
+	IssueError   = "error"
+	IssueWarning = "warning"
+)
+
+// ConfigIssue describes a problem found in the general, stages or steps section of a configuration file
+type ConfigIssue struct {
+	Severity string `json:"severity"`
+	// Section is the part of the configuration file, e.g. general, steps/mavenBuild or stages/Build
+	Section    string `json:"section"`
+	Key        string `json:"key,omitempty"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// String returns a human-readable representation of the issue, e.g.
+// .pipeline/config.yml:12: error: steps/mavenBuild: unknown parameter 'goalz' (did you mean 'goals'?)
+func (i ConfigIssue) String() string {
+	location := ""
+	if len(i.File) > 0 {
+		location = i.File
+		if i.Line > 0 {
+			location += fmt.Sprintf(":%d", i.Line)
+		}
+		location += ": "
+	}
+	message := fmt.Sprintf("%s%s: %s: %s", location, i.Severity, i.Section, i.Message)
+	if len(i.Suggestion) > 0 {
+		message += fmt.Sprintf(" (did you mean '%s'?)", i.Suggestion)
+	}
+	return message
+}
+
+// ConfigIssuesContainErrors returns true if at least one of the issues has severity error
+func ConfigIssuesContainErrors(issues []ConfigIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == IssueError {
+			return true
+		}
+	}
+	return false
+}
+
+var vaultSecretNameRegex = regexp.MustCompile(vaultSecretName)
+
+// parameterDefinition is a parameter of a step which can be configured using a certain key
+type parameterDefinition struct {
+	step  string
+	param StepParameters
+	// alias is set if the key is an alias of the parameter
+	alias *Alias
+	// nested is set if the key is only the first part of an alias like "docker/image"
+	nested bool
+}
+
+// configSchema contains all keys which are known for the different sections of the configuration
+type configSchema struct {
+	steps     map[string]StepData
+	stepNames []string
+	// general and stages contain the known keys of all steps, stepKeys the known keys per step
+	general  map[string][]parameterDefinition
+	stages   map[string][]parameterDefinition
+	stepKeys map[string]map[string][]parameterDefinition
+	// scopes contains the scopes a key can be used in, which is used to report keys placed in the wrong section
+	scopes map[string]map[string]bool
+}
+
+// Validate checks the general, stages and steps sections of the configuration against the metadata of the steps.
+// Unknown keys are reported together with a suggestion for the intended key, values are checked against
+// the type and possible values of the parameters and conditionally mandatory parameters are reported if missing.
+// Keys in the general and stages sections might also be used by other tools, thus unknown keys are only reported as warnings there.
+func (c *Config) Validate(metadata map[string]StepData) []ConfigIssue {
+	schema := newConfigSchema(metadata)
+	issues := []ConfigIssue{}
+
+	for _, key := range sortedKeys(c.General) {
+		issues = append(issues, c.validateKey(schema, []string{"general"}, key, c.General[key], schema.general, schema.generalCandidates())...)
+	}
+
+	for _, stage := range sortedKeys(c.Stages) {
+		for _, key := range sortedKeys(c.Stages[stage]) {
+			section := []string{"stages", stage}
+			if _, ok := schema.steps[key]; ok {
+				// steps can be activated/deactivated per stage
+				issues = append(issues, c.validateStepAlias(section, key, schema)...)
+				continue
+			}
+			issues = append(issues, c.validateKey(schema, section, key, c.Stages[stage][key], schema.stages, schema.stageCandidates())...)
+		}
+	}
+
+	for _, step := range sortedKeys(c.Steps) {
+		section := []string{"steps", step}
+		stepData, ok := schema.steps[step]
+		if !ok {
+			issues = append(issues, c.issue(IssueWarning, section, "", fmt.Sprintf("unknown step '%s'", step), suggest(step, schema.stepNames)))
+			continue
+		}
+		issues = append(issues, c.validateStepAlias(section, step, schema)...)
+		known := schema.stepKeys[stepData.Metadata.Name]
+		for _, key := range sortedKeys(c.Steps[step]) {
+			issues = append(issues, c.validateKey(schema, section, key, c.Steps[step][key], known, sortedKeys(known))...)
+		}
+		issues = append(issues, c.validateMandatoryIf(section, stepData, c.Steps[step])...)
+	}
+
+	return issues
+}
+
+// validateKey checks whether the key is known in the given section and whether its value is valid
+func (c *Config) validateKey(schema *configSchema, section []string, key string, value interface{}, known map[string][]parameterDefinition, candidates []string) []ConfigIssue {
+	severity := IssueError
+	if section[0] != "steps" {
+		severity = IssueWarning
+	}
+
+	definitions, ok := known[key]
+	if !ok {
+		if vaultSecretNameRegex.MatchString(key) {
+			return nil
+		}
+		if scopes, ok := schema.scopes[key]; ok {
+			return []ConfigIssue{c.issue(severity, section, key, fmt.Sprintf("parameter '%s' cannot be configured in section %s, supported sections: %s", key, section[0], strings.Join(sortedKeys(scopes), ", ")), "")}
+		}
+		return []ConfigIssue{c.issue(severity, section, key, fmt.Sprintf("unknown parameter '%s'", key), suggest(key, candidates))}
+	}
+
+	issues := []ConfigIssue{}
+	for _, definition := range definitions {
+		if definition.alias != nil && definition.alias.Deprecated {
+			issues = append(issues, c.issue(IssueWarning, section, key, fmt.Sprintf("'%s' is deprecated, please use '%s' instead", key, definition.param.Name), ""))
+			break
+		}
+	}
+	for _, definition := range definitions {
+		if len(definition.param.DeprecationMessage) > 0 {
+			issues = append(issues, c.issue(IssueWarning, section, key, fmt.Sprintf("parameter '%s' is deprecated: %s", key, definition.param.DeprecationMessage), ""))
+			break
+		}
+	}
+
+	if value == nil {
+		return issues
+	}
+	var typeMismatch, valueMismatch *parameterDefinition
+	for i, definition := range definitions {
+		if definition.nested || len(definition.param.Type) == 0 {
+			return issues
+		}
+		if !valueMatchesType(value, definition.param.Type) {
+			if typeMismatch == nil {
+				typeMismatch = &definitions[i]
+			}
+			continue
+		}
+		if !valueIsPossible(value, definition.param.PossibleValues) {
+			if valueMismatch == nil {
+				valueMismatch = &definitions[i]
+			}
+			continue
+		}
+		return issues
+	}
+
+	if valueMismatch != nil {
+		possibleValues := make([]string, 0, len(valueMismatch.param.PossibleValues))
+		for _, possibleValue := range valueMismatch.param.PossibleValues {
+			possibleValues = append(possibleValues, fmt.Sprint(possibleValue))
+		}
+		suggestion := ""
+		if s, ok := value.(string); ok {
+			suggestion = suggest(s, possibleValues)
+		}
+		return append(issues, c.issue(IssueError, section, key, fmt.Sprintf("value '%v' of parameter '%s' is not allowed, possible values: %s", value, key, strings.Join(possibleValues, ", ")), suggestion))
+	}
+	return append(issues, c.issue(IssueError, section, key, fmt.Sprintf("value of parameter '%s' is of type %s, expected %s", key, typeName(value), typeMismatch.param.Type), ""))
+}
+
+// validateStepAlias reports the usage of a deprecated step name
+func (c *Config) validateStepAlias(section []string, key string, schema *configSchema) []ConfigIssue {
+	stepData := schema.steps[key]
+	for _, alias := range stepData.Metadata.Aliases {
+		if alias.Name == key && alias.Deprecated {
+			issueSection := section
+			if section[0] == "stages" {
+				issueSection = append(section, key)
+			}
+			return []ConfigIssue{c.issue(IssueWarning, issueSection, "", fmt.Sprintf("step name '%s' is deprecated, please use '%s' instead", key, stepData.Metadata.Name), "")}
+		}
+	}
+	return nil
+}
+
+// validateMandatoryIf reports parameters which are mandatory due to the configuration of the step but not configured.
+// Since the value might still be provided via defaults, the pipeline environment or vault this is only reported as warning.
+func (c *Config) validateMandatoryIf(section []string, stepData StepData, stepConfig map[string]interface{}) []ConfigIssue {
+	issues := []ConfigIssue{}
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		if _, configured := c.configuredValue(param, stepConfig); configured || hasDefault(param) {
+			continue
+		}
+		for _, dependence := range param.MandatoryIf {
+			dependentParam := StepParameters{Name: dependence.Name, Scope: []string{"GENERAL"}}
+			for _, p := range stepData.Spec.Inputs.Parameters {
+				if p.Name == dependence.Name {
+					dependentParam = p
+				}
+			}
+			if value, ok := c.configuredValue(dependentParam, stepConfig); ok && fmt.Sprint(value) == dependence.Value {
+				issues = append(issues, c.issue(IssueWarning, section, "", fmt.Sprintf("parameter '%s' is mandatory if '%s' is '%s'", param.Name, dependence.Name, dependence.Value), ""))
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// configuredValue returns the value of the parameter if it or one of its aliases is set in the step or general section
+func (c *Config) configuredValue(param StepParameters, stepConfig map[string]interface{}) (interface{}, bool) {
+	names := []string{param.Name}
+	for _, alias := range param.Aliases {
+		names = append(names, alias.Name)
+	}
+	for _, name := range names {
+		if value, ok := nestedValue(stepConfig, strings.Split(name, "/")); ok {
+			return value, true
+		}
+		if !slices.Contains(param.Scope, "GENERAL") {
+			continue
+		}
+		if value, ok := nestedValue(c.General, strings.Split(name, "/")); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// nestedValue returns the value at the given path, e.g. [maven m2Path] for an alias maven/m2Path
+func nestedValue(config map[string]interface{}, path []string) (interface{}, bool) {
+	value, ok := config[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return nestedValue(nested, path[1:])
+}
+
+// hasDefault checks whether the parameter has a non-empty default value
+func hasDefault(param StepParameters) bool {
+	if param.Default == nil {
+		return false
+	}
+	value := reflect.ValueOf(param.Default)
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() > 0
+	}
+	return true
+}
+
+func (c *Config) issue(severity string, section []string, key, message, suggestion string) ConfigIssue {
+	issue := ConfigIssue{Severity: severity, Section: strings.Join(section, "/"), Key: key, Message: message, Suggestion: suggestion}
+	if c.source != nil {
+		issue.File = c.source.name
+		path := section
+		if len(key) > 0 {
+			path = append(append([]string{}, section...), key)
+		}
+		issue.Line = c.source.line(path...)
+	}
+	return issue
+}
+
+func newConfigSchema(metadata map[string]StepData) *configSchema {
+	schema := configSchema{
+		steps:    map[string]StepData{},
+		general:  map[string][]parameterDefinition{},
+		stages:   map[string][]parameterDefinition{},
+		stepKeys: map[string]map[string][]parameterDefinition{},
+		scopes:   map[string]map[string]bool{},
+	}
+
+	// keys which are evaluated independent of the step metadata
+	commonKeys := append([]string{"verbose", "collectTelemetryData"}, vaultFilter...)
+	commonKeys = append(commonKeys, CommandParameters...)
+	commonKeys = append(commonKeys, HTTPParameters...)
+	for _, param := range ReportingParameters.Parameters {
+		commonKeys = append(commonKeys, param.Name)
+		for _, alias := range param.Aliases {
+			commonKeys = append(commonKeys, alias.Name)
+		}
+	}
+
+	for name, stepData := range metadata {
+		stepName := stepData.Metadata.Name
+		if len(stepName) == 0 {
+			stepName = name
+		}
+		stepData.Metadata.Name = stepName
+		schema.steps[stepName] = stepData
+		schema.stepNames = append(schema.stepNames, stepName)
+		for _, alias := range stepData.Metadata.Aliases {
+			schema.steps[alias.Name] = stepData
+		}
+
+		stepKeys := map[string][]parameterDefinition{}
+		schema.stepKeys[stepName] = stepKeys
+
+		for _, param := range stepData.Spec.Inputs.Parameters {
+			definitions := []parameterDefinition{{step: stepName, param: param}}
+			for i, alias := range param.Aliases {
+				parts := strings.Split(alias.Name, "/")
+				definitions = append(definitions, parameterDefinition{step: stepName, param: param, alias: &param.Aliases[i], nested: len(parts) > 1})
+			}
+			for _, definition := range definitions {
+				key := definition.param.Name
+				if definition.alias != nil {
+					key = strings.Split(definition.alias.Name, "/")[0]
+				}
+				for _, scope := range param.Scope {
+					schema.addKey(scope, key)
+					switch scope {
+					case "GENERAL":
+						schema.general[key] = append(schema.general[key], definition)
+					case "STAGES":
+						schema.stages[key] = append(schema.stages[key], definition)
+					case "STEPS":
+						stepKeys[key] = append(stepKeys[key], definition)
+					}
+				}
+			}
+			// keys which define where secrets are read from, e.g. from vault
+			for _, ref := range param.ResourceRef {
+				if ref.Type == "vaultSecret" || ref.Type == "vaultSecretFile" || ref.Type == RefTypeVaultDynamicSecret || ref.Type == RefTypeSystemTrustSecret {
+					schema.addUntypedKey(stepKeys, ref.Name)
+					for _, alias := range ref.Aliases {
+						schema.addUntypedKey(stepKeys, alias.Name)
+					}
+				}
+			}
+		}
+
+		for _, secret := range stepData.Spec.Inputs.Secrets {
+			for _, alias := range secret.Aliases {
+				schema.addUntypedKey(stepKeys, alias.Name)
+			}
+		}
+
+		filters := stepData.GetParameterFilters()
+		contextFilters := stepData.GetContextParameterFilters()
+		for _, key := range append(append(append([]string{}, filters.Steps...), contextFilters.Steps...), commonKeys...) {
+			schema.addUntypedKey(stepKeys, key)
+		}
+		// keys of conditional parameters
+		for _, key := range append(append([]string{}, filters.General...), contextFilters.General...) {
+			if _, ok := schema.general[key]; !ok {
+				schema.general[key] = []parameterDefinition{{step: stepName}}
+			}
+		}
+		for _, key := range append(append([]string{}, filters.Stages...), contextFilters.Stages...) {
+			if _, ok := schema.stages[key]; !ok {
+				schema.stages[key] = []parameterDefinition{{step: stepName}}
+			}
+		}
+	}
+
+	for _, key := range commonKeys {
+		if _, ok := schema.general[key]; !ok {
+			schema.general[key] = []parameterDefinition{{}}
+		}
+		if _, ok := schema.stages[key]; !ok {
+			schema.stages[key] = []parameterDefinition{{}}
+		}
+	}
+
+	sort.Strings(schema.stepNames)
+	return &schema
+}
+
+func (s *configSchema) addKey(scope, key string) {
+	if s.scopes[key] == nil {
+		s.scopes[key] = map[string]bool{}
+	}
+	switch scope {
+	case "GENERAL":
+		s.scopes[key]["general"] = true
+	case "STAGES":
+		s.scopes[key]["stages"] = true
+	case "STEPS":
+		s.scopes[key]["steps"] = true
+	case "PARAMETERS":
+		s.scopes[key]["parameters"] = true
+	}
+}
+
+// addUntypedKey adds a key without type information, unless the key is already known with type information
+func (s *configSchema) addUntypedKey(keys map[string][]parameterDefinition, key string) {
+	if _, ok := keys[key]; !ok {
+		keys[key] = []parameterDefinition{{}}
+	}
+}
+
+func (s *configSchema) generalCandidates() []string {
+	return sortedKeys(s.general)
+}
+
+func (s *configSchema) stageCandidates() []string {
+	return append(sortedKeys(s.stages), s.stepNames...)
+}
+
+// valueMatchesType checks if the value can be used for a parameter of the given type.
+// Conversions which are done when the step configuration is applied, e.g. from "true" to true, are considered.
+func valueMatchesType(value interface{}, paramType string) bool {
+	switch paramType {
+	case "string":
+		switch value.(type) {
+		case string, float64, int:
+			return true
+		}
+		return false
+	case "bool":
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			v = strings.ToLower(v)
+			return v == "true" || v == "false"
+		}
+		return false
+	case "int":
+		switch v := value.(type) {
+		case int:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "[]string":
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if !valueMatchesType(item, "string") {
+				return false
+			}
+		}
+		return true
+	case "map[string]interface{}":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "[]map[string]interface{}":
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if _, ok := item.(map[string]interface{}); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	// unknown types are not validated
+	return true
+}
+
+// valueIsPossible checks if the value or all values of a list are contained in the possible values
+func valueIsPossible(value interface{}, possibleValues []interface{}) bool {
+	if len(possibleValues) == 0 {
+		return true
+	}
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if !valueIsPossible(item, possibleValues) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, possibleValue := range possibleValues {
+		if fmt.Sprint(possibleValue) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		if v == float64(int64(v)) {
+			return "int"
+		}
+		return "float"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// suggest returns the candidate which is most similar to the given key or an empty string if no candidate is similar enough
+func suggest(key string, candidates []string) string {
+	best := ""
+	bestDistance := 0
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, key) {
+			return candidate
+		}
+		distance := levenshtein(strings.ToLower(key), strings.ToLower(candidate))
+		if len(best) == 0 || distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	// allow roughly one typo per four characters
+	maxDistance := len(key) / 4
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	if len(best) == 0 || bestDistance > maxDistance {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validationMetadata() map[string]StepData {
+	return map[string]StepData{
+		"mavenBuild": {
+			Metadata: StepMetadata{Name: "mavenBuild", Aliases: []Alias{{Name: "mavenCompile", Deprecated: true}}},
+			Spec: StepSpec{Inputs: StepInputs{
+				Parameters: []StepParameters{
+					{Name: "buildTool", Type: "string", Scope: []string{"GENERAL", "STEPS"}, PossibleValues: []interface{}{"maven", "npm"}},
+					{Name: "goals", Type: "[]string", Scope: []string{"PARAMETERS", "STEPS"}},
+					{Name: "publish", Type: "bool", Scope: []string{"STEPS", "STAGES"}, Aliases: []Alias{{Name: "deploy", Deprecated: true}}},
+					{Name: "retries", Type: "int", Scope: []string{"STEPS"}},
+					{Name: "altDeploymentRepositoryUrl", Type: "string", Scope: []string{"STEPS"}, MandatoryIf: []ParameterDependence{{Name: "publish", Value: "true"}}},
+					{Name: "globalSettingsFile", Type: "string", Scope: []string{"PARAMETERS"}},
+					{Name: "m2Path", Type: "string", Scope: []string{"STEPS"}, Aliases: []Alias{{Name: "maven/m2Path"}}},
+				},
+				Secrets: []StepSecrets{{Name: "mavenCredentialsId", Type: "jenkins"}},
+			}},
+		},
+	}
+}
+
+func readValidationConfig(t *testing.T, content string) *Config {
+	c := &Config{}
+	require.NoError(t, c.ReadConfig(namedStringReader{Reader: strings.NewReader(content), name: ".pipeline/config.yml"}))
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid configuration", func(t *testing.T) {
+		c := readValidationConfig(t, `general:
+  buildTool: maven
+  verbose: true
+  mavenCredentialsId: maven
+stages:
+  Build:
+    publish: false
+    mavenBuild: true
+steps:
+  mavenBuild:
+    goals: [install]
+    publish: "true"
+    retries: 3
+    altDeploymentRepositoryUrl: https://nexus
+    maven:
+      m2Path: .m2
+    githubTokenVaultSecretName: github
+`)
+		assert.Empty(t, c.Validate(validationMetadata()))
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		c := readValidationConfig(t, `general:
+  buildTol: maven
+steps:
+  mavenBuild:
+    goal: [install]
+  mavenBiuld:
+    goals: [install]
+`)
+		issues := c.Validate(validationMetadata())
+		require.Len(t, issues, 3)
+		assert.Equal(t, ConfigIssue{Severity: IssueWarning, Section: "general", Key: "buildTol", File: ".pipeline/config.yml", Line: 2, Message: "unknown parameter 'buildTol'", Suggestion: "buildTool"}, issues[0])
+		assert.Equal(t, ConfigIssue{Severity: IssueWarning, Section: "steps/mavenBiuld", File: ".pipeline/config.yml", Line: 6, Message: "unknown step 'mavenBiuld'", Suggestion: "mavenBuild"}, issues[1])
+		assert.Equal(t, ConfigIssue{Severity: IssueError, Section: "steps/mavenBuild", Key: "goal", File: ".pipeline/config.yml", Line: 5, Message: "unknown parameter 'goal'", Suggestion: "goals"}, issues[2])
+		assert.Equal(t, ".pipeline/config.yml:5: error: steps/mavenBuild: unknown parameter 'goal' (did you mean 'goals'?)", issues[2].String())
+		assert.True(t, ConfigIssuesContainErrors(issues))
+	})
+
+	t.Run("wrong section", func(t *testing.T) {
+		c := readValidationConfig(t, `steps:
+  mavenBuild:
+    globalSettingsFile: settings.xml
+`)
+		issues := c.Validate(validationMetadata())
+		require.Len(t, issues, 1)
+		assert.Equal(t, "parameter 'globalSettingsFile' cannot be configured in section steps, supported sections: parameters", issues[0].Message)
+	})
+
+	t.Run("types and possible values", func(t *testing.T) {
+		c := readValidationConfig(t, `general:
+  buildTool: mavn
+steps:
+  mavenBuild:
+    goals: install
+    publish: "yes"
+    retries: 1.5
+`)
+		issues := c.Validate(validationMetadata())
+		require.Len(t, issues, 4)
+		assert.Equal(t, IssueError, issues[0].Severity)
+		assert.Equal(t, "value 'mavn' of parameter 'buildTool' is not allowed, possible values: maven, npm", issues[0].Message)
+		assert.Equal(t, "maven", issues[0].Suggestion)
+		assert.Equal(t, "value of parameter 'goals' is of type string, expected []string", issues[1].Message)
+		assert.Equal(t, "value of parameter 'publish' is of type string, expected bool", issues[2].Message)
+		assert.Equal(t, "value of parameter 'retries' is of type float, expected int", issues[3].Message)
+	})
+
+	t.Run("deprecations and mandatory if", func(t *testing.T) {
+		c := readValidationConfig(t, `steps:
+  mavenCompile:
+    deploy: true
+`)
+		issues := c.Validate(validationMetadata())
+		require.Len(t, issues, 3)
+		assert.Equal(t, "step name 'mavenCompile' is deprecated, please use 'mavenBuild' instead", issues[0].Message)
+		assert.Equal(t, "'deploy' is deprecated, please use 'publish' instead", issues[1].Message)
+		assert.Equal(t, 3, issues[1].Line)
+		assert.Equal(t, "parameter 'altDeploymentRepositoryUrl' is mandatory if 'publish' is 'true'", issues[2].Message)
+		assert.False(t, ConfigIssuesContainErrors(issues))
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"goals", "publish", "buildTool"}
+	assert.Equal(t, "goals", suggest("goal", candidates))
+	assert.Equal(t, "buildTool", suggest("BuildTool", candidates))
+	assert.Equal(t, "", suggest("somethingElse", candidates))
+}
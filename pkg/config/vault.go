@@ -11,6 +11,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/vault"
 	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -33,6 +34,12 @@ const (
 	vaultTestCredentialEnvPrefixDefault = "PIPER_TESTCREDENTIAL_"
 	VaultCredentialEnvPrefixDefault     = "PIPER_VAULTCREDENTIAL_"
 	vaultSecretName                     = ".+VaultSecretName$"
+	vaultAuthMethod                     = "vaultAuthMethod"
+	vaultAuthMountPath                  = "vaultAuthMountPath"
+	vaultRole                           = "vaultRole"
+	vaultJwtAudience                    = "vaultJwtAudience"
+	vaultClientCertificate              = "vaultClientCertificate"
+	vaultClientKey                      = "vaultClientKey"
 )
 
 var (
@@ -54,6 +61,13 @@ var (
 		vaultCredentialKeys,
 		vaultCredentialEnvPrefix,
 		vaultSecretName,
+		vaultAuthMethod,
+		vaultAuthMountPath,
+		vaultRole,
+		vaultJwtAudience,
+		vaultClientCertificate,
+		vaultClientKey,
+		secretProviderPath,
 	}
 
 	// VaultRootPaths are the lookup paths piper tries to use during the vault lookup.
@@ -78,6 +92,7 @@ type VaultCredentials struct {
 // VaultClient interface for mocking
 type VaultClient interface {
 	GetKvSecret(string) (map[string]string, error)
+	GetDynamicSecret(string, map[string]interface{}) (map[string]string, error)
 	MustRevokeToken()
 	GetOIDCTokenByValidation(string) (string, error)
 }
@@ -95,6 +110,12 @@ func GlobalVaultClient() VaultClient {
 	return globalVaultClient
 }
 
+// VaultConfigurationKeys returns the keys of the vault configuration, which are considered for all steps.
+// Keys ending with "$" are regular expressions, e.g. for the names of vault secrets.
+func VaultConfigurationKeys() []string {
+	return append([]string{}, vaultFilter...)
+}
+
 func (s *StepConfig) mixinVaultConfig(parameters []StepParameters, configs ...map[string]interface{}) {
 	for _, config := range configs {
 		s.mixIn(config, vaultFilter, StepData{})
@@ -109,9 +130,12 @@ func (s *StepConfig) mixinVaultConfig(parameters []StepParameters, configs ...ma
 // It's important to revoke token provided to this client after usage.
 // Currently, revocation will happen at the end of each step execution (see _generated.go part of the steps)
 func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredentials) (VaultClient, error) {
-	address, addressOk := config["vaultServerUrl"].(string)
+	address, addressOk := config[vaultServerUrl].(string)
+	authMethod, _ := config[vaultAuthMethod].(string)
+	// kubernetes, jwt and cert logins do not require credentials stored in the CI system
+	loginWithoutCredentials := authMethod == vault.AuthMethodKubernetes || authMethod == vault.AuthMethodJWT || authMethod == vault.AuthMethodCert
 	// if vault isn't used it's not an error
-	if !addressOk || creds.VaultToken == "" && (creds.AppRoleID == "" || creds.AppRoleSecretID == "") {
+	if !addressOk || creds.VaultToken == "" && (creds.AppRoleID == "" || creds.AppRoleSecretID == "") && !loginWithoutCredentials {
 		log.Entry().Debug("Vault not configured")
 		return nil, nil
 	}
@@ -126,13 +150,25 @@ func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredenti
 	var client *vault.Client
 	var err error
 	clientConfig := &vault.ClientConfig{Config: &api.Config{Address: address}, Namespace: namespace}
-	if creds.VaultToken != "" {
+	if creds.VaultToken != "" && !loginWithoutCredentials {
 		log.Entry().Debugf("  with Token authentication")
 		client, err = vault.NewClientWithToken(clientConfig, creds.VaultToken)
 	} else {
-		log.Entry().Debugf("  with AppRole authentication")
-		clientConfig.RoleID = creds.AppRoleID
-		clientConfig.SecretID = creds.AppRoleSecretID
+		clientConfig.AuthMountPoint, _ = config[vaultAuthMountPath].(string)
+		if loginWithoutCredentials {
+			log.Entry().Debugf("  with %s authentication", authMethod)
+			clientConfig.AuthMethod = authMethod
+			clientConfig.Role, _ = config[vaultRole].(string)
+			clientConfig.JWTAudience, _ = config[vaultJwtAudience].(string)
+		} else {
+			log.Entry().Debugf("  with AppRole authentication")
+			clientConfig.RoleID = creds.AppRoleID
+			clientConfig.SecretID = creds.AppRoleSecretID
+		}
+		if err = configureVaultClientCertificate(clientConfig, config); err != nil {
+			log.Entry().Info("  failed")
+			return nil, err
+		}
 		client, err = vault.NewClient(clientConfig)
 	}
 	if err != nil {
@@ -147,7 +183,25 @@ func GetVaultClientFromConfig(config map[string]interface{}, creds VaultCredenti
 	return client, nil
 }
 
+// configureVaultClientCertificate configures the client certificate which is required for the cert auth method
+func configureVaultClientCertificate(clientConfig *vault.ClientConfig, config map[string]interface{}) error {
+	certificate, _ := config[vaultClientCertificate].(string)
+	key, _ := config[vaultClientKey].(string)
+	if certificate == "" && key == "" {
+		if clientConfig.AuthMethod == vault.AuthMethodCert {
+			return errors.New("the Vault cert auth method requires vaultClientCertificate and vaultClientKey")
+		}
+		return nil
+	}
+	if err := clientConfig.ConfigureTLS(&api.TLSConfig{ClientCert: certificate, ClientKey: key}); err != nil {
+		return errors.Wrap(err, "failed to configure Vault client certificate")
+	}
+	return nil
+}
+
 func resolveAllVaultReferences(config *StepConfig, client VaultClient, params []StepParameters) {
+	// several parameters usually reference the same credentials, e.g. username and password
+	dynamicSecrets := map[string]map[string]string{}
 	for _, param := range params {
 		if ref := param.GetReference("vaultSecret"); ref != nil {
 			resolveVaultReference(ref, config, client, param)
@@ -155,10 +209,17 @@ func resolveAllVaultReferences(config *StepConfig, client VaultClient, params []
 		if ref := param.GetReference("vaultSecretFile"); ref != nil {
 			resolveVaultReference(ref, config, client, param)
 		}
+		if ref := param.GetReference(RefTypeVaultDynamicSecret); ref != nil {
+			resolveVaultDynamicSecretReference(ref, config, client, param, dynamicSecrets)
+		}
 	}
 }
 
 func resolveVaultReference(ref *ResourceReference, config *StepConfig, client VaultClient, param StepParameters) {
+	if scheme, _ := secretAddress(ref, config.Config); len(scheme) > 0 {
+		// resolved by resolveAllSecretProviderReferences
+		return
+	}
 	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
 	if paramValue, _ := config.Config[param.Name].(string); vaultDisableOverwrite && paramValue != "" {
 		log.Entry().Debugf("Not fetching '%s' from Vault since it has already been set", param.Name)
@@ -178,16 +239,7 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client Va
 		secretValue = lookupPath(client, vaultPath, &param)
 		if secretValue != nil {
 			log.Entry().Infof("  succeeded with Vault path '%s'", vaultPath)
-			if ref.Type == "vaultSecret" {
-				config.Config[param.Name] = *secretValue
-			} else if ref.Type == "vaultSecretFile" {
-				filePath, err := createTemporarySecretFile(param.Name, *secretValue)
-				if err != nil {
-					log.Entry().WithError(err).Warnf("Couldn't create temporary secret file for '%s'", param.Name)
-					return
-				}
-				config.Config[param.Name] = filePath
-			}
+			setSecretParameter(ref, config, param, *secretValue)
 			break
 		}
 	}
@@ -196,6 +248,20 @@ func resolveVaultReference(ref *ResourceReference, config *StepConfig, client Va
 	}
 }
 
+// setSecretParameter sets the parameter to the secret or for vaultSecretFile references to a file containing the secret
+func setSecretParameter(ref *ResourceReference, config *StepConfig, param StepParameters, secretValue string) {
+	if ref.Type == "vaultSecret" {
+		config.Config[param.Name] = secretValue
+	} else if ref.Type == "vaultSecretFile" {
+		filePath, err := createTemporarySecretFile(param.Name, secretValue)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Couldn't create temporary secret file for '%s'", param.Name)
+			return
+		}
+		config.Config[param.Name] = filePath
+	}
+}
+
 func resolveVaultTestCredentialsWrapper(config *StepConfig, client VaultClient) {
 	log.Entry().Infof("Resolving test credentials wrapper")
 	resolveVaultCredentialsWrapperBase(config, client, vaultTestCredentialPath, vaultTestCredentialKeys, vaultTestCredentialEnvPrefix, resolveVaultTestCredentials)
@@ -480,7 +546,11 @@ func lookupPath(client VaultClient, path string, param *StepParameters) *string
 	if secret == nil {
 		return nil
 	}
+	return lookupSecretField(secret, param)
+}
 
+// lookupSecretField returns the field of the secret named like the parameter or one of its aliases
+func lookupSecretField(secret map[string]string, param *StepParameters) *string {
 	field := secret[param.Name]
 	if field != "" {
 		log.RegisterSecret(field)
@@ -4,7 +4,10 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
@@ -14,6 +17,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/SAP/jenkins-library/pkg/vault"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -540,3 +544,45 @@ func Test_convertEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVaultClientFromConfig(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		client, err := GetVaultClientFromConfig(map[string]interface{}{"vaultServerUrl": "https://vault.example.com"}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("JWT login without credentials", func(t *testing.T) {
+		var loginPath string
+		var loginData map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			loginPath = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&loginData)
+			w.Write([]byte(`{"auth":{"client_token":"hvs.token","renewable":false}}`))
+		}))
+		defer server.Close()
+		defer func() { globalVaultClient = nil }()
+		t.Setenv(vault.JWTEnvVar, "ciJWT")
+		config := map[string]interface{}{
+			"vaultServerUrl":     server.URL,
+			"vaultAuthMethod":    "jwt",
+			"vaultAuthMountPath": "jwt-github",
+			"vaultRole":          "piper",
+		}
+
+		client, err := GetVaultClientFromConfig(config, VaultCredentials{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.Equal(t, "/v1/auth/jwt-github/login", loginPath)
+		assert.Equal(t, map[string]interface{}{"role": "piper", "jwt": "ciJWT"}, loginData)
+	})
+
+	t.Run("cert login without certificate", func(t *testing.T) {
+		config := map[string]interface{}{"vaultServerUrl": "https://vault.example.com", "vaultAuthMethod": "cert"}
+
+		_, err := GetVaultClientFromConfig(config, VaultCredentials{})
+
+		assert.EqualError(t, err, "the Vault cert auth method requires vaultClientCertificate and vaultClientKey")
+	})
+}
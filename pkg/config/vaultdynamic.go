@@ -0,0 +1,79 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config/interpolation"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// RefTypeVaultDynamicSecret references credentials of a Vault dynamic secrets engine, e.g. database/creds/<role>.
+// The credentials are only valid for the duration of the step, their lease is revoked at its end.
+const RefTypeVaultDynamicSecret = "vaultDynamicSecret"
+
+// resolveVaultDynamicSecretReference sets the parameter to a field of the credentials returned for the referenced path.
+// The field is defined by the param of the reference and defaults to the name of the parameter.
+// Parameters referencing the same path share the credentials, e.g. username and password of a database role.
+func resolveVaultDynamicSecretReference(ref *ResourceReference, config *StepConfig, client VaultClient, param StepParameters, secrets map[string]map[string]string) {
+	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
+	if paramValue, _ := config.Config[param.Name].(string); vaultDisableOverwrite && paramValue != "" {
+		log.Entry().Debugf("Not fetching '%s' from Vault since it has already been set", param.Name)
+		return
+	}
+
+	secretPath := ref.Default
+	if providedPath, ok := config.Config[ref.Name].(string); ok && providedPath != "" {
+		secretPath = providedPath
+	}
+	secretPath, ok := interpolation.ResolveString(secretPath, config.Config)
+	if !ok || secretPath == "" {
+		log.Entry().Debugf("No Vault path of dynamic secret for '%s' available", param.Name)
+		return
+	}
+
+	log.Entry().Infof("Resolving '%s' from dynamic secret '%s'", param.Name, secretPath)
+	secret, ok := secrets[secretPath]
+	if !ok {
+		path, data, err := dynamicSecretRequest(secretPath)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("  invalid Vault path '%s'", secretPath)
+			return
+		}
+		if secret, err = client.GetDynamicSecret(path, data); err != nil {
+			log.Entry().WithError(err).Warnf("Couldn't fetch dynamic secret at '%s'", path)
+			return
+		}
+		secrets[secretPath] = secret
+	}
+
+	field := param.Name
+	if len(ref.Param) > 0 {
+		field = ref.Param
+	}
+	value := secret[field]
+	if value == "" {
+		log.Entry().Warnf("  failed, dynamic secret did not contain a field '%s'", field)
+		return
+	}
+	log.RegisterSecret(value)
+	config.Config[param.Name] = value
+}
+
+// dynamicSecretRequest splits the query of the path into the data which is written to the path,
+// e.g. pki/issue/web?common_name=app.example.com. Paths without query are read.
+func dynamicSecretRequest(secretPath string) (string, map[string]interface{}, error) {
+	path, query, found := strings.Cut(secretPath, "?")
+	if !found {
+		return path, nil, nil
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", nil, err
+	}
+	data := map[string]interface{}{}
+	for key, value := range values {
+		data[key] = strings.Join(value, ",")
+	}
+	return path, data, nil
+}
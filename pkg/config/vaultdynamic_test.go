@@ -0,0 +1,103 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func dynamicSecretParam(name, field, pathProperty, defaultPath string) StepParameters {
+	param := stepParam(name, RefTypeVaultDynamicSecret, pathProperty, defaultPath)
+	param.ResourceRef[0].Param = field
+	return param
+}
+
+func TestResolveVaultDynamicSecrets(t *testing.T) {
+	t.Parallel()
+	t.Run("credentials of the same path share one lease", func(t *testing.T) {
+		vaultMock := &mocks.VaultClient{}
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+		stepParams := []StepParameters{
+			dynamicSecretParam("dbUser", "username", "dbVaultPath", "database/creds/readonly"),
+			dynamicSecretParam("dbPassword", "password", "dbVaultPath", "database/creds/readonly"),
+		}
+		vaultMock.On("GetDynamicSecret", "database/creds/readonly", map[string]interface{}(nil)).
+			Return(map[string]string{"username": "v-token-readonly", "password": "dynamicPassword"}, nil).Once()
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Equal(t, "v-token-readonly", stepConfig.Config["dbUser"])
+		assert.Equal(t, "dynamicPassword", stepConfig.Config["dbPassword"])
+		vaultMock.AssertExpectations(t)
+	})
+
+	t.Run("path from configuration with query is written", func(t *testing.T) {
+		vaultMock := &mocks.VaultClient{}
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"certVaultPath": "pki/issue/web?common_name=$(name).example.com&ttl=1h",
+			"name":          "app",
+		}}
+		stepParams := []StepParameters{dynamicSecretParam("certificate", "", "certVaultPath", "pki/issue/default")}
+		vaultMock.On("GetDynamicSecret", "pki/issue/web", map[string]interface{}{"common_name": "app.example.com", "ttl": "1h"}).
+			Return(map[string]string{"certificate": "-----BEGIN CERTIFICATE-----"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Equal(t, "-----BEGIN CERTIFICATE-----", stepConfig.Config["certificate"])
+	})
+
+	t.Run("failure keeps the configured value", func(t *testing.T) {
+		vaultMock := &mocks.VaultClient{}
+		stepConfig := StepConfig{Config: map[string]interface{}{"dbPassword": "static"}}
+		stepParams := []StepParameters{dynamicSecretParam("dbPassword", "password", "dbVaultPath", "database/creds/readonly")}
+		vaultMock.On("GetDynamicSecret", "database/creds/readonly", mock.Anything).Return(nil, fmt.Errorf("permission denied"))
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Equal(t, "static", stepConfig.Config["dbPassword"])
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		vaultMock := &mocks.VaultClient{}
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+		stepParams := []StepParameters{dynamicSecretParam("token", "security_token", "awsVaultPath", "aws/sts/deploy")}
+		vaultMock.On("GetDynamicSecret", "aws/sts/deploy", mock.Anything).Return(map[string]string{"access_key": "AKIA"}, nil)
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Nil(t, stepConfig.Config["token"])
+	})
+
+	t.Run("overwrite disabled", func(t *testing.T) {
+		vaultMock := &mocks.VaultClient{}
+		stepConfig := StepConfig{Config: map[string]interface{}{"dbPassword": "static", "vaultDisableOverwrite": true}}
+		stepParams := []StepParameters{dynamicSecretParam("dbPassword", "password", "dbVaultPath", "database/creds/readonly")}
+
+		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+
+		assert.Equal(t, "static", stepConfig.Config["dbPassword"])
+		vaultMock.AssertNotCalled(t, "GetDynamicSecret", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDynamicSecretRequest(t *testing.T) {
+	t.Parallel()
+	path, data, err := dynamicSecretRequest("database/creds/readonly")
+	assert.NoError(t, err)
+	assert.Equal(t, "database/creds/readonly", path)
+	assert.Nil(t, data)
+
+	path, data, err = dynamicSecretRequest("ssh/creds/otp?ip=10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh/creds/otp", path)
+	assert.Equal(t, map[string]interface{}{"ip": "10.0.0.1"}, data)
+
+	_, _, err = dynamicSecretRequest("pki/issue/web?common_name=%zz")
+	assert.Error(t, err)
+}
@@ -121,7 +121,7 @@ func parameterFurtherInfo(paramName string, stepData *config.StepData, execution
 			if param.Secret {
 				secretInfo := fmt.Sprintf("%s pass via ENV or Jenkins credentials", secretBadge)
 
-				isVaultSecret := param.GetReference("vaultSecret") != nil || param.GetReference("vaultSecretFile") != nil
+				isVaultSecret := param.GetReference("vaultSecret") != nil || param.GetReference("vaultSecretFile") != nil || param.GetReference(config.RefTypeVaultDynamicSecret) != nil
 				isSystemTrustSecret := param.GetReference(config.RefTypeSystemTrustSecret) != nil
 				if isVaultSecret && isSystemTrustSecret {
 					secretInfo = fmt.Sprintf(" %s %s %s pass via ENV, Vault, System Trust or Jenkins credentials", vaultBadge, systemTrustBadge, secretBadge)
@@ -347,6 +347,10 @@ func resourceReferenceDetails(resourceRef []config.ResourceReference) string {
 			resourceDetails = addVaultResourceDetails(resource, resourceDetails)
 			continue
 		}
+		if resource.Type == config.RefTypeVaultDynamicSecret {
+			resourceDetails = addVaultDynamicSecretResourceDetails(resource, resourceDetails)
+			continue
+		}
 		if resource.Type == config.RefTypeSystemTrustSecret {
 			resourceDetails = addSystemTrustResourceDetails(resource, resourceDetails)
 		}
@@ -355,6 +359,17 @@ func resourceReferenceDetails(resourceRef []config.ResourceReference) string {
 	return resourceDetails
 }
 
+func addVaultDynamicSecretResourceDetails(resource config.ResourceReference, resourceDetails string) string {
+	resourceDetails += "<br/>Vault dynamic secret:<br />"
+	resourceDetails += fmt.Sprintf("&nbsp;&nbsp;name: `%v`<br />", resource.Name)
+	resourceDetails += fmt.Sprintf("&nbsp;&nbsp;default value: `%v`<br />", resource.Default)
+	if resource.Param != "" {
+		resourceDetails += fmt.Sprintf("&nbsp;&nbsp;field: `%v`<br />", resource.Param)
+	}
+
+	return resourceDetails
+}
+
 func addVaultResourceDetails(resource config.ResourceReference, resourceDetails string) string {
 	resourceDetails += "<br/>Vault resource:<br />"
 	resourceDetails += fmt.Sprintf("&nbsp;&nbsp;name: `%v`<br />", resource.Name)
@@ -0,0 +1,281 @@
+// Package dryrun records the side effects a step would have instead of executing them.
+//
+// If enabled, commands executed via pkg/command, HTTP requests which modify data sent via pkg/http
+// and files written via piperutils.Files are recorded in a plan instead of being executed.
+// Read-only HTTP requests (GET, HEAD, OPTIONS) are still executed since their results are often
+// required to determine the subsequent actions. They are part of the plan as well.
+// Requests which modify data are answered with the response a step provided via SetResponse,
+// or with a placeholder response which can be detected via IsPlaceholder. Requests which do not modify
+// data despite their method, e.g. requests for OAuth tokens, can be sent via SendRequest.
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// ActionCommand is an executable or shell script which would be executed
+	ActionCommand = "command"
+	// ActionHTTP is an HTTP request which would be sent
+	ActionHTTP = "http"
+	// ActionFile is a file system modification which would be performed
+	ActionFile = "file"
+)
+
+// Action describes a single side effect of a step
+type Action struct {
+	Type string `json:"type"`
+	// Command is the command line of an executable or the shell used for a script
+	Command string `json:"command,omitempty"`
+	Script  string `json:"script,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+	// Method and URL describe an HTTP request, Executed is true for requests which have been sent since they do not modify data
+	Method   string `json:"method,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Executed bool   `json:"executed,omitempty"`
+	// Operation and Path describe a file system modification, e.g. write or remove
+	Operation string `json:"operation,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Target    string `json:"target,omitempty"`
+	Size      int    `json:"size,omitempty"`
+}
+
+// Plan contains all actions recorded during a dry run
+type Plan struct {
+	Step    string   `json:"step,omitempty"`
+	Actions []Action `json:"actions"`
+}
+
+// Response is the answer to a request which modifies data and is therefore not sent
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+type responseDefinition struct {
+	method    string
+	urlPrefix string
+	response  Response
+	// send is true for requests which do not modify data despite their method
+	send bool
+}
+
+// PlaceholderHeader marks responses to requests which have not been sent
+const PlaceholderHeader = "X-Piper-Dry-Run"
+
+// PlaceholderBody is the body of the placeholder response to requests without a response provided by the step
+const PlaceholderBody = `{"dryRun":true,"message":"placeholder response, the request has not been sent"}`
+
+var (
+	mu        sync.Mutex
+	enabled   bool
+	plan      = Plan{Actions: []Action{}}
+	responses []responseDefinition
+)
+
+// Enable activates the dry run mode
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
+
+// Enabled returns true if the dry run mode is active
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabled
+}
+
+// Reset deactivates the dry run mode and removes all recorded actions, intended for tests
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = false
+	plan = Plan{Actions: []Action{}}
+	responses = nil
+}
+
+// SetResponse defines the response to requests with the method whose URL starts with the prefix.
+// Steps use it to provide a response their subsequent processing can rely on, the latest matching definition wins.
+func SetResponse(method, urlPrefix string, response Response) {
+	mu.Lock()
+	defer mu.Unlock()
+	responses = append(responses, responseDefinition{method: method, urlPrefix: urlPrefix, response: response})
+}
+
+// SendRequest defines that requests with the method whose URL starts with the prefix do not modify data despite
+// their method and are sent, e.g. requests for OAuth tokens.
+func SendRequest(method, urlPrefix string) {
+	mu.Lock()
+	defer mu.Unlock()
+	responses = append(responses, responseDefinition{method: method, urlPrefix: urlPrefix, send: true})
+}
+
+// IsPlaceholder returns true for the placeholder response to a request which has not been sent
+func IsPlaceholder(response *http.Response) bool {
+	return response != nil && response.Header.Get(PlaceholderHeader) == "placeholder"
+}
+
+func responseFor(method, url string) (responseDefinition, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i := len(responses) - 1; i >= 0; i-- {
+		if responses[i].method == method && strings.HasPrefix(url, responses[i].urlPrefix) {
+			return responses[i], true
+		}
+	}
+	return responseDefinition{}, false
+}
+
+// SetStepName sets the name of the step the plan belongs to
+func SetStepName(stepName string) {
+	mu.Lock()
+	defer mu.Unlock()
+	plan.Step = stepName
+}
+
+// Record adds an action to the plan
+func Record(action Action) {
+	mu.Lock()
+	defer mu.Unlock()
+	plan.Actions = append(plan.Actions, action)
+	log.Entry().Infof("[dry run] %v", action)
+}
+
+// RecordCommand records an executable with its parameters
+func RecordCommand(dir, executable string, params ...string) {
+	Record(Action{Type: ActionCommand, Command: strings.TrimSpace(executable + " " + strings.Join(params, " ")), Dir: dir})
+}
+
+// RecordFile records a file system modification
+func RecordFile(operation, path string) {
+	Record(Action{Type: ActionFile, Operation: operation, Path: path})
+}
+
+// GetPlan returns a copy of the recorded plan
+func GetPlan() Plan {
+	mu.Lock()
+	defer mu.Unlock()
+	return Plan{Step: plan.Step, Actions: append([]Action{}, plan.Actions...)}
+}
+
+// String returns a human-readable description of the action
+func (a Action) String() string {
+	switch a.Type {
+	case ActionCommand:
+		description := "run " + a.Command
+		if len(a.Script) > 0 {
+			description = fmt.Sprintf("run %v script: %v", a.Command, a.Script)
+		}
+		if len(a.Dir) > 0 {
+			description += fmt.Sprintf(" (in %v)", a.Dir)
+		}
+		return description
+	case ActionHTTP:
+		description := fmt.Sprintf("%v %v", a.Method, a.URL)
+		if a.Executed {
+			description += " (executed)"
+		}
+		return description
+	case ActionFile:
+		description := fmt.Sprintf("%v %v", a.Operation, a.Path)
+		if len(a.Target) > 0 {
+			description += " -> " + a.Target
+		}
+		if a.Size > 0 {
+			description += fmt.Sprintf(" (%v bytes)", a.Size)
+		}
+		return description
+	}
+	return a.Type
+}
+
+// Text returns the plan in a human-readable format, secrets are masked
+func (p Plan) Text() string {
+	var text strings.Builder
+	if len(p.Step) > 0 {
+		fmt.Fprintf(&text, "Dry run plan of step %v:\n", p.Step)
+	} else {
+		text.WriteString("Dry run plan:\n")
+	}
+	if len(p.Actions) == 0 {
+		text.WriteString("  no actions\n")
+	}
+	for i, action := range p.Actions {
+		fmt.Fprintf(&text, "  %3d. %-7v %v\n", i+1, action.Type, action)
+	}
+	return log.MaskSecrets(text.String())
+}
+
+// JSON returns the plan in JSON format, secrets are masked
+func (p Plan) JSON() ([]byte, error) {
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(log.MaskSecrets(string(content))), nil
+}
+
+// Transport returns an http.RoundTripper which records requests. Requests which modify data are not sent
+// and answered with the response provided via SetResponse, or a placeholder response with status code 200.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	action := Action{Type: ActionHTTP, Method: req.Method, URL: req.URL.Redacted()}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		action.Executed = true
+		Record(action)
+		return t.next.RoundTrip(req)
+	}
+	definition, ok := responseFor(req.Method, req.URL.String())
+	if ok && definition.send {
+		action.Executed = true
+		Record(action)
+		return t.next.RoundTrip(req)
+	}
+	Record(action)
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	response := definition.response
+	header := http.Header{PlaceholderHeader: []string{"step"}}
+	if !ok {
+		log.Entry().Warnf("[dry run] %v %v has not been sent, continuing with a placeholder response", req.Method, req.URL.Redacted())
+		response = Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"application/json"}}, Body: PlaceholderBody}
+		header.Set(PlaceholderHeader, "placeholder")
+	}
+	for name, values := range response.Header {
+		header[name] = values
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%v %v (dry run)", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
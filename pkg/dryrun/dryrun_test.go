@@ -0,0 +1,145 @@
+//go:build unit
+// +build unit
+
+package dryrun
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	t.Cleanup(Reset)
+	Reset()
+	assert.False(t, Enabled())
+	Enable()
+	assert.True(t, Enabled())
+
+	SetStepName("cloudFoundryDeploy")
+	RecordCommand("app", "cf", "push", "myApp")
+	Record(Action{Type: ActionCommand, Command: "/bin/bash", Script: "echo hello"})
+	RecordFile("remove", "manifest.yml")
+	Record(Action{Type: ActionFile, Operation: "write", Path: "out.json", Size: 42})
+	Record(Action{Type: ActionHTTP, Method: http.MethodGet, URL: "https://example.org/api", Executed: true})
+
+	plan := GetPlan()
+	assert.Equal(t, "cloudFoundryDeploy", plan.Step)
+	require.Len(t, plan.Actions, 5)
+	assert.Equal(t, Action{Type: ActionCommand, Command: "cf push myApp", Dir: "app"}, plan.Actions[0])
+
+	t.Run("text", func(t *testing.T) {
+		text := plan.Text()
+		assert.Contains(t, text, "Dry run plan of step cloudFoundryDeploy:")
+		assert.Contains(t, text, "run cf push myApp (in app)")
+		assert.Contains(t, text, "run /bin/bash script: echo hello")
+		assert.Contains(t, text, "remove manifest.yml")
+		assert.Contains(t, text, "write out.json (42 bytes)")
+		assert.Contains(t, text, "GET https://example.org/api (executed)")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content, err := plan.JSON()
+		require.NoError(t, err)
+		var parsed Plan
+		require.NoError(t, json.Unmarshal(content, &parsed))
+		assert.Equal(t, plan, parsed)
+		assert.NotContains(t, string(content), "target")
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		Reset()
+		assert.False(t, Enabled())
+		assert.Empty(t, GetPlan().Actions)
+		assert.Contains(t, GetPlan().Text(), "no actions")
+	})
+}
+
+func TestPlanMasksSecrets(t *testing.T) {
+	t.Cleanup(Reset)
+	log.RegisterSecret("dryRunSecret")
+	plan := Plan{Actions: []Action{{Type: ActionCommand, Command: "cf login -p dryRunSecret"}}}
+
+	assert.NotContains(t, plan.Text(), "dryRunSecret")
+	content, err := plan.JSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "dryRunSecret")
+	assert.Contains(t, string(content), "cf login -p ****")
+}
+
+func TestTransport(t *testing.T) {
+	t.Cleanup(Reset)
+	Reset()
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	t.Run("read-only request is executed", func(t *testing.T) {
+		response, err := client.Get(server.URL + "/info")
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, "content", string(body))
+	})
+
+	t.Run("modifying request is recorded only", func(t *testing.T) {
+		response, err := client.Post(server.URL+"/upload", "text/plain", strings.NewReader("data"))
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.True(t, IsPlaceholder(response))
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, PlaceholderBody, string(body))
+	})
+
+	t.Run("modifying request is answered with the response of the step", func(t *testing.T) {
+		SetResponse(http.MethodPost, server.URL+"/deployments", Response{StatusCode: http.StatusCreated, Header: http.Header{"Location": []string{"/deployments/1"}}, Body: `{"id":"1"}`})
+
+		response, err := client.Post(server.URL+"/deployments?async=true", "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, "/deployments/1", response.Header.Get("Location"))
+		assert.False(t, IsPlaceholder(response))
+		assert.Equal(t, "step", response.Header.Get(PlaceholderHeader))
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, `{"id":"1"}`, string(body))
+
+		// other methods are not affected
+		request, _ := http.NewRequest(http.MethodDelete, server.URL+"/deployments/1", nil)
+		response, err = client.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.True(t, IsPlaceholder(response))
+	})
+
+	t.Run("request which does not modify data is executed", func(t *testing.T) {
+		SendRequest(http.MethodPost, server.URL+"/oauth/token")
+
+		response, err := client.Post(server.URL+"/oauth/token?grant_type=client_credentials", "application/x-www-form-urlencoded", strings.NewReader(""))
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, "content", string(body))
+	})
+
+	assert.Equal(t, []string{http.MethodGet, http.MethodPost}, requests)
+	assert.Equal(t, []Action{
+		{Type: ActionHTTP, Method: http.MethodGet, URL: server.URL + "/info", Executed: true},
+		{Type: ActionHTTP, Method: http.MethodPost, URL: server.URL + "/upload"},
+		{Type: ActionHTTP, Method: http.MethodPost, URL: server.URL + "/deployments?async=true"},
+		{Type: ActionHTTP, Method: http.MethodDelete, URL: server.URL + "/deployments/1"},
+		{Type: ActionHTTP, Method: http.MethodPost, URL: server.URL + "/oauth/token?grant_type=client_credentials", Executed: true},
+	}, GetPlan().Actions)
+}
@@ -0,0 +1,166 @@
+package errorpatterns
+
+import (
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// APIVersion is the supported format version of error pattern catalogues
+const APIVersion = "v1"
+
+//go:embed catalogue.yaml
+var defaultCatalogue []byte
+
+// Catalogue contains the error patterns of the supported tools
+type Catalogue struct {
+	APIVersion string `json:"apiVersion"`
+	// Version identifies the content of the catalogue and is reported together with a matched hint
+	Version string `json:"version"`
+	// Tools contains the patterns per tool, the key is the name of the executable, e.g. mvn
+	Tools map[string][]Pattern `json:"tools"`
+}
+
+// Pattern describes an error which can be detected in the console output of a tool
+type Pattern struct {
+	ID string `json:"id"`
+	// Pattern is a regular expression which is matched against each line of the output
+	Pattern       string `json:"pattern"`
+	Category      string `json:"category"`
+	Hint          string `json:"hint"`
+	Documentation string `json:"documentation,omitempty"`
+	regex         *regexp.Regexp
+}
+
+// Match describes a pattern which matched a line of the output of a tool
+type Match struct {
+	Tool             string
+	Line             string
+	Pattern          Pattern
+	CatalogueVersion string
+}
+
+var (
+	catalogues []*Catalogue
+	mutex      sync.RWMutex
+)
+
+// Parse reads a catalogue in YAML format and validates its patterns
+func Parse(content []byte) (*Catalogue, error) {
+	var catalogue Catalogue
+	if err := yaml.Unmarshal(content, &catalogue); err != nil {
+		return nil, errors.Wrap(err, "format of error pattern catalogue is invalid")
+	}
+	if catalogue.APIVersion != APIVersion {
+		return nil, errors.Errorf("unsupported apiVersion '%v' of error pattern catalogue, expected '%v'", catalogue.APIVersion, APIVersion)
+	}
+	for tool, patterns := range catalogue.Tools {
+		for i := range patterns {
+			pattern := &patterns[i]
+			if len(pattern.ID) == 0 || len(pattern.Pattern) == 0 {
+				return nil, errors.Errorf("error pattern %v of tool '%v' requires an id and a pattern", i+1, tool)
+			}
+			if category := log.ErrorCategoryByString(pattern.Category); category == log.ErrorUndefined {
+				return nil, errors.Errorf("error pattern '%v' of tool '%v' has an invalid category '%v'", pattern.ID, tool, pattern.Category)
+			}
+			regex, err := regexp.Compile(pattern.Pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error pattern '%v' of tool '%v' is not a valid regular expression", pattern.ID, tool)
+			}
+			pattern.regex = regex
+		}
+	}
+	return &catalogue, nil
+}
+
+// LoadDefault registers the catalogue which is shipped with the piper binary
+func LoadDefault() error {
+	catalogue, err := Parse(defaultCatalogue)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the default error pattern catalogue")
+	}
+	Register(catalogue)
+	return nil
+}
+
+// Register activates a catalogue, the patterns of catalogues registered later take precedence
+func Register(catalogue *Catalogue) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	catalogues = append(catalogues, catalogue)
+}
+
+// Reset removes all registered catalogues
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	catalogues = nil
+}
+
+// ToolName returns the name of the tool the patterns are registered for based on the executable
+func ToolName(executable string) string {
+	name := filepath.Base(executable)
+	for _, extension := range []string{".exe", ".cmd", ".bat"} {
+		name = strings.TrimSuffix(name, extension)
+	}
+	return name
+}
+
+// HasPatterns reports whether any registered catalogue contains patterns for the tool
+func HasPatterns(tool string) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for _, catalogue := range catalogues {
+		if len(catalogue.Tools[tool]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the first pattern of the tool matching the line
+func Find(tool, line string) (Match, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	for i := len(catalogues) - 1; i >= 0; i-- {
+		for _, pattern := range catalogues[i].Tools[tool] {
+			if pattern.regex != nil && pattern.regex.MatchString(line) {
+				return Match{Tool: tool, Line: line, Pattern: pattern, CatalogueVersion: catalogues[i].Version}, true
+			}
+		}
+	}
+	return Match{}, false
+}
+
+// Apply sets the error category and the hint for the fatal error details if the line matches a pattern of the tool
+func Apply(tool, line string) bool {
+	match, ok := Find(tool, line)
+	if !ok {
+		return false
+	}
+	log.Entry().Debugf("output of %v matches error pattern '%v'", tool, match.Pattern.ID)
+	log.SetErrorCategory(log.ErrorCategoryByString(match.Pattern.Category))
+	log.SetErrorHint(&log.ErrorHint{
+		Hint:             match.Pattern.Hint,
+		Documentation:    match.Pattern.Documentation,
+		PatternID:        match.Pattern.ID,
+		CatalogueVersion: match.CatalogueVersion,
+	})
+	return true
+}
+
+// String returns a short description of the catalogue
+func (c *Catalogue) String() string {
+	count := 0
+	for _, patterns := range c.Tools {
+		count += len(patterns)
+	}
+	return fmt.Sprintf("error pattern catalogue %v with %v patterns", c.Version, count)
+}
@@ -0,0 +1,138 @@
+//go:build unit
+// +build unit
+
+package errorpatterns
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCatalogue = `apiVersion: v1
+version: "2.0.0"
+tools:
+  mvn:
+    - id: custom-nexus-down
+      pattern: 'Could not transfer artifact .*nexus\.acme\.corp'
+      category: service
+      hint: Nexus is down, see the status page.
+      documentation: https://status.acme.corp
+`
+
+func TestParse(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		catalogue, err := Parse([]byte(testCatalogue))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2.0.0", catalogue.Version)
+			assert.Len(t, catalogue.Tools["mvn"], 1)
+			assert.Equal(t, "error pattern catalogue 2.0.0 with 1 patterns", catalogue.String())
+		}
+	})
+
+	t.Run("default catalogue", func(t *testing.T) {
+		_, err := Parse(defaultCatalogue)
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "invalid yaml", content: "tools: [", expected: "format of error pattern catalogue is invalid"},
+		{name: "unsupported version", content: "apiVersion: v2", expected: "unsupported apiVersion 'v2' of error pattern catalogue, expected 'v1'"},
+		{name: "missing pattern", content: "apiVersion: v1\ntools:\n  mvn:\n    - id: x\n      category: build", expected: "error pattern 1 of tool 'mvn' requires an id and a pattern"},
+		{name: "invalid category", content: "apiVersion: v1\ntools:\n  mvn:\n    - id: x\n      pattern: y\n      category: other", expected: "error pattern 'x' of tool 'mvn' has an invalid category 'other'"},
+		{name: "invalid regex", content: "apiVersion: v1\ntools:\n  mvn:\n    - id: x\n      pattern: '('\n      category: build", expected: "error pattern 'x' of tool 'mvn' is not a valid regular expression"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.content))
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	Reset()
+	defer Reset()
+	assert.NoError(t, LoadDefault())
+
+	tests := []struct {
+		tool string
+		line string
+		id   string
+	}{
+		{tool: "mvn", line: "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.acme:app:jar:1.0: Could not transfer artifact com.acme:lib:jar:1.0 from/to central (https://repo.maven.apache.org/maven2): Connection reset", id: "mvn-repository-unreachable"},
+		{tool: "mvn", line: "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.acme:app:jar:1.0: Could not find artifact com.acme:lib:jar:1.0 in central (https://repo.maven.apache.org/maven2)", id: "mvn-artifact-not-found"},
+		{tool: "mvn", line: "[ERROR] Tests run: 12, Failures: 2, Errors: 0, Skipped: 0", id: "mvn-test-failures"},
+		{tool: "npm", line: "npm ERR! code E401", id: "npm-unauthorized"},
+		{tool: "npm", line: "npm error code ECONNRESET", id: "npm-network"},
+		{tool: "npm", line: "npm ERR! `npm ci` can only install packages when your package.json and package-lock.json or npm-shrinkwrap.json are in sync.", id: "npm-lockfile-out-of-sync"},
+		{tool: "cf", line: "Credentials were rejected, please try again.", id: "cf-credentials-rejected"},
+		{tool: "cf", line: "Start unsuccessful", id: "cf-app-start-failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			match, ok := Find(test.tool, test.line)
+			if assert.True(t, ok) {
+				assert.Equal(t, test.id, match.Pattern.ID)
+				assert.Equal(t, "1.0.0", match.CatalogueVersion)
+				assert.NotEmpty(t, match.Pattern.Hint)
+			}
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		_, ok := Find("mvn", "[INFO] BUILD SUCCESS")
+		assert.False(t, ok)
+		_, ok = Find("npm", "Tests run: 12, Failures: 2")
+		assert.False(t, ok)
+	})
+
+	t.Run("custom catalogue takes precedence", func(t *testing.T) {
+		catalogue, err := Parse([]byte(testCatalogue))
+		assert.NoError(t, err)
+		Register(catalogue)
+
+		match, ok := Find("mvn", "Could not transfer artifact com.acme:lib:jar:1.0 from/to nexus (https://nexus.acme.corp): Connection reset")
+		if assert.True(t, ok) {
+			assert.Equal(t, "custom-nexus-down", match.Pattern.ID)
+			assert.Equal(t, "2.0.0", match.CatalogueVersion)
+		}
+	})
+}
+
+func TestApply(t *testing.T) {
+	Reset()
+	defer Reset()
+	defer log.SetErrorCategory(log.ErrorUndefined)
+	defer log.SetErrorHint(nil)
+	catalogue, err := Parse([]byte(testCatalogue))
+	assert.NoError(t, err)
+	Register(catalogue)
+
+	assert.True(t, HasPatterns("mvn"))
+	assert.False(t, HasPatterns("npm"))
+
+	assert.False(t, Apply("mvn", "[INFO] BUILD SUCCESS"))
+	assert.Nil(t, log.GetErrorHint())
+
+	assert.True(t, Apply("mvn", "Could not transfer artifact a:b:jar:1 from/to nexus (https://nexus.acme.corp)"))
+	assert.Equal(t, log.ErrorService, log.GetErrorCategory())
+	assert.Equal(t, &log.ErrorHint{
+		Hint:             "Nexus is down, see the status page.",
+		Documentation:    "https://status.acme.corp",
+		PatternID:        "custom-nexus-down",
+		CatalogueVersion: "2.0.0",
+	}, log.GetErrorHint())
+}
+
+func TestToolName(t *testing.T) {
+	assert.Equal(t, "mvn", ToolName("mvn"))
+	assert.Equal(t, "mvn", ToolName("/usr/share/maven/bin/mvn"))
+	assert.Equal(t, "npm", ToolName("npm.cmd"))
+	assert.Equal(t, "cf", ToolName("cf.exe"))
+}
@@ -0,0 +1,299 @@
+package format
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Normalized severities of a Finding, ordered from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Normalized states of a Finding
+const (
+	// FindingStatusOpen marks findings which still need to be addressed
+	FindingStatusOpen = "open"
+	// FindingStatusSuppressed marks findings which were audited as not being an issue
+	FindingStatusSuppressed = "suppressed"
+	// FindingStatusFixed marks findings which were remediated
+	FindingStatusFixed = "fixed"
+)
+
+var severityRanks = map[string]int{SeverityCritical: 5, SeverityHigh: 4, SeverityMedium: 3, SeverityLow: 2, SeverityInfo: 1}
+
+// Finding is the scanner independent representation of a result of a security or quality scan.
+// Scanners which provide SARIF, e.g. Fortify, Checkmarx and CodeQL, are supported via FindingsFromSARIF.
+type Finding struct {
+	// Tool is the scanner which reported the finding, after deduplication the first of Tools
+	Tool  string   `json:"tool"`
+	Tools []string `json:"tools,omitempty"`
+	Rule  string   `json:"rule,omitempty"`
+	// CWE is the number of the weakness without prefix, e.g. 79
+	CWE        string           `json:"cwe,omitempty"`
+	CVE        string           `json:"cve,omitempty"`
+	PackageURL string           `json:"purl,omitempty"`
+	Location   *FindingLocation `json:"location,omitempty"`
+	Severity   string           `json:"severity"`
+	Status     string           `json:"status"`
+	// AuditState is the audit state as reported by the scanner, e.g. NOT_EXPLOITABLE
+	AuditState string `json:"auditState,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// FindingLocation is the place in the source code a finding refers to
+type FindingLocation struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+}
+
+var cwePattern = regexp.MustCompile(`(?i)^(?:external/cwe/)?cwe-(\d+)$`)
+
+// NormalizeCWE returns the number of a CWE given e.g. as "CWE-79", "external/cwe/cwe-079" or "79"
+func NormalizeCWE(cwe string) string {
+	cwe = strings.TrimSpace(cwe)
+	if matches := cwePattern.FindStringSubmatch(cwe); matches != nil {
+		cwe = matches[1]
+	}
+	// CodeQL pads the numbers with zeros, e.g. cwe-079
+	if number, err := strconv.Atoi(cwe); err == nil && number > 0 {
+		return strconv.Itoa(number)
+	}
+	return ""
+}
+
+// NormalizeSeverity maps the severities of the different scanners to the normalized severities
+func NormalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical", "blocker", "urgent":
+		return SeverityCritical
+	case "high", "error", "major":
+		return SeverityHigh
+	case "medium", "warning", "moderate":
+		return SeverityMedium
+	case "low", "note", "minor":
+		return SeverityLow
+	}
+	return SeverityInfo
+}
+
+// SeverityFromScore maps a CVSS score to the normalized severities
+func SeverityFromScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityInfo
+}
+
+// Key identifies findings which describe the same issue, also when reported by different scanners:
+// dependency findings by CVE and package URL, code findings by CWE and location.
+func (f Finding) Key() string {
+	if len(f.CVE) > 0 && len(f.PackageURL) > 0 {
+		return strings.ToLower(fmt.Sprintf("cve:%v|%v", f.CVE, f.PackageURL))
+	}
+	if len(f.CWE) > 0 && f.Location != nil && len(f.Location.File) > 0 {
+		return strings.ToLower(fmt.Sprintf("cwe:%v|%v:%v", f.CWE, normalizeFile(f.Location.File), f.Location.Line))
+	}
+	// findings without the information are only identical to findings of the same tool
+	location := ""
+	if f.Location != nil {
+		location = fmt.Sprintf("%v:%v", normalizeFile(f.Location.File), f.Location.Line)
+	}
+	return fmt.Sprintf("%v|%v|%v|%v|%v|%v", f.Tool, f.Rule, f.CVE, f.PackageURL, location, f.Message)
+}
+
+func normalizeFile(file string) string {
+	file = strings.TrimPrefix(file, "file://")
+	file = path.Clean(strings.ReplaceAll(file, "\\", "/"))
+	return strings.TrimPrefix(strings.TrimPrefix(file, "./"), "/")
+}
+
+// DeduplicateFindings merges findings with the same Key, keeping the order in which they were found.
+// A merged finding lists all tools which reported it, has the highest severity reported and stays open
+// as long as one tool considers it open.
+func DeduplicateFindings(findings []Finding) []Finding {
+	merged := []Finding{}
+	index := map[string]int{}
+	for _, finding := range findings {
+		if len(finding.Tools) == 0 {
+			finding.Tools = []string{finding.Tool}
+		}
+		key := finding.Key()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, finding)
+			continue
+		}
+		merged[i] = mergeFindings(merged[i], finding)
+	}
+	return merged
+}
+
+func mergeFindings(f, other Finding) Finding {
+	tools := append([]string{}, f.Tools...)
+	for _, tool := range other.Tools {
+		if !slices.Contains(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+	f.Tools = tools
+	if severityRanks[other.Severity] > severityRanks[f.Severity] {
+		f.Severity = other.Severity
+	}
+	if other.Status == FindingStatusOpen || len(f.Status) == 0 {
+		f.Status = other.Status
+	}
+	f.Rule = firstNonEmpty(f.Rule, other.Rule)
+	f.CWE = firstNonEmpty(f.CWE, other.CWE)
+	f.CVE = firstNonEmpty(f.CVE, other.CVE)
+	f.PackageURL = firstNonEmpty(f.PackageURL, other.PackageURL)
+	f.AuditState = firstNonEmpty(f.AuditState, other.AuditState)
+	f.Message = firstNonEmpty(f.Message, other.Message)
+	if f.Location == nil {
+		f.Location = other.Location
+	}
+	return f
+}
+
+// SortFindings orders findings by severity, most severe first, and by tool
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if severityRanks[findings[i].Severity] != severityRanks[findings[j].Severity] {
+			return severityRanks[findings[i].Severity] > severityRanks[findings[j].Severity]
+		}
+		return findings[i].Tool < findings[j].Tool
+	})
+}
+
+// FindingsFromSARIF converts the results of all runs of a SARIF file into findings
+func FindingsFromSARIF(sarif SARIF) []Finding {
+	findings := []Finding{}
+	for _, run := range sarif.Runs {
+		for _, result := range run.Results {
+			rule := sarifRule(run.Tool.Driver.Rules, result)
+			finding := Finding{
+				Tool:     run.Tool.Driver.Name,
+				Rule:     result.RuleID,
+				CWE:      sarifCWE(rule),
+				Severity: sarifSeverity(rule, result),
+				Status:   FindingStatusOpen,
+			}
+			if result.Message != nil {
+				finding.Message = result.Message.Text
+			}
+			if len(result.Locations) > 0 {
+				location := result.Locations[0].PhysicalLocation
+				finding.Location = &FindingLocation{File: location.ArtifactLocation.URI, Line: location.Region.StartLine}
+			}
+			if result.Properties != nil {
+				finding.AuditState = result.Properties.ToolState
+				if isSuppressedAuditState(result.Properties.ToolState) || isSuppressedAuditState(result.Properties.UnifiedAuditState) {
+					finding.Status = FindingStatusSuppressed
+				}
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func sarifRule(rules []SarifRule, result Results) *SarifRule {
+	if result.RuleIndex >= 0 && result.RuleIndex < len(rules) && rules[result.RuleIndex].ID == result.RuleID {
+		return &rules[result.RuleIndex]
+	}
+	for i := range rules {
+		if rules[i].ID == result.RuleID {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func sarifCWE(rule *SarifRule) string {
+	if rule == nil {
+		return ""
+	}
+	for _, relationship := range rule.Relationships {
+		if strings.EqualFold(relationship.Target.ToolComponent.Name, "CWE") {
+			if cwe := NormalizeCWE(relationship.Target.Id); len(cwe) > 0 {
+				return cwe
+			}
+		}
+	}
+	if rule.Properties != nil {
+		for _, tag := range rule.Properties.Tags {
+			if cwePattern.MatchString(tag) {
+				return NormalizeCWE(tag)
+			}
+		}
+	}
+	return ""
+}
+
+func sarifSeverity(rule *SarifRule, result Results) string {
+	if result.Properties != nil {
+		if len(result.Properties.UnifiedSeverity) > 0 {
+			return NormalizeSeverity(result.Properties.UnifiedSeverity)
+		}
+		if len(result.Properties.ToolSeverity) > 0 {
+			return NormalizeSeverity(result.Properties.ToolSeverity)
+		}
+	}
+	if rule != nil && rule.Properties != nil && len(rule.Properties.SecuritySeverity) > 0 {
+		if score, err := strconv.ParseFloat(rule.Properties.SecuritySeverity, 64); err == nil {
+			return SeverityFromScore(score)
+		}
+	}
+	level := result.Level
+	if len(level) == 0 && rule != nil && rule.DefaultConfiguration != nil {
+		level = rule.DefaultConfiguration.Level
+	}
+	return NormalizeSeverity(level)
+}
+
+// isSuppressedAuditState checks whether the audit state of Checkmarx or Fortify marks a finding as no issue
+func isSuppressedAuditState(state string) bool {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(state), " ", "_")) {
+	case "NOT_EXPLOITABLE", "NOT_AN_ISSUE", "FALSE_POSITIVE":
+		return true
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+var cvePattern = regexp.MustCompile(`(?i)CVE-\d{4}-\d+`)
+
+// FindCVE returns the first CVE id contained in the values, e.g. in the name or the link of a vulnerability
+func FindCVE(values ...string) string {
+	for _, value := range values {
+		if cve := cvePattern.FindString(value); len(cve) > 0 {
+			return strings.ToUpper(cve)
+		}
+	}
+	return ""
+}
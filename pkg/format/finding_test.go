@@ -0,0 +1,109 @@
+//go:build unit
+// +build unit
+
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCWE(t *testing.T) {
+	assert.Equal(t, "79", NormalizeCWE("CWE-79"))
+	assert.Equal(t, "79", NormalizeCWE("external/cwe/cwe-079"))
+	assert.Equal(t, "79", NormalizeCWE("79"))
+	assert.Equal(t, "", NormalizeCWE("0"))
+	assert.Equal(t, "", NormalizeCWE("owasp-a1"))
+}
+
+func TestNormalizeSeverity(t *testing.T) {
+	assert.Equal(t, SeverityCritical, NormalizeSeverity("BLOCKER"))
+	assert.Equal(t, SeverityHigh, NormalizeSeverity("error"))
+	assert.Equal(t, SeverityMedium, NormalizeSeverity("Medium"))
+	assert.Equal(t, SeverityLow, NormalizeSeverity("note"))
+	assert.Equal(t, SeverityInfo, NormalizeSeverity("INFORMATION"))
+	assert.Equal(t, SeverityCritical, SeverityFromScore(9.8))
+	assert.Equal(t, SeverityLow, SeverityFromScore(3.7))
+	assert.Equal(t, SeverityInfo, SeverityFromScore(0))
+}
+
+func TestFindCVE(t *testing.T) {
+	assert.Equal(t, "CVE-2021-44228", FindCVE("BDSA-2021-3779", "https://nvd.nist.gov/vuln/detail/cve-2021-44228"))
+	assert.Equal(t, "", FindCVE("WS-2020-0001"))
+}
+
+func TestDeduplicateFindings(t *testing.T) {
+	t.Run("dependencies by CVE and package URL", func(t *testing.T) {
+		findings := []Finding{
+			{Tool: "BlackDuck", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: SeverityHigh, Status: FindingStatusSuppressed, AuditState: "IGNORED"},
+			{Tool: "Mend", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: SeverityCritical, Status: FindingStatusOpen},
+			{Tool: "Mend", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1", Severity: SeverityCritical, Status: FindingStatusOpen},
+		}
+
+		deduplicated := DeduplicateFindings(findings)
+
+		if assert.Len(t, deduplicated, 2) {
+			assert.Equal(t, []string{"BlackDuck", "Mend"}, deduplicated[0].Tools)
+			assert.Equal(t, SeverityCritical, deduplicated[0].Severity)
+			assert.Equal(t, FindingStatusOpen, deduplicated[0].Status)
+			assert.Equal(t, "IGNORED", deduplicated[0].AuditState)
+			assert.Equal(t, []string{"Mend"}, deduplicated[1].Tools)
+		}
+	})
+
+	t.Run("code by CWE and location", func(t *testing.T) {
+		findings := []Finding{
+			{Tool: "CheckmarxOne", Rule: "Reflected_XSS", CWE: "79", Location: &FindingLocation{File: "/src/main/App.java", Line: 12}, Severity: SeverityHigh},
+			{Tool: "CodeQL", Rule: "java/xss", CWE: "79", Location: &FindingLocation{File: "src/main/App.java", Line: 12}, Severity: SeverityMedium},
+			{Tool: "CodeQL", Rule: "java/xss", CWE: "79", Location: &FindingLocation{File: "src/main/App.java", Line: 20}, Severity: SeverityMedium},
+			{Tool: "SonarQube", Rule: "java:S1234", Location: &FindingLocation{File: "src/main/App.java", Line: 12}, Severity: SeverityLow},
+		}
+
+		deduplicated := DeduplicateFindings(findings)
+
+		if assert.Len(t, deduplicated, 3) {
+			assert.Equal(t, []string{"CheckmarxOne", "CodeQL"}, deduplicated[0].Tools)
+			assert.Equal(t, "Reflected_XSS", deduplicated[0].Rule)
+			assert.Equal(t, SeverityHigh, deduplicated[0].Severity)
+			assert.Equal(t, 20, deduplicated[1].Location.Line)
+			assert.Equal(t, []string{"SonarQube"}, deduplicated[2].Tools)
+		}
+	})
+}
+
+func TestSortFindings(t *testing.T) {
+	findings := []Finding{{Tool: "b", Severity: SeverityLow}, {Tool: "b", Severity: SeverityCritical}, {Tool: "a", Severity: SeverityLow}}
+
+	SortFindings(findings)
+
+	assert.Equal(t, []Finding{{Tool: "b", Severity: SeverityCritical}, {Tool: "a", Severity: SeverityLow}, {Tool: "b", Severity: SeverityLow}}, findings)
+}
+
+func TestFindingsFromSARIF(t *testing.T) {
+	sarif := SARIF{Runs: []Runs{{
+		Tool: Tool{Driver: Driver{Name: "CodeQL", Rules: []SarifRule{
+			{ID: "java/xss", Properties: &SarifRuleProperties{Tags: []string{"security", "external/cwe/cwe-079"}, SecuritySeverity: "6.1"}},
+			{ID: "fortify/sql", Relationships: []Relationships{{Target: Target{Id: "89", ToolComponent: ToolComponent{Name: "CWE"}}}}},
+		}}},
+		Results: []Results{
+			{
+				RuleID:    "java/xss",
+				Message:   &Message{Text: "Cross-site scripting"},
+				Locations: []Location{{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: "src/App.java"}, Region: Region{StartLine: 12}}}},
+			},
+			{
+				RuleID:     "fortify/sql",
+				RuleIndex:  1,
+				Properties: &SarifProperties{ToolSeverity: "Critical", ToolState: "Not an Issue"},
+			},
+		},
+	}}}
+
+	findings := FindingsFromSARIF(sarif)
+
+	assert.Equal(t, []Finding{
+		{Tool: "CodeQL", Rule: "java/xss", CWE: "79", Location: &FindingLocation{File: "src/App.java", Line: 12}, Severity: SeverityMedium, Status: FindingStatusOpen, Message: "Cross-site scripting"},
+		{Tool: "CodeQL", Rule: "fortify/sql", CWE: "89", Severity: SeverityCritical, Status: FindingStatusSuppressed, AuditState: "Not an Issue"},
+	}, findings)
+}
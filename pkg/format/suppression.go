@@ -0,0 +1,167 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Suppression is a reviewed exception for findings which are no issue, maintained in the repository next to the code.
+// Contrary to audits in the UI of a scanner it travels with the code and is reviewed like it.
+type Suppression struct {
+	// ID is the rule, e.g. js/xss, the CWE, e.g. CWE-79, or the CVE, e.g. CVE-2021-44228, of the suppressed findings, * is a wildcard
+	ID string `json:"id"`
+	// Path is a glob pattern of the files the suppression applies to, for findings in dependencies it is matched against the package URL
+	Path string `json:"path,omitempty"`
+	// Expires is the last day the suppression applies, e.g. 2026-12-31, an empty value never expires
+	Expires       string `json:"expires,omitempty"`
+	Justification string `json:"justification"`
+	Approver      string `json:"approver"`
+
+	expiryDate time.Time
+}
+
+// Suppressions are the suppressions of a suppression file
+type Suppressions []Suppression
+
+// ReadSuppressions loads the suppressions of a suppression file and validates them
+func ReadSuppressions(suppressionFile io.ReadCloser, fileName string) (Suppressions, error) {
+	defer suppressionFile.Close()
+	file := struct {
+		Suppressions Suppressions `json:"suppressions"`
+	}{}
+
+	content, err := io.ReadAll(suppressionFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading %v", fileName)
+	}
+
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, NewParseError(fmt.Sprintf("format of suppression file is invalid: %v", err))
+	}
+	for i := range file.Suppressions {
+		if err := file.Suppressions[i].validate(); err != nil {
+			return nil, NewParseError(fmt.Sprintf("suppression %v is invalid: %v", i+1, err))
+		}
+	}
+	return file.Suppressions, nil
+}
+
+func (s *Suppression) validate() error {
+	missing := []string{}
+	if len(strings.TrimSpace(s.ID)) == 0 {
+		missing = append(missing, "id")
+	}
+	if len(strings.TrimSpace(s.Justification)) == 0 {
+		missing = append(missing, "justification")
+	}
+	if len(strings.TrimSpace(s.Approver)) == 0 {
+		missing = append(missing, "approver")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %v", strings.Join(missing, ", "))
+	}
+	if len(s.Expires) > 0 {
+		expiryDate, err := time.Parse("2006-01-02", s.Expires)
+		if err != nil {
+			// YAML dates are converted into timestamps
+			if expiryDate, err = time.Parse(time.RFC3339, s.Expires); err != nil {
+				return fmt.Errorf("expiry date %q is not of the format YYYY-MM-DD", s.Expires)
+			}
+			s.Expires = expiryDate.Format("2006-01-02")
+		}
+		s.expiryDate = expiryDate
+	}
+	if len(s.Path) > 0 {
+		if _, err := doublestar.Match(s.Path, ""); err != nil {
+			return fmt.Errorf("path %q is no valid glob pattern", s.Path)
+		}
+	}
+	return nil
+}
+
+// IsExpired checks whether the suppression does not apply anymore at the given time, the day of expiry is still included
+func (s Suppression) IsExpired(now time.Time) bool {
+	if s.expiryDate.IsZero() {
+		return false
+	}
+	return !now.Before(s.expiryDate.AddDate(0, 0, 1))
+}
+
+// Active returns the suppressions which are not expired at the given time
+func (s Suppressions) Active(now time.Time) Suppressions {
+	active := Suppressions{}
+	for _, suppression := range s {
+		if !suppression.IsExpired(now) {
+			active = append(active, suppression)
+		}
+	}
+	return active
+}
+
+// Expired returns the suppressions which are expired at the given time
+func (s Suppressions) Expired(now time.Time) Suppressions {
+	expired := Suppressions{}
+	for _, suppression := range s {
+		if suppression.IsExpired(now) {
+			expired = append(expired, suppression)
+		}
+	}
+	return expired
+}
+
+// Matches checks whether the suppression applies to the finding, regardless of its expiry
+func (s Suppression) Matches(finding Finding) bool {
+	if !s.matchesID(finding) {
+		return false
+	}
+	if len(s.Path) == 0 {
+		return true
+	}
+	if finding.Location != nil && len(finding.Location.File) > 0 {
+		matched, _ := doublestar.Match(s.Path, normalizeFile(finding.Location.File))
+		return matched
+	}
+	if len(finding.PackageURL) > 0 {
+		matched, _ := doublestar.Match(s.Path, finding.PackageURL)
+		return matched
+	}
+	return false
+}
+
+func (s Suppression) matchesID(finding Finding) bool {
+	id := strings.TrimSpace(s.ID)
+	if cwePattern.MatchString(id) {
+		return len(finding.CWE) > 0 && NormalizeCWE(id) == finding.CWE
+	}
+	expression := regexp.MustCompile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(id), `\*`, ".*") + "$")
+	return expression.MatchString(finding.Rule) || (len(finding.CVE) > 0 && expression.MatchString(finding.CVE))
+}
+
+// Match returns the first suppression which applies to the finding or nil
+func (s Suppressions) Match(finding Finding) *Suppression {
+	for i := range s {
+		if s[i].Matches(finding) {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+// Apply marks the open findings matched by a suppression as suppressed and returns their number
+func (s Suppressions) Apply(findings []Finding) int {
+	suppressed := 0
+	for i := range findings {
+		if findings[i].Status == FindingStatusOpen && s.Match(findings[i]) != nil {
+			findings[i].Status = FindingStatusSuppressed
+			suppressed++
+		}
+	}
+	return suppressed
+}
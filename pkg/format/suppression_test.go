@@ -0,0 +1,130 @@
+//go:build unit
+// +build unit
+
+package format
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSuppressionsFromString(t *testing.T, content string) (Suppressions, error) {
+	t.Helper()
+	return ReadSuppressions(io.NopCloser(strings.NewReader(content)), ".pipeline/suppressions.yml")
+}
+
+func TestReadSuppressions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		suppressions, err := readSuppressionsFromString(t, `suppressions:
+  - id: js/xss
+    path: "src/legacy/**"
+    expires: 2026-12-31
+    justification: output is escaped by the template engine
+    approver: security-champion
+  - id: CVE-2021-44228
+    path: "pkg:maven/org.apache.logging.log4j/*"
+    justification: JNDI lookups are disabled
+    approver: security-champion
+`)
+
+		require.NoError(t, err)
+		require.Len(t, suppressions, 2)
+		assert.Equal(t, "js/xss", suppressions[0].ID)
+		assert.Equal(t, "2026-12-31", suppressions[0].Expires)
+		assert.Equal(t, "src/legacy/**", suppressions[0].Path)
+		assert.Equal(t, "", suppressions[1].Expires)
+	})
+
+	t.Run("quoted expiry date", func(t *testing.T) {
+		suppressions, err := readSuppressionsFromString(t, "suppressions:\n  - id: G101\n    expires: \"2026-01-31\"\n    justification: test data\n    approver: someone\n")
+
+		require.NoError(t, err)
+		assert.True(t, suppressions[0].IsExpired(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("missing mandatory fields", func(t *testing.T) {
+		_, err := readSuppressionsFromString(t, "suppressions:\n  - id: G101\n")
+
+		assert.EqualError(t, err, "suppression 1 is invalid: missing justification, approver")
+	})
+
+	t.Run("invalid expiry date", func(t *testing.T) {
+		_, err := readSuppressionsFromString(t, "suppressions:\n  - id: G101\n    expires: end of year\n    justification: test data\n    approver: someone\n")
+
+		assert.EqualError(t, err, `suppression 1 is invalid: expiry date "end of year" is not of the format YYYY-MM-DD`)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := readSuppressionsFromString(t, "suppressions: [")
+
+		assert.ErrorContains(t, err, "format of suppression file is invalid")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		_, err := ReadSuppressions(io.NopCloser(iotest.ErrReader(errors.New("disk failure"))), ".pipeline/suppressions.yml")
+
+		assert.EqualError(t, err, "error reading .pipeline/suppressions.yml: disk failure")
+	})
+}
+
+func TestSuppressionIsExpired(t *testing.T) {
+	suppressions, err := readSuppressionsFromString(t, "suppressions:\n  - id: G101\n    expires: \"2026-01-31\"\n    justification: test data\n    approver: someone\n  - id: G102\n    justification: test data\n    approver: someone\n")
+	require.NoError(t, err)
+
+	lastDay := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	assert.False(t, suppressions[0].IsExpired(lastDay))
+	assert.True(t, suppressions[0].IsExpired(lastDay.Add(time.Hour)))
+	assert.False(t, suppressions[1].IsExpired(lastDay.AddDate(10, 0, 0)))
+
+	assert.Len(t, suppressions.Active(lastDay.Add(time.Hour)), 1)
+	assert.Equal(t, "G101", suppressions.Expired(lastDay.Add(time.Hour))[0].ID)
+}
+
+func TestSuppressionMatches(t *testing.T) {
+	codeFinding := Finding{Tool: "CodeQL", Rule: "js/xss", CWE: "79", Location: &FindingLocation{File: "./src/legacy/views/index.js", Line: 3}, Status: FindingStatusOpen}
+	dependencyFinding := Finding{Tool: "Mend", Rule: "CVE-2021-44228", CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Status: FindingStatusOpen}
+
+	tt := []struct {
+		name        string
+		suppression Suppression
+		finding     Finding
+		expected    bool
+	}{
+		{name: "rule", suppression: Suppression{ID: "js/xss"}, finding: codeFinding, expected: true},
+		{name: "rule with wildcard", suppression: Suppression{ID: "js/*"}, finding: codeFinding, expected: true},
+		{name: "other rule", suppression: Suppression{ID: "js/sql-injection"}, finding: codeFinding},
+		{name: "CWE", suppression: Suppression{ID: "CWE-079"}, finding: codeFinding, expected: true},
+		{name: "other CWE", suppression: Suppression{ID: "CWE-89"}, finding: codeFinding},
+		{name: "path", suppression: Suppression{ID: "js/xss", Path: "src/legacy/**"}, finding: codeFinding, expected: true},
+		{name: "other path", suppression: Suppression{ID: "js/xss", Path: "src/app/**"}, finding: codeFinding},
+		{name: "CVE", suppression: Suppression{ID: "cve-2021-44228"}, finding: dependencyFinding, expected: true},
+		{name: "CVE and package", suppression: Suppression{ID: "CVE-2021-44228", Path: "pkg:maven/org.apache.logging.log4j/*"}, finding: dependencyFinding, expected: true},
+		{name: "CVE and other package", suppression: Suppression{ID: "CVE-2021-44228", Path: "pkg:npm/*"}, finding: dependencyFinding},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.suppression.Matches(test.finding))
+		})
+	}
+}
+
+func TestSuppressionsApply(t *testing.T) {
+	suppressions := Suppressions{{ID: "G101"}}
+	findings := []Finding{
+		{Tool: "gosec", Rule: "G101", Status: FindingStatusOpen},
+		{Tool: "gosec", Rule: "G101", Status: FindingStatusFixed},
+		{Tool: "gosec", Rule: "G102", Status: FindingStatusOpen},
+	}
+
+	assert.Equal(t, 1, suppressions.Apply(findings))
+	assert.Equal(t, FindingStatusSuppressed, findings[0].Status)
+	assert.Equal(t, FindingStatusFixed, findings[1].Status)
+	assert.Equal(t, FindingStatusOpen, findings[2].Status)
+}
@@ -0,0 +1,42 @@
+package fortify
+
+import (
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/piper-validation/fortify-client-go/models"
+)
+
+// ToFindings converts the issues of a project version into scanner independent findings.
+// The issues do not contain the CWE of their category, so the findings are not deduplicated by CWE and location
+// with the findings of other scanners.
+func ToFindings(issues []*models.ProjectVersionIssue) []format.Finding {
+	findings := []format.Finding{}
+	for _, issue := range issues {
+		finding := format.Finding{
+			Tool:   "Fortify",
+			Status: format.FindingStatusOpen,
+		}
+		if issue.IssueName != nil {
+			finding.Rule = *issue.IssueName
+		}
+		if issue.Friority != nil {
+			finding.Severity = format.NormalizeSeverity(*issue.Friority)
+		}
+		if issue.PrimaryTag != nil {
+			finding.AuditState = *issue.PrimaryTag
+		}
+		if issue.FullFileName != nil {
+			finding.Location = &format.FindingLocation{File: *issue.FullFileName}
+			if issue.LineNumber != nil {
+				finding.Location.Line = int(*issue.LineNumber)
+			}
+		}
+		switch {
+		case issue.Removed != nil && *issue.Removed:
+			finding.Status = format.FindingStatusFixed
+		case (issue.Suppressed != nil && *issue.Suppressed) || finding.AuditState == "Not an Issue":
+			finding.Status = format.FindingStatusSuppressed
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,39 @@
+//go:build unit
+// +build unit
+
+package fortify
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	issueName := "Cross-Site Scripting: Reflected"
+	friority := "Critical"
+	file := "src/main/java/com/sap/Servlet.java"
+	line := int32(42)
+	notAnIssue := "Not an Issue"
+	suppressed := true
+
+	findings := ToFindings([]*models.ProjectVersionIssue{
+		{IssueName: &issueName, Friority: &friority, FullFileName: &file, LineNumber: &line},
+		{IssueName: &issueName, PrimaryTag: &notAnIssue},
+		{IssueName: &issueName, Suppressed: &suppressed},
+	})
+
+	assert.Len(t, findings, 3)
+	assert.Equal(t, format.Finding{
+		Tool:     "Fortify",
+		Rule:     issueName,
+		Severity: format.SeverityCritical,
+		Status:   format.FindingStatusOpen,
+		Location: &format.FindingLocation{File: file, Line: 42},
+	}, findings[0])
+	assert.Equal(t, format.FindingStatusSuppressed, findings[1].Status)
+	assert.Equal(t, "Not an Issue", findings[1].AuditState)
+	assert.Equal(t, format.FindingStatusSuppressed, findings[2].Status)
+}
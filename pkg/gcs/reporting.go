@@ -6,6 +6,8 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 )
 
 type ReportOutputParam struct {
@@ -26,6 +28,10 @@ func PersistReportsToGCS(gcsClient Client, outputParams []ReportOutputParam, inp
 	}
 
 	for _, task := range tasks {
+		if dryrun.Enabled() {
+			dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "upload", Path: task.SourcePath, Target: fmt.Sprintf("gs://%v/%v", gcsBucketID, task.TargetPath)})
+			continue
+		}
 		if err := gcsClient.UploadFile(context.Background(), gcsBucketID, task.SourcePath, task.TargetPath); err != nil {
 			return fmt.Errorf("failed to persist reports: %v", err)
 		}
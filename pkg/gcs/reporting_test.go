@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/SAP/jenkins-library/pkg/gcs/mocks"
 	"github.com/bmatcuk/doublestar"
 	"github.com/stretchr/testify/assert"
@@ -169,3 +170,18 @@ func TestPersistReportsToGCS(t *testing.T) {
 		})
 	}
 }
+
+func TestPersistReportsToGCSDryRun(t *testing.T) {
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+	mockedClient := &mocks.Client{}
+	searchFn := func(path string) ([]string, error) { return []string{"report.json"}, nil }
+	fileInfoFn := func(name string) (os.FileInfo, error) { return testFileInfo{name}, nil }
+
+	err := PersistReportsToGCS(mockedClient, []ReportOutputParam{{FilePattern: "*.json", StepResultType: "general"}}, map[string]string{}, "folder", "bucket", "", searchFn, fileInfoFn)
+	assert.NoError(t, err)
+	mockedClient.Mock.AssertNotCalled(t, "UploadFile")
+	assert.Equal(t, []dryrun.Action{
+		{Type: dryrun.ActionFile, Operation: "upload", Path: "report.json", Target: "gs://bucket/folder/general/report.json"},
+	}, dryrun.GetPlan().Actions)
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -313,6 +314,12 @@ func {{ .StepName }}Metadata() config.StepData {
 						{{- if $value.DeprecationMessage }}
 						DeprecationMessage: {{ $value.DeprecationMessage | quote }},
 						{{- end}}
+						{{- if $value.PossibleValues }}
+						PossibleValues: []interface{}{ {{- range $i, $possibleValue := $value.PossibleValues }}{{ if gt $i 0 }}, {{ end }}{{ goValue $possibleValue }}{{ end -}} },
+						{{- end}}
+						{{- if $value.MandatoryIf }}
+						MandatoryIf: []config.ParameterDependence{ {{- range $i, $dependence := $value.MandatoryIf }} {Name: {{ $dependence.Name | quote }}, Value: {{ $dependence.Value | quote }}}, {{ end -}} },
+						{{- end}}
 					},{{ end }}
 				},
 			},
@@ -818,6 +825,14 @@ func isCLIParam(myType string) bool {
 	return myType != "map[string]interface{}" && myType != "[]map[string]interface{}"
 }
 
+// goValue returns the Go literal of a scalar value read from the step metadata
+func goValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%#v", value)
+}
+
 func stepTemplate(myStepInfo stepInfo, templateName, goTemplate string) []byte {
 	funcMap := sprig.HermeticTxtFuncMap()
 	funcMap["flagType"] = flagType
@@ -826,6 +841,7 @@ func stepTemplate(myStepInfo stepInfo, templateName, goTemplate string) []byte {
 	funcMap["longName"] = longName
 	funcMap["uniqueName"] = mustUniqName
 	funcMap["isCLIParam"] = isCLIParam
+	funcMap["goValue"] = goValue
 
 	return generateCode(myStepInfo, templateName, goTemplate, funcMap)
 }
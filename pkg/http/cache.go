@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// responseCache stores responses of GET requests which carry an ETag on disk and revalidates them with If-None-Match,
+// a 304 response of the server is replaced with the cached response
+type responseCache struct {
+	dir string
+}
+
+type cacheEntry struct {
+	ETag       string      `json:"etag"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+func newResponseCache(dir string) *responseCache {
+	if len(dir) == 0 {
+		return nil
+	}
+	return &responseCache{dir: dir}
+}
+
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet && len(req.Header.Get("Range")) == 0 && len(req.Header.Get("If-None-Match")) == 0
+}
+
+// file returns the location of the entry for the request, the credentials are part of the key
+// in order to not serve a response to a user which is not authorized to retrieve it
+func (c *responseCache) file(req *http.Request) string {
+	hash := sha256.New()
+	io.WriteString(hash, req.URL.String())
+	for _, name := range []string{"Accept", "Authorization"} {
+		io.WriteString(hash, "\n"+req.Header.Get(name))
+	}
+	return filepath.Join(c.dir, hex.EncodeToString(hash.Sum(nil))+".json")
+}
+
+func (c *responseCache) load(req *http.Request) *cacheEntry {
+	content, err := os.ReadFile(c.file(req))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil || len(entry.ETag) == 0 {
+		log.Entry().Debugf("ignoring invalid cache entry for %v", req.URL.Redacted())
+		return nil
+	}
+	return &entry
+}
+
+// store writes the response to the cache, the body of the response is replaced since it has been consumed
+func (c *responseCache) store(req *http.Request, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	content, err := json.Marshal(cacheEntry{ETag: resp.Header.Get("ETag"), StatusCode: resp.StatusCode, Header: resp.Header, Body: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		log.Entry().WithError(err).Debug("failed to create response cache directory")
+		return
+	}
+	if err := os.WriteFile(c.file(req), content, 0600); err != nil {
+		log.Entry().WithError(err).Debugf("failed to cache response of %v", req.URL.Redacted())
+	}
+}
+
+// response creates the response for a request which the server confirmed with 304 Not Modified
+func (e *cacheEntry) response(req *http.Request, notModified *http.Response) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
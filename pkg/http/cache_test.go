@@ -0,0 +1,77 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	body := `{"status":"SUCCESS"}`
+	requests := []string{}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer svr.Close()
+	dir := t.TempDir()
+
+	send := func(token string) *http.Response {
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, Token: token, ResponseCacheDir: dir})
+		response, err := client.SendRequest(http.MethodGet, svr.URL+"/api/ce/task", nil, nil, nil)
+		require.NoError(t, err)
+		return response
+	}
+
+	for i := 0; i < 2; i++ {
+		response := send("Bearer token")
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		content, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(content))
+	}
+	assert.Equal(t, []string{"", `"v1"`}, requests)
+
+	t.Run("credentials are part of the key", func(t *testing.T) {
+		send("Bearer other")
+		assert.Equal(t, "", requests[len(requests)-1])
+	})
+
+	t.Run("other methods are not cached", func(t *testing.T) {
+		entries, _ := os.ReadDir(dir)
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, ResponseCacheDir: dir})
+		_, err := client.SendRequest(http.MethodPost, svr.URL+"/api/ce/submit", nil, nil, nil)
+		assert.NoError(t, err)
+		after, _ := os.ReadDir(dir)
+		assert.Len(t, after, len(entries))
+	})
+
+	t.Run("cache directory from defaults", func(t *testing.T) {
+		defaultsDir := t.TempDir()
+		SetClientDefaults(0, 0, defaultsDir)
+		defer SetClientDefaults(0, 0, "")
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+		_, err := client.SendRequest(http.MethodGet, svr.URL+"/api/ce/task", nil, nil, nil)
+		assert.NoError(t, err)
+		entries, _ := os.ReadDir(defaultsDir)
+		assert.Len(t, entries, 1)
+	})
+}
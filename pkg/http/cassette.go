@@ -0,0 +1,257 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RecordEnvVar defines a directory to which all HTTP interactions are recorded
+	RecordEnvVar = "PIPER_HTTP_RECORD"
+	// ReplayEnvVar defines a directory from which recorded HTTP interactions are replayed instead of sending requests
+	ReplayEnvVar = "PIPER_HTTP_REPLAY"
+	// CassetteFile is the name of the file containing the recorded interactions
+	CassetteFile = "cassette.json"
+)
+
+// sensitiveHeaders are masked completely since their values cannot be replayed anyway
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Interaction is a request and the response or error it resulted in
+type Interaction struct {
+	Request  RecordedRequest   `json:"request"`
+	Response *RecordedResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// RecordedRequest is a request with masked secrets
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// BodyBase64 contains bodies which are not valid UTF-8, e.g. uploaded archives
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+// RecordedResponse is a response with masked secrets
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"bodyBase64,omitempty"`
+}
+
+// Cassette contains the recorded interactions in the order in which they happened
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type cassette struct {
+	mutex    sync.Mutex
+	path     string
+	content  Cassette
+	replayed []bool
+	err      error
+}
+
+var (
+	cassettes      = map[string]*cassette{}
+	cassettesMutex sync.Mutex
+)
+
+// openCassette returns the cassette of the directory, all clients share the cassette so that
+// interactions are recorded and replayed in the order of the requests across clients
+func openCassette(dir string, replay bool) *cassette {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+	path := filepath.Join(dir, CassetteFile)
+	if c, ok := cassettes[path]; ok {
+		return c
+	}
+	c := &cassette{path: path, content: Cassette{Interactions: []Interaction{}}}
+	if content, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(content, &c.content); err != nil {
+			c.err = errors.Wrapf(err, "failed to parse cassette %v", path)
+		}
+	} else if replay {
+		c.err = errors.Wrapf(err, "failed to read cassette %v", path)
+	}
+	c.replayed = make([]bool, len(c.content.Interactions))
+	cassettes[path] = c
+	return c
+}
+
+// Record returns a transport which sends requests via next and appends the interactions to the cassette in dir.
+// Registered secrets are masked in URLs, headers and bodies, credential headers are masked completely.
+func Record(dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordTransport{cassette: openCassette(dir, false), next: next}
+}
+
+// Replay returns a transport which answers requests with the interactions recorded in the cassette in dir.
+// A request is answered with the first interaction not replayed yet which has the same method and URL.
+func Replay(dir string) http.RoundTripper {
+	return &replayTransport{cassette: openCassette(dir, true)}
+}
+
+type recordTransport struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+	interaction := Interaction{Request: RecordedRequest{Method: req.Method, URL: log.MaskSecrets(req.URL.Redacted()), Header: maskHeader(req.Header)}}
+	interaction.Request.Body, interaction.Request.BodyBase64 = maskBody(body)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		interaction.Error = log.MaskSecrets(err.Error())
+	} else {
+		body, readErr := readBody(&resp.Body)
+		if readErr != nil {
+			resp.Body.Close()
+			return nil, errors.Wrap(readErr, "failed to read response body")
+		}
+		interaction.Response = &RecordedResponse{StatusCode: resp.StatusCode, Header: maskHeader(resp.Header)}
+		interaction.Response.Body, interaction.Response.BodyBase64 = maskBody(body)
+	}
+
+	if recordErr := t.cassette.append(interaction); recordErr != nil {
+		log.Entry().WithError(recordErr).Warnf("failed to record %v request to %v", req.Method, interaction.Request.URL)
+	}
+	return resp, err
+}
+
+func (c *cassette) append(interaction Interaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.content.Interactions = append(c.content.Interactions, interaction)
+	content, err := json.MarshalIndent(c.content, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0777); err != nil {
+		return err
+	}
+	// the file is written after each interaction since there is no point in time at which all clients are done
+	return os.WriteFile(c.path, content, 0666)
+}
+
+type replayTransport struct {
+	cassette *cassette
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	interaction, err := t.cassette.next(req.Method, log.MaskSecrets(req.URL.Redacted()))
+	if err != nil {
+		return nil, err
+	}
+	if len(interaction.Error) > 0 {
+		return nil, errors.New(interaction.Error)
+	}
+	if interaction.Response == nil {
+		return nil, errors.Errorf("no response recorded for %v request to %v", req.Method, interaction.Request.URL)
+	}
+	body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyBase64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid response body recorded for %v request to %v", req.Method, interaction.Request.URL)
+	}
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (c *cassette) next(method, url string) (Interaction, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return Interaction{}, c.err
+	}
+	for i, interaction := range c.content.Interactions {
+		if !c.replayed[i] && interaction.Request.Method == method && interaction.Request.URL == url {
+			c.replayed[i] = true
+			return interaction, nil
+		}
+	}
+	return Interaction{}, errors.Errorf("no recorded interaction left for %v request to %v in %v", method, url, c.path)
+}
+
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	content, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(content))
+	return content, err
+}
+
+func maskHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	masked := http.Header{}
+	for name, values := range header {
+		for _, value := range values {
+			masked.Add(name, log.MaskSecrets(value))
+		}
+	}
+	for _, name := range sensitiveHeaders {
+		if len(masked.Values(name)) > 0 {
+			masked.Set(name, "****")
+		}
+	}
+	return masked
+}
+
+// maskBody returns the body with masked secrets as text or base64 encoded if it is binary content
+func maskBody(body []byte) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	masked := log.MaskSecrets(string(body))
+	if !utf8.Valid(body) {
+		return "", base64.StdEncoding.EncodeToString([]byte(masked))
+	}
+	return masked, ""
+}
+
+func decodeBody(body, bodyBase64 string) ([]byte, error) {
+	if len(bodyBase64) > 0 {
+		return base64.StdEncoding.DecodeString(bodyBase64)
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+func resetCassettes(t *testing.T) {
+	t.Cleanup(func() {
+		cassettes = map[string]*cassette{}
+	})
+	cassettes = map[string]*cassette{}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	resetCassettes(t)
+	secret := "s3cr3t-t0ken"
+	log.RegisterSecret(secret)
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"received":"` + string(body) + `","id":1}`))
+			return
+		}
+		w.Write([]byte(`{"status":"RUNNING","count":` + string(rune('0'+count)) + `}`))
+	}))
+	defer svr.Close()
+	dir := t.TempDir()
+
+	t.Setenv(RecordEnvVar, dir)
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1, Token: "Bearer " + secret})
+	_, err := client.SendRequest(http.MethodPost, svr.URL+"/api/scans?token="+secret, bytes.NewBufferString("project "+secret), nil, nil)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = client.SendRequest(http.MethodGet, svr.URL+"/api/scans/1", nil, nil, nil)
+		require.NoError(t, err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, CassetteFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), secret)
+	var recorded Cassette
+	require.NoError(t, json.Unmarshal(content, &recorded))
+	require.Len(t, recorded.Interactions, 3)
+	assert.Contains(t, recorded.Interactions[0].Request.URL, "/api/scans?")
+	assert.True(t, strings.HasSuffix(recorded.Interactions[0].Request.URL, "=****"))
+	assert.Equal(t, "****", recorded.Interactions[0].Request.Header.Get("Authorization"))
+	assert.Equal(t, "project ****", recorded.Interactions[0].Request.Body)
+	assert.Equal(t, "****", recorded.Interactions[0].Response.Header.Get("Set-Cookie"))
+	assert.Equal(t, http.StatusCreated, recorded.Interactions[0].Response.StatusCode)
+
+	t.Run("replay", func(t *testing.T) {
+		resetCassettes(t)
+		t.Setenv(RecordEnvVar, "")
+		t.Setenv(ReplayEnvVar, dir)
+		svr.Close()
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, Token: "Bearer " + secret})
+
+		response, err := client.SendRequest(http.MethodPost, svr.URL+"/api/scans?token="+secret, bytes.NewBufferString("project "+secret), nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		for _, expected := range []string{`{"status":"RUNNING","count":2}`, `{"status":"RUNNING","count":3}`} {
+			response, err = client.SendRequest(http.MethodGet, svr.URL+"/api/scans/1", nil, nil, nil)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(response.Body)
+			assert.Equal(t, expected, string(body))
+			assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		}
+
+		_, err = client.SendRequest(http.MethodGet, svr.URL+"/api/scans/1", nil, nil, nil)
+		assert.ErrorContains(t, err, "no recorded interaction left for GET request to "+svr.URL+"/api/scans/1")
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestRecord(t *testing.T) {
+	t.Run("unreadable response body", func(t *testing.T) {
+		resetCassettes(t)
+		transport := Record(t.TempDir(), roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(failingReader{})}, nil
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, "https://tms.example.org/files/1", nil)
+		response, err := transport.RoundTrip(req)
+		assert.EqualError(t, err, "failed to read response body: unexpected EOF")
+		assert.Nil(t, response)
+	})
+
+	t.Run("binary body is masked", func(t *testing.T) {
+		secret := "b1n4ry-s3cr3t"
+		log.RegisterSecret(secret)
+		body, bodyBase64 := maskBody(append([]byte{0x00, 0xff}, secret...))
+		assert.Empty(t, body)
+		decoded, err := decodeBody(body, bodyBase64)
+		require.NoError(t, err)
+		assert.Equal(t, append([]byte{0x00, 0xff}, "****"...), decoded)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("binary body and errors", func(t *testing.T) {
+		resetCassettes(t)
+		dir := t.TempDir()
+		cassette := Cassette{Interactions: []Interaction{
+			{Request: RecordedRequest{Method: http.MethodGet, URL: "https://tms.example.org/files/1"}, Response: &RecordedResponse{StatusCode: http.StatusOK, BodyBase64: "AP8="}},
+			{Request: RecordedRequest{Method: http.MethodGet, URL: "https://tms.example.org/files/2"}, Error: "connection reset by peer"},
+		}}
+		content, _ := json.Marshal(cassette)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, CassetteFile), content, 0666))
+		transport := Replay(dir)
+
+		req, _ := http.NewRequest(http.MethodGet, "https://tms.example.org/files/1", nil)
+		response, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, []byte{0x00, 0xff}, body)
+
+		req, _ = http.NewRequest(http.MethodGet, "https://tms.example.org/files/2", nil)
+		_, err = transport.RoundTrip(req)
+		assert.EqualError(t, err, "connection reset by peer")
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		resetCassettes(t)
+		req, _ := http.NewRequest(http.MethodGet, "https://tms.example.org/files/1", nil)
+		_, err := Replay(t.TempDir()).RoundTrip(req)
+		assert.ErrorContains(t, err, "failed to read cassette")
+	})
+}
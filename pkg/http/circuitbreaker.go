@@ -0,0 +1,98 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+// circuitBreaker rejects requests to a host after a number of consecutive server errors until the cooldown passed.
+// The state belongs to the client the circuit breaker was created for.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	mutex     sync.Mutex
+	hosts     map[string]*hostState
+}
+
+type hostState struct {
+	failures  int
+	openUntil time.Time
+}
+
+var timeNow = time.Now
+
+// newCircuitBreaker returns nil if the threshold is not positive, i.e. the circuit breaker is disabled
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, hosts: map[string]*hostState{}}
+}
+
+// reject returns a response for requests to a host whose circuit is open, the response has status
+// 503 and a Retry-After header so that the request is repeated once the cooldown passed
+func (b *circuitBreaker) reject(req *http.Request) *http.Response {
+	b.mutex.Lock()
+	var failures int
+	var remaining time.Duration
+	if state, ok := b.hosts[req.URL.Host]; ok {
+		failures = state.failures
+		remaining = state.openUntil.Sub(timeNow())
+	}
+	b.mutex.Unlock()
+	if remaining <= 0 {
+		return nil
+	}
+	log.Entry().Debugf("circuit breaker for %v is open, rejecting %v request to %v", req.URL.Host, req.Method, req.URL.Redacted())
+	message := fmt.Sprintf("circuit breaker open after %v consecutive server errors of %v", failures, req.URL.Host)
+	return &http.Response{
+		Status:     fmt.Sprintf("%v %v (%v)", http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable), message),
+		StatusCode: http.StatusServiceUnavailable,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Retry-After":             []string{strconv.Itoa(int(math.Ceil(remaining.Seconds())))},
+			"X-Piper-Circuit-Breaker": []string{"open"},
+		},
+		Body:    io.NopCloser(bytes.NewBufferString(message)),
+		Request: req,
+	}
+}
+
+// record updates the state of the host with the result of a request, the circuit is opened once the number of
+// consecutive server errors reaches the threshold and is opened again by the first failure after the cooldown
+func (b *circuitBreaker) record(req *http.Request, resp *http.Response, err error) {
+	failed := err != nil || (resp != nil && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	state, ok := b.hosts[req.URL.Host]
+	if !failed {
+		if ok {
+			delete(b.hosts, req.URL.Host)
+		}
+		return
+	}
+	if !ok {
+		state = &hostState{}
+		b.hosts[req.URL.Host] = state
+	}
+	state.failures++
+	if state.failures >= b.threshold {
+		log.Entry().Warnf("%v consecutive server errors of %v, pausing requests for %v", state.failures, req.URL.Host, b.cooldown)
+		state.openUntil = timeNow().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetTimeNow(t *testing.T) {
+	t.Cleanup(func() { timeNow = time.Now })
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://sonar.example.org/api/ce/task", nil)
+	serverError := &http.Response{StatusCode: http.StatusBadGateway}
+
+	t.Run("opens after consecutive server errors", func(t *testing.T) {
+		resetTimeNow(t)
+		current := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		timeNow = func() time.Time { return current }
+		breaker := newCircuitBreaker(3, time.Minute)
+
+		breaker.record(req, serverError, nil)
+		breaker.record(req, nil, errors.New("connection reset"))
+		assert.Nil(t, breaker.reject(req))
+		breaker.record(req, serverError, nil)
+
+		resp := breaker.reject(req)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+			assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+			assert.Equal(t, "open", resp.Header.Get("X-Piper-Circuit-Breaker"))
+		}
+		// other hosts are not affected
+		other, _ := http.NewRequest(http.MethodGet, "https://github.example.org/api/v3", nil)
+		assert.Nil(t, breaker.reject(other))
+
+		// half open after the cooldown, the next failure opens the circuit again
+		current = current.Add(time.Minute)
+		assert.Nil(t, breaker.reject(req))
+		breaker.record(req, serverError, nil)
+		assert.NotNil(t, breaker.reject(req))
+	})
+
+	t.Run("success closes the circuit", func(t *testing.T) {
+		breaker := newCircuitBreaker(2, time.Minute)
+
+		breaker.record(req, serverError, nil)
+		breaker.record(req, &http.Response{StatusCode: http.StatusNotFound}, nil)
+		breaker.record(req, serverError, nil)
+
+		assert.Nil(t, breaker.reject(req))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.Nil(t, newCircuitBreaker(0, 0), "disabled by default")
+		assert.Nil(t, newCircuitBreaker(-1, 0))
+		assert.Equal(t, defaultCircuitBreakerCooldown, newCircuitBreaker(1, 0).cooldown)
+	})
+}
+
+func TestSendRequestCircuitBreaker(t *testing.T) {
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer svr.Close()
+
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1, CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour})
+	for i := 0; i < 4; i++ {
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 2, count)
+	_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+	assert.Contains(t, err.Error(), "circuit breaker open after 2 consecutive server errors")
+
+	// the state belongs to the client
+	other := Client{}
+	other.SetOptions(ClientOptions{MaxRetries: -1, CircuitBreakerThreshold: 2, CircuitBreakerCooldown: time.Hour})
+	_, err = other.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+	assert.NotContains(t, err.Error(), "circuit breaker open")
+	assert.Equal(t, 3, count)
+}
+
+func TestClientDefaults(t *testing.T) {
+	t.Cleanup(func() { SetClientDefaults(0, 0, "") })
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer svr.Close()
+
+	t.Run("circuit breaker disabled by default", func(t *testing.T) {
+		count = 0
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+		for i := 0; i < 3; i++ {
+			client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		}
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("circuit breaker from defaults", func(t *testing.T) {
+		SetClientDefaults(1, time.Hour, "")
+		count = 0
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+		for i := 0; i < 3; i++ {
+			client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("circuit breaker disabled for client", func(t *testing.T) {
+		SetClientDefaults(1, time.Hour, "")
+		count = 0
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1, CircuitBreakerThreshold: -1})
+		for i := 0; i < 3; i++ {
+			client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		}
+		assert.Equal(t, 3, count)
+	})
+}
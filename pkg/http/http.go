@@ -19,8 +19,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/motemen/go-nuts/roundtime"
 	"github.com/pkg/errors"
@@ -44,6 +46,9 @@ type Client struct {
 	useDefaultTransport       bool
 	trustedCerts              []string
 	certificates              []tls.Certificate // contains one or more certificate chains to present to the other side of the connection (client-authentication)
+	circuitBreakerThreshold   int
+	circuitBreakerCooldown    time.Duration
+	responseCacheDir          string
 	fileUtils                 piperutils.FileUtils
 	httpClient                *http.Client
 }
@@ -71,6 +76,17 @@ type ClientOptions struct {
 	UseDefaultTransport       bool
 	TrustedCerts              []string          // defines the set of root certificate authorities that clients use when verifying server certificates
 	Certificates              []tls.Certificate // contains one or more certificate chains to present to the other side of the connection (client-authentication)
+	// CircuitBreakerThreshold is the number of consecutive server errors of
+	// a host after which requests to this host are paused for the
+	// CircuitBreakerCooldown. If not set, the default defined via
+	// SetClientDefaults applies, a negative value disables the circuit
+	// breaker. The cooldown defaults to 30 seconds.
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	// ResponseCacheDir enables an on-disk cache for GET requests. Cached
+	// responses are revalidated with their ETag via If-None-Match. If not
+	// set, the default defined via SetClientDefaults applies.
+	ResponseCacheDir string
 }
 
 // TransportWrapper is a wrapper for central round trip capabilities
@@ -81,6 +97,8 @@ type TransportWrapper struct {
 	username                 string
 	password                 string
 	token                    string
+	circuitBreaker           *circuitBreaker
+	cache                    *responseCache
 }
 
 // UploadRequestData encapsulates the parameters for calling uploader.Upload()
@@ -264,6 +282,9 @@ func (c *Client) SetOptions(options ClientOptions) {
 	c.trustedCerts = options.TrustedCerts
 	c.fileUtils = &piperutils.Files{}
 	c.certificates = options.Certificates
+	c.circuitBreakerThreshold = options.CircuitBreakerThreshold
+	c.circuitBreakerCooldown = options.CircuitBreakerCooldown
+	c.responseCacheDir = options.ResponseCacheDir
 }
 
 // SetFileUtils can be used to overwrite the default file utils
@@ -284,7 +305,7 @@ func (c *Client) initializeHttpClient() *http.Client {
 	c.applyDefaults()
 
 	var transport = &TransportWrapper{
-		Transport: &http.Transport{
+		Transport: withHostTLS(&http.Transport{
 			DialContext: (&net.Dialer{
 				Timeout: c.transportTimeout,
 			}).DialContext,
@@ -296,12 +317,14 @@ func (c *Client) initializeHttpClient() *http.Client {
 				InsecureSkipVerify: c.transportSkipVerification,
 				Certificates:       c.certificates,
 			},
-		},
+		}),
 		doLogRequestBodyOnDebug:  c.doLogRequestBodyOnDebug,
 		doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 		token:                    c.token,
 		username:                 c.username,
 		password:                 c.password,
+		circuitBreaker:           newCircuitBreaker(c.circuitBreakerThreshold, c.circuitBreakerCooldown),
+		cache:                    newResponseCache(c.responseCacheDir),
 	}
 
 	if len(c.trustedCerts) > 0 && !c.useDefaultTransport && !c.transportSkipVerification {
@@ -329,15 +352,12 @@ func (c *Client) initializeHttpClient() *http.Client {
 				doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 				token:                    c.token,
 				username:                 c.username,
-				password:                 c.password}
-		}
-		retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
-			if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
-				// Assuming timeouts, resets, and similar could be retried
-				return true, nil
-			}
-			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+				password:                 c.password,
+				circuitBreaker:           transport.circuitBreaker,
+				cache:                    transport.cache}
 		}
+		retryClient.CheckRetry = checkRetry
+		retryClient.Backoff = backoff
 		c.httpClient = retryClient.StandardClient()
 	} else {
 		c.httpClient = &http.Client{
@@ -349,6 +369,20 @@ func (c *Client) initializeHttpClient() *http.Client {
 		}
 	}
 
+	if dir := os.Getenv(ReplayEnvVar); len(dir) > 0 {
+		c.logger.Debugf("Replaying HTTP interactions from %v", dir)
+		c.httpClient.Transport = Replay(dir)
+	} else if dir := os.Getenv(RecordEnvVar); len(dir) > 0 {
+		c.logger.Debugf("Recording HTTP interactions to %v", dir)
+		c.httpClient.Transport = Record(dir, c.httpClient.Transport)
+	}
+	if tracing.Enabled() {
+		c.httpClient.Transport = tracing.Transport(c.httpClient.Transport)
+	}
+	if dryrun.Enabled() {
+		c.httpClient.Transport = dryrun.Transport(c.httpClient.Transport)
+	}
+
 	if c.transportSkipVerification {
 		c.logger.Debugf("TLS verification disabled")
 	}
@@ -371,17 +405,53 @@ func (t *TransportWrapper) RoundTrip(req *http.Request) (*http.Response, error)
 	ctx := context.WithValue(req.Context(), contextKeyRequestStart, time.Now())
 	req = req.WithContext(ctx)
 
+	if t.circuitBreaker != nil {
+		if resp := t.circuitBreaker.reject(req); resp != nil {
+			return resp, nil
+		}
+	}
+
 	handleAuthentication(req, t.username, t.password, t.token)
 
+	useCache := t.cache != nil && cacheable(req)
+	var cached *cacheEntry
+	if useCache {
+		if cached = t.cache.load(req); cached != nil {
+			req = req.Clone(ctx)
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
 	t.logRequest(req)
 
 	resp, err := t.Transport.RoundTrip(req)
 
 	t.logResponse(resp)
 
+	if t.circuitBreaker != nil {
+		t.circuitBreaker.record(req, resp, err)
+	}
+
+	if useCache && err == nil {
+		resp = t.cacheResponse(req, resp, cached)
+	}
+
 	return resp, err
 }
 
+// cacheResponse replaces a 304 response with the cached response and stores new responses which carry an ETag
+func (t *TransportWrapper) cacheResponse(req *http.Request, resp *http.Response, cached *cacheEntry) *http.Response {
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		log.Entry().Debugf("using cached response of %v", req.URL.Redacted())
+		resp.Body.Close()
+		return cached.response(req, resp)
+	}
+	if resp.StatusCode == http.StatusOK && len(resp.Header.Get("ETag")) > 0 {
+		t.cache.store(req, resp)
+	}
+	return resp
+}
+
 func handleAuthentication(req *http.Request, username, password, token string) {
 	// Handle authentication if not done already
 	if (len(username) > 0 || len(password) > 0) && len(req.Header.Get(authHeaderKey)) == 0 {
@@ -516,10 +586,34 @@ func (c *Client) handleResponse(response *http.Response, url string) (*http.Resp
 	return response, fmt.Errorf("request to %v returned with response %v", response.Request.URL, response.Status)
 }
 
+// clientDefaults are set from the general configuration of the step
+var clientDefaults struct {
+	circuitBreakerThreshold int
+	circuitBreakerCooldown  time.Duration
+	responseCacheDir        string
+}
+
+// SetClientDefaults defines the circuit breaker and the response cache of all clients created afterwards which do not define their own.
+// Both are disabled by default, i.e. with a threshold of 0 and without cache directory.
+func SetClientDefaults(circuitBreakerThreshold int, circuitBreakerCooldown time.Duration, responseCacheDir string) {
+	clientDefaults.circuitBreakerThreshold = circuitBreakerThreshold
+	clientDefaults.circuitBreakerCooldown = circuitBreakerCooldown
+	clientDefaults.responseCacheDir = responseCacheDir
+}
+
 func (c *Client) applyDefaults() {
 	if c.transportTimeout == 0 {
 		c.transportTimeout = 3 * time.Minute
 	}
+	if c.circuitBreakerThreshold == 0 {
+		c.circuitBreakerThreshold = clientDefaults.circuitBreakerThreshold
+		if c.circuitBreakerCooldown == 0 {
+			c.circuitBreakerCooldown = clientDefaults.circuitBreakerCooldown
+		}
+	}
+	if len(c.responseCacheDir) == 0 {
+		c.responseCacheDir = clientDefaults.responseCacheDir
+	}
 	if c.logger == nil {
 		c.logger = log.Entry().WithField("package", "SAP/jenkins-library/pkg/http")
 	}
@@ -543,7 +637,7 @@ func (c *Client) configureTLSToTrustCertificates(transport *TransportWrapper) er
 	}
 
 	*transport = TransportWrapper{
-		Transport: &http.Transport{
+		Transport: withHostTLS(&http.Transport{
 			DialContext: (&net.Dialer{
 				Timeout: c.transportTimeout,
 			}).DialContext,
@@ -555,12 +649,14 @@ func (c *Client) configureTLSToTrustCertificates(transport *TransportWrapper) er
 				RootCAs:            rootCAs,
 				Certificates:       c.certificates,
 			},
-		},
+		}),
 		doLogRequestBodyOnDebug:  c.doLogRequestBodyOnDebug,
 		doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 		token:                    c.token,
 		username:                 c.username,
 		password:                 c.password,
+		circuitBreaker:           transport.circuitBreaker,
+		cache:                    transport.cache,
 	}
 
 	for _, certificate := range c.trustedCerts {
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
 
@@ -119,6 +120,32 @@ func TestDefaultTransport(t *testing.T) {
 	})
 }
 
+func TestSendDryRun(t *testing.T) {
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+	methods := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1})
+	response, err := client.SendRequest(http.MethodGet, server.URL+"/api/apps", nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	response, err = client.SendRequest(http.MethodPut, server.URL+"/api/apps/myApp", bytes.NewBufferString("{}"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	assert.Equal(t, []string{http.MethodGet}, methods)
+	assert.Equal(t, []dryrun.Action{
+		{Type: dryrun.ActionHTTP, Method: http.MethodGet, URL: server.URL + "/api/apps", Executed: true},
+		{Type: dryrun.ActionHTTP, Method: http.MethodPut, URL: server.URL + "/api/apps/myApp"},
+	}, dryrun.GetPlan().Actions)
+}
+
 func TestSendRequest(t *testing.T) {
 	var passedHeaders = map[string][]string{}
 	passedCookies := []*http.Cookie{}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// maxRateLimitWait limits the time to wait for a rate limit to be reset before the request is repeated
+const maxRateLimitWait = 10 * time.Minute
+
+// checkRetry decides whether a request is repeated, in addition to the default policy of retryablehttp
+// timeouts and resets as well as requests rejected because of an exhausted rate limit are repeated
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
+		// Assuming timeouts, resets, and similar could be retried
+		return true, nil
+	}
+	if err == nil && ctx.Err() == nil && isRateLimited(resp) {
+		return true, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// backoff waits until the rate limit is reset if the server provides this information, otherwise
+// the wait time grows exponentially with the number of attempts and is randomized to spread repeated requests
+func backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if wait, ok := rateLimitWait(resp, time.Now()); ok {
+		if wait > maxRateLimitWait {
+			wait = maxRateLimitWait
+		}
+		return wait
+	}
+
+	wait := float64(min) * math.Pow(2, float64(attemptNum))
+	if wait > float64(max) {
+		wait = float64(max)
+	}
+	half := int64(wait / 2)
+	if half <= 0 {
+		return time.Duration(wait)
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// isRateLimited reports whether the server rejected the request because the rate limit of the client is exhausted,
+// GitHub for example answers with 403 instead of 429 in this case
+func isRateLimited(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || len(resp.Header.Get("Retry-After")) > 0
+	}
+	return false
+}
+
+// rateLimitWait returns the time until the server accepts requests again based on the headers Retry-After and X-RateLimit-Reset
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusServiceUnavailable && !isRateLimited(resp)) {
+		return 0, false
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); len(retryAfter) > 0 {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			if seconds < 0 {
+				return 0, false
+			}
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+	if reset := resp.Header.Get("X-RateLimit-Reset"); len(reset) > 0 {
+		value, err := strconv.ParseInt(reset, 10, 64)
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		// GitHub provides the reset as epoch seconds, other servers provide the seconds until the reset
+		if value > now.Unix()/2 {
+			return nonNegative(time.Unix(value, 0).Sub(now)), true
+		}
+		return time.Duration(value) * time.Second, true
+	}
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
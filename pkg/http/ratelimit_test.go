@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tt := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		wait       time.Duration
+		ok         bool
+	}{
+		{name: "retry after seconds", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"120"}}, wait: 2 * time.Minute, ok: true},
+		{name: "retry after date", statusCode: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, wait: time.Minute, ok: true},
+		{name: "retry after in the past", statusCode: http.StatusTooManyRequests, header: http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}}, wait: 0, ok: true},
+		{name: "GitHub rate limit reset", statusCode: http.StatusForbidden, header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)}}, wait: 90 * time.Second, ok: true},
+		{name: "rate limit reset in seconds", statusCode: http.StatusTooManyRequests, header: http.Header{"X-Ratelimit-Reset": {"30"}}, wait: 30 * time.Second, ok: true},
+		{name: "forbidden", statusCode: http.StatusForbidden, header: http.Header{"X-Ratelimit-Reset": {"30"}}},
+		{name: "server error", statusCode: http.StatusInternalServerError, header: http.Header{"Retry-After": {"30"}}},
+		{name: "without header", statusCode: http.StatusTooManyRequests, header: http.Header{}},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			wait, ok := rateLimitWait(&http.Response{StatusCode: test.statusCode, Header: test.header}, now)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.wait, wait)
+		})
+	}
+	t.Run("no response", func(t *testing.T) {
+		_, ok := rateLimitWait(nil, now)
+		assert.False(t, ok)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("exponential with jitter", func(t *testing.T) {
+		for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+			wait := backoff(time.Second, 8*time.Second, attempt, nil)
+			assert.GreaterOrEqual(t, wait, max/2)
+			assert.LessOrEqual(t, wait, max)
+		}
+	})
+	t.Run("rate limit", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"42"}}}
+		assert.Equal(t, 42*time.Second, backoff(time.Second, 8*time.Second, 1, resp))
+	})
+	t.Run("rate limit exceeding the maximum wait time", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"86400"}}}
+		assert.Equal(t, maxRateLimitWait, backoff(time.Second, 8*time.Second, 1, resp))
+	})
+}
+
+func TestCheckRetry(t *testing.T) {
+	ctx := context.Background()
+	tt := []struct {
+		name  string
+		resp  *http.Response
+		retry bool
+	}{
+		{name: "GitHub rate limit", resp: &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"X-Ratelimit-Remaining": {"0"}}}, retry: true},
+		{name: "secondary rate limit", resp: &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Retry-After": {"60"}}}, retry: true},
+		{name: "too many requests", resp: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}, retry: true},
+		{name: "forbidden", resp: &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"X-Ratelimit-Remaining": {"4711"}}}, retry: false},
+		{name: "ok", resp: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, retry: false},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			retry, _ := checkRetry(ctx, test.resp, nil)
+			assert.Equal(t, test.retry, retry)
+		})
+	}
+}
+
+func TestSendRequestRateLimited(t *testing.T) {
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+
+	client := Client{maxRetries: 2}
+	response, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// HostTLSConfig defines the TLS settings for requests to a host, e.g. an internal Nexus with its own PKI
+type HostTLSConfig struct {
+	// Host is the host name of the server, optionally with port. A leading "*." matches all subdomains.
+	Host string `json:"host"`
+	// CABundle is the path of a PEM file with the certificate authorities trusted in addition to the system ones
+	CABundle string `json:"caBundle,omitempty"`
+	// ClientCertificate and ClientKey are the paths of the PEM files used for client authentication (mTLS)
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	ClientKey         string `json:"clientKey,omitempty"`
+	// ClientCertificateVaultPath is the path of a Vault secret containing the keys "certificate" and "key" in PEM format
+	ClientCertificateVaultPath string `json:"clientCertificateVaultPath,omitempty"`
+	// MinVersion is the minimum TLS version, one of 1.0, 1.1, 1.2 or 1.3
+	MinVersion string `json:"minVersion,omitempty"`
+	// ServerName overrides the name sent via SNI and used to verify the server certificate
+	ServerName string `json:"serverName,omitempty"`
+	// ClientCertificatePEM and ClientKeyPEM contain the client certificate retrieved from Vault
+	ClientCertificatePEM []byte `json:"-"`
+	ClientKeyPEM         []byte `json:"-"`
+}
+
+var (
+	hostTLSConfigs      []HostTLSConfig
+	hostTLSConfigsMutex sync.RWMutex
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// SetHostTLSConfigs defines the TLS settings per host used by all clients created afterwards, of several
+// matching entries the most specific one is used and of equally specific ones the last one
+func SetHostTLSConfigs(configs []HostTLSConfig) {
+	hostTLSConfigsMutex.Lock()
+	defer hostTLSConfigsMutex.Unlock()
+	hostTLSConfigs = configs
+}
+
+// matchHostTLSConfig returns the configuration for the host of a request
+func matchHostTLSConfig(configs []HostTLSConfig, u *url.URL) (HostTLSConfig, bool) {
+	host, hostname := strings.ToLower(u.Host), strings.ToLower(u.Hostname())
+	var match HostTLSConfig
+	best := 0
+	for _, config := range configs {
+		score := 0
+		pattern := strings.ToLower(config.Host)
+		switch {
+		case pattern == host:
+			score = 3
+		case pattern == hostname:
+			score = 2
+		case strings.HasPrefix(pattern, "*.") && strings.HasSuffix(hostname, pattern[1:]):
+			score = 1
+		}
+		if score > 0 && score >= best {
+			match, best = config, score
+		}
+	}
+	return match, best > 0
+}
+
+// apply returns a copy of the base configuration with the settings of the host
+func (h HostTLSConfig) apply(base *tls.Config) (*tls.Config, error) {
+	config := &tls.Config{}
+	if base != nil {
+		config = base.Clone()
+	}
+	if len(h.CABundle) > 0 && !config.InsecureSkipVerify {
+		bundle, err := os.ReadFile(h.CABundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA bundle of host %v", h.Host)
+		}
+		if config.RootCAs == nil {
+			if config.RootCAs, err = x509.SystemCertPool(); err != nil {
+				log.Entry().Debugf("Caught error on store lookup %v", err)
+				config.RootCAs = x509.NewCertPool()
+			}
+		} else {
+			config.RootCAs = config.RootCAs.Clone()
+		}
+		if !config.RootCAs.AppendCertsFromPEM(bundle) {
+			return nil, errors.Errorf("CA bundle %v of host %v does not contain a PEM certificate", h.CABundle, h.Host)
+		}
+	}
+	certificate, key := h.ClientCertificatePEM, h.ClientKeyPEM
+	if len(h.ClientCertificate) > 0 || len(h.ClientKey) > 0 {
+		var err error
+		if certificate, err = os.ReadFile(h.ClientCertificate); err != nil {
+			return nil, errors.Wrapf(err, "failed to read client certificate of host %v", h.Host)
+		}
+		if key, err = os.ReadFile(h.ClientKey); err != nil {
+			return nil, errors.Wrapf(err, "failed to read client key of host %v", h.Host)
+		}
+	}
+	if len(certificate) > 0 {
+		keyPair, err := tls.X509KeyPair(certificate, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client certificate of host %v", h.Host)
+		}
+		config.Certificates = []tls.Certificate{keyPair}
+	}
+	if len(h.MinVersion) > 0 {
+		version, ok := tlsVersions[h.MinVersion]
+		if !ok {
+			return nil, errors.Errorf("invalid minimum TLS version '%v' of host %v", h.MinVersion, h.Host)
+		}
+		config.MinVersion = version
+	}
+	if len(h.ServerName) > 0 {
+		config.ServerName = h.ServerName
+	}
+	return config, nil
+}
+
+// withHostTLS returns a transport which uses the TLS settings of the host of a request, if any are defined
+func withHostTLS(base *http.Transport) http.RoundTripper {
+	hostTLSConfigsMutex.RLock()
+	defer hostTLSConfigsMutex.RUnlock()
+	if len(hostTLSConfigs) == 0 {
+		return base
+	}
+	return &hostTLSTransport{base: base, configs: hostTLSConfigs, transports: map[string]*http.Transport{}}
+}
+
+type hostTLSTransport struct {
+	base       *http.Transport
+	configs    []HostTLSConfig
+	transports map[string]*http.Transport
+	mutex      sync.Mutex
+}
+
+func (t *hostTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	config, ok := matchHostTLSConfig(t.configs, req.URL)
+	if !ok || req.URL.Scheme != "https" {
+		return t.base.RoundTrip(req)
+	}
+	transport, err := t.transport(config)
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+// transport returns the transport for the configuration, transports are reused to keep the connections alive
+func (t *hostTLSTransport) transport(config HostTLSConfig) (*http.Transport, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if transport, ok := t.transports[config.Host]; ok {
+		return transport, nil
+	}
+	tlsConfig, err := config.apply(t.base.TLSClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid TLS configuration")
+	}
+	log.Entry().Debugf("using TLS configuration of host %v", config.Host)
+	transport := t.base.Clone()
+	transport.TLSClientConfig = tlsConfig
+	t.transports[config.Host] = transport
+	return transport, nil
+}
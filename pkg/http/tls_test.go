@@ -0,0 +1,149 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setHostTLSConfigs(t *testing.T, configs []HostTLSConfig) {
+	t.Cleanup(func() {
+		SetHostTLSConfigs(nil)
+	})
+	SetHostTLSConfigs(configs)
+}
+
+func TestMatchHostTLSConfig(t *testing.T) {
+	configs := []HostTLSConfig{
+		{Host: "*.acme.corp", MinVersion: "1.2"},
+		{Host: "nexus.acme.corp", MinVersion: "1.3"},
+		{Host: "nexus.acme.corp:8443", ServerName: "nexus-internal"},
+		{Host: "*.acme.corp", MinVersion: "1.1"},
+	}
+	tt := []struct {
+		url      string
+		expected HostTLSConfig
+		ok       bool
+	}{
+		{url: "https://nexus.acme.corp:8443/repository", expected: configs[2], ok: true},
+		{url: "https://NEXUS.acme.corp/repository", expected: configs[1], ok: true},
+		{url: "https://sonar.acme.corp/api", expected: configs[3], ok: true},
+		{url: "https://acme.corp/api", ok: false},
+		{url: "https://github.com/SAP", ok: false},
+	}
+	for _, test := range tt {
+		t.Run(test.url, func(t *testing.T) {
+			u, _ := url.Parse(test.url)
+			config, ok := matchHostTLSConfig(configs, u)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, config)
+		})
+	}
+}
+
+func TestHostTLSConfigApply(t *testing.T) {
+	t.Run("minimum version and server name", func(t *testing.T) {
+		config, err := HostTLSConfig{Host: "nexus.acme.corp", MinVersion: "1.3", ServerName: "nexus-internal.acme.corp"}.apply(&tls.Config{InsecureSkipVerify: true})
+		require.NoError(t, err)
+		assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+		assert.Equal(t, "nexus-internal.acme.corp", config.ServerName)
+		assert.True(t, config.InsecureSkipVerify)
+	})
+	t.Run("invalid minimum version", func(t *testing.T) {
+		_, err := HostTLSConfig{Host: "nexus.acme.corp", MinVersion: "TLS1.2"}.apply(nil)
+		assert.EqualError(t, err, "invalid minimum TLS version 'TLS1.2' of host nexus.acme.corp")
+	})
+	t.Run("missing CA bundle", func(t *testing.T) {
+		_, err := HostTLSConfig{Host: "nexus.acme.corp", CABundle: "missing.pem"}.apply(nil)
+		assert.ErrorContains(t, err, "failed to read CA bundle of host nexus.acme.corp")
+	})
+	t.Run("invalid client certificate", func(t *testing.T) {
+		_, err := HostTLSConfig{Host: "nexus.acme.corp", ClientCertificatePEM: []byte("invalid"), ClientKeyPEM: []byte("invalid")}.apply(nil)
+		assert.ErrorContains(t, err, "invalid client certificate of host nexus.acme.corp")
+	})
+}
+
+func TestSendRequestWithHostTLSConfig(t *testing.T) {
+	certificate, key := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certificate)
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	svr.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	svr.StartTLS()
+	defer svr.Close()
+
+	dir := t.TempDir()
+	caBundle := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw}), 0666))
+	serverURL, _ := url.Parse(svr.URL)
+
+	t.Run("success", func(t *testing.T) {
+		setHostTLSConfigs(t, []HostTLSConfig{{Host: serverURL.Hostname(), CABundle: caBundle, ClientCertificatePEM: certificate, ClientKeyPEM: key, MinVersion: "1.2"}})
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+
+		response, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		setHostTLSConfigs(t, []HostTLSConfig{{Host: serverURL.Host, CABundle: caBundle}})
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("without CA bundle", func(t *testing.T) {
+		client := Client{}
+		client.SetOptions(ClientOptions{MaxRetries: -1})
+
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+
+		assert.ErrorContains(t, err, "certificate")
+	})
+}
+
+func generateClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "piper"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
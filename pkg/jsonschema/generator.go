@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/generator/helper"
+	generator "github.com/SAP/jenkins-library/pkg/jsonschema/generator"
+)
+
+func main() {
+	var metadataPath string
+	var targetDir string
+
+	flag.StringVar(&metadataPath, "metadataDir", "./resources/metadata", "The directory containing the step metadata. Default points to \\'resources/metadata\\'.")
+	flag.StringVar(&targetDir, "targetDir", "./schema", "The target directory for the generated JSON schemas.")
+	flag.Parse()
+
+	fmt.Println("Generating JSON schemas")
+	fmt.Println("using Metadata Directory:", metadataPath)
+	fmt.Println("using Target Directory:", targetDir)
+
+	metadataFiles, err := helper.MetadataFiles(metadataPath)
+	checkError(err)
+	checkError(os.MkdirAll(targetDir, 0755))
+	err = generator.GenerateSchemas(metadataFiles, targetDir, generator.HelperData{
+		OpenFile:  openFile,
+		WriteFile: writeFile,
+	})
+	checkError(err)
+}
+
+func openFile(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func writeFile(filename string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(filename, data, perm)
+}
+
+func checkError(err error) {
+	if err != nil {
+		fmt.Printf("Error occurred: %v\n", err)
+		os.Exit(1)
+	}
+}
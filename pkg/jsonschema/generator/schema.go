@@ -0,0 +1,360 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	// ConfigSchemaFileName is the name of the schema file for .pipeline/config.yml
+	ConfigSchemaFileName = "piper-config.schema.json"
+	// ParametersSchemaFileSuffix is appended to the step name for the schema files of parametersJSON
+	ParametersSchemaFileSuffix = ".parameters.schema.json"
+)
+
+// Schema is the subset of JSON Schema (draft 2020-12) which is required to describe the step configuration
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// HelperData is used to transport the functions for file access to the schema generation
+type HelperData struct {
+	OpenFile  func(s string) (io.ReadCloser, error)
+	WriteFile func(f string, d []byte, p os.FileMode) error
+}
+
+// GenerateSchemas reads the step metadata and writes the JSON schema of .pipeline/config.yml
+// as well as the JSON schema of parametersJSON for each step into the target directory
+func GenerateSchemas(metadataFiles []string, targetDir string, helperData HelperData) error {
+	steps := make([]config.StepData, 0, len(metadataFiles))
+	for _, metadataFile := range metadataFiles {
+		metadata, err := helperData.OpenFile(metadataFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open metadata file %v", metadataFile)
+		}
+		var stepData config.StepData
+		if err := stepData.ReadPipelineStepData(metadata); err != nil {
+			return errors.Wrapf(err, "failed to read metadata file %v", metadataFile)
+		}
+		steps = append(steps, stepData)
+
+		if err := writeSchema(ParametersSchema(stepData), filepath.Join(targetDir, stepData.Metadata.Name+ParametersSchemaFileSuffix), helperData); err != nil {
+			return err
+		}
+	}
+	return writeSchema(ConfigSchema(steps), filepath.Join(targetDir, ConfigSchemaFileName), helperData)
+}
+
+func writeSchema(schema *Schema, path string, helperData HelperData) error {
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal schema %v", path)
+	}
+	if err := helperData.WriteFile(path, append(content, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write schema %v", path)
+	}
+	return nil
+}
+
+// ConfigSchema returns the JSON schema of the project configuration .pipeline/config.yml.
+// Keys of the general and stages sections might also be used by other tools, thus additional keys are allowed there.
+func ConfigSchema(steps []config.StepData) *Schema {
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Metadata.Name < steps[j].Metadata.Name })
+
+	general := map[string][]*Schema{}
+	stage := map[string][]*Schema{}
+	stepSchemas := map[string]*Schema{}
+	stepRefs := map[string]*Schema{}
+
+	for _, step := range steps {
+		stepName := step.Metadata.Name
+		for _, param := range step.Spec.Inputs.Parameters {
+			for _, scope := range param.Scope {
+				switch scope {
+				case "GENERAL":
+					addParameter(general, param)
+				case "STAGES":
+					addParameter(stage, param)
+				}
+			}
+		}
+		stepSchemas[stepName] = sectionSchema(step, "STEPS", append(step.GetParameterFilters().Steps, step.GetContextParameterFilters().Steps...))
+		stepSchemas[stepName].Description = strings.TrimSpace(step.Metadata.Description)
+		stepRefs[stepName] = &Schema{Ref: "#/$defs/" + stepName}
+		for _, alias := range step.Metadata.Aliases {
+			stepRefs[alias.Name] = &Schema{Ref: "#/$defs/" + stepName, Deprecated: alias.Deprecated, Description: fmt.Sprintf("Alias of step %s", stepName)}
+		}
+	}
+
+	generalSchema := &Schema{Type: "object", Properties: mergeParameters(general), AdditionalProperties: true}
+	stageSchema := &Schema{Type: "object", Properties: mergeParameters(stage), AdditionalProperties: true}
+	addCommonKeys(generalSchema)
+	addCommonKeys(stageSchema)
+	for stepName := range stepRefs {
+		if _, ok := stageSchema.Properties[stepName]; !ok {
+			stageSchema.Properties[stepName] = &Schema{Type: "boolean", Description: fmt.Sprintf("Activates or deactivates the step %s in the stage", stepName)}
+		}
+	}
+
+	return &Schema{
+		Schema:      schemaDraft,
+		Title:       "Project 'Piper' configuration",
+		Description: "Configuration of the project 'Piper' steps, usually located in .pipeline/config.yml",
+		Type:        "object",
+		Properties: map[string]*Schema{
+			"customDefaults": {Type: "array", Items: &Schema{Type: "string"}, Description: "Custom default configurations, passed as path or URL to yaml files"},
+			"general":        generalSchema,
+			"stages":         {Type: "object", AdditionalProperties: stageSchema, Description: "Configuration per stage, overrides the general configuration"},
+			"steps":          {Type: "object", Properties: stepRefs, AdditionalProperties: &Schema{Type: "object"}, Description: "Configuration per step, overrides the general and stage configuration"},
+			"hooks":          {Type: "object"},
+			"tls":            {Type: "array", Items: tlsSchema(), Description: "TLS settings per host, e.g. client certificates and CA bundles of internal servers"},
+		},
+		Defs: stepSchemas,
+	}
+}
+
+// ParametersSchema returns the JSON schema of the parameters of a step passed via parametersJSON
+func ParametersSchema(step config.StepData) *Schema {
+	schema := sectionSchema(step, "PARAMETERS", append(step.GetParameterFilters().Parameters, step.GetContextParameterFilters().Parameters...))
+	schema.Schema = schemaDraft
+	schema.Title = fmt.Sprintf("Parameters of step %s", step.Metadata.Name)
+	schema.Description = strings.TrimSpace(step.Metadata.Description)
+	// parametersJSON is also used to pass information like the stage name to the step
+	schema.AdditionalProperties = true
+	return schema
+}
+
+// sectionSchema returns the schema of all parameters of a step in the given scope.
+// Additional keys, e.g. of the step context like dockerImage, are added without type information.
+func sectionSchema(step config.StepData, scope string, additionalKeys []string) *Schema {
+	parameters := map[string][]*Schema{}
+	for _, param := range step.Spec.Inputs.Parameters {
+		for _, s := range param.Scope {
+			if s == scope {
+				addParameter(parameters, param)
+			}
+		}
+	}
+	schema := &Schema{Type: "object", Properties: mergeParameters(parameters), AdditionalProperties: false}
+	for _, key := range additionalKeys {
+		if _, ok := schema.Properties[key]; !ok {
+			schema.Properties[key] = &Schema{}
+		}
+	}
+	for _, secret := range step.Spec.Inputs.Secrets {
+		if property, ok := schema.Properties[secret.Name]; ok {
+			property.Type = "string"
+			property.Description = strings.TrimSpace(secret.Description)
+		}
+		for _, alias := range secret.Aliases {
+			schema.Properties[alias.Name] = &Schema{Type: "string", Description: fmt.Sprintf("Alias of %s", secret.Name), Deprecated: alias.Deprecated}
+		}
+	}
+	addCommonKeys(schema)
+	return schema
+}
+
+// tlsSchema returns the schema of an entry of the TLS settings per host
+func tlsSchema() *Schema {
+	str := func(description string) *Schema { return &Schema{Type: "string", Description: description} }
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"host":                       str("Host name, optionally with port, a leading '*.' matches all subdomains"),
+			"caBundle":                   str("Path of a PEM file with additionally trusted certificate authorities"),
+			"clientCertificate":          str("Path of the PEM file of the client certificate"),
+			"clientKey":                  str("Path of the PEM file of the client key"),
+			"clientCertificateVaultPath": str("Vault path of a secret containing the client certificate and key as 'certificate' and 'key'"),
+			"minVersion":                 {Type: "string", Enum: []interface{}{"1.0", "1.1", "1.2", "1.3"}, Description: "Minimum TLS version"},
+			"serverName":                 str("Server name used for SNI and the verification of the server certificate"),
+		},
+		AdditionalProperties: false,
+	}
+}
+
+// addCommonKeys adds the keys which are evaluated independent of the step metadata, e.g. the vault configuration
+func addCommonKeys(schema *Schema) {
+	if schema.Properties == nil {
+		schema.Properties = map[string]*Schema{}
+	}
+	for _, key := range []string{"verbose", "collectTelemetryData"} {
+		if _, ok := schema.Properties[key]; !ok {
+			schema.Properties[key] = &Schema{Type: "boolean"}
+		}
+	}
+	generalKeys := map[string]*Schema{
+		"commandTimeout":  {Type: []interface{}{"string", "number"}, Description: "Timeout of each command executed by the step, e.g. '30m' or a number of seconds"},
+		"retryOnPatterns": {Type: "array", Items: &Schema{Type: "string"}, Description: "Failed commands are repeated if a line of their error output matches one of the patterns"},
+		"retryAttempts":   {Type: "integer", Description: "Maximum number of executions of a command including the first one, defaults to 3"},
+		"retryDelay":      {Type: []interface{}{"string", "number"}, Description: "Time between two executions of a command, defaults to 10s"},
+
+		"httpCircuitBreakerThreshold": {Type: "integer", Description: "Number of consecutive server errors of a host after which requests to this host are paused, disabled if 0"},
+		"httpCircuitBreakerCooldown":  {Type: []interface{}{"string", "number"}, Description: "Time requests are paused by the circuit breaker, defaults to 30s"},
+		"httpCacheDir":                {Type: "string", Description: "Directory of an on-disk cache for GET requests which are revalidated via ETag"},
+	}
+	for _, key := range append(append([]string{}, config.CommandParameters...), config.HTTPParameters...) {
+		if _, ok := schema.Properties[key]; !ok {
+			schema.Properties[key] = generalKeys[key]
+		}
+	}
+	for _, param := range config.ReportingParameters.Parameters {
+		for _, key := range append([]string{param.Name}, aliasNames(param.Aliases)...) {
+			if _, ok := schema.Properties[key]; !ok {
+				schema.Properties[key] = &Schema{Type: "string"}
+			}
+		}
+	}
+	for _, key := range config.VaultConfigurationKeys() {
+		if strings.HasSuffix(key, "$") {
+			if schema.PatternProperties == nil {
+				schema.PatternProperties = map[string]*Schema{}
+			}
+			schema.PatternProperties[key] = &Schema{Type: "string"}
+			continue
+		}
+		if _, ok := schema.Properties[key]; !ok {
+			schema.Properties[key] = &Schema{}
+		}
+	}
+}
+
+// addParameter adds the schema of the parameter and its aliases to the list of schemas for the respective keys
+func addParameter(parameters map[string][]*Schema, param config.StepParameters) {
+	parameters[param.Name] = append(parameters[param.Name], parameterSchema(param))
+	for _, alias := range param.Aliases {
+		aliasSchema := parameterSchema(param)
+		aliasSchema.Description = fmt.Sprintf("Alias of %s", param.Name)
+		aliasSchema.Deprecated = alias.Deprecated
+		aliasSchema.Default = nil
+
+		// aliases like docker/image refer to nested keys
+		path := strings.Split(alias.Name, "/")
+		for i := len(path) - 1; i > 0; i-- {
+			aliasSchema = &Schema{Type: "object", Properties: map[string]*Schema{path[i]: aliasSchema}}
+		}
+		parameters[path[0]] = append(parameters[path[0]], aliasSchema)
+	}
+}
+
+// mergeParameters combines the schemas of parameters which are defined by multiple steps
+func mergeParameters(parameters map[string][]*Schema) map[string]*Schema {
+	result := map[string]*Schema{}
+	for key, schemas := range parameters {
+		result[key] = mergeSchemas(schemas)
+	}
+	return result
+}
+
+func mergeSchemas(schemas []*Schema) *Schema {
+	merged := schemas[0]
+	if len(schemas) == 1 {
+		return merged
+	}
+
+	// nested keys of aliases are combined into a single object
+	if merged.Type == "object" && merged.Properties != nil {
+		properties := map[string][]*Schema{}
+		for _, schema := range schemas {
+			for key, property := range schema.Properties {
+				properties[key] = append(properties[key], property)
+			}
+		}
+		return &Schema{Type: "object", Properties: mergeParameters(properties)}
+	}
+
+	// a value is valid if it is valid for one of the steps
+	distinct := []*Schema{}
+	for _, schema := range schemas {
+		typeOnly := &Schema{Type: schema.Type, Items: schema.Items, Enum: schema.Enum, Pattern: schema.Pattern, AnyOf: schema.AnyOf}
+		found := false
+		for _, d := range distinct {
+			if reflect.DeepEqual(d, typeOnly) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			distinct = append(distinct, typeOnly)
+		}
+	}
+	if len(distinct) == 1 {
+		distinct[0].Description = merged.Description
+		return distinct[0]
+	}
+	return &Schema{Description: merged.Description, AnyOf: distinct}
+}
+
+// parameterSchema returns the schema of a single parameter. Values which are converted when the step
+// configuration is applied, e.g. "true" to true, are considered valid.
+func parameterSchema(param config.StepParameters) *Schema {
+	schema := typeSchema(param.Type)
+	schema.Description = strings.TrimSpace(param.Description)
+	if len(param.DeprecationMessage) > 0 {
+		schema.Deprecated = true
+		schema.Description = strings.TrimSpace(schema.Description + " Deprecated: " + param.DeprecationMessage)
+	}
+	if param.Default != nil {
+		schema.Default = param.Default
+	}
+	if len(param.PossibleValues) > 0 {
+		if schema.Items != nil {
+			schema.Items = &Schema{Enum: param.PossibleValues}
+		} else {
+			schema.Type = nil
+			schema.AnyOf = nil
+			schema.Pattern = ""
+			schema.Enum = param.PossibleValues
+		}
+	}
+	return schema
+}
+
+func typeSchema(paramType string) *Schema {
+	switch paramType {
+	case "string":
+		return &Schema{Type: []string{"string", "number"}}
+	case "bool":
+		return &Schema{AnyOf: []*Schema{{Type: "boolean"}, {Type: "string", Pattern: "^([Tt]rue|TRUE|[Ff]alse|FALSE)$"}}}
+	case "int":
+		return &Schema{Type: "integer"}
+	case "[]string":
+		return &Schema{Type: "array", Items: &Schema{Type: []string{"string", "number"}}}
+	case "map[string]interface{}":
+		return &Schema{Type: "object"}
+	case "[]map[string]interface{}":
+		return &Schema{Type: "array", Items: &Schema{Type: "object"}}
+	}
+	return &Schema{}
+}
+
+func aliasNames(aliases []config.Alias) []string {
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, alias.Name)
+	}
+	return names
+}
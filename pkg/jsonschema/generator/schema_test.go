@@ -0,0 +1,159 @@
+//go:build unit
+// +build unit
+
+package generator
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStepMetadata = `metadata:
+  name: testStep
+  aliases:
+    - name: oldTestStep
+      deprecated: true
+  description: Test description
+spec:
+  inputs:
+    secrets:
+      - name: testCredentialsId
+        description: Jenkins credentials ID
+        type: jenkins
+    params:
+      - name: buildTool
+        type: string
+        possibleValues: [maven, npm]
+        scope: [GENERAL, PARAMETERS, STEPS]
+      - name: goals
+        type: "[]string"
+        description: Goals to execute
+        aliases:
+          - name: maven/goals
+        scope: [PARAMETERS, STAGES, STEPS]
+      - name: publish
+        type: bool
+        default: false
+        aliases:
+          - name: deploy
+            deprecated: true
+        scope: [PARAMETERS, STEPS]
+      - name: retries
+        type: int
+        deprecationMessage: use retryCount
+        scope: [STEPS]
+`
+
+var otherStepMetadata = `metadata:
+  name: otherStep
+spec:
+  inputs:
+    params:
+      - name: buildTool
+        type: string
+        scope: [GENERAL, STEPS]
+`
+
+func readTestMetadata(t *testing.T, content string) config.StepData {
+	var stepData config.StepData
+	require.NoError(t, stepData.ReadPipelineStepData(io.NopCloser(strings.NewReader(content))))
+	return stepData
+}
+
+func toJSON(t *testing.T, schema *Schema) string {
+	content, err := json.Marshal(schema)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestParametersSchema(t *testing.T) {
+	schema := ParametersSchema(readTestMetadata(t, testStepMetadata))
+
+	assert.Equal(t, schemaDraft, schema.Schema)
+	assert.Equal(t, "Parameters of step testStep", schema.Title)
+	assert.Equal(t, true, schema.AdditionalProperties)
+	assert.Equal(t, `{"enum":["maven","npm"]}`, toJSON(t, schema.Properties["buildTool"]))
+	assert.Equal(t, `{"description":"Goals to execute","type":"array","items":{"type":["string","number"]}}`, toJSON(t, schema.Properties["goals"]))
+	assert.Equal(t, `{"anyOf":[{"type":"boolean"},{"type":"string","pattern":"^([Tt]rue|TRUE|[Ff]alse|FALSE)$"}],"default":false}`, toJSON(t, schema.Properties["publish"]))
+	assert.True(t, schema.Properties["deploy"].Deprecated)
+	assert.Equal(t, "Alias of publish", schema.Properties["deploy"].Description)
+	assert.Equal(t, `{"type":"object","properties":{"goals":{"description":"Alias of goals","type":"array","items":{"type":["string","number"]}}}}`, toJSON(t, schema.Properties["maven"]))
+	assert.Equal(t, `{"description":"Jenkins credentials ID","type":"string"}`, toJSON(t, schema.Properties["testCredentialsId"]))
+	assert.NotContains(t, schema.Properties, "retries")
+	assert.Contains(t, schema.Properties, "verbose")
+	assert.Contains(t, schema.PatternProperties, ".+VaultSecretName$")
+}
+
+func TestConfigSchema(t *testing.T) {
+	schema := ConfigSchema([]config.StepData{readTestMetadata(t, testStepMetadata), readTestMetadata(t, otherStepMetadata)})
+
+	t.Run("general", func(t *testing.T) {
+		general := schema.Properties["general"]
+		assert.Equal(t, true, general.AdditionalProperties)
+		// any string is valid since otherStep does not restrict the values
+		assert.Equal(t, `{"anyOf":[{"type":["string","number"]},{"enum":["maven","npm"]}]}`, toJSON(t, general.Properties["buildTool"]))
+		assert.Contains(t, general.Properties, "vaultPath")
+		assert.NotContains(t, general.Properties, "goals")
+	})
+
+	t.Run("stages", func(t *testing.T) {
+		stage := schema.Properties["stages"].AdditionalProperties.(*Schema)
+		assert.Contains(t, stage.Properties, "goals")
+		assert.Equal(t, "boolean", stage.Properties["testStep"].Type)
+		assert.Equal(t, "boolean", stage.Properties["oldTestStep"].Type)
+	})
+
+	t.Run("steps", func(t *testing.T) {
+		steps := schema.Properties["steps"]
+		assert.Equal(t, "#/$defs/testStep", steps.Properties["testStep"].Ref)
+		assert.Equal(t, "#/$defs/testStep", steps.Properties["oldTestStep"].Ref)
+		assert.True(t, steps.Properties["oldTestStep"].Deprecated)
+
+		testStep := schema.Defs["testStep"]
+		assert.Equal(t, false, testStep.AdditionalProperties)
+		assert.Equal(t, "Test description", testStep.Description)
+		assert.True(t, testStep.Properties["retries"].Deprecated)
+		assert.Equal(t, "Deprecated: use retryCount", testStep.Properties["retries"].Description)
+		assert.Contains(t, testStep.Properties, "testCredentialsId")
+		assert.Contains(t, testStep.Properties, "collectTelemetryData")
+	})
+}
+
+func TestGenerateSchemas(t *testing.T) {
+	files := map[string]string{}
+	helperData := HelperData{
+		OpenFile: func(name string) (io.ReadCloser, error) {
+			switch name {
+			case "testStep.yaml":
+				return io.NopCloser(strings.NewReader(testStepMetadata)), nil
+			case "otherStep.yaml":
+				return io.NopCloser(strings.NewReader(otherStepMetadata)), nil
+			}
+			return nil, os.ErrNotExist
+		},
+		WriteFile: func(name string, content []byte, _ os.FileMode) error {
+			files[name] = string(content)
+			return nil
+		},
+	}
+
+	t.Run("success case", func(t *testing.T) {
+		require.NoError(t, GenerateSchemas([]string{"testStep.yaml", "otherStep.yaml"}, "schema", helperData))
+		assert.Len(t, files, 3)
+		assert.Contains(t, files, "schema/piper-config.schema.json")
+		assert.Contains(t, files, "schema/testStep.parameters.schema.json")
+		assert.Contains(t, files["schema/otherStep.parameters.schema.json"], `"title": "Parameters of step otherStep"`)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		err := GenerateSchemas([]string{"missing.yaml"}, "schema", helperData)
+		assert.EqualError(t, err, "failed to open metadata file missing.yaml: file does not exist")
+	})
+}
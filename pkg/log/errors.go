@@ -16,8 +16,17 @@ const (
 )
 
 var errorCategory ErrorCategory = ErrorUndefined
+var errorHint *ErrorHint
 var fatalError []byte
 
+// ErrorHint provides remediation guidance for an error detected in the output of a tool
+type ErrorHint struct {
+	Hint             string
+	Documentation    string
+	PatternID        string
+	CatalogueVersion string
+}
+
 func (e ErrorCategory) String() string {
 	return [...]string{
 		"undefined",
@@ -65,6 +74,16 @@ func GetErrorCategory() ErrorCategory {
 	return errorCategory
 }
 
+// SetErrorHint sets the remediation hint which is added to the fatal error details
+func SetErrorHint(hint *ErrorHint) {
+	errorHint = hint
+}
+
+// GetErrorHint retrieves the remediation hint which is currently known to the execution of a step
+func GetErrorHint() *ErrorHint {
+	return errorHint
+}
+
 // SetFatalErrorDetail sets the fatal error to be stored
 func SetFatalErrorDetail(error []byte) {
 	fatalError = error
@@ -35,6 +35,18 @@ func (f *FatalHook) Fire(entry *logrus.Entry) error {
 	details["result"] = "failure"
 	details["correlationId"] = f.CorrelationID
 	details["time"] = entry.Time
+	if hint := GetErrorHint(); hint != nil {
+		details["hint"] = hint.Hint
+		details["documentation"] = hint.Documentation
+		details["errorPattern"] = hint.PatternID
+		details["errorPatternCatalogue"] = hint.CatalogueVersion
+		Entry().Infof("hint: %v", hint.Hint)
+		if len(hint.Documentation) > 0 {
+			Entry().Infof("documentation: %v", hint.Documentation)
+		}
+		// the hint belongs to this error only
+		SetErrorHint(nil)
+	}
 
 	fileName := "errorDetails.json"
 	if details["stepName"] != nil {
@@ -79,4 +79,27 @@ func TestFatalHookFire(t *testing.T) {
 
 		assert.Contains(t, string(fileContent), `"message":"the error message"`)
 	})
+
+	t.Run("with error hint", func(t *testing.T) {
+		SetErrorHint(&ErrorHint{Hint: "re-run the build", Documentation: "https://docs.example.org", PatternID: "mvn-repository-unreachable", CatalogueVersion: "1.0.0"})
+		defer SetErrorHint(nil)
+		hook := FatalHook{Path: t.TempDir()}
+		entry := logrus.Entry{
+			Data: logrus.Fields{
+				"stepName": "mavenBuild",
+			},
+			Message: "the error message",
+		}
+
+		err := hook.Fire(&entry)
+
+		assert.NoError(t, err)
+		fileContent, err := os.ReadFile(filepath.Join(hook.Path, "mavenBuild_errorDetails.json"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(fileContent), `"hint":"re-run the build"`)
+		assert.Contains(t, string(fileContent), `"documentation":"https://docs.example.org"`)
+		assert.Contains(t, string(fileContent), `"errorPattern":"mvn-repository-unreachable"`)
+		assert.Contains(t, string(fileContent), `"errorPatternCatalogue":"1.0.0"`)
+		assert.Nil(t, GetErrorHint(), "the hint is not reported for later errors")
+	})
 }
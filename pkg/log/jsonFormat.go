@@ -0,0 +1,95 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const logFormatJSON = "json"
+
+var (
+	stageName     string
+	correlationID string
+)
+
+// SetStageName sets the name of the stage which is part of the log output in JSON format
+func SetStageName(name string) {
+	stageName = name
+}
+
+// SetCorrelationID sets the correlation ID of the pipeline run which is part of the log output in JSON format
+func SetCorrelationID(id string) {
+	correlationID = id
+}
+
+// jsonLogKeys are set by the JSON formatter itself, fields of the entry with the same name are ignored
+var jsonLogKeys = map[string]bool{
+	"time":          true,
+	"level":         true,
+	"message":       true,
+	"error":         true,
+	"stepName":      true,
+	"stageName":     true,
+	"correlationId": true,
+	"category":      true,
+}
+
+// formatJSON formats the entry as single line JSON object, secrets contained in the message or the fields are masked
+func formatJSON(entry *logrus.Entry, level string) ([]byte, error) {
+	data := map[string]interface{}{
+		"time":    entry.Time.Format(time.RFC3339Nano),
+		"level":   level,
+		"message": MaskSecrets(entry.Message),
+	}
+	if stepName, ok := entry.Data["stepName"]; ok {
+		data["stepName"] = MaskSecrets(fmt.Sprint(stepName))
+	}
+	if len(stageName) > 0 {
+		data["stageName"] = stageName
+	}
+	if len(correlationID) > 0 {
+		data["correlationId"] = correlationID
+	}
+	if errorCategory != ErrorUndefined {
+		data["category"] = errorCategory.String()
+	}
+	if err, ok := entry.Data[logrus.ErrorKey]; ok && err != nil {
+		data["error"] = MaskSecrets(fmt.Sprint(err))
+	}
+
+	for key, value := range entry.Data {
+		if jsonLogKeys[key] {
+			continue
+		}
+		data[key] = jsonLogValue(value)
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to serialize log entry: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// jsonLogValue keeps numbers and booleans, all other values are converted to strings in order to mask secrets
+func jsonLogValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case string:
+		return MaskSecrets(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case error:
+		return MaskSecrets(v.Error())
+	case fmt.Stringer:
+		return MaskSecrets(v.String())
+	}
+	return MaskSecrets(fmt.Sprintf("%v", value))
+}
@@ -48,6 +48,8 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 		message = fmt.Sprintf("%s %-5s %-6s %s%s\n", entry.Time.Format("15:04:05"), levelString, stepName, entry.Message, errorMessageSnippet)
 	case logFormatPlain:
 		message = fmt.Sprintf("%s%s\n", entry.Message, errorMessageSnippet)
+	case logFormatJSON:
+		return formatJSON(entry, levelString)
 	default:
 		formattedMessage, err := formatter.TextFormatter.Format(entry)
 		if err != nil {
@@ -56,11 +58,7 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 		message = string(formattedMessage)
 	}
 
-	for _, secret := range secrets {
-		message = strings.Replace(message, secret, "****", -1)
-	}
-
-	return []byte(message), nil
+	return []byte(MaskSecrets(message)), nil
 }
 
 // LibraryRepository that is passed into with -ldflags
@@ -127,3 +125,11 @@ func RegisterSecret(secret string) {
 		}
 	}
 }
+
+// MaskSecrets replaces all registered secrets contained in the given text
+func MaskSecrets(text string) string {
+	for _, secret := range secrets {
+		text = strings.Replace(text, secret, "****", -1)
+	}
+	return text
+}
@@ -4,6 +4,7 @@
 package mock
 
 import (
+	"context"
 	"io"
 	"regexp"
 	"strings"
@@ -75,6 +76,13 @@ func (m *ExecMockRunner) RunExecutableWithAttrs(e string, attrs *syscall.SysProc
 	return m.handleCall(c, m.StdoutReturn, m.ShouldFailOnCommand, m.stdout)
 }
 
+func (m *ExecMockRunner) RunExecutableWithContext(ctx context.Context, e string, p ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.RunExecutableWithAttrs(e, nil, p...)
+}
+
 func (m *ExecMockRunner) GetExitCode() int {
 	return m.ExitCode
 }
@@ -142,6 +150,13 @@ func (m *ShellMockRunner) RunShell(s string, c string) error {
 	return handleCall(c, m.StdoutReturn, m.ShouldFailOnCommand, m.stdout)
 }
 
+func (m *ShellMockRunner) RunShellWithContext(ctx context.Context, s string, c string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.RunShell(s, c)
+}
+
 func (m *ShellMockRunner) GetExitCode() int {
 	return m.ExitCode
 }
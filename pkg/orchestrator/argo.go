@@ -0,0 +1,308 @@
+package orchestrator
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	argoWorkflowLabel         = "workflows.argoproj.io/workflow"
+	argoWorkflowTemplateLabel = "workflows.argoproj.io/workflow-template"
+	argoCronWorkflowLabel     = "workflows.argoproj.io/cron-workflow"
+	argoCreatorLabel          = "workflows.argoproj.io/creator"
+	argoEventsSensorLabel     = "events.argoproj.io/sensor"
+	argoNodeNameAnnotation    = "workflows.argoproj.io/node-name"
+)
+
+var argoWorkflows = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}
+
+type argoWorkflowsConfigProvider struct {
+	client       piperHttp.Client
+	configured   bool
+	workflowData argoWorkflow
+}
+
+type argoWorkflow struct {
+	fetched  bool
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Status struct {
+		Phase     string    `json:"phase"`
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"status"`
+}
+
+func newArgoWorkflowsConfigProvider() *argoWorkflowsConfigProvider {
+	return &argoWorkflowsConfigProvider{}
+}
+
+// Configure initializes http client for the Argo Server API. Connection details are taken from the
+// environment variables also used by the argo CLI (ARGO_SERVER, ARGO_TOKEN, ARGO_SECURE, ARGO_BASE_HREF).
+func (a *argoWorkflowsConfigProvider) Configure(_ *Options) error {
+	a.client.SetOptions(piperHttp.ClientOptions{
+		Token:            getEnv("ARGO_TOKEN", ""),
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	})
+	a.configured = true
+
+	log.Entry().Debug("Successfully initialized Argo Workflows config provider")
+	return nil
+}
+
+// OrchestratorVersion returns the version of the Argo Server, e.g. v3.5.5
+func (a *argoWorkflowsConfigProvider) OrchestratorVersion() string {
+	if !a.configured || len(a.serverURL()) == 0 {
+		return "n/a"
+	}
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := getJSON(&a.client, a.serverURL()+"/api/v1/version", nil, &version); err != nil {
+		log.Entry().WithError(err).Debug("failed to get Argo Server version")
+		return "n/a"
+	}
+	return version.Version
+}
+
+// OrchestratorType returns the orchestrator name e.g. Azure/GitHubActions/Jenkins/ArgoWorkflows
+func (a *argoWorkflowsConfigProvider) OrchestratorType() string {
+	return "ArgoWorkflows"
+}
+
+// BuildStatus returns the phase of the workflow. Return variables are aligned with Jenkins build statuses.
+func (a *argoWorkflowsConfigProvider) BuildStatus() string {
+	a.fetchWorkflowData()
+	switch a.workflowData.Status.Phase {
+	case "Succeeded":
+		return BuildStatusSuccess
+	case "Running", "Pending", "":
+		return BuildStatusInProgress
+	default:
+		// Failed, Error
+		return BuildStatusFailure
+	}
+}
+
+// FullLogs returns the logs of the main containers of all workflow pods
+func (a *argoWorkflowsConfigProvider) FullLogs() ([]byte, error) {
+	if !a.configured || len(a.serverURL()) == 0 {
+		log.Entry().Debug("ConfigProvider for Argo Workflows is not configured. Unable to fetch logs")
+		return []byte{}, nil
+	}
+
+	URL := a.workflowURL() + "/log?logOptions.container=main"
+	log.Entry().Debugf("Getting logs from %v", URL)
+	response, err := a.client.GetRequest(URL, nil, nil)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "failed to get workflow logs")
+	}
+	defer response.Body.Close()
+
+	// logs are streamed as newline-delimited JSON objects, one per log line
+	var logs []byte
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Result struct {
+				Content string `json:"content"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return []byte{}, errors.Wrap(err, "failed to parse workflow logs")
+		}
+		logs = append(logs, entry.Result.Content...)
+		logs = append(logs, '\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return []byte{}, errors.Wrap(err, "failed to read workflow logs")
+	}
+
+	return logs, nil
+}
+
+// PipelineStartTime returns the start time of the workflow in UTC. It is taken from the Argo Server if available,
+// otherwise it is read from the workflow resource.
+func (a *argoWorkflowsConfigProvider) PipelineStartTime() time.Time {
+	a.fetchWorkflowData()
+	if !a.workflowData.Status.StartedAt.IsZero() {
+		return a.workflowData.Status.StartedAt.UTC()
+	}
+
+	startTime, err := resourceStartTime(argoWorkflows, a.BuildID(), "startedAt")
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to get start time of the workflow")
+	}
+	return startTime.UTC()
+}
+
+// BuildID returns the name of the workflow, e.g. build-pipeline-x7k2p
+func (a *argoWorkflowsConfigProvider) BuildID() string {
+	return podLabel(argoWorkflowLabel, "n/a")
+}
+
+// StageName returns the name of the template the current pod runs, e.g. "build"
+func (a *argoWorkflowsConfigProvider) StageName() string {
+	// ARGO_TEMPLATE is set by the workflow controller and contains the template definition as JSON
+	if templateJSON := getEnv("ARGO_TEMPLATE", ""); len(templateJSON) > 0 {
+		var template struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal([]byte(templateJSON), &template); err == nil && len(template.Name) > 0 {
+			return template.Name
+		}
+	}
+	// node names look like <workflow>.<step>[.<nested step>]
+	nodeName := podAnnotation(argoNodeNameAnnotation, "n/a")
+	return nodeName[strings.LastIndex(nodeName, ".")+1:]
+}
+
+// BuildReason returns the reason the workflow was created.
+// BuildReasons are unified with AzureDevOps build reasons, see
+// https://docs.microsoft.com/en-us/azure/devops/pipelines/build/variables?view=azure-devops&tabs=yaml#build-variables-devops-services
+func (a *argoWorkflowsConfigProvider) BuildReason() string {
+	a.fetchWorkflowData()
+	labels := a.workflowData.Metadata.Labels
+	if _, ok := labels[argoCronWorkflowLabel]; ok {
+		return BuildReasonSchedule
+	}
+	if _, ok := labels[argoEventsSensorLabel]; ok {
+		return BuildReasonResourceTrigger
+	}
+	if _, ok := labels[argoCreatorLabel]; ok {
+		return BuildReasonManual
+	}
+	return BuildReasonUnknown
+}
+
+// Branch is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) Branch() string {
+	log.Entry().Debug("Branch() for Argo Workflows is not applicable.")
+	return "n/a"
+}
+
+// GitReference is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) GitReference() string {
+	log.Entry().Debug("GitReference() for Argo Workflows is not applicable.")
+	return "n/a"
+}
+
+// BuildURL returns the URL of the workflow in the Argo UI, e.g. https://argo.example.com/workflows/ci/build-pipeline-x7k2p
+func (a *argoWorkflowsConfigProvider) BuildURL() string {
+	if len(a.serverURL()) == 0 {
+		return "n/a"
+	}
+	return a.serverURL() + "/workflows/" + podNamespace() + "/" + a.BuildID()
+}
+
+// JobURL returns the URL of the workflow template or cron workflow the workflow was created from
+func (a *argoWorkflowsConfigProvider) JobURL() string {
+	if len(a.serverURL()) == 0 {
+		return "n/a"
+	}
+	a.fetchWorkflowData()
+	labels := a.workflowData.Metadata.Labels
+	if name, ok := labels[argoCronWorkflowLabel]; ok {
+		return a.serverURL() + "/cron-workflows/" + podNamespace() + "/" + name
+	}
+	if name, ok := labels[argoWorkflowTemplateLabel]; ok {
+		return a.serverURL() + "/workflow-templates/" + podNamespace() + "/" + name
+	}
+	return "n/a"
+}
+
+// JobName returns the name of the workflow template or cron workflow the workflow was created from
+func (a *argoWorkflowsConfigProvider) JobName() string {
+	a.fetchWorkflowData()
+	labels := a.workflowData.Metadata.Labels
+	if name, ok := labels[argoCronWorkflowLabel]; ok {
+		return name
+	}
+	if name, ok := labels[argoWorkflowTemplateLabel]; ok {
+		return name
+	}
+	return "n/a"
+}
+
+// CommitSHA is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) CommitSHA() string {
+	log.Entry().Debug("CommitSHA() for Argo Workflows is not applicable.")
+	return "n/a"
+}
+
+// RepoURL is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) RepoURL() string {
+	log.Entry().Debug("RepoURL() for Argo Workflows is not applicable.")
+	return "n/a"
+}
+
+// PullRequestConfig is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) PullRequestConfig() PullRequestConfig {
+	log.Entry().Debug("PullRequestConfig() for Argo Workflows is not applicable.")
+	return PullRequestConfig{
+		Branch: "n/a",
+		Base:   "n/a",
+		Key:    "n/a",
+	}
+}
+
+// IsPullRequest indicates whether the current workflow was triggered by a pull request, which is not known to Argo Workflows
+func (a *argoWorkflowsConfigProvider) IsPullRequest() bool {
+	return false
+}
+
+func (a *argoWorkflowsConfigProvider) ChangeSets() []ChangeSet {
+	log.Entry().Debug("ChangeSets for Argo Workflows not implemented")
+	return []ChangeSet{}
+}
+
+// serverURL returns the URL of the Argo Server, e.g. https://argo.example.com
+func (a *argoWorkflowsConfigProvider) serverURL() string {
+	server := getEnv("ARGO_SERVER", "")
+	if len(server) == 0 {
+		return ""
+	}
+	scheme := "https://"
+	if getEnv("ARGO_SECURE", "true") == "false" {
+		scheme = "http://"
+	}
+	return scheme + server + strings.TrimSuffix(getEnv("ARGO_BASE_HREF", ""), "/")
+}
+
+// workflowURL returns the API URL of the current workflow
+func (a *argoWorkflowsConfigProvider) workflowURL() string {
+	return a.serverURL() + "/api/v1/workflows/" + podNamespace() + "/" + a.BuildID()
+}
+
+func (a *argoWorkflowsConfigProvider) fetchWorkflowData() {
+	if !a.configured || len(a.serverURL()) == 0 {
+		log.Entry().Debug("ConfigProvider for Argo Workflows is not configured. Unable to fetch workflow data")
+		return
+	}
+	if a.workflowData.fetched {
+		return
+	}
+
+	if err := getJSON(&a.client, a.workflowURL(), nil, &a.workflowData); err != nil {
+		log.Entry().WithError(err).Error("failed to get workflow information from Argo Server")
+		return
+	}
+	a.workflowData.fetched = true
+}
+
+func isArgoWorkflows() bool {
+	if envVarsAreSet([]string{"ARGO_NODE_ID", "ARGO_TEMPLATE"}) {
+		return true
+	}
+	_, found := readPodMetadata("labels")[argoWorkflowLabel]
+	return found
+}
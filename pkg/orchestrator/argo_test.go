@@ -0,0 +1,195 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const argoWorkflowAPI = "https://argo.example.com/api/v1/workflows/ci/build-pipeline-x7k2p"
+
+func newTestArgoProvider() *argoWorkflowsConfigProvider {
+	a := &argoWorkflowsConfigProvider{configured: true}
+	a.client.SetOptions(piperhttp.ClientOptions{
+		MaxRequestDuration:        5 * time.Second,
+		Token:                     "Bearer TOKEN",
+		TransportSkipVerification: true,
+		UseDefaultTransport:       true, // need to use default transport for http mock
+		MaxRetries:                -1,
+	})
+	return a
+}
+
+func setArgoEnv(t *testing.T) {
+	writePodInfo(t, `workflows.argoproj.io/workflow="build-pipeline-x7k2p"`,
+		`workflows.argoproj.io/node-name="build-pipeline-x7k2p.test"`)
+	os.Setenv("POD_NAMESPACE", "ci")
+	os.Setenv("ARGO_SERVER", "argo.example.com")
+}
+
+func TestArgoWorkflows(t *testing.T) {
+	t.Run("Argo Workflows - detection and pod metadata", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		defer ResetConfigProvider()
+		ResetConfigProvider()
+		setArgoEnv(t)
+
+		p, err := GetOrchestratorConfigProvider(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ArgoWorkflows, DetectOrchestrator())
+		assert.Equal(t, "ArgoWorkflows", p.OrchestratorType())
+		assert.Equal(t, "build-pipeline-x7k2p", p.BuildID())
+		assert.Equal(t, "test", p.StageName())
+		assert.Equal(t, "https://argo.example.com/workflows/ci/build-pipeline-x7k2p", p.BuildURL())
+		assert.False(t, p.IsPullRequest())
+	})
+
+	t.Run("stage name from template", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("ARGO_TEMPLATE", `{"name":"build","container":{"image":"piper"}}`)
+
+		assert.Equal(t, ArgoWorkflows, DetectOrchestrator())
+		assert.Equal(t, "build", (&argoWorkflowsConfigProvider{}).StageName())
+	})
+
+	t.Run("insecure server", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("ARGO_SERVER", "localhost:2746")
+		os.Setenv("ARGO_SECURE", "false")
+		os.Setenv("ARGO_BASE_HREF", "/argo/")
+
+		assert.Equal(t, "http://localhost:2746/argo", (&argoWorkflowsConfigProvider{}).serverURL())
+	})
+}
+
+func TestArgoWorkflowsConfigProvider_WorkflowData(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantStatus string
+		wantReason string
+		wantJob    string
+		wantJobURL string
+	}{
+		{
+			name:       "cron workflow",
+			response:   `{"metadata":{"labels":{"workflows.argoproj.io/cron-workflow":"nightly"}},"status":{"phase":"Running","startedAt":"2024-03-18T12:30:42Z"}}`,
+			wantStatus: BuildStatusInProgress,
+			wantReason: BuildReasonSchedule,
+			wantJob:    "nightly",
+			wantJobURL: "https://argo.example.com/cron-workflows/ci/nightly",
+		},
+		{
+			name:       "submitted from template",
+			response:   `{"metadata":{"labels":{"workflows.argoproj.io/workflow-template":"build","workflows.argoproj.io/creator":"jane"}},"status":{"phase":"Succeeded","startedAt":"2024-03-18T12:30:42Z"}}`,
+			wantStatus: BuildStatusSuccess,
+			wantReason: BuildReasonManual,
+			wantJob:    "build",
+			wantJobURL: "https://argo.example.com/workflow-templates/ci/build",
+		},
+		{
+			name:       "triggered by sensor",
+			response:   `{"metadata":{"labels":{"events.argoproj.io/sensor":"webhook"}},"status":{"phase":"Failed","startedAt":"2024-03-18T12:30:42Z"}}`,
+			wantStatus: BuildStatusFailure,
+			wantReason: BuildReasonResourceTrigger,
+			wantJob:    "n/a",
+			wantJobURL: "n/a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetEnv(os.Environ())
+			os.Clearenv()
+			setArgoEnv(t)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(http.MethodGet, argoWorkflowAPI, httpmock.NewStringResponder(200, tt.response))
+
+			a := newTestArgoProvider()
+
+			assert.Equal(t, tt.wantStatus, a.BuildStatus())
+			assert.Equal(t, tt.wantReason, a.BuildReason())
+			assert.Equal(t, tt.wantJob, a.JobName())
+			assert.Equal(t, tt.wantJobURL, a.JobURL())
+			assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), a.PipelineStartTime())
+			assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		})
+	}
+}
+
+func TestArgoWorkflowsConfigProvider_PipelineStartTime(t *testing.T) {
+	t.Run("from workflow resource", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		writePodInfo(t, `workflows.argoproj.io/workflow="build-pipeline-x7k2p"`, "")
+		defer func(f func(schema.GroupVersionResource, string, string) (time.Time, error)) { resourceStartTime = f }(resourceStartTime)
+		resourceStartTime = func(resource schema.GroupVersionResource, name, field string) (time.Time, error) {
+			assert.Equal(t, argoWorkflows, resource)
+			assert.Equal(t, "build-pipeline-x7k2p", name)
+			assert.Equal(t, "startedAt", field)
+			return time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), nil
+		}
+
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), newTestArgoProvider().PipelineStartTime())
+	})
+}
+
+func TestArgoWorkflowsConfigProvider_FullLogs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setArgoEnv(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, argoWorkflowAPI+"/log?logOptions.container=main",
+			httpmock.NewStringResponder(200, `{"result":{"content":"build","podName":"a"}}
+{"result":{"content":"test","podName":"b"}}
+`))
+
+		a := newTestArgoProvider()
+		logs, err := a.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "build\ntest\n", string(logs))
+	})
+
+	t.Run("server not configured", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		a := newTestArgoProvider()
+		logs, err := a.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+		assert.Equal(t, "n/a", a.OrchestratorVersion())
+	})
+
+	t.Run("version", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setArgoEnv(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, "https://argo.example.com/api/v1/version",
+			httpmock.NewStringResponder(200, `{"version":"v3.5.5"}`))
+
+		assert.Equal(t, "v3.5.5", newTestArgoProvider().OrchestratorVersion())
+	})
+}
@@ -0,0 +1,341 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type gitlabConfigProvider struct {
+	client       piperHttp.Client
+	header       http.Header
+	configured   bool
+	pipelineData gitlabPipeline
+}
+
+type gitlabPipeline struct {
+	fetched   bool
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type gitlabJob struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Stage  string `json:"stage"`
+	Status string `json:"status"`
+}
+
+type gitlabCommit struct {
+	ID            string `json:"id"`
+	CommittedDate string `json:"committed_date"`
+}
+
+type gitlabCompare struct {
+	Commits []gitlabCommit `json:"commits"`
+}
+
+func newGitlabConfigProvider() *gitlabConfigProvider {
+	return &gitlabConfigProvider{}
+}
+
+// Configure initializes http client for GitLabConfigProvider.
+// A configured token is sent as bearer token, otherwise the job token of the pipeline is used.
+func (g *gitlabConfigProvider) Configure(opts *Options) error {
+	token := ""
+	g.header = http.Header{}
+	if len(opts.GitLabToken) > 0 {
+		token = "Bearer " + opts.GitLabToken
+	} else if jobToken := getEnv("CI_JOB_TOKEN", ""); len(jobToken) > 0 {
+		// job tokens are only accepted in the JOB-TOKEN header
+		g.header.Set("JOB-TOKEN", jobToken)
+	}
+	g.client.SetOptions(piperHttp.ClientOptions{
+		Token:            token,
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	})
+	g.configured = true
+
+	log.Entry().Debug("Successfully initialized GitLab config provider")
+	return nil
+}
+
+// OrchestratorVersion returns the version of the GitLab instance, e.g. 16.11.1-ee
+func (g *gitlabConfigProvider) OrchestratorVersion() string {
+	return getEnv("CI_SERVER_VERSION", "n/a")
+}
+
+// OrchestratorType returns the orchestrator name e.g. Azure/GitHubActions/GitLab/Jenkins
+func (g *gitlabConfigProvider) OrchestratorType() string {
+	return "GitLab"
+}
+
+// BuildStatus returns status of the pipeline. Return variables are aligned with Jenkins build statuses.
+func (g *gitlabConfigProvider) BuildStatus() string {
+	// CI_JOB_STATUS is only available in after_script, otherwise the pipeline status is taken from the API
+	status := getEnv("CI_JOB_STATUS", "")
+	if len(status) == 0 {
+		g.fetchPipelineData()
+		status = g.pipelineData.Status
+	}
+
+	switch status {
+	case "success":
+		return BuildStatusSuccess
+	case "canceled", "canceling", "skipped":
+		return BuildStatusAborted
+	case "running", "pending", "created", "preparing", "waiting_for_resource", "scheduled", "manual":
+		return BuildStatusInProgress
+	default:
+		return BuildStatusFailure
+	}
+}
+
+// FullLogs returns the logs of all finished jobs of the current pipeline
+func (g *gitlabConfigProvider) FullLogs() ([]byte, error) {
+	if !g.configured {
+		log.Entry().Debug("ConfigProvider for GitLab is not configured. Unable to fetch logs")
+		return []byte{}, nil
+	}
+
+	jobs, err := g.fetchJobs()
+	if err != nil {
+		return []byte{}, err
+	}
+
+	var logs []byte
+	currentJobID := getEnv("CI_JOB_ID", "")
+	for _, job := range jobs {
+		// the log of the running job is incomplete and therefore skipped
+		if fmt.Sprint(job.ID) == currentJobID {
+			continue
+		}
+		logURL := fmt.Sprintf("%s/jobs/%d/trace", g.projectAPIURL(), job.ID)
+		log.Entry().Debugf("Getting log of job %s from %v", job.Name, logURL)
+		response, err := g.client.GetRequest(logURL, g.header, nil)
+		if err != nil {
+			log.Entry().Error("failed to get log", err)
+			return []byte{}, err
+		}
+		content, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			log.Entry().Error("failed to read http response", err)
+			return []byte{}, err
+		}
+		logs = append(logs, content...)
+	}
+
+	return logs, nil
+}
+
+// PipelineStartTime returns the pipeline start time in UTC
+func (g *gitlabConfigProvider) PipelineStartTime() time.Time {
+	if createdAt := getEnv("CI_PIPELINE_CREATED_AT", ""); len(createdAt) > 0 {
+		parsed, err := time.Parse(time.RFC3339, createdAt)
+		if err == nil {
+			return parsed.UTC()
+		}
+		log.Entry().Errorf("could not parse timestamp, %v", err)
+	}
+
+	g.fetchPipelineData()
+	if !g.pipelineData.StartedAt.IsZero() {
+		return g.pipelineData.StartedAt.UTC()
+	}
+	return g.pipelineData.CreatedAt.UTC()
+}
+
+// BuildID returns the ID of the pipeline, e.g. 1234567
+func (g *gitlabConfigProvider) BuildID() string {
+	return getEnv("CI_PIPELINE_ID", "n/a")
+}
+
+// StageName returns the name of the stage of the current job, e.g. "build"
+func (g *gitlabConfigProvider) StageName() string {
+	return getEnv("CI_JOB_STAGE", "n/a")
+}
+
+// BuildReason returns the reason of the pipeline trigger.
+// BuildReasons are unified with AzureDevOps build reasons, see
+// https://docs.microsoft.com/en-us/azure/devops/pipelines/build/variables?view=azure-devops&tabs=yaml#build-variables-devops-services
+func (g *gitlabConfigProvider) BuildReason() string {
+	// https://docs.gitlab.com/ee/ci/jobs/job_rules.html#ci_pipeline_source-predefined-variable
+	switch getEnv("CI_PIPELINE_SOURCE", "") {
+	case "merge_request_event", "external_pull_request_event":
+		return BuildReasonPullRequest
+	case "schedule":
+		return BuildReasonSchedule
+	case "web", "chat":
+		return BuildReasonManual
+	case "api", "trigger", "pipeline", "parent_pipeline":
+		return BuildReasonResourceTrigger
+	case "push":
+		return BuildReasonIndividualCI
+	default:
+		return BuildReasonUnknown
+	}
+}
+
+// Branch returns the source branch name, e.g. main
+func (g *gitlabConfigProvider) Branch() string {
+	if g.IsPullRequest() {
+		return getEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "n/a")
+	}
+	return getEnv("CI_COMMIT_REF_NAME", "n/a")
+}
+
+// GitReference returns the git reference. For example, refs/heads/your_branch_name
+func (g *gitlabConfigProvider) GitReference() string {
+	if g.IsPullRequest() {
+		return "refs/merge-requests/" + getEnv("CI_MERGE_REQUEST_IID", "n/a") + "/head"
+	}
+	if tag := getEnv("CI_COMMIT_TAG", ""); len(tag) > 0 {
+		return "refs/tags/" + tag
+	}
+	return "refs/heads/" + getEnv("CI_COMMIT_REF_NAME", "n/a")
+}
+
+// BuildURL returns the pipeline URL, e.g. https://gitlab.com/foo/bar/-/pipelines/1234567
+func (g *gitlabConfigProvider) BuildURL() string {
+	return getEnv("CI_PIPELINE_URL", "n/a")
+}
+
+// JobURL returns the URL of the project's pipelines, e.g. https://gitlab.com/foo/bar/-/pipelines
+func (g *gitlabConfigProvider) JobURL() string {
+	return g.RepoURL() + "/-/pipelines"
+}
+
+// JobName returns the project path, e.g. foo/bar
+func (g *gitlabConfigProvider) JobName() string {
+	return getEnv("CI_PROJECT_PATH", "n/a")
+}
+
+// CommitSHA returns the commit SHA the pipeline runs for
+func (g *gitlabConfigProvider) CommitSHA() string {
+	return getEnv("CI_COMMIT_SHA", "n/a")
+}
+
+// RepoURL returns the project URL, e.g. https://gitlab.com/foo/bar
+func (g *gitlabConfigProvider) RepoURL() string {
+	return getEnv("CI_PROJECT_URL", "n/a")
+}
+
+// PullRequestConfig returns merge request configuration
+func (g *gitlabConfigProvider) PullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: getEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "n/a"),
+		Base:   getEnv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "n/a"),
+		Key:    getEnv("CI_MERGE_REQUEST_IID", "n/a"),
+	}
+}
+
+// IsPullRequest indicates whether the current pipeline is a merge request pipeline
+func (g *gitlabConfigProvider) IsPullRequest() bool {
+	// the IID is a number, e.g. 1, which is only set in merge request pipelines
+	return len(getEnv("CI_MERGE_REQUEST_IID", "")) > 0
+}
+
+// ChangeSets returns the commits of the merge request or, for branch pipelines, the commits of the push
+func (g *gitlabConfigProvider) ChangeSets() []ChangeSet {
+	if !g.configured {
+		log.Entry().Debug("ConfigProvider for GitLab is not configured. Unable to fetch change sets")
+		return []ChangeSet{}
+	}
+
+	var commits []gitlabCommit
+	prNumber := 0
+	if g.IsPullRequest() {
+		URL := g.projectAPIURL() + "/merge_requests/" + getEnv("CI_MERGE_REQUEST_IID", "") + "/commits"
+		if err := g.getJSON(URL, &commits); err != nil {
+			log.Entry().WithError(err).Error("failed to fetch merge request commits")
+			return []ChangeSet{}
+		}
+		prNumber, _ = strconv.Atoi(getEnv("CI_MERGE_REQUEST_IID", "0"))
+	} else {
+		before := getEnv("CI_COMMIT_BEFORE_SHA", "")
+		// GitLab sets an all-zero SHA for new branches and non-push pipelines
+		if len(before) == 0 || strings.Trim(before, "0") == "" {
+			log.Entry().Debug("no previous commit available, returning empty change sets")
+			return []ChangeSet{}
+		}
+		var compare gitlabCompare
+		URL := g.projectAPIURL() + "/repository/compare?from=" + url.QueryEscape(before) + "&to=" + url.QueryEscape(g.CommitSHA())
+		if err := g.getJSON(URL, &compare); err != nil {
+			log.Entry().WithError(err).Error("failed to fetch commits of the push")
+			return []ChangeSet{}
+		}
+		commits = compare.Commits
+	}
+
+	changeSets := make([]ChangeSet, 0, len(commits))
+	for _, c := range commits {
+		changeSets = append(changeSets, ChangeSet{
+			CommitId:  c.ID,
+			Timestamp: c.CommittedDate,
+			PrNumber:  prNumber,
+		})
+	}
+	return changeSets
+}
+
+// projectAPIURL returns the API URL of the current project, e.g. https://gitlab.com/api/v4/projects/42
+func (g *gitlabConfigProvider) projectAPIURL() string {
+	return getEnv("CI_API_V4_URL", "n/a") + "/projects/" + getEnv("CI_PROJECT_ID", "n/a")
+}
+
+func (g *gitlabConfigProvider) fetchPipelineData() {
+	if !g.configured {
+		log.Entry().Debug("ConfigProvider for GitLab is not configured. Unable to fetch pipeline data")
+		return
+	}
+	if g.pipelineData.fetched {
+		return
+	}
+
+	URL := g.projectAPIURL() + "/pipelines/" + g.BuildID()
+	if err := g.getJSON(URL, &g.pipelineData); err != nil {
+		log.Entry().WithError(err).Error("failed to get pipeline information from GitLab")
+		return
+	}
+	g.pipelineData.fetched = true
+}
+
+func (g *gitlabConfigProvider) fetchJobs() ([]gitlabJob, error) {
+	var jobs []gitlabJob
+	for page := 1; ; page++ {
+		var pageJobs []gitlabJob
+		URL := fmt.Sprintf("%s/pipelines/%s/jobs?per_page=100&page=%d", g.projectAPIURL(), g.BuildID(), page)
+		if err := g.getJSON(URL, &pageJobs); err != nil {
+			return nil, errors.Wrap(err, "failed to fetch pipeline jobs")
+		}
+		jobs = append(jobs, pageJobs...)
+		if len(pageJobs) < 100 {
+			break
+		}
+	}
+	// the API returns the jobs in reverse order, logs are returned in order of execution
+	for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	}
+	return jobs, nil
+}
+
+func (g *gitlabConfigProvider) getJSON(URL string, target interface{}) error {
+	return getJSON(&g.client, URL, g.header, target)
+}
+
+func isGitLab() bool {
+	envVars := []string{"GITLAB_CI"}
+	return envVarsAreSet(envVars)
+}
@@ -0,0 +1,377 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const gitlabProjectAPI = "https://gitlab.example.com/api/v4/projects/42"
+
+func newTestGitlabProvider() *gitlabConfigProvider {
+	g := &gitlabConfigProvider{configured: true}
+	g.client.SetOptions(piperhttp.ClientOptions{
+		MaxRequestDuration:        5 * time.Second,
+		Token:                     "Bearer TOKEN",
+		TransportSkipVerification: true,
+		UseDefaultTransport:       true, // need to use default transport for http mock
+		MaxRetries:                -1,
+	})
+	return g
+}
+
+func setGitlabAPIEnv() {
+	os.Setenv("CI_API_V4_URL", "https://gitlab.example.com/api/v4")
+	os.Setenv("CI_PROJECT_ID", "42")
+	os.Setenv("CI_PIPELINE_ID", "1234")
+}
+
+func TestGitLab(t *testing.T) {
+	t.Run("GitLab - BranchBuild", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		defer ResetConfigProvider()
+		ResetConfigProvider()
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("CI_COMMIT_REF_NAME", "feat/test-gitlab")
+		os.Setenv("CI_PIPELINE_URL", "https://gitlab.example.com/foo/bar/-/pipelines/1234")
+		os.Setenv("CI_PROJECT_URL", "https://gitlab.example.com/foo/bar")
+		os.Setenv("CI_PROJECT_PATH", "foo/bar")
+		os.Setenv("CI_COMMIT_SHA", "abcdef42713")
+		os.Setenv("CI_PIPELINE_ID", "1234")
+		os.Setenv("CI_JOB_STAGE", "build")
+		os.Setenv("CI_SERVER_VERSION", "16.11.1-ee")
+		os.Setenv("CI_PIPELINE_SOURCE", "push")
+
+		p, err := GetOrchestratorConfigProvider(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, GitLab, DetectOrchestrator())
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-gitlab", p.Branch())
+		assert.Equal(t, "refs/heads/feat/test-gitlab", p.GitReference())
+		assert.Equal(t, "https://gitlab.example.com/foo/bar/-/pipelines/1234", p.BuildURL())
+		assert.Equal(t, "https://gitlab.example.com/foo/bar/-/pipelines", p.JobURL())
+		assert.Equal(t, "foo/bar", p.JobName())
+		assert.Equal(t, "abcdef42713", p.CommitSHA())
+		assert.Equal(t, "https://gitlab.example.com/foo/bar", p.RepoURL())
+		assert.Equal(t, "1234", p.BuildID())
+		assert.Equal(t, "build", p.StageName())
+		assert.Equal(t, "16.11.1-ee", p.OrchestratorVersion())
+		assert.Equal(t, "GitLab", p.OrchestratorType())
+		assert.Equal(t, BuildReasonIndividualCI, p.BuildReason())
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_COMMIT_REF_NAME", "v1.0.0")
+		os.Setenv("CI_COMMIT_TAG", "v1.0.0")
+
+		p := gitlabConfigProvider{}
+
+		assert.Equal(t, "refs/tags/v1.0.0", p.GitReference())
+	})
+
+	t.Run("MR", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_COMMIT_REF_NAME", "refs/merge-requests/42/head")
+		os.Setenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "feat/test-gitlab")
+		os.Setenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "main")
+		os.Setenv("CI_MERGE_REQUEST_IID", "42")
+		os.Setenv("CI_PIPELINE_SOURCE", "merge_request_event")
+
+		p := gitlabConfigProvider{}
+		c := p.PullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, BuildReasonPullRequest, p.BuildReason())
+		assert.Equal(t, "feat/test-gitlab", p.Branch())
+		assert.Equal(t, "refs/merge-requests/42/head", p.GitReference())
+		assert.Equal(t, "feat/test-gitlab", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("MR with single digit IID", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_MERGE_REQUEST_IID", "1")
+
+		p := gitlabConfigProvider{}
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "refs/merge-requests/1/head", p.GitReference())
+	})
+
+	t.Run("GitLab - false", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+
+		os.Setenv("GITLAB_CI", "false")
+
+		o := DetectOrchestrator()
+
+		assert.Equal(t, Orchestrator(Unknown), o)
+	})
+}
+
+func TestGitlabConfigProvider_Configure(t *testing.T) {
+	t.Run("job token", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_JOB_TOKEN", "JOBTOKEN")
+
+		g := &gitlabConfigProvider{}
+		err := g.Configure(&Options{})
+
+		assert.NoError(t, err)
+		assert.True(t, g.configured)
+		assert.Equal(t, "JOBTOKEN", g.header.Get("JOB-TOKEN"))
+	})
+
+	t.Run("configured token takes precedence", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_JOB_TOKEN", "JOBTOKEN")
+
+		g := &gitlabConfigProvider{}
+		err := g.Configure(&Options{GitLabToken: "TOKEN"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, g.header.Get("JOB-TOKEN"))
+	})
+
+	t.Run("job token is sent in header", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+		g := newTestGitlabProvider()
+		g.header = http.Header{"Job-Token": []string{"JOBTOKEN"}}
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234",
+			func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "JOBTOKEN", req.Header.Get("JOB-TOKEN"))
+				return httpmock.NewStringResponse(200, `{"status":"success"}`), nil
+			})
+
+		assert.Equal(t, BuildStatusSuccess, g.BuildStatus())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+}
+
+func TestGitlabConfigProvider_BuildReason(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "merge_request_event", want: BuildReasonPullRequest},
+		{source: "external_pull_request_event", want: BuildReasonPullRequest},
+		{source: "schedule", want: BuildReasonSchedule},
+		{source: "web", want: BuildReasonManual},
+		{source: "trigger", want: BuildReasonResourceTrigger},
+		{source: "parent_pipeline", want: BuildReasonResourceTrigger},
+		{source: "push", want: BuildReasonIndividualCI},
+		{source: "ondemand_dast_scan", want: BuildReasonUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			defer resetEnv(os.Environ())
+			os.Clearenv()
+			os.Setenv("CI_PIPELINE_SOURCE", tt.source)
+
+			g := &gitlabConfigProvider{}
+
+			assert.Equal(t, tt.want, g.BuildReason())
+		})
+	}
+}
+
+func TestGitlabConfigProvider_BuildStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		jobStatus      string
+		pipelineStatus string
+		want           string
+	}{
+		{name: "job success", jobStatus: "success", want: BuildStatusSuccess},
+		{name: "job failed", jobStatus: "failed", want: BuildStatusFailure},
+		{name: "job canceled", jobStatus: "canceled", want: BuildStatusAborted},
+		{name: "pipeline running", pipelineStatus: "running", want: BuildStatusInProgress},
+		{name: "pipeline success", pipelineStatus: "success", want: BuildStatusSuccess},
+		{name: "pipeline failed", pipelineStatus: "failed", want: BuildStatusFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetEnv(os.Environ())
+			os.Clearenv()
+			setGitlabAPIEnv()
+			if len(tt.jobStatus) > 0 {
+				os.Setenv("CI_JOB_STATUS", tt.jobStatus)
+			}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234",
+				httpmock.NewStringResponder(200, `{"status":"`+tt.pipelineStatus+`"}`))
+
+			g := newTestGitlabProvider()
+
+			assert.Equal(t, tt.want, g.BuildStatus())
+		})
+	}
+}
+
+func TestGitlabConfigProvider_PipelineStartTime(t *testing.T) {
+	t.Run("from environment", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_PIPELINE_CREATED_AT", "2024-03-18T12:30:42Z")
+
+		g := &gitlabConfigProvider{}
+
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), g.PipelineStartTime())
+	})
+
+	t.Run("from API", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234",
+			httpmock.NewStringResponder(200, `{"status":"running","created_at":"2024-03-18T12:30:00.000Z","started_at":"2024-03-18T12:30:42.000Z"}`))
+
+		g := newTestGitlabProvider()
+
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), g.PipelineStartTime())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+		// pipeline data is fetched only once
+		assert.Equal(t, BuildStatusInProgress, g.BuildStatus())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234",
+			httpmock.NewStringResponder(404, `{"message":"404 Not found"}`))
+
+		g := newTestGitlabProvider()
+
+		assert.Equal(t, time.Time{}.UTC(), g.PipelineStartTime())
+	})
+}
+
+func TestGitlabConfigProvider_FullLogs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+		os.Setenv("CI_JOB_ID", "3")
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234/jobs?per_page=100&page=1",
+			httpmock.NewStringResponder(200, `[{"id":3,"name":"deploy"},{"id":2,"name":"test"},{"id":1,"name":"build"}]`))
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/jobs/1/trace",
+			httpmock.NewStringResponder(200, "build log\n"))
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/jobs/2/trace",
+			httpmock.NewStringResponder(200, "test log\n"))
+
+		g := newTestGitlabProvider()
+		logs, err := g.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "build log\ntest log\n", string(logs))
+	})
+
+	t.Run("jobs request fails", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/pipelines/1234/jobs?per_page=100&page=1",
+			httpmock.NewStringResponder(401, `{"message":"401 Unauthorized"}`))
+
+		g := newTestGitlabProvider()
+		_, err := g.FullLogs()
+
+		assert.ErrorContains(t, err, "failed to fetch pipeline jobs")
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		g := &gitlabConfigProvider{}
+		logs, err := g.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+}
+
+func TestGitlabConfigProvider_ChangeSets(t *testing.T) {
+	t.Run("merge request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+		os.Setenv("CI_MERGE_REQUEST_IID", "7")
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/merge_requests/7/commits",
+			httpmock.NewStringResponder(200, `[{"id":"abc","committed_date":"2024-03-18T12:30:42.000Z"},{"id":"def","committed_date":"2024-03-18T12:31:42.000Z"}]`))
+
+		g := newTestGitlabProvider()
+
+		assert.Equal(t, []ChangeSet{
+			{CommitId: "abc", Timestamp: "2024-03-18T12:30:42.000Z", PrNumber: 7},
+			{CommitId: "def", Timestamp: "2024-03-18T12:31:42.000Z", PrNumber: 7},
+		}, g.ChangeSets())
+	})
+
+	t.Run("push", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "aaa")
+		os.Setenv("CI_COMMIT_SHA", "bbb")
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, gitlabProjectAPI+"/repository/compare?from=aaa&to=bbb",
+			httpmock.NewStringResponder(200, `{"commits":[{"id":"bbb","committed_date":"2024-03-18T12:30:42.000Z"}]}`))
+
+		g := newTestGitlabProvider()
+
+		assert.Equal(t, []ChangeSet{{CommitId: "bbb", Timestamp: "2024-03-18T12:30:42.000Z"}}, g.ChangeSets())
+	})
+
+	t.Run("new branch", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setGitlabAPIEnv()
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "0000000000000000000000000000000000000000")
+
+		g := newTestGitlabProvider()
+
+		assert.Empty(t, g.ChangeSets())
+	})
+}
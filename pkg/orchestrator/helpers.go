@@ -1,8 +1,12 @@
 package orchestrator
 
 import (
-	"github.com/SAP/jenkins-library/pkg/log"
+	"net/http"
 	"os"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
 )
 
 // envVarsAreSet verifies if any envvar from the list has nona non-empty, non-false value
@@ -38,3 +42,16 @@ func getEnv(key, fallback string) string {
 	log.Entry().Debugf("Could not read env variable %v using fallback value %v", key, fallback)
 	return fallback
 }
+
+// getJSON sends a GET request to the given URL and parses the JSON response into target
+func getJSON(client *piperHttp.Client, URL string, header http.Header, target interface{}) error {
+	log.Entry().Debugf("API URL: %s", URL)
+	response, err := client.GetRequest(URL, header, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to get HTTP response")
+	}
+	if response.StatusCode != 200 {
+		return errors.Errorf("response code is %v", response.StatusCode)
+	}
+	return piperHttp.ParseHTTPResponseBodyJSON(response, target)
+}
@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// Locations of the pod metadata inside the container. podInfoDir is expected to contain the files "labels" and
+// "annotations" which are provided by a Kubernetes downward API volume, see
+// https://kubernetes.io/docs/concepts/workloads/pods/downward-api/
+var (
+	podInfoDir        = "/etc/podinfo"
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// podLabel returns the value of a label of the current pod or the fallback value if the label is not available
+func podLabel(key, fallback string) string {
+	return podMetadata("labels", key, fallback)
+}
+
+// podAnnotation returns the value of an annotation of the current pod or the fallback value if the annotation is not available
+func podAnnotation(key, fallback string) string {
+	return podMetadata("annotations", key, fallback)
+}
+
+func podMetadata(file, key, fallback string) string {
+	if value, found := readPodMetadata(file)[key]; found {
+		return value
+	}
+	return fallback
+}
+
+// readPodMetadata parses a downward API file, which contains one key="value" pair per line
+func readPodMetadata(file string) map[string]string {
+	metadata := map[string]string{}
+	f, err := os.Open(filepath.Join(getEnv("PIPER_PODINFO_PATH", podInfoDir), file))
+	if err != nil {
+		return metadata
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		metadata[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		log.Entry().WithError(err).Debugf("failed to read pod %s", file)
+	}
+	return metadata
+}
+
+// podNamespace returns the namespace of the current pod
+func podNamespace() string {
+	if namespace := getEnv("POD_NAMESPACE", ""); len(namespace) > 0 {
+		return namespace
+	}
+	content, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		log.Entry().WithError(err).Debug("failed to read pod namespace")
+		return "n/a"
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// serviceAccountToken returns the token of the service account the current pod runs with
+func serviceAccountToken() string {
+	content, err := os.ReadFile(filepath.Join(serviceAccountDir, "token"))
+	if err != nil {
+		log.Entry().WithError(err).Debug("failed to read service account token")
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// resourceStartTime reads the start time of a resource in the namespace of the current pod, e.g. a PipelineRun, from
+// the Kubernetes API. The field is read from the status of the resource, if it is not set the creation time is returned.
+// The service account of the pod needs permission to get the resource.
+var resourceStartTime = func(resource schema.GroupVersionResource, name, field string) (time.Time, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to load in-cluster configuration")
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to create Kubernetes client")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	object, err := client.Resource(resource).Namespace(podNamespace()).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to get %s %s", resource.Resource, name)
+	}
+	return startTimeOf(object, field)
+}
+
+func startTimeOf(object *unstructured.Unstructured, field string) (time.Time, error) {
+	value, found, err := unstructured.NestedString(object.Object, "status", field)
+	if err != nil || !found || len(value) == 0 {
+		return object.GetCreationTimestamp().Time, nil
+	}
+	startTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse start time %s", value)
+	}
+	return startTime, nil
+}
@@ -0,0 +1,103 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// writePodInfo creates a downward API directory with the given labels and annotations
+// and points PIPER_PODINFO_PATH to it
+func writePodInfo(t *testing.T, labels, annotations string) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "labels"), []byte(labels), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "annotations"), []byte(annotations), 0644))
+	os.Setenv("PIPER_PODINFO_PATH", dir)
+}
+
+func TestReadPodMetadata(t *testing.T) {
+	t.Run("parse downward API file", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		writePodInfo(t, "app=\"piper\"\ntekton.dev/pipelineRun=\"build-run-x7k2p\"\nmalformed\n", "")
+
+		assert.Equal(t, map[string]string{
+			"app":                    "piper",
+			"tekton.dev/pipelineRun": "build-run-x7k2p",
+		}, readPodMetadata("labels"))
+		assert.Equal(t, "piper", podLabel("app", "n/a"))
+		assert.Equal(t, "n/a", podLabel("missing", "n/a"))
+		assert.Equal(t, "n/a", podAnnotation("app", "n/a"))
+	})
+
+	t.Run("file not available", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_PODINFO_PATH", t.TempDir())
+
+		assert.Empty(t, readPodMetadata("labels"))
+	})
+}
+
+func TestPodNamespace(t *testing.T) {
+	t.Run("from environment", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("POD_NAMESPACE", "ci")
+
+		assert.Equal(t, "ci", podNamespace())
+	})
+
+	t.Run("from service account", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		defer func(dir string) { serviceAccountDir = dir }(serviceAccountDir)
+		serviceAccountDir = t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(serviceAccountDir, "namespace"), []byte("pipelines\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(serviceAccountDir, "token"), []byte("ey.token\n"), 0644))
+
+		assert.Equal(t, "pipelines", podNamespace())
+		assert.Equal(t, "ey.token", serviceAccountToken())
+	})
+}
+
+func TestStartTimeOf(t *testing.T) {
+	created := metav1.NewTime(time.Date(2024, time.March, 18, 12, 30, 0, 0, time.UTC))
+
+	t.Run("from status", func(t *testing.T) {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"startTime": "2024-03-18T12:30:42Z"}}}
+		object.SetCreationTimestamp(created)
+
+		startTime, err := startTimeOf(object, "startTime")
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), startTime.UTC())
+	})
+
+	t.Run("creation time if not started", func(t *testing.T) {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		object.SetCreationTimestamp(created)
+
+		startTime, err := startTimeOf(object, "startTime")
+
+		assert.NoError(t, err)
+		assert.Equal(t, created.Time, startTime.UTC())
+	})
+
+	t.Run("invalid start time", func(t *testing.T) {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"startedAt": "yesterday"}}}
+
+		_, err := startTimeOf(object, "startedAt")
+
+		assert.ErrorContains(t, err, "failed to parse start time yesterday")
+	})
+}
@@ -14,6 +14,9 @@ const (
 	AzureDevOps
 	GitHubActions
 	Jenkins
+	GitLab
+	Tekton
+	ArgoWorkflows
 )
 
 const (
@@ -66,6 +69,7 @@ type (
 		JenkinsToken    string
 		AzureToken      string
 		GitHubToken     string
+		GitLabToken     string
 	}
 
 	PullRequestConfig struct {
@@ -89,11 +93,17 @@ func GetOrchestratorConfigProvider(opts *Options) (ConfigProvider, error) {
 			provider = newAzureDevopsConfigProvider()
 		case GitHubActions:
 			provider = newGithubActionsConfigProvider()
+		case GitLab:
+			provider = newGitlabConfigProvider()
+		case Tekton:
+			provider = newTektonConfigProvider()
+		case ArgoWorkflows:
+			provider = newArgoWorkflowsConfigProvider()
 		case Jenkins:
 			provider = newJenkinsConfigProvider()
 		default:
 			provider = newUnknownOrchestratorConfigProvider()
-			err = errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins)")
+			err = errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, GitLab, Tekton, Argo Workflows, Jenkins)")
 		}
 	})
 	if err != nil {
@@ -119,6 +129,12 @@ func DetectOrchestrator() Orchestrator {
 		return AzureDevOps
 	} else if isGitHubActions() {
 		return GitHubActions
+	} else if isGitLab() {
+		return GitLab
+	} else if isTekton() {
+		return Tekton
+	} else if isArgoWorkflows() {
+		return ArgoWorkflows
 	} else if isJenkins() {
 		return Jenkins
 	} else {
@@ -127,7 +143,7 @@ func DetectOrchestrator() Orchestrator {
 }
 
 func (o Orchestrator) String() string {
-	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins"}[o]
+	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins", "GitLab", "Tekton", "ArgoWorkflows"}[o]
 }
 
 // ResetConfigProvider is intended to be used only for unit tests because some of these tests
@@ -0,0 +1,320 @@
+package orchestrator
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	tektonPipelineRunLabel    = "tekton.dev/pipelineRun"
+	tektonPipelineRunUIDLabel = "tekton.dev/pipelineRunUID"
+	tektonPipelineTaskLabel   = "tekton.dev/pipelineTask"
+	tektonPipelineLabel       = "tekton.dev/pipeline"
+	tektonTaskRunLabel        = "tekton.dev/taskRun"
+	tektonTaskRunUIDLabel     = "tekton.dev/taskRunUID"
+	tektonReleaseAnnotation   = "pipeline.tekton.dev/release"
+	// annotations set by Pipelines-as-Code, see https://pipelinesascode.com/docs/guide/running/
+	pacPrefix = "pipelinesascode.tekton.dev/"
+
+	tektonResultsAPIPath = "/apis/results.tekton.dev/v1alpha2/parents/"
+)
+
+var (
+	tektonPipelineRuns = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+	tektonTaskRuns     = schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "taskruns"}
+)
+
+type tektonConfigProvider struct {
+	client     piperHttp.Client
+	configured bool
+	result     tektonResult
+}
+
+type tektonResult struct {
+	fetched    bool
+	CreateTime time.Time `json:"createTime"`
+	Summary    struct {
+		Status string `json:"status"`
+	} `json:"summary"`
+}
+
+type tektonLogRecords struct {
+	Records []struct {
+		Name string `json:"name"`
+	} `json:"records"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func newTektonConfigProvider() *tektonConfigProvider {
+	return &tektonConfigProvider{}
+}
+
+// Configure initializes http client for the Tekton results API
+func (t *tektonConfigProvider) Configure(_ *Options) error {
+	token := ""
+	if saToken := serviceAccountToken(); len(saToken) > 0 {
+		token = "Bearer " + saToken
+	}
+	t.client.SetOptions(piperHttp.ClientOptions{
+		Token:            token,
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	})
+	t.configured = true
+
+	log.Entry().Debug("Successfully initialized Tekton config provider")
+	return nil
+}
+
+// OrchestratorVersion returns the Tekton Pipelines release, e.g. v0.53.0
+func (t *tektonConfigProvider) OrchestratorVersion() string {
+	return podAnnotation(tektonReleaseAnnotation, "n/a")
+}
+
+// OrchestratorType returns the orchestrator name e.g. Azure/GitHubActions/Jenkins/Tekton
+func (t *tektonConfigProvider) OrchestratorType() string {
+	return "Tekton"
+}
+
+// BuildStatus returns the status of the PipelineRun as recorded by Tekton Results.
+// Return variables are aligned with Jenkins build statuses.
+func (t *tektonConfigProvider) BuildStatus() string {
+	t.fetchResult()
+	switch t.result.Summary.Status {
+	case "SUCCESS":
+		return BuildStatusSuccess
+	case "CANCELLED":
+		return BuildStatusAborted
+	case "FAILURE", "TIMEOUT":
+		return BuildStatusFailure
+	default:
+		// the summary is only written once the PipelineRun is finished
+		return BuildStatusInProgress
+	}
+}
+
+// FullLogs returns the logs of all TaskRuns of the current PipelineRun stored in Tekton Results
+func (t *tektonConfigProvider) FullLogs() ([]byte, error) {
+	if !t.configured || len(t.resultsURL()) == 0 {
+		log.Entry().Debug("ConfigProvider for Tekton is not configured. Unable to fetch logs")
+		return []byte{}, nil
+	}
+
+	var logs []byte
+	pageToken := ""
+	for {
+		URL := t.resultsURL() + "/logs"
+		if len(pageToken) > 0 {
+			URL += "?page_token=" + url.QueryEscape(pageToken)
+		}
+		var records tektonLogRecords
+		if err := getJSON(&t.client, URL, nil, &records); err != nil {
+			return []byte{}, errors.Wrap(err, "failed to list log records")
+		}
+		for _, record := range records.Records {
+			content, err := t.fetchLog(record.Name)
+			if err != nil {
+				return []byte{}, err
+			}
+			logs = append(logs, content...)
+		}
+		if len(records.NextPageToken) == 0 {
+			break
+		}
+		pageToken = records.NextPageToken
+	}
+
+	return logs, nil
+}
+
+// fetchLog fetches the content of a single log record. The content is streamed as
+// newline-delimited JSON objects with base64 encoded chunks.
+func (t *tektonConfigProvider) fetchLog(name string) ([]byte, error) {
+	URL := getEnv("TEKTON_RESULTS_API_URL", "") + tektonResultsAPIPath + name
+	log.Entry().Debugf("Getting log from %v", URL)
+	response, err := t.client.GetRequest(URL, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get log %s", name)
+	}
+	defer response.Body.Close()
+
+	var content []byte
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var chunk struct {
+			Result struct {
+				Data string `json:"data"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse log %s", name)
+		}
+		data, err := base64.StdEncoding.DecodeString(chunk.Result.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode log %s", name)
+		}
+		content = append(content, data...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read log %s", name)
+	}
+	return content, nil
+}
+
+// PipelineStartTime returns the start time of the PipelineRun in UTC. It is taken from Tekton Results if available,
+// otherwise it is read from the PipelineRun, or the TaskRun if it does not run within a pipeline.
+func (t *tektonConfigProvider) PipelineStartTime() time.Time {
+	t.fetchResult()
+	if !t.result.CreateTime.IsZero() {
+		return t.result.CreateTime.UTC()
+	}
+
+	resource, name := tektonPipelineRuns, podLabel(tektonPipelineRunLabel, "")
+	if len(name) == 0 {
+		resource, name = tektonTaskRuns, podLabel(tektonTaskRunLabel, "")
+	}
+	startTime, err := resourceStartTime(resource, name, "startTime")
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to get start time of the PipelineRun")
+	}
+	return startTime.UTC()
+}
+
+// BuildID returns the name of the PipelineRun, e.g. build-pipeline-run-x7k2p
+func (t *tektonConfigProvider) BuildID() string {
+	return podLabel(tektonPipelineRunLabel, podLabel(tektonTaskRunLabel, "n/a"))
+}
+
+// StageName returns the name of the task within the pipeline, e.g. "build"
+func (t *tektonConfigProvider) StageName() string {
+	return podLabel(tektonPipelineTaskLabel, "n/a")
+}
+
+// BuildReason returns the reason of the PipelineRun trigger.
+// BuildReasons are unified with AzureDevOps build reasons, see
+// https://docs.microsoft.com/en-us/azure/devops/pipelines/build/variables?view=azure-devops&tabs=yaml#build-variables-devops-services
+func (t *tektonConfigProvider) BuildReason() string {
+	switch podAnnotation(pacPrefix+"event-type", "") {
+	case "pull_request", "merge_request", "retest-comment", "ok-to-test-comment":
+		return BuildReasonPullRequest
+	case "push":
+		return BuildReasonIndividualCI
+	case "incoming":
+		return BuildReasonResourceTrigger
+	default:
+		return BuildReasonUnknown
+	}
+}
+
+// Branch returns the source branch name, e.g. main
+func (t *tektonConfigProvider) Branch() string {
+	if t.IsPullRequest() {
+		return t.PullRequestConfig().Branch
+	}
+	return strings.TrimPrefix(podAnnotation(pacPrefix+"branch", "n/a"), "refs/heads/")
+}
+
+// GitReference returns the git reference, e.g. refs/heads/main
+func (t *tektonConfigProvider) GitReference() string {
+	if t.IsPullRequest() {
+		return "refs/pull/" + t.PullRequestConfig().Key + "/head"
+	}
+	ref := podAnnotation(pacPrefix+"branch", "n/a")
+	if ref == "n/a" || strings.HasPrefix(ref, "refs/") {
+		return ref
+	}
+	return "refs/heads/" + ref
+}
+
+// BuildURL returns the URL of the PipelineRun in the Tekton Dashboard or the log URL provided by Pipelines-as-Code
+func (t *tektonConfigProvider) BuildURL() string {
+	if dashboardURL := getEnv("TEKTON_DASHBOARD_URL", ""); len(dashboardURL) > 0 {
+		return strings.TrimSuffix(dashboardURL, "/") + "/#/namespaces/" + podNamespace() + "/pipelineruns/" + t.BuildID()
+	}
+	return podAnnotation(pacPrefix+"log-url", "n/a")
+}
+
+// JobURL returns the URL of the pipeline in the Tekton Dashboard
+func (t *tektonConfigProvider) JobURL() string {
+	if dashboardURL := getEnv("TEKTON_DASHBOARD_URL", ""); len(dashboardURL) > 0 {
+		return strings.TrimSuffix(dashboardURL, "/") + "/#/namespaces/" + podNamespace() + "/pipelines/" + t.JobName()
+	}
+	return "n/a"
+}
+
+// JobName returns the name of the Pipeline, e.g. build-pipeline
+func (t *tektonConfigProvider) JobName() string {
+	return podLabel(tektonPipelineLabel, "n/a")
+}
+
+// CommitSHA returns the commit SHA the PipelineRun was triggered for
+func (t *tektonConfigProvider) CommitSHA() string {
+	return podAnnotation(pacPrefix+"sha", "n/a")
+}
+
+// RepoURL returns the repository URL, e.g. https://github.com/SAP/jenkins-library
+func (t *tektonConfigProvider) RepoURL() string {
+	return podAnnotation(pacPrefix+"repo-url", "n/a")
+}
+
+// PullRequestConfig returns pull request configuration
+func (t *tektonConfigProvider) PullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: strings.TrimPrefix(podAnnotation(pacPrefix+"source-branch", "n/a"), "refs/heads/"),
+		Base:   strings.TrimPrefix(podAnnotation(pacPrefix+"branch", "n/a"), "refs/heads/"),
+		Key:    podAnnotation(pacPrefix+"pull-request", "n/a"),
+	}
+}
+
+// IsPullRequest indicates whether the current PipelineRun was triggered by a pull request
+func (t *tektonConfigProvider) IsPullRequest() bool {
+	return t.BuildReason() == BuildReasonPullRequest
+}
+
+func (t *tektonConfigProvider) ChangeSets() []ChangeSet {
+	log.Entry().Debug("ChangeSets for Tekton not implemented")
+	return []ChangeSet{}
+}
+
+// resultsURL returns the URL of the Tekton Results record of the current PipelineRun
+func (t *tektonConfigProvider) resultsURL() string {
+	baseURL := getEnv("TEKTON_RESULTS_API_URL", "")
+	uid := podLabel(tektonPipelineRunUIDLabel, podLabel(tektonTaskRunUIDLabel, ""))
+	if len(baseURL) == 0 || len(uid) == 0 {
+		return ""
+	}
+	return baseURL + tektonResultsAPIPath + podNamespace() + "/results/" + uid
+}
+
+func (t *tektonConfigProvider) fetchResult() {
+	if !t.configured || len(t.resultsURL()) == 0 {
+		log.Entry().Debug("ConfigProvider for Tekton is not configured. Unable to fetch PipelineRun result")
+		return
+	}
+	if t.result.fetched {
+		return
+	}
+
+	if err := getJSON(&t.client, t.resultsURL(), nil, &t.result); err != nil {
+		log.Entry().WithError(err).Error("failed to get PipelineRun result from Tekton Results")
+		return
+	}
+	t.result.fetched = true
+}
+
+func isTekton() bool {
+	labels := readPodMetadata("labels")
+	_, pipelineRun := labels[tektonPipelineRunLabel]
+	_, taskRun := labels[tektonTaskRunLabel]
+	return pipelineRun || taskRun
+}
@@ -0,0 +1,204 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"encoding/base64"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const tektonResultsAPI = "https://results.example.com/apis/results.tekton.dev/v1alpha2/parents/ci/results/4711"
+
+const tektonLabels = `tekton.dev/pipeline="build-pipeline"
+tekton.dev/pipelineRun="build-pipeline-run-x7k2p"
+tekton.dev/pipelineRunUID="4711"
+tekton.dev/pipelineTask="build"
+tekton.dev/taskRun="build-pipeline-run-x7k2p-build"
+`
+
+func newTestTektonProvider() *tektonConfigProvider {
+	t := &tektonConfigProvider{configured: true}
+	t.client.SetOptions(piperhttp.ClientOptions{
+		MaxRequestDuration:        5 * time.Second,
+		Token:                     "Bearer TOKEN",
+		TransportSkipVerification: true,
+		UseDefaultTransport:       true, // need to use default transport for http mock
+		MaxRetries:                -1,
+	})
+	return t
+}
+
+func TestTekton(t *testing.T) {
+	t.Run("Tekton - push", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		defer ResetConfigProvider()
+		ResetConfigProvider()
+		writePodInfo(t, tektonLabels, `pipeline.tekton.dev/release="v0.53.0"
+pipelinesascode.tekton.dev/event-type="push"
+pipelinesascode.tekton.dev/branch="refs/heads/main"
+pipelinesascode.tekton.dev/sha="abcdef42713"
+pipelinesascode.tekton.dev/repo-url="https://github.com/foo/bar"
+`)
+		os.Setenv("POD_NAMESPACE", "ci")
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com/")
+
+		p, err := GetOrchestratorConfigProvider(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Tekton, DetectOrchestrator())
+		assert.Equal(t, "Tekton", p.OrchestratorType())
+		assert.Equal(t, "v0.53.0", p.OrchestratorVersion())
+		assert.Equal(t, "build-pipeline-run-x7k2p", p.BuildID())
+		assert.Equal(t, "build", p.StageName())
+		assert.Equal(t, "build-pipeline", p.JobName())
+		assert.Equal(t, BuildReasonIndividualCI, p.BuildReason())
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "main", p.Branch())
+		assert.Equal(t, "refs/heads/main", p.GitReference())
+		assert.Equal(t, "abcdef42713", p.CommitSHA())
+		assert.Equal(t, "https://github.com/foo/bar", p.RepoURL())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-pipeline-run-x7k2p", p.BuildURL())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelines/build-pipeline", p.JobURL())
+	})
+
+	t.Run("Tekton - PR", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		writePodInfo(t, tektonLabels, `pipelinesascode.tekton.dev/event-type="pull_request"
+pipelinesascode.tekton.dev/branch="main"
+pipelinesascode.tekton.dev/source-branch="feat/test-tekton"
+pipelinesascode.tekton.dev/pull-request="42"
+pipelinesascode.tekton.dev/log-url="https://console.example.com/run/42"
+`)
+
+		p := tektonConfigProvider{}
+		c := p.PullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, BuildReasonPullRequest, p.BuildReason())
+		assert.Equal(t, "feat/test-tekton", p.Branch())
+		assert.Equal(t, "refs/pull/42/head", p.GitReference())
+		assert.Equal(t, "https://console.example.com/run/42", p.BuildURL())
+		assert.Equal(t, "feat/test-tekton", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("Tekton - false", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		writePodInfo(t, `app="piper"`, "")
+
+		assert.Equal(t, Orchestrator(Unknown), DetectOrchestrator())
+	})
+}
+
+func TestTektonConfigProvider_Results(t *testing.T) {
+	setup := func(t *testing.T) {
+		writePodInfo(t, tektonLabels, "")
+		os.Setenv("POD_NAMESPACE", "ci")
+		os.Setenv("TEKTON_RESULTS_API_URL", "https://results.example.com")
+	}
+
+	t.Run("status and start time", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setup(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI,
+			httpmock.NewStringResponder(200, `{"name":"ci/results/4711","createTime":"2024-03-18T12:30:42Z","summary":{"status":"SUCCESS"}}`))
+
+		p := newTestTektonProvider()
+
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), p.PipelineStartTime())
+		assert.Equal(t, BuildStatusSuccess, p.BuildStatus())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("running PipelineRun", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setup(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI,
+			httpmock.NewStringResponder(200, `{"name":"ci/results/4711","createTime":"2024-03-18T12:30:42Z"}`))
+
+		p := newTestTektonProvider()
+
+		assert.Equal(t, BuildStatusInProgress, p.BuildStatus())
+	})
+
+	t.Run("results API not configured", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		writePodInfo(t, tektonLabels, "")
+		defer func(f func(schema.GroupVersionResource, string, string) (time.Time, error)) { resourceStartTime = f }(resourceStartTime)
+		resourceStartTime = func(resource schema.GroupVersionResource, name, field string) (time.Time, error) {
+			assert.Equal(t, tektonPipelineRuns, resource)
+			assert.Equal(t, "build-pipeline-run-x7k2p", name)
+			assert.Equal(t, "startTime", field)
+			return time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), nil
+		}
+
+		p := newTestTektonProvider()
+		logs, err := p.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+		assert.Equal(t, time.Date(2024, time.March, 18, 12, 30, 42, 0, time.UTC), p.PipelineStartTime())
+	})
+
+	t.Run("full logs", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setup(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI+"/logs",
+			httpmock.NewStringResponder(200, `{"records":[{"name":"ci/results/4711/logs/a"}],"nextPageToken":"next"}`))
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI+"/logs?page_token=next",
+			httpmock.NewStringResponder(200, `{"records":[{"name":"ci/results/4711/logs/b"}]}`))
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI+"/logs/a",
+			httpmock.NewStringResponder(200, `{"result":{"data":"`+base64.StdEncoding.EncodeToString([]byte("clone\n"))+`"}}
+{"result":{"data":"`+base64.StdEncoding.EncodeToString([]byte("done\n"))+`"}}`))
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI+"/logs/b",
+			httpmock.NewStringResponder(200, `{"result":{"data":"`+base64.StdEncoding.EncodeToString([]byte("build\n"))+`"}}`))
+
+		p := newTestTektonProvider()
+		logs, err := p.FullLogs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "clone\ndone\nbuild\n", string(logs))
+	})
+
+	t.Run("listing logs fails", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		setup(t)
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, tektonResultsAPI+"/logs",
+			httpmock.NewStringResponder(403, `{"message":"forbidden"}`))
+
+		p := newTestTektonProvider()
+		_, err := p.FullLogs()
+
+		assert.ErrorContains(t, err, "failed to list log records")
+	})
+}
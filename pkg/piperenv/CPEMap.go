@@ -7,8 +7,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
 
@@ -27,14 +29,28 @@ func (c *CPEMap) LoadFromDisk(path string) error {
 	return nil
 }
 
-// WriteToDisk writes the CPEMap to a disk and uses rootDirectory as the starting point
+// WriteToDisk writes the CPEMap to a disk and uses rootDirectory as the starting point.
+// Changed values are recorded in the journal of the directory. In dry run mode the values are only recorded in the plan.
 func (c CPEMap) WriteToDisk(rootDirectory string) error {
+	if dryrun.Enabled() {
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			dryrun.RecordFile("write", path.Join(rootDirectory, k))
+		}
+		return nil
+	}
+
 	err := os.MkdirAll(rootDirectory, 0777)
 	if err != nil {
 		return err
 	}
 
 	for k, v := range c {
+		journalChange(rootDirectory, k, v)
 		entryPath := path.Join(rootDirectory, k)
 		err := os.MkdirAll(filepath.Dir(entryPath), 0777)
 		if err != nil {
@@ -74,6 +90,9 @@ func dirToMap(m map[string]interface{}, dirPath, prefix string) error {
 	}
 
 	for _, dirItem := range items {
+		if len(prefix) == 0 && dirItem.Name() == JournalFileName {
+			continue
+		}
 		if dirItem.IsDir() {
 			err := dirToMap(m, path.Join(dirPath, dirItem.Name()), dirItem.Name())
 			if err != nil {
@@ -103,6 +122,10 @@ func dirToMap(m map[string]interface{}, dirPath, prefix string) error {
 }
 
 func addEmptyValueToFile(fullPath string) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile("write", fullPath)
+		return nil
+	}
 	err := os.WriteFile(fullPath, []byte(""), 0666)
 	if err != nil {
 		return err
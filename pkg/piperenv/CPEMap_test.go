@@ -10,6 +10,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,6 +57,24 @@ func Test_writeMapToDisk(t *testing.T) {
 	}
 }
 
+func TestCPEMap_WriteToDiskDryRun(t *testing.T) {
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+	tmpDir := t.TempDir()
+
+	require.NoError(t, CPEMap{"git/commitId": "abc", "custom/list": []string{"a"}}.WriteToDisk(tmpDir))
+	require.NoError(t, SetResourceParameter(tmpDir, "commonPipelineEnvironment", "custom/value", "x"))
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, []dryrun.Action{
+		{Type: dryrun.ActionFile, Operation: "write", Path: path.Join(tmpDir, "custom/list")},
+		{Type: dryrun.ActionFile, Operation: "write", Path: path.Join(tmpDir, "git/commitId")},
+		{Type: dryrun.ActionFile, Operation: "write", Path: path.Join(tmpDir, "commonPipelineEnvironment", "custom", "value"), Size: 1},
+	}, dryrun.GetPlan().Actions)
+}
+
 func TestCPEMap_LoadFromDisk(t *testing.T) {
 	t.Parallel()
 	tmpDir := t.TempDir()
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/pkg/errors"
 )
@@ -29,6 +30,9 @@ func SetResourceParameter(path, resourceName, paramName string, value interface{
 			return errors.Wrapf(err, "failed to marshal resource parameter value %v", typedValue)
 		}
 	}
+	if resourceName == CommonPipelineEnvironment && len(content) > 0 {
+		journalChange(filepath.Join(path, resourceName), paramName, value)
+	}
 	return writeToDisk(paramPath, content)
 }
 
@@ -53,6 +57,12 @@ func GetParameter(path, name string) string {
 }
 
 func writeToDisk(filename string, data []byte) error {
+	if dryrun.Enabled() {
+		if len(data) > 0 {
+			dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "write", Path: filename, Size: len(data)})
+		}
+		return nil
+	}
 
 	if _, err := os.Stat(filepath.Dir(filename)); os.IsNotExist(err) {
 		log.Entry().Debugf("Creating directory: %v", filepath.Dir(filename))
@@ -0,0 +1,194 @@
+package piperenv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/dryrun"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// CommonPipelineEnvironment is the name of the resource containing the common pipeline environment
+const CommonPipelineEnvironment = "commonPipelineEnvironment"
+
+// JournalFileName is the name of the journal within the directory of an environment, e.g.
+// .pipeline/commonPipelineEnvironment/.journal.jsonl, so that it is stashed together with the environment.
+// It is not part of the values of the environment.
+const JournalFileName = ".journal.jsonl"
+
+// JournalEntry records a change of a value of the environment
+type JournalEntry struct {
+	Time     time.Time   `json:"time"`
+	Step     string      `json:"step,omitempty"`
+	Key      string      `json:"key"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue"`
+}
+
+var (
+	journalStep  string
+	journalMutex sync.Mutex
+	timeNow      = time.Now
+)
+
+// SetJournalStep defines the step which is recorded as the writer of changes
+func SetJournalStep(step string) {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	journalStep = step
+}
+
+// JournalPath returns the location of the journal of the environment in the directory
+func JournalPath(envDir string) string {
+	return filepath.Join(envDir, JournalFileName)
+}
+
+// journalChange appends an entry to the journal if the value of the key differs from the value on disk,
+// it needs to be called before the value is written. Failures are only logged since the journal is
+// a debugging aid which must not break a pipeline.
+func journalChange(envDir, key string, newValue interface{}) {
+	if dryrun.Enabled() {
+		// the values are not written, so there is no change
+		return
+	}
+	oldValue, exists := readValue(envDir, key)
+	if exists && reflect.DeepEqual(oldValue, normalize(newValue)) {
+		return
+	}
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+	entry := JournalEntry{Time: timeNow().UTC(), Step: journalStep, Key: filepath.ToSlash(key), OldValue: oldValue, NewValue: newValue}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("failed to journal change of %v", key)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(JournalPath(envDir)), 0777); err != nil {
+		log.Entry().WithError(err).Debugf("failed to journal change of %v", key)
+		return
+	}
+	journal, err := os.OpenFile(JournalPath(envDir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("failed to journal change of %v", key)
+		return
+	}
+	defer journal.Close()
+	if _, err := journal.Write(append(line, '\n')); err != nil {
+		log.Entry().WithError(err).Debugf("failed to journal change of %v", key)
+	}
+}
+
+// ReadJournal returns the changes of the environment in the directory in the order they happened
+func ReadJournal(envDir string) ([]JournalEntry, error) {
+	journal, err := os.Open(JournalPath(envDir))
+	if os.IsNotExist(err) {
+		return []JournalEntry{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open journal")
+	}
+	defer journal.Close()
+
+	entries := []JournalEntry{}
+	scanner := bufio.NewScanner(journal)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, errors.Wrapf(err, "invalid entry %v in journal", len(entries)+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errors.Wrap(scanner.Err(), "failed to read journal")
+}
+
+// readValue returns the value of the key as it is stored on disk
+func readValue(envDir, key string) (interface{}, bool) {
+	entryPath := filepath.Join(envDir, key)
+	if content, err := os.ReadFile(entryPath); err == nil {
+		return string(content), true
+	}
+	content, err := os.ReadFile(entryPath + ".json")
+	if err != nil {
+		return nil, false
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// normalize converts a value to the representation it has after being read from disk
+func normalize(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// KeyChange is the overall change of a key across all entries of a journal
+type KeyChange struct {
+	Key      string      `json:"key"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue"`
+	Steps    []string    `json:"steps,omitempty"`
+}
+
+// Diff condenses the entries of a journal to the changes per key, sorted by key.
+// Keys whose final value equals their initial value are omitted.
+func Diff(entries []JournalEntry) []KeyChange {
+	changes := map[string]*KeyChange{}
+	for _, entry := range entries {
+		change, ok := changes[entry.Key]
+		if !ok {
+			change = &KeyChange{Key: entry.Key, OldValue: entry.OldValue}
+			changes[entry.Key] = change
+		}
+		change.NewValue = entry.NewValue
+		if len(entry.Step) > 0 && (len(change.Steps) == 0 || change.Steps[len(change.Steps)-1] != entry.Step) {
+			change.Steps = append(change.Steps, entry.Step)
+		}
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	diff := []KeyChange{}
+	for _, key := range keys {
+		change := changes[key]
+		if change.OldValue != nil && reflect.DeepEqual(normalize(change.OldValue), normalize(change.NewValue)) {
+			continue
+		}
+		diff = append(diff, *change)
+	}
+	return diff
+}
@@ -0,0 +1,85 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	envDir := filepath.Join(t.TempDir(), CommonPipelineEnvironment)
+
+	t.Run("no journal", func(t *testing.T) {
+		entries, err := ReadJournal(envDir)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("records changes", func(t *testing.T) {
+		defer SetJournalStep("")
+
+		SetJournalStep("artifactPrepareVersion")
+		require.NoError(t, CPEMap{"artifactVersion": "1.0.0", "custom/list": []string{"a"}}.WriteToDisk(envDir))
+		// unchanged values are not recorded
+		SetJournalStep("mavenBuild")
+		require.NoError(t, CPEMap{"artifactVersion": "1.0.0", "custom/list": []string{"a"}}.WriteToDisk(envDir))
+		SetJournalStep("kanikoExecute")
+		require.NoError(t, CPEMap{"artifactVersion": "1.0.1"}.WriteToDisk(envDir))
+
+		entries, err := ReadJournal(envDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		versionEntries := []JournalEntry{}
+		for _, entry := range entries {
+			if entry.Key == "artifactVersion" {
+				versionEntries = append(versionEntries, entry)
+			}
+		}
+		require.Len(t, versionEntries, 2)
+		assert.Equal(t, "artifactPrepareVersion", versionEntries[0].Step)
+		assert.Nil(t, versionEntries[0].OldValue)
+		assert.Equal(t, "1.0.0", versionEntries[0].NewValue)
+		assert.Equal(t, "kanikoExecute", versionEntries[1].Step)
+		assert.Equal(t, "1.0.0", versionEntries[1].OldValue)
+		assert.Equal(t, "1.0.1", versionEntries[1].NewValue)
+	})
+
+	t.Run("records changes of resource parameters", func(t *testing.T) {
+		path := t.TempDir()
+		defer SetJournalStep("")
+		SetJournalStep("gitopsUpdateDeployment")
+
+		require.NoError(t, SetResourceParameter(path, CommonPipelineEnvironment, "custom/flag", true))
+		// other resources are not recorded
+		require.NoError(t, SetResourceParameter(path, "influx", "step_data/flag", true))
+
+		entries, err := ReadJournal(filepath.Join(path, CommonPipelineEnvironment))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "custom/flag", entries[0].Key)
+		assert.Equal(t, true, entries[0].NewValue)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	entries := []JournalEntry{
+		{Step: "artifactPrepareVersion", Key: "artifactVersion", NewValue: "1.0.0"},
+		{Step: "mavenBuild", Key: "custom/count", OldValue: json.Number("1"), NewValue: json.Number("2")},
+		{Step: "kanikoExecute", Key: "artifactVersion", OldValue: "1.0.0", NewValue: "1.0.1"},
+		{Step: "kanikoExecute", Key: "artifactVersion", OldValue: "1.0.1", NewValue: "1.0.2"},
+		{Step: "helmExecute", Key: "custom/count", OldValue: json.Number("2"), NewValue: json.Number("1")},
+	}
+
+	diff := Diff(entries)
+
+	assert.Equal(t, []KeyChange{
+		{Key: "artifactVersion", NewValue: "1.0.2", Steps: []string{"artifactPrepareVersion", "kanikoExecute"}},
+	}, diff)
+}
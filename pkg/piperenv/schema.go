@@ -0,0 +1,89 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Schema describes the known keys of an environment, e.g. derived from the outputs of the steps
+type Schema map[string]KeySchema
+
+// KeySchema describes the type of the value of a key and the steps which write it
+type KeySchema struct {
+	Type  string   `json:"type"`
+	Steps []string `json:"steps,omitempty"`
+}
+
+// Add registers a key written by a step, keys without type are strings
+func (s Schema) Add(key, valueType, step string) {
+	if len(valueType) == 0 {
+		valueType = "string"
+	}
+	keySchema := s[key]
+	if len(keySchema.Type) == 0 {
+		keySchema.Type = valueType
+	}
+	keySchema.Steps = append(keySchema.Steps, step)
+	s[key] = keySchema
+}
+
+// Check returns an error for each value whose type does not match the schema, unknown keys are not checked
+func (s Schema) Check(cpe CPEMap) []error {
+	keys := make([]string, 0, len(cpe))
+	for key := range cpe {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := []error{}
+	for _, key := range keys {
+		keySchema, ok := s[key]
+		if !ok || cpe[key] == nil || typeMatches(keySchema.Type, cpe[key]) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("value of '%v' is %T instead of %v", key, cpe[key], keySchema.Type))
+	}
+	return errs
+}
+
+// typeMatches checks whether the value has the type of a step parameter, values of unknown types always match
+func typeMatches(valueType string, value interface{}) bool {
+	value = normalize(value)
+	switch valueType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := number.Int64()
+		return err == nil
+	case "[]string":
+		return isListOf(value, func(item interface{}) bool { _, ok := item.(string); return ok })
+	case "map[string]interface{}":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "[]map[string]interface{}":
+		return isListOf(value, func(item interface{}) bool { _, ok := item.(map[string]interface{}); return ok })
+	}
+	return true
+}
+
+func isListOf(value interface{}, matches func(interface{}) bool) bool {
+	list, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if !matches(item) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	schema := Schema{}
+	schema.Add("artifactVersion", "", "artifactPrepareVersion")
+	schema.Add("artifactVersion", "", "kanikoExecute")
+	schema.Add("custom/isChangeInDevelopment", "bool", "isChangeInDevelopment")
+	schema.Add("container/imageNames", "[]string", "kanikoExecute")
+	schema.Add("custom/count", "int", "mavenBuild")
+	schema.Add("custom/artifacts", "piperenv.Artifacts", "mavenBuild")
+
+	t.Run("add", func(t *testing.T) {
+		assert.Equal(t, KeySchema{Type: "string", Steps: []string{"artifactPrepareVersion", "kanikoExecute"}}, schema["artifactVersion"])
+	})
+
+	t.Run("matching values", func(t *testing.T) {
+		errs := schema.Check(CPEMap{
+			"artifactVersion":              "1.0.0",
+			"custom/isChangeInDevelopment": true,
+			"container/imageNames":         []interface{}{"image"},
+			"custom/count":                 json.Number("5"),
+			"custom/artifacts":             []interface{}{map[string]interface{}{"name": "app"}},
+			"custom/unknown":               5,
+		})
+		assert.Empty(t, errs)
+	})
+
+	t.Run("mismatching values", func(t *testing.T) {
+		errs := schema.Check(CPEMap{
+			"artifactVersion":              json.Number("1"),
+			"custom/isChangeInDevelopment": "true",
+			"container/imageNames":         []interface{}{1},
+		})
+		if assert.Len(t, errs, 3) {
+			assert.EqualError(t, errs[0], "value of 'artifactVersion' is json.Number instead of string")
+			assert.EqualError(t, errs[1], "value of 'container/imageNames' is []interface {} instead of []string")
+			assert.EqualError(t, errs[2], "value of 'custom/isChangeInDevelopment' is string instead of bool")
+		}
+	})
+}
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/bmatcuk/doublestar"
+
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 )
 
 // FileUtils ...
@@ -107,6 +109,15 @@ func (f Files) Copy(src, dst string) (int64, error) {
 		return 0, errors.New("Source file '" + src + "' does not exist")
 	}
 
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "copy", Path: src, Target: dst})
+		stats, err := os.Stat(src)
+		if err != nil {
+			return 0, err
+		}
+		return stats.Size(), nil
+	}
+
 	source, err := os.Open(src)
 	if err != nil {
 		return 0, err
@@ -136,6 +147,11 @@ func (f Files) Move(src, dst string) error {
 		return fmt.Errorf("file doesn't exist: %s", src)
 	}
 
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "move", Path: src, Target: dst})
+		return nil
+	}
+
 	if _, err := f.Copy(src, dst); err != nil {
 		return err
 	}
@@ -145,11 +161,19 @@ func (f Files) Move(src, dst string) error {
 
 // Chmod is a wrapper for os.Chmod().
 func (f Files) Chmod(path string, mode os.FileMode) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile(fmt.Sprintf("chmod %v", mode), path)
+		return nil
+	}
 	return os.Chmod(path, mode)
 }
 
 // Chown is a recursive wrapper for os.Chown().
 func (f Files) Chown(path string, uid, gid int) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile(fmt.Sprintf("chown %v:%v", uid, gid), path)
+		return nil
+	}
 	return filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -389,6 +413,10 @@ func (f Files) ReadFile(path string) ([]byte, error) {
 
 // FileWrite is a wrapper for os.WriteFile().
 func (f Files) FileWrite(path string, content []byte, perm os.FileMode) error {
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "write", Path: path, Size: len(content)})
+		return nil
+	}
 	return os.WriteFile(path, content, perm)
 }
 
@@ -399,26 +427,47 @@ func (f Files) WriteFile(path string, content []byte, perm os.FileMode) error {
 
 // FileRemove is a wrapper for os.Remove().
 func (f Files) FileRemove(path string) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile("remove", path)
+		return nil
+	}
 	return os.Remove(path)
 }
 
 // FileRename is a wrapper for os.Rename().
 func (f Files) FileRename(oldPath, newPath string) error {
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "rename", Path: oldPath, Target: newPath})
+		return nil
+	}
 	return os.Rename(oldPath, newPath)
 }
 
 // FileOpen is a wrapper for os.OpenFile().
 func (f *Files) FileOpen(name string, flag int, perm os.FileMode) (*os.File, error) {
+	if dryrun.Enabled() && flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		dryrun.RecordFile("write", name)
+		// everything written is discarded
+		return os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	}
 	return os.OpenFile(name, flag, perm)
 }
 
 // MkdirAll is a wrapper for os.MkdirAll().
 func (f Files) MkdirAll(path string, perm os.FileMode) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile("mkdir", path)
+		return nil
+	}
 	return os.MkdirAll(path, perm)
 }
 
 // RemoveAll is a wrapper for os.RemoveAll().
 func (f Files) RemoveAll(path string) error {
+	if dryrun.Enabled() {
+		dryrun.RecordFile("remove", path)
+		return nil
+	}
 	return os.RemoveAll(path)
 }
 
@@ -478,6 +527,10 @@ func (f Files) Abs(path string) (string, error) {
 
 // Symlink is a wrapper for os.Symlink
 func (f Files) Symlink(oldname, newname string) error {
+	if dryrun.Enabled() {
+		dryrun.Record(dryrun.Action{Type: dryrun.ActionFile, Operation: "symlink", Path: newname, Target: oldname})
+		return nil
+	}
 	return os.Symlink(oldname, newname)
 }
 
@@ -514,6 +567,10 @@ func (f Files) Open(name string) (io.ReadWriteCloser, error) {
 
 // Create is a wrapper for os.Create
 func (f Files) Create(name string) (io.ReadWriteCloser, error) {
+	if dryrun.Enabled() {
+		dryrun.RecordFile("write", name)
+		return &discardFile{}, nil
+	}
 	return os.Create(name)
 }
 
@@ -526,3 +583,18 @@ func (f Files) Readlink(name string) (string, error) {
 func (f Files) Lstat(path string) (os.FileInfo, error) {
 	return os.Lstat(path)
 }
+
+// discardFile is returned by Create in dry run mode, everything written to it is discarded
+type discardFile struct{}
+
+func (d *discardFile) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (d *discardFile) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (d *discardFile) Close() error {
+	return nil
+}
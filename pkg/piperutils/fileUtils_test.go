@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFileExists(t *testing.T) {
@@ -84,6 +86,49 @@ func TestCopy(t *testing.T) {
 	})
 }
 
+func TestFilesDryRun(t *testing.T) {
+	runInTempDir(t, "file modifications are recorded only", func(t *testing.T) {
+		require.NoError(t, os.WriteFile("source.txt", []byte("content"), 0644))
+		dryrun.Enable()
+		t.Cleanup(dryrun.Reset)
+		files := Files{}
+
+		assert.NoError(t, files.FileWrite("target.txt", []byte("new"), 0644))
+		size, err := files.Copy("source.txt", "copy.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), size)
+		assert.NoError(t, files.MkdirAll("out", 0755))
+		assert.NoError(t, files.FileRemove("source.txt"))
+		file, err := files.Create("created.txt")
+		require.NoError(t, err)
+		_, err = file.Write([]byte("data"))
+		assert.NoError(t, err)
+		assert.NoError(t, file.Close())
+		opened, err := files.FileOpen("opened.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = opened.Write([]byte("data"))
+		assert.NoError(t, err)
+		assert.NoError(t, opened.Close())
+		opened, err = files.FileOpen("source.txt", os.O_RDONLY, 0)
+		require.NoError(t, err)
+		assert.NoError(t, opened.Close())
+
+		for _, name := range []string{"target.txt", "copy.txt", "out", "created.txt", "opened.txt"} {
+			_, err := os.Stat(name)
+			assert.True(t, os.IsNotExist(err), name)
+		}
+		assert.FileExists(t, "source.txt")
+		assert.Equal(t, []dryrun.Action{
+			{Type: dryrun.ActionFile, Operation: "write", Path: "target.txt", Size: 3},
+			{Type: dryrun.ActionFile, Operation: "copy", Path: "source.txt", Target: "copy.txt"},
+			{Type: dryrun.ActionFile, Operation: "mkdir", Path: "out"},
+			{Type: dryrun.ActionFile, Operation: "remove", Path: "source.txt"},
+			{Type: dryrun.ActionFile, Operation: "write", Path: "created.txt"},
+			{Type: dryrun.ActionFile, Operation: "write", Path: "opened.txt"},
+		}, dryrun.GetPlan().Actions)
+	})
+}
+
 func runInTempDir(t *testing.T, nameOfRun string, run func(t *testing.T)) {
 	t.Run(nameOfRun, func(t *testing.T) {
 		dir := t.TempDir()
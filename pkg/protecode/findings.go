@@ -0,0 +1,51 @@
+package protecode
+
+import (
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/package-url/packageurl-go"
+)
+
+// ToFindings converts the vulnerabilities of the components of a result into scanner independent findings.
+// Historical vulnerabilities, i.e. vulnerabilities of older versions of a component, are skipped.
+func ToFindings(result Result, excludeCVEs string) []format.Finding {
+	findings := []format.Finding{}
+	for _, component := range result.Components {
+		purl := ""
+		if len(component.Lib) > 0 {
+			purl = packageurl.NewPackageURL("generic", "", component.Lib, component.Version, nil, "").ToString()
+		}
+		for _, vulnerability := range component.Vulns {
+			if !isExact(vulnerability) {
+				continue
+			}
+			finding := format.Finding{
+				Tool:       "Protecode",
+				Rule:       vulnerability.Vuln.Cve,
+				CVE:        format.FindCVE(vulnerability.Vuln.Cve),
+				PackageURL: purl,
+				Severity:   format.SeverityFromScore(vulnerabilityScore(vulnerability)),
+				Status:     format.FindingStatusOpen,
+			}
+			if isTriaged(vulnerability) {
+				finding.Status = format.FindingStatusSuppressed
+				finding.AuditState = vulnerability.Triage[0].Scope
+			} else if isExcluded(vulnerability, excludeCVEs) {
+				finding.Status = format.FindingStatusSuppressed
+				finding.AuditState = "excluded"
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// vulnerabilityScore returns the CVSS v3 score with a fallback to CVSS v2
+func vulnerabilityScore(vulnerability Vulnerability) float64 {
+	if cvss3, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss3Score, 64); cvss3 > 0 {
+		return cvss3
+	}
+	cvss, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss, 64)
+	return cvss
+}
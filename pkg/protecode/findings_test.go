@@ -0,0 +1,42 @@
+//go:build unit
+// +build unit
+
+package protecode
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	result := Result{Components: []Component{{
+		Lib:     "acl",
+		Version: "2.2.52-1",
+		Vulns: []Vulnerability{
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2009-4411", Cvss: "3.7", Cvss3Score: "0"}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2018-1000", Cvss: "4.0", Cvss3Score: "9.1"}, Triage: []Triage{{Scope: "GLOBAL"}}},
+			{Exact: true, Vuln: Vuln{Cve: "CVE-2019-1000", Cvss3Score: "7.5"}},
+			{Exact: false, Vuln: Vuln{Cve: "CVE-2000-1000", Cvss3Score: "7.5"}},
+		},
+	}}}
+
+	findings := ToFindings(result, "CVE-2019-1000")
+
+	if assert.Len(t, findings, 3) {
+		assert.Equal(t, format.Finding{
+			Tool:       "Protecode",
+			Rule:       "CVE-2009-4411",
+			CVE:        "CVE-2009-4411",
+			PackageURL: "pkg:generic/acl@2.2.52-1",
+			Severity:   format.SeverityLow,
+			Status:     format.FindingStatusOpen,
+		}, findings[0])
+		assert.Equal(t, format.SeverityCritical, findings[1].Severity)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[1].Status)
+		assert.Equal(t, "GLOBAL", findings[1].AuditState)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[2].Status)
+		assert.Equal(t, "excluded", findings[2].AuditState)
+	}
+}
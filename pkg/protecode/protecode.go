@@ -46,7 +46,9 @@ type Result struct {
 
 // Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib     string          `json:"lib,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Vulns   []Vulnerability `json:"vulns,omitempty"`
 }
 
 // Vulnerability the protecode vulnerability information
@@ -0,0 +1,69 @@
+package reporting
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/pkg/errors"
+)
+
+const findingsMdTemplate = `## Consolidated Findings
+
+Total number of findings after deduplication across tools: {{len .}} (open: {{openCount .}})
+
+<details><summary><i>Consolidated findings details:</i></summary>
+<p>
+
+<table>
+<tr>
+	<th>Severity</th>
+	<th>Status</th>
+	<th>Tools</th>
+	<th>Vulnerability</th>
+	<th>Package / Location</th>
+	<th>Rule</th>
+</tr>
+{{range $f := . -}}
+<tr>
+	<td>{{$f.Severity}}</td>
+	<td>{{$f.Status}}{{if $f.AuditState}} ({{$f.AuditState}}){{end}}</td>
+	<td>{{join $f.Tools}}</td>
+	<td>{{if $f.CVE}}{{$f.CVE}}{{else if $f.CWE}}CWE-{{$f.CWE}}{{end}}</td>
+	<td>{{if $f.PackageURL}}{{$f.PackageURL}}{{else if $f.Location}}{{$f.Location.File}}{{if $f.Location.Line}}:{{$f.Location.Line}}{{end}}{{end}}</td>
+	<td>{{$f.Rule}}</td>
+</tr>
+{{end -}}
+</table>
+</p>
+</details>
+
+`
+
+// FindingsToMarkdown creates a markdown table of findings, e.g. the deduplicated findings of all scans of a pipeline
+func FindingsToMarkdown(findings []format.Finding) ([]byte, error) {
+	funcMap := template.FuncMap{
+		"join": func(values []string) string {
+			return strings.Join(values, ", ")
+		},
+		"openCount": func(findings []format.Finding) int {
+			count := 0
+			for _, finding := range findings {
+				if finding.Status == format.FindingStatusOpen {
+					count++
+				}
+			}
+			return count
+		},
+	}
+	tmpl, err := template.New("findings").Funcs(funcMap).Parse(findingsMdTemplate)
+	if err != nil {
+		return []byte{}, errors.Wrap(err, "failed to create Markdown findings template")
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, findings); err != nil {
+		return []byte{}, errors.Wrap(err, "failed to execute Markdown findings template")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,29 @@
+//go:build unit
+// +build unit
+
+package reporting
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindingsToMarkdown(t *testing.T) {
+	findings := []format.Finding{
+		{Tools: []string{"BlackDuck", "Mend"}, CVE: "CVE-2021-44228", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Severity: format.SeverityCritical, Status: format.FindingStatusOpen},
+		{Tools: []string{"CodeQL"}, Rule: "java/xss", CWE: "79", Location: &format.FindingLocation{File: "src/App.java", Line: 12}, Severity: format.SeverityMedium, Status: format.FindingStatusSuppressed, AuditState: "NOT_EXPLOITABLE"},
+	}
+
+	markdown, err := FindingsToMarkdown(findings)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(markdown), "Total number of findings after deduplication across tools: 2 (open: 1)")
+	assert.Contains(t, string(markdown), "<td>BlackDuck, Mend</td>")
+	assert.Contains(t, string(markdown), "<td>CVE-2021-44228</td>")
+	assert.Contains(t, string(markdown), "<td>pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1</td>")
+	assert.Contains(t, string(markdown), "<td>suppressed (NOT_EXPLOITABLE)</td>")
+	assert.Contains(t, string(markdown), "<td>CWE-79</td>")
+	assert.Contains(t, string(markdown), "<td>src/App.java:12</td>")
+}
@@ -7,6 +7,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/pkg/errors"
 )
 
@@ -27,6 +28,8 @@ type ScanReport struct {
 	ReportTime     time.Time       `json:"reportTime"`
 	DetailTable    ScanDetailTable `json:"detailTable"`
 	SuccessfulScan bool            `json:"successfulScan"`
+	// Findings are the scanner independent findings which are consolidated across steps by pipelineCreateScanSummary
+	Findings []format.Finding `json:"findings,omitempty"`
 }
 
 // ScanDetailTable defines a table containing scan result details
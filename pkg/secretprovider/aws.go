@@ -0,0 +1,52 @@
+package secretprovider
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// awsEndpointEnvVar allows to use another endpoint than the one of the region, e.g. of LocalStack
+const awsEndpointEnvVar = "AWS_ENDPOINT_URL_SECRETS_MANAGER"
+
+// AWSSecretsManager reads secrets from AWS Secrets Manager.
+// The credentials and the region are determined via the default AWS configuration, e.g. AWS_REGION.
+type AWSSecretsManager struct {
+	client *secretsmanager.Client
+}
+
+// NewAWSSecretsManager creates a client for AWS Secrets Manager
+func NewAWSSecretsManager() (*AWSSecretsManager, error) {
+	cfg, err := awsConfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS configuration")
+	}
+	if len(cfg.Region) == 0 {
+		return nil, errors.New("no AWS region configured, please set AWS_REGION")
+	}
+	client := secretsmanager.NewFromConfig(cfg, func(options *secretsmanager.Options) {
+		if endpoint := os.Getenv(awsEndpointEnvVar); len(endpoint) > 0 {
+			options.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+	return &AWSSecretsManager{client: client}, nil
+}
+
+// GetSecret returns the fields of the current version of the secret with the given name or ARN
+func (a *AWSSecretsManager) GetSecret(secretID string) (map[string]string, error) {
+	value, err := a.client.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret '%v' from AWS Secrets Manager", secretID)
+	}
+	if value.SecretString != nil {
+		return parseSecret(secretID, []byte(*value.SecretString))
+	}
+	if len(value.SecretBinary) > 0 {
+		return parseSecret(secretID, value.SecretBinary)
+	}
+	return nil, errors.Errorf("secret '%v' has no value", secretID)
+}
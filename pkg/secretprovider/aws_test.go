@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package secretprovider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretsManager behaves like the GetSecretValue action of LocalStack
+func fakeSecretsManager(t *testing.T, secrets map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/"), r.Header.Get("Authorization"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-central-1/secretsmanager/aws4_request")
+		var input struct{ SecretId string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		value, ok := secrets[input.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"Name": input.SecretId, "SecretString": value})
+	}))
+}
+
+func setAWSEnvironment(t *testing.T, endpoint string) {
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_REGION", "eu-central-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secretKey")
+	t.Setenv(awsEndpointEnvVar, endpoint)
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	server := fakeSecretsManager(t, map[string]string{
+		"piper/github": `{"githubToken":"ghp_token","retries":3}`,
+		"piper/plain":  "plainValue",
+	})
+	defer server.Close()
+	setAWSEnvironment(t, server.URL)
+
+	provider, err := NewAWSSecretsManager()
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		secret, err := provider.GetSecret("piper/github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"githubToken": "ghp_token", "retries": "3"}, secret)
+	})
+
+	t.Run("unknown secret", func(t *testing.T) {
+		_, err := provider.GetSecret("piper/unknown")
+		assert.ErrorContains(t, err, "failed to get secret 'piper/unknown' from AWS Secrets Manager")
+		assert.ErrorContains(t, err, "ResourceNotFoundException")
+	})
+
+	t.Run("no JSON object", func(t *testing.T) {
+		_, err := provider.GetSecret("piper/plain")
+		assert.EqualError(t, err, "secret 'piper/plain' is not a JSON object")
+	})
+}
+
+func TestNewAWSSecretsManager(t *testing.T) {
+	setAWSEnvironment(t, "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	_, err := NewAWSSecretsManager()
+	assert.EqualError(t, err, "no AWS region configured, please set AWS_REGION")
+}
@@ -0,0 +1,55 @@
+package secretprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/pkg/errors"
+)
+
+// AzureKeyVault reads secrets from Azure Key Vault.
+// It authenticates via the default Azure credential chain, i.e. a service principal given via AZURE_TENANT_ID,
+// AZURE_CLIENT_ID and AZURE_CLIENT_SECRET, workload identity, managed identity or the Azure CLI.
+// AZURE_AUTHORITY_HOST allows to use another cloud than the public Azure cloud.
+type AzureKeyVault struct {
+	credential azcore.TokenCredential
+	options    *azsecrets.ClientOptions
+}
+
+// NewAzureKeyVault creates a client for Azure Key Vault
+func NewAzureKeyVault() (*AzureKeyVault, error) {
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Azure credentials")
+	}
+	return &AzureKeyVault{credential: credential}, nil
+}
+
+// GetSecret returns the fields of the secret at the address <vault>/<name>[/<version>]. The vault is either the name of
+// a key vault in the public Azure cloud or the host of the key vault, e.g. my-vault.vault.azure.cn.
+func (k *AzureKeyVault) GetSecret(address string) (map[string]string, error) {
+	vault, name, found := strings.Cut(address, "/")
+	if !found || len(vault) == 0 || len(name) == 0 {
+		return nil, errors.Errorf("invalid Azure Key Vault secret '%v', expected format <vault>/<name>", address)
+	}
+	if !strings.Contains(vault, ".") {
+		vault += ".vault.azure.net"
+	}
+	name, version, _ := strings.Cut(name, "/")
+
+	client, err := azsecrets.NewClient("https://"+vault, k.credential, k.options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create Azure Key Vault client for '%v'", vault)
+	}
+	secret, err := client.GetSecret(context.Background(), name, version, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret '%v' from Azure Key Vault", address)
+	}
+	if secret.Value == nil {
+		return nil, errors.Errorf("secret '%v' has no value", address)
+	}
+	return parseSecret(address, []byte(*secret.Value))
+}
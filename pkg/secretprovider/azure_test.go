@@ -0,0 +1,90 @@
+//go:build unit
+// +build unit
+
+package secretprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticCredential struct {
+	scopes []string
+}
+
+func (c *staticCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.scopes = options.Scopes
+	return azcore.AccessToken{Token: "azureAccessToken"}, nil
+}
+
+func TestAzureKeyVault(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Key Vault answers requests without token with the authentication challenge
+		if len(r.Header.Get("Authorization")) == 0 {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tenant", resource="https://vault.azure.net"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "Bearer azureAccessToken", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/secrets/github/", "/secrets/github/v2":
+			json.NewEncoder(w).Encode(map[string]string{"value": `{"githubToken":"ghp_token"}`})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"SecretNotFound","message":"A secret with (name/id) unknown was not found in this key vault."}}`))
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	credential := &staticCredential{}
+	provider := &AzureKeyVault{credential: credential, options: &azsecrets.ClientOptions{
+		ClientOptions:                        azcore.ClientOptions{Transport: server.Client(), Retry: policy.RetryOptions{MaxRetries: -1}},
+		DisableChallengeResourceVerification: true,
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		secret, err := provider.GetSecret(host + "/github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"githubToken": "ghp_token"}, secret)
+		assert.Equal(t, []string{"https://vault.azure.net/.default"}, credential.scopes)
+	})
+
+	t.Run("specific version", func(t *testing.T) {
+		secret, err := provider.GetSecret(host + "/github/v2")
+		assert.NoError(t, err)
+		assert.Equal(t, "ghp_token", secret["githubToken"])
+	})
+
+	t.Run("unknown secret", func(t *testing.T) {
+		_, err := provider.GetSecret(host + "/unknown")
+		assert.ErrorContains(t, err, "failed to get secret '"+host+"/unknown' from Azure Key Vault")
+		assert.ErrorContains(t, err, "SecretNotFound")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := provider.GetSecret("github")
+		assert.EqualError(t, err, "invalid Azure Key Vault secret 'github', expected format <vault>/<name>")
+	})
+}
+
+func TestNewAzureKeyVault(t *testing.T) {
+	// the credential chain is evaluated when the first token is requested, e.g. to support workload identity
+	t.Setenv("AZURE_TENANT_ID", "")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_CLIENT_SECRET", "")
+
+	provider, err := NewAzureKeyVault()
+	require.NoError(t, err)
+	assert.NotNil(t, provider.credential)
+}
@@ -0,0 +1,56 @@
+package secretprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	"google.golang.org/api/secretmanager/v1"
+)
+
+// GCPSecretManager reads secrets from Google Cloud Secret Manager.
+// The credentials are determined via the application default credentials, e.g. GOOGLE_APPLICATION_CREDENTIALS.
+type GCPSecretManager struct {
+	service *secretmanager.Service
+}
+
+// NewGCPSecretManager creates a client for Google Cloud Secret Manager
+func NewGCPSecretManager() (*GCPSecretManager, error) {
+	return newGCPSecretManager()
+}
+
+func newGCPSecretManager(options ...option.ClientOption) (*GCPSecretManager, error) {
+	service, err := secretmanager.NewService(context.Background(), options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Google Cloud Secret Manager client")
+	}
+	return &GCPSecretManager{service: service}, nil
+}
+
+// GetSecret returns the fields of the secret at the address <project>/<name>[@<version>], the latest version is used by default
+func (g *GCPSecretManager) GetSecret(address string) (map[string]string, error) {
+	project, name, found := strings.Cut(address, "/")
+	if !found || len(project) == 0 || len(name) == 0 {
+		return nil, errors.Errorf("invalid Google Cloud secret '%v', expected format <project>/<name>", address)
+	}
+	version := "latest"
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name, version = name[:i], name[i+1:]
+	}
+
+	secret, err := g.service.Projects.Secrets.Versions.Access(fmt.Sprintf("projects/%v/secrets/%v/versions/%v", project, name, version)).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get secret '%v' from Google Cloud Secret Manager", address)
+	}
+	if secret.Payload == nil {
+		return nil, errors.Errorf("secret '%v' has no value", address)
+	}
+	data, err := base64.StdEncoding.DecodeString(secret.Payload.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret '%v'", address)
+	}
+	return parseSecret(address, data)
+}
@@ -0,0 +1,60 @@
+//go:build unit
+// +build unit
+
+package secretprovider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+func TestGCPSecretManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gcpAccessToken", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/github/versions/latest:access", "/v1/projects/my-project/secrets/github/versions/2:access":
+			fmt.Fprintf(w, `{"name":"%v","payload":{"data":"%v"}}`, r.URL.Path, base64.StdEncoding.EncodeToString([]byte(`{"githubToken":"ghp_token"}`)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"Secret [projects/my-project/secrets/unknown] not found.","status":"NOT_FOUND"}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newGCPSecretManager(
+		option.WithEndpoint(server.URL+"/"),
+		option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcpAccessToken"})),
+	)
+	require.NoError(t, err)
+
+	t.Run("latest version", func(t *testing.T) {
+		secret, err := provider.GetSecret("my-project/github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"githubToken": "ghp_token"}, secret)
+	})
+
+	t.Run("specific version", func(t *testing.T) {
+		secret, err := provider.GetSecret("my-project/github@2")
+		assert.NoError(t, err)
+		assert.Equal(t, "ghp_token", secret["githubToken"])
+	})
+
+	t.Run("unknown secret", func(t *testing.T) {
+		_, err := provider.GetSecret("my-project/unknown")
+		assert.ErrorContains(t, err, "failed to get secret 'my-project/unknown' from Google Cloud Secret Manager")
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := provider.GetSecret("github")
+		assert.EqualError(t, err, "invalid Google Cloud secret 'github', expected format <project>/<name>")
+	})
+}
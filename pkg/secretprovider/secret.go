@@ -0,0 +1,35 @@
+// Package secretprovider reads the secrets of parameter references from secret stores other than Vault.
+package secretprovider
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// parseSecret returns the fields of a secret, the value of the secret needs to be a JSON object like a Vault secret
+func parseSecret(name string, value []byte) (map[string]string, error) {
+	var fields map[string]interface{}
+	// the error is not returned since it might contain parts of the secret
+	if err := json.Unmarshal(value, &fields); err != nil || fields == nil {
+		return nil, errors.Errorf("secret '%v' is not a JSON object", name)
+	}
+	return toStringMap(fields), nil
+}
+
+func toStringMap(fields map[string]interface{}) map[string]string {
+	result := map[string]string{}
+	for key, field := range fields {
+		switch value := field.(type) {
+		case nil:
+		case string:
+			result[key] = value
+		default:
+			content, err := json.Marshal(value)
+			if err == nil {
+				result[key] = string(content)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package secretprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// SOPS reads secrets from files encrypted with sops, e.g. using age keys.
+// The keys are provided to sops via its environment variables, e.g. SOPS_AGE_KEY_FILE.
+type SOPS struct {
+	runner command.ExecRunner
+	files  map[string]map[string]interface{}
+}
+
+// NewSOPS creates a provider which decrypts files via the sops executable
+func NewSOPS(runner command.ExecRunner) *SOPS {
+	return &SOPS{runner: runner, files: map[string]map[string]interface{}{}}
+}
+
+// GetSecret returns the fields of the secret at the address <file>#<key>, the key is the top-level key
+// of the secret in the file. Without key the top-level keys of the file are the fields of the secret.
+func (s *SOPS) GetSecret(address string) (map[string]string, error) {
+	file, key, _ := strings.Cut(address, "#")
+	content, err := s.decrypt(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return toStringMap(content), nil
+	}
+	secret, ok := content[key].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("file '%v' does not contain a secret '%v' with fields", file, key)
+	}
+	return toStringMap(secret), nil
+}
+
+// decrypt returns the decrypted content of the file, files are decrypted once since usually all secrets are in one file
+func (s *SOPS) decrypt(file string) (map[string]interface{}, error) {
+	if content, ok := s.files[file]; ok {
+		return content, nil
+	}
+	var stdout bytes.Buffer
+	s.runner.Stdout(&stdout)
+	defer s.runner.Stdout(log.Writer())
+	if err := s.runner.RunExecutable("sops", "--decrypt", "--output-type", "json", file); err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt '%v' with sops", file)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &content); err != nil || content == nil {
+		return nil, errors.Errorf("decrypted file '%v' is not a JSON object", file)
+	}
+	s.files[file] = content
+	return content, nil
+}
@@ -0,0 +1,54 @@
+//go:build unit
+// +build unit
+
+package secretprovider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOPS(t *testing.T) {
+	t.Parallel()
+	decrypt := "sops --decrypt --output-type json .pipeline/secrets.enc.yaml"
+
+	t.Run("secret of key", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{decrypt: `{"github":{"githubToken":"ghp_token"},"sonar":{"token":"sonarToken"}}`}}
+		provider := NewSOPS(runner)
+
+		secret, err := provider.GetSecret(".pipeline/secrets.enc.yaml#github")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"githubToken": "ghp_token"}, secret)
+
+		secret, err = provider.GetSecret(".pipeline/secrets.enc.yaml#sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "sonarToken"}, secret)
+		// the file is only decrypted once
+		assert.Len(t, runner.Calls, 1)
+	})
+
+	t.Run("whole file", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{decrypt: `{"githubToken":"ghp_token","sops_version":1}`}}
+
+		secret, err := NewSOPS(runner).GetSecret(".pipeline/secrets.enc.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, "ghp_token", secret["githubToken"])
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{StdoutReturn: map[string]string{decrypt: `{"github":"ghp_token"}`}}
+
+		_, err := NewSOPS(runner).GetSecret(".pipeline/secrets.enc.yaml#github")
+		assert.EqualError(t, err, "file '.pipeline/secrets.enc.yaml' does not contain a secret 'github' with fields")
+	})
+
+	t.Run("decryption fails", func(t *testing.T) {
+		runner := &mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{decrypt: errors.New("no key could decrypt the data key")}}
+
+		_, err := NewSOPS(runner).GetSecret(".pipeline/secrets.enc.yaml#github")
+		assert.ErrorContains(t, err, "failed to decrypt '.pipeline/secrets.enc.yaml' with sops")
+	})
+}
@@ -0,0 +1,41 @@
+package sonar
+
+import (
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	sonargo "github.com/magicsong/sonargo/sonar"
+)
+
+// ToFindings converts issues into scanner independent findings
+func ToFindings(issues []*sonargo.Issue) []format.Finding {
+	findings := []format.Finding{}
+	for _, issue := range issues {
+		if issue == nil {
+			continue
+		}
+		finding := format.Finding{
+			Tool:       "SonarQube",
+			Rule:       issue.Rule,
+			Severity:   format.NormalizeSeverity(issue.Severity),
+			Status:     format.FindingStatusOpen,
+			AuditState: issue.Status,
+			Message:    issue.Message,
+		}
+		// the component is the key of the project followed by the path of the file
+		if _, file, found := strings.Cut(issue.Component, ":"); found {
+			finding.Location = &format.FindingLocation{File: file, Line: issue.Line}
+		}
+		switch issue.Resolution {
+		case "FIXED", "REMOVED":
+			finding.Status = format.FindingStatusFixed
+		case "FALSE-POSITIVE", "WONTFIX":
+			finding.Status = format.FindingStatusSuppressed
+		}
+		if len(issue.Resolution) > 0 {
+			finding.AuditState = issue.Resolution
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,38 @@
+//go:build unit
+// +build unit
+
+package sonar
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	issues := []*sonargo.Issue{
+		{Rule: "java:S2076", Severity: "CRITICAL", Status: "OPEN", Component: "piper:src/main/App.java", Line: 12, Message: "Change this code"},
+		{Rule: "java:S1234", Severity: "MINOR", Status: "RESOLVED", Resolution: "FALSE-POSITIVE", Component: "piper"},
+		nil,
+	}
+
+	findings := ToFindings(issues)
+
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, format.Finding{
+			Tool:       "SonarQube",
+			Rule:       "java:S2076",
+			Location:   &format.FindingLocation{File: "src/main/App.java", Line: 12},
+			Severity:   format.SeverityCritical,
+			Status:     format.FindingStatusOpen,
+			AuditState: "OPEN",
+			Message:    "Change this code",
+		}, findings[0])
+		assert.Nil(t, findings[1].Location)
+		assert.Equal(t, format.SeverityLow, findings[1].Severity)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[1].Status)
+		assert.Equal(t, "FALSE-POSITIVE", findings[1].AuditState)
+	}
+}
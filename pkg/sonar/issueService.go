@@ -2,6 +2,7 @@ package sonar
 
 import (
 	"net/http"
+	"strconv"
 
 	sonargo "github.com/magicsong/sonargo/sonar"
 	"github.com/pkg/errors"
@@ -10,6 +11,11 @@ import (
 // EndpointIssuesSearch API endpoint for https://sonarcloud.io/web_api/api/issues/search
 const EndpointIssuesSearch = "issues/search"
 
+const (
+	issuesPageSize = 500
+	maxIssues      = 10000
+)
+
 // IssueService ...
 type IssueService struct {
 	Organization string
@@ -45,11 +51,20 @@ func (service *IssueService) SearchIssues(options *IssuesSearchOption) (*sonargo
 }
 
 func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+	options := service.unresolvedIssuesOptions()
+	options.Severities = severity.ToString()
+	options.Ps = "1"
+	result, _, err := service.SearchIssues(options)
+	if err != nil {
+		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
+	}
+	return result.Total, nil
+}
+
+func (service *IssueService) unresolvedIssuesOptions() *IssuesSearchOption {
 	options := &IssuesSearchOption{
 		ComponentKeys: service.Project,
-		Severities:    severity.ToString(),
 		Resolved:      "false",
-		Ps:            "1",
 	}
 	if len(service.Organization) > 0 {
 		options.Organization = service.Organization
@@ -60,11 +75,26 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	} else if len(service.Branch) > 0 {
 		options.Branch = service.Branch
 	}
-	result, _, err := service.SearchIssues(options)
-	if err != nil {
-		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
+	return options
+}
+
+// GetIssues returns the unresolved issues, SonarQube returns at most the first 10000 issues.
+func (service *IssueService) GetIssues() ([]*sonargo.Issue, error) {
+	issues := []*sonargo.Issue{}
+	for page := 1; page*issuesPageSize <= maxIssues; page++ {
+		options := service.unresolvedIssuesOptions()
+		options.P = strconv.Itoa(page)
+		options.Ps = strconv.Itoa(issuesPageSize)
+		result, _, err := service.SearchIssues(options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch page %v of the issues", page)
+		}
+		issues = append(issues, result.Issues...)
+		if len(result.Issues) < issuesPageSize || len(issues) >= result.Total {
+			break
+		}
 	}
-	return result.Total, nil
+	return issues, nil
 }
 
 // GetNumberOfBlockerIssues returns the number of issue with BLOCKER severity.
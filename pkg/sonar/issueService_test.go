@@ -5,6 +5,7 @@ package sonar
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -73,6 +74,53 @@ func TestIssueService(t *testing.T) {
 	})
 }
 
+func TestIssueServiceGetIssues(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		var query url.Values
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			return httpmock.NewStringResponse(http.StatusOK, `{"total": 2, "issues": [{"key": "AXERR2JBbm9IiM5TEST", "rule": "java:S2076", "severity": "CRITICAL"}, {"key": "AXERR2JBbm9IiM5TES2", "rule": "java:S1234", "severity": "MINOR"}]}`), nil
+		})
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "piper", mock.Anything, mock.Anything, "", sender)
+		// test
+		issues, err := serviceUnderTest.GetIssues()
+		// assert
+		assert.NoError(t, err)
+		if assert.Len(t, issues, 2) {
+			assert.Equal(t, "java:S2076", issues[0].Rule)
+		}
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "unexpected number of requests")
+		assert.Equal(t, "piper", query.Get("componentKeys"))
+		assert.Equal(t, "false", query.Get("resolved"))
+		assert.Equal(t, "1", query.Get("p"))
+		assert.Equal(t, "500", query.Get("ps"))
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusNotFound, responseIssueSearchError))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		issues, err := serviceUnderTest.GetIssues()
+		// assert
+		assert.ErrorContains(t, err, "failed to fetch page 1 of the issues")
+		assert.Nil(t, issues)
+	})
+}
+
 const responseIssueSearchError = `{
   "errors": [
     {
@@ -1,9 +1,17 @@
 package sonar
 
 import (
+	"crypto/sha1"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/pkg/errors"
 )
 
 const reportFileName = "sonarscan.json"
@@ -38,3 +46,40 @@ func WriteReport(data ReportData, reportPath string, writeToFile func(f string,
 	}
 	return writeToFile(filepath.Join(reportPath, reportFileName), jsonData, 0644)
 }
+
+// CreateCustomReport creates the report of a scan which is consolidated by step pipelineCreateScanSummary
+func CreateCustomReport(data ReportData, issues []*sonargo.Issue) reporting.ScanReport {
+	return reporting.ScanReport{
+		ReportTitle: "SonarQube Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "SonarQube project key", Details: data.ProjectKey},
+			{Description: "SonarQube URL", Details: data.ServerURL},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Number of blocker issues", Details: fmt.Sprint(data.NumberOfIssues.Blocker)},
+			{Description: "Number of critical issues", Details: fmt.Sprint(data.NumberOfIssues.Critical)},
+			{Description: "Number of major issues", Details: fmt.Sprint(data.NumberOfIssues.Major)},
+			{Description: "Number of minor issues", Details: fmt.Sprint(data.NumberOfIssues.Minor)},
+			{Description: "Number of info issues", Details: fmt.Sprint(data.NumberOfIssues.Info)},
+		},
+		Findings:       ToFindings(issues),
+		SuccessfulScan: data.NumberOfIssues.Blocker == 0 && data.NumberOfIssues.Critical == 0,
+		ReportTime:     time.Now(),
+	}
+}
+
+// WriteCustomReports writes the JSON report which is used by step pipelineCreateScanSummary
+func WriteCustomReports(scanReport reporting.ScanReport, projectKey string, utils piperutils.FileUtils) error {
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(reporting.StepReportDirectory); !exists {
+		if err := utils.MkdirAll(reporting.StepReportDirectory, 0777); err != nil {
+			return errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	reportSha := fmt.Sprintf("%x", sha1.Sum([]byte(projectKey)))
+	if err := utils.FileWrite(filepath.Join(reporting.StepReportDirectory, fmt.Sprintf("sonarExecuteScan_%v.json", reportSha)), jsonReport, 0666); err != nil {
+		return errors.Wrap(err, "failed to write json report")
+	}
+	return nil
+}
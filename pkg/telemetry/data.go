@@ -48,6 +48,12 @@ type CustomData struct {
 	BuildVersionCreation  string `json:"buildVersionCreation,omitempty"`
 	PullRequestMode       string `json:"pullRequestMode,omitempty"`
 	GroovyTemplateUsed    string `json:"groovyTemplateUsed,omitempty"`
+	CommandExecutions     string `json:"commandExecutions,omitempty"`
+	CommandRetries        string `json:"commandRetries,omitempty"`
+	CommandTimeouts       string `json:"commandTimeouts,omitempty"`
+	CommandDuration       string `json:"commandDuration,omitempty"` // wall time of all command executions in milliseconds
+	CommandCPUTime        string `json:"commandCpuTime,omitempty"`  // cpu time of all command executions in milliseconds
+	CommandMaxRSS         string `json:"commandMaxRss,omitempty"`   // maximum resident set size of any command execution in bytes
 }
 
 // StepTelemetryData definition for telemetry reporting and monitoring
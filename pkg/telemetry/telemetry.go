@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
@@ -89,12 +90,20 @@ func (t *Telemetry) toSha1OrNA(input string) string {
 	return fmt.Sprintf("%x", sha1.Sum([]byte(input)))
 }
 
-// SetData sets the custom telemetry and base data
+// SetData sets the custom telemetry and base data, the resource usage of the executed commands is added
 func (t *Telemetry) SetData(customData *CustomData) {
 	t.data = Data{
 		BaseData:   t.baseData,
 		CustomData: *customData,
 	}
+	if usage := command.TotalUsage(); usage.Executions > 0 {
+		t.data.CommandExecutions = strconv.Itoa(usage.Executions)
+		t.data.CommandRetries = strconv.Itoa(usage.Retries)
+		t.data.CommandTimeouts = strconv.Itoa(usage.Timeouts)
+		t.data.CommandDuration = strconv.FormatInt(usage.WallTime.Milliseconds(), 10)
+		t.data.CommandCPUTime = strconv.FormatInt(usage.CPUTime.Milliseconds(), 10)
+		t.data.CommandMaxRSS = strconv.FormatInt(usage.MaxRSS, 10)
+	}
 }
 
 // GetData returns telemetryData
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/xsuaa"
@@ -32,6 +33,10 @@ func NewCommunicationInstance(httpClient piperHttp.Uploader, tmsUrl, uaaUrl, cli
 		isVerbose:    isVerbose,
 	}
 
+	if dryrun.Enabled() {
+		setDryRunResponses(tmsUrl, uaaUrl)
+	}
+
 	token, err := communicationInstance.getOAuthToken()
 	if err != nil {
 		return communicationInstance, errors.Wrap(err, "Error fetching OAuth token")
@@ -43,6 +48,20 @@ func NewCommunicationInstance(httpClient piperHttp.Uploader, tmsUrl, uaaUrl, cli
 	return communicationInstance, nil
 }
 
+// setDryRunResponses provides the responses of the requests which are not sent in dry run mode, the OAuth token is
+// requested since the subsequent read-only requests require it
+func setDryRunResponses(tmsUrl, uaaUrl string) {
+	tmsUrl = strings.TrimSuffix(tmsUrl, "/")
+	dryrun.SendRequest(http.MethodPost, strings.TrimSuffix(uaaUrl, "/")+"/oauth/token")
+	dryrun.SetResponse(http.MethodPost, tmsUrl+"/v2/files/upload", dryrun.Response{StatusCode: http.StatusCreated, Body: `{"fileId":0,"fileName":"dry-run"}`})
+	// upload of MTA extension descriptors
+	dryrun.SetResponse(http.MethodPost, tmsUrl+"/v2/nodes/", dryrun.Response{StatusCode: http.StatusCreated, Body: `{}`})
+	dryrun.SetResponse(http.MethodPut, tmsUrl+"/v2/nodes/", dryrun.Response{StatusCode: http.StatusOK, Body: `{}`})
+	nodeResponse := dryrun.Response{StatusCode: http.StatusOK, Body: `{"transportRequestDescription":"dry-run","queueEntries":[{"nodeName":"dry-run"}]}`}
+	dryrun.SetResponse(http.MethodPost, tmsUrl+"/v2/nodes/upload", nodeResponse)
+	dryrun.SetResponse(http.MethodPost, tmsUrl+"/v2/nodes/export", nodeResponse)
+}
+
 func (communicationInstance *CommunicationInstance) getOAuthToken() (string, error) {
 	if communicationInstance.isVerbose {
 		communicationInstance.logger.Info("OAuth token retrieval started")
@@ -9,15 +9,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/dryrun"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type uploaderMock struct {
@@ -621,3 +625,26 @@ func TestNewCommunicationInstance(t *testing.T) {
 	})
 
 }
+
+func TestDryRun(t *testing.T) {
+	dryrun.Enable()
+	t.Cleanup(dryrun.Reset)
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"token_type":"bearer","access_token":"testOAuthToken","expires_in":54321}`))
+	}))
+	defer server.Close()
+	file := filepath.Join(t.TempDir(), "app.mtar")
+	require.NoError(t, os.WriteFile(file, []byte("mtar"), 0644))
+
+	communicationInstance, err := NewCommunicationInstance(&piperHttp.Client{}, server.URL+"/tms", server.URL+"/uaa", "testClientId", "testClientSecret", false, piperHttp.ClientOptions{})
+	require.NoError(t, err)
+	fileInfo, err := communicationInstance.UploadFile(file, "testUser")
+	require.NoError(t, err)
+	_, err = communicationInstance.UploadFileToNode(fileInfo, "QA", "description", "testUser")
+	require.NoError(t, err)
+
+	assert.Equal(t, FileInfo{Name: "dry-run"}, fileInfo)
+	assert.Equal(t, []string{"POST /uaa/oauth/token/"}, requests)
+}
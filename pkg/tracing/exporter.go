@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newExporters creates the exporters of traces and metrics for the protocol of the configuration
+func newExporters(config Config) (sdktrace.SpanExporter, sdkmetric.Exporter, error) {
+	ctx := context.Background()
+	switch config.Protocol {
+	case "", ProtocolHTTP:
+		endpoint, err := url.Parse(config.Endpoint)
+		if err != nil || len(endpoint.Host) == 0 {
+			return nil, nil, errors.Errorf("invalid OTLP endpoint '%v', expected URL like http://localhost:4318", config.Endpoint)
+		}
+		base := strings.TrimSuffix(config.Endpoint, "/")
+		spanExporter, err := otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(base+"/v1/traces"),
+			otlptracehttp.WithHeaders(config.Headers),
+			otlptracehttp.WithTimeout(exportTimeout),
+		)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create OTLP/HTTP trace exporter")
+		}
+		metricExporter, err := otlpmetrichttp.New(ctx,
+			otlpmetrichttp.WithEndpointURL(base+"/v1/metrics"),
+			otlpmetrichttp.WithHeaders(config.Headers),
+			otlpmetrichttp.WithTimeout(exportTimeout),
+		)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create OTLP/HTTP metric exporter")
+		}
+		return spanExporter, metricExporter, nil
+	case ProtocolGRPC:
+		traceOptions := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(config.Headers), otlptracegrpc.WithTimeout(exportTimeout)}
+		metricOptions := []otlpmetricgrpc.Option{otlpmetricgrpc.WithHeaders(config.Headers), otlpmetricgrpc.WithTimeout(exportTimeout)}
+		if endpoint, err := url.Parse(config.Endpoint); err == nil && len(endpoint.Host) > 0 {
+			// endpoints with scheme define whether TLS is used
+			traceOptions = append(traceOptions, otlptracegrpc.WithEndpointURL(config.Endpoint))
+			metricOptions = append(metricOptions, otlpmetricgrpc.WithEndpointURL(config.Endpoint))
+		} else {
+			traceOptions = append(traceOptions, otlptracegrpc.WithEndpoint(config.Endpoint))
+			metricOptions = append(metricOptions, otlpmetricgrpc.WithEndpoint(config.Endpoint))
+			if config.Insecure {
+				traceOptions = append(traceOptions, otlptracegrpc.WithInsecure())
+				metricOptions = append(metricOptions, otlpmetricgrpc.WithInsecure())
+			}
+		}
+		spanExporter, err := otlptracegrpc.New(ctx, traceOptions...)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid OTLP endpoint '%v'", config.Endpoint)
+		}
+		metricExporter, err := otlpmetricgrpc.New(ctx, metricOptions...)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid OTLP endpoint '%v'", config.Endpoint)
+		}
+		return spanExporter, metricExporter, nil
+	}
+	return nil, nil, errors.Errorf("unsupported OTLP protocol '%v', supported protocols: %v, %v", config.Protocol, ProtocolGRPC, ProtocolHTTP)
+}
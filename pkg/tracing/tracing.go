@@ -0,0 +1,381 @@
+// Package tracing exports traces and metrics of a step execution via the OpenTelemetry protocol (OTLP).
+//
+// Each step creates a span, commands executed via pkg/command and requests sent via pkg/http create
+// child spans of the step span. The trace ID is derived from the correlation ID of the pipeline run,
+// so all steps of a pipeline run end up in the same trace.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// ProtocolGRPC exports via OTLP/gRPC
+	ProtocolGRPC = "grpc"
+	// ProtocolHTTP exports via OTLP/HTTP using protobuf encoding
+	ProtocolHTTP = "http/protobuf"
+
+	exportTimeout = 10 * time.Second
+
+	scopeName = "github.com/SAP/jenkins-library/pkg/tracing"
+)
+
+// SpanKind defines the relationship of a span to its callers, see https://opentelemetry.io/docs/specs/otel/trace/api/#spankind
+type SpanKind = trace.SpanKind
+
+// Span kinds as defined by OTLP
+const (
+	SpanKindInternal = trace.SpanKindInternal
+	SpanKindClient   = trace.SpanKindClient
+)
+
+// stepDurationBounds are the bucket boundaries of the step duration histogram in seconds
+var stepDurationBounds = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+// Config contains the settings of the OTLP export
+type Config struct {
+	// Endpoint of the OTLP receiver, e.g. http://localhost:4318 for OTLP/HTTP or localhost:4317 for OTLP/gRPC
+	Endpoint string
+	Protocol string
+	// Headers are sent with each export request, e.g. for authentication
+	Headers map[string]string
+	// Insecure disables TLS for OTLP/gRPC endpoints without scheme
+	Insecure       bool
+	ServiceName    string
+	ServiceVersion string
+}
+
+// ConfigFromEnv reads the configuration from the environment variables defined by the OpenTelemetry specification
+// (OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_PROTOCOL, OTEL_EXPORTER_OTLP_HEADERS, OTEL_EXPORTER_OTLP_INSECURE, OTEL_SERVICE_NAME).
+func ConfigFromEnv() Config {
+	config := Config{
+		Endpoint:    os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		Protocol:    os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"),
+		Headers:     map[string]string{},
+		Insecure:    strings.EqualFold(os.Getenv("OTEL_EXPORTER_OTLP_INSECURE"), "true"),
+		ServiceName: os.Getenv("OTEL_SERVICE_NAME"),
+	}
+	for _, header := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if key, value, ok := strings.Cut(header, "="); ok {
+			config.Headers[strings.TrimSpace(key)] = strings.TrimSpace(unescape(value))
+		}
+	}
+	return config
+}
+
+// Attribute is a key value pair describing a span or a metric data point
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr creates an attribute, supported values are strings, booleans, integers, floats and string slices
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func keyValues(attributes []Attribute) []attribute.KeyValue {
+	keyValues := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch value := a.Value.(type) {
+		case string:
+			keyValues = append(keyValues, attribute.String(a.Key, value))
+		case bool:
+			keyValues = append(keyValues, attribute.Bool(a.Key, value))
+		case int:
+			keyValues = append(keyValues, attribute.Int(a.Key, value))
+		case int64:
+			keyValues = append(keyValues, attribute.Int64(a.Key, value))
+		case float64:
+			keyValues = append(keyValues, attribute.Float64(a.Key, value))
+		case []string:
+			keyValues = append(keyValues, attribute.StringSlice(a.Key, value))
+		default:
+			keyValues = append(keyValues, attribute.String(a.Key, fmt.Sprint(value)))
+		}
+	}
+	return keyValues
+}
+
+// Span represents a single operation within a trace. All methods can be called on a nil span.
+type Span struct {
+	span trace.Span
+	kind SpanKind
+}
+
+type tracer struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	tracer         trace.Tracer
+	stepContext    context.Context
+	stepSpan       *Span
+	start          time.Time
+	stepName       string
+	stageName      string
+}
+
+var (
+	mu      sync.Mutex
+	current *tracer
+)
+
+// Start sets up the export and starts the span of the step. Without endpoint nothing is exported.
+func Start(config Config, stepName, stageName, correlationID string) error {
+	if len(config.Endpoint) == 0 {
+		return nil
+	}
+	spanExporter, metricExporter, err := newExporters(config)
+	if err != nil {
+		return err
+	}
+	if len(config.ServiceName) == 0 {
+		config.ServiceName = "piper"
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+		attribute.String("service.version", config.ServiceVersion),
+	))
+	if err != nil {
+		return errors.Wrap(err, "failed to create the OpenTelemetry resource")
+	}
+
+	traceID, parentID := traceContext(correlationID)
+	t := &tracer{
+		tracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(spanExporter),
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithIDGenerator(&idGenerator{traceID: traceID}),
+		),
+		meterProvider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+			sdkmetric.WithResource(res),
+		),
+		start:     time.Now(),
+		stepName:  stepName,
+		stageName: stageName,
+	}
+	t.tracer = t.tracerProvider.Tracer(scopeName)
+
+	ctx := context.Background()
+	if parentID.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID, SpanID: parentID, TraceFlags: trace.FlagsSampled, Remote: true,
+		}))
+	}
+	var span trace.Span
+	t.stepContext, span = t.tracer.Start(ctx, stepName,
+		trace.WithSpanKind(SpanKindInternal),
+		trace.WithTimestamp(t.start),
+		trace.WithAttributes(keyValues([]Attribute{
+			Attr("piper.step.name", stepName),
+			Attr("piper.stage.name", stageName),
+			Attr("piper.correlation_id", correlationID),
+		})...),
+	)
+	t.stepSpan = &Span{span: span, kind: SpanKindInternal}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = t
+	log.Entry().Debugf("Exporting traces to %v via %v (trace ID %v)", config.Endpoint, config.Protocol, traceID)
+	return nil
+}
+
+// Enabled returns true if traces are exported
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return current != nil
+}
+
+// StartSpan starts a child span of the step span, it returns nil if traces are not exported.
+func StartSpan(name string, kind SpanKind, attributes ...Attribute) *Span {
+	mu.Lock()
+	t := current
+	mu.Unlock()
+	if t == nil {
+		return nil
+	}
+	_, span := t.tracer.Start(t.stepContext, name, trace.WithSpanKind(kind), trace.WithAttributes(keyValues(attributes)...))
+	return &Span{span: span, kind: kind}
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(keyValues(attributes)...)
+}
+
+// End ends the span, a non-nil error marks the span as failed
+func (s *Span) End(err error) {
+	if s == nil || !s.span.IsRecording() {
+		return
+	}
+	if err != nil {
+		s.span.SetStatus(codes.Error, log.MaskSecrets(err.Error()))
+	} else if s.kind == SpanKindInternal {
+		s.span.SetStatus(codes.Ok, "")
+	}
+	s.span.End()
+}
+
+// Finish ends the step span and exports all spans as well as the metrics of the step.
+func Finish(failed bool, errorCategory string) {
+	mu.Lock()
+	t := current
+	current = nil
+	mu.Unlock()
+	if t == nil {
+		return
+	}
+
+	result := "success"
+	var err error
+	if failed {
+		result = "failure"
+		err = errors.Errorf("step failed with error category '%v'", errorCategory)
+	}
+	t.stepSpan.SetAttributes(Attr("piper.result", result), Attr("piper.error.category", errorCategory))
+	t.stepSpan.End(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	if err := t.tracerProvider.Shutdown(ctx); err != nil {
+		log.Entry().WithError(err).Warn("failed to export traces")
+	}
+
+	if err := t.recordStepMetrics(ctx, result, errorCategory); err != nil {
+		log.Entry().WithError(err).Warn("failed to record metrics")
+	}
+	if err := t.meterProvider.Shutdown(ctx); err != nil {
+		log.Entry().WithError(err).Warn("failed to export metrics")
+	}
+}
+
+// recordStepMetrics records the duration and the execution of the step
+func (t *tracer) recordStepMetrics(ctx context.Context, result, errorCategory string) error {
+	meter := t.meterProvider.Meter(scopeName)
+	duration, err := meter.Float64Histogram("piper.step.duration",
+		metric.WithDescription("Duration of the step execution"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(stepDurationBounds...),
+	)
+	if err != nil {
+		return err
+	}
+	executions, err := meter.Int64Counter("piper.step.executions", metric.WithDescription("Number of step executions"))
+	if err != nil {
+		return err
+	}
+	attributes := metric.WithAttributes(keyValues([]Attribute{
+		Attr("piper.step.name", t.stepName),
+		Attr("piper.stage.name", t.stageName),
+		Attr("piper.result", result),
+		Attr("piper.error.category", errorCategory),
+	})...)
+	duration.Record(ctx, time.Since(t.start).Seconds(), attributes)
+	executions.Add(ctx, 1, attributes)
+	return nil
+}
+
+// Transport returns an http.RoundTripper which creates a span for each request
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := StartSpan("HTTP "+req.Method, SpanKindClient,
+		Attr("http.request.method", req.Method),
+		Attr("url.full", log.MaskSecrets(req.URL.Redacted())),
+		Attr("server.address", req.URL.Hostname()),
+	)
+	resp, err := t.next.RoundTrip(req)
+	spanErr := err
+	if err == nil {
+		span.SetAttributes(Attr("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			spanErr = errors.Errorf("HTTP status %v", resp.Status)
+		}
+	}
+	span.End(spanErr)
+	return resp, err
+}
+
+// idGenerator uses the trace ID of the pipeline run for all spans without parent
+type idGenerator struct {
+	traceID trace.TraceID
+}
+
+func (g *idGenerator) NewIDs(context.Context) (trace.TraceID, trace.SpanID) {
+	return g.traceID, newSpanID()
+}
+
+func (g *idGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return newSpanID()
+}
+
+// traceContext returns the trace ID and the parent span ID. A W3C trace context provided via TRACEPARENT
+// takes precedence, otherwise the trace ID is derived from the correlation ID.
+func traceContext(correlationID string) (traceID trace.TraceID, parentID trace.SpanID) {
+	if parts := strings.Split(os.Getenv("TRACEPARENT"), "-"); len(parts) == 4 {
+		trace, traceErr := hex.DecodeString(parts[1])
+		parent, parentErr := hex.DecodeString(parts[2])
+		if traceErr == nil && parentErr == nil && len(trace) == 16 && len(parent) == 8 {
+			copy(traceID[:], trace)
+			copy(parentID[:], parent)
+			return
+		}
+	}
+	if len(correlationID) == 0 {
+		rand.Read(traceID[:])
+		return
+	}
+	hash := sha256.Sum256([]byte(correlationID))
+	copy(traceID[:], hash[:16])
+	return
+}
+
+func newSpanID() (id trace.SpanID) {
+	rand.Read(id[:])
+	return
+}
+
+// unescape decodes percent-encoded header values as defined for OTEL_EXPORTER_OTLP_HEADERS
+func unescape(value string) string {
+	if decoded, err := url.PathUnescape(value); err == nil {
+		return decoded
+	}
+	return value
+}
@@ -0,0 +1,295 @@
+//go:build unit
+// +build unit
+
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	otlptracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+type receiver struct {
+	tracepb.UnimplementedTraceServiceServer
+
+	mu      sync.Mutex
+	traces  []*tracepb.ExportTraceServiceRequest
+	metrics []*metricspb.ExportMetricsServiceRequest
+	headers map[string]string
+}
+
+func (r *receiver) Export(ctx context.Context, request *tracepb.ExportTraceServiceRequest) (*tracepb.ExportTraceServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, request)
+	r.headers = incomingHeaders(ctx)
+	return &tracepb.ExportTraceServiceResponse{}, nil
+}
+
+// metricsService adapts the receiver to the metrics service, which has an Export method as well
+type metricsService struct {
+	metricspb.UnimplementedMetricsServiceServer
+	r *receiver
+}
+
+func (m metricsService) Export(_ context.Context, request *metricspb.ExportMetricsServiceRequest) (*metricspb.ExportMetricsServiceResponse, error) {
+	m.r.mu.Lock()
+	defer m.r.mu.Unlock()
+	m.r.metrics = append(m.r.metrics, request)
+	return &metricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func incomingHeaders(ctx context.Context) map[string]string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := map[string]string{}
+	if values := md.Get("authorization"); len(values) > 0 {
+		headers["authorization"] = values[0]
+	}
+	return headers
+}
+
+func newHTTPReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	r := &receiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		payload, _ := io.ReadAll(req.Body)
+		assert.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.headers = map[string]string{"authorization": req.Header.Get("Authorization")}
+		switch req.URL.Path {
+		case "/v1/traces":
+			request := &tracepb.ExportTraceServiceRequest{}
+			require.NoError(t, proto.Unmarshal(payload, request))
+			r.traces = append(r.traces, request)
+		case "/v1/metrics":
+			request := &metricspb.ExportMetricsServiceRequest{}
+			require.NoError(t, proto.Unmarshal(payload, request))
+			r.metrics = append(r.metrics, request)
+		default:
+			t.Errorf("unexpected path %v", req.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+// attributes returns the values of the given key values
+func attributes(keyValues []*commonpb.KeyValue) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, kv := range keyValues {
+		switch value := kv.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_StringValue:
+			values[kv.GetKey()] = value.StringValue
+		case *commonpb.AnyValue_IntValue:
+			values[kv.GetKey()] = value.IntValue
+		case *commonpb.AnyValue_ArrayValue:
+			items := []string{}
+			for _, item := range value.ArrayValue.GetValues() {
+				items = append(items, item.GetStringValue())
+			}
+			values[kv.GetKey()] = items
+		}
+	}
+	return values
+}
+
+// spans returns the exported spans and the resource attributes
+func (r *receiver) spans(t *testing.T) ([]*otlptracepb.Span, map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spans := []*otlptracepb.Span{}
+	resource := map[string]interface{}{}
+	for _, request := range r.traces {
+		for _, resourceSpans := range request.GetResourceSpans() {
+			resource = attributes(resourceSpans.GetResource().GetAttributes())
+			for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+				assert.Equal(t, scopeName, scopeSpans.GetScope().GetName())
+				spans = append(spans, scopeSpans.GetSpans()...)
+			}
+		}
+	}
+	return spans, resource
+}
+
+func TestTracing(t *testing.T) {
+	t.Setenv("TRACEPARENT", "")
+	r, server := newHTTPReceiver(t)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer backend.Close()
+
+	config := Config{Endpoint: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}, ServiceVersion: "abc123"}
+	require.NoError(t, Start(config, "mavenBuild", "Build", "https://ci.example.org/job/app/1"))
+	require.True(t, Enabled())
+
+	span := StartSpan("exec mvn", SpanKindInternal, Attr("process.command_args", []string{"mvn", "install"}))
+	span.SetAttributes(Attr("process.exit.code", 1))
+	span.End(errors.New("exit status 1"))
+
+	client := &http.Client{Transport: Transport(nil)}
+	response, err := client.Get(backend.URL + "/missing")
+	require.NoError(t, err)
+	response.Body.Close()
+
+	Finish(true, "build")
+	assert.False(t, Enabled())
+	assert.Equal(t, "Bearer token", r.headers["authorization"])
+
+	spans, resource := r.spans(t)
+	assert.Equal(t, "piper", resource["service.name"])
+	assert.Equal(t, "abc123", resource["service.version"])
+	require.Len(t, spans, 3)
+
+	hash := sha256.Sum256([]byte("https://ci.example.org/job/app/1"))
+	command, request, step := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "mavenBuild", step.GetName())
+	assert.Equal(t, hash[:16], step.GetTraceId())
+	assert.Empty(t, step.GetParentSpanId())
+	assert.Equal(t, otlptracepb.Status_STATUS_CODE_ERROR, step.GetStatus().GetCode())
+	assert.Equal(t, map[string]interface{}{
+		"piper.step.name":      "mavenBuild",
+		"piper.stage.name":     "Build",
+		"piper.correlation_id": "https://ci.example.org/job/app/1",
+		"piper.result":         "failure",
+		"piper.error.category": "build",
+	}, attributes(step.GetAttributes()))
+
+	assert.Equal(t, "exec mvn", command.GetName())
+	assert.Equal(t, hash[:16], command.GetTraceId())
+	assert.Equal(t, step.GetSpanId(), command.GetParentSpanId())
+	assert.Equal(t, otlptracepb.Status_STATUS_CODE_ERROR, command.GetStatus().GetCode())
+	assert.Equal(t, "exit status 1", command.GetStatus().GetMessage())
+	commandAttributes := attributes(command.GetAttributes())
+	assert.Equal(t, []string{"mvn", "install"}, commandAttributes["process.command_args"])
+	assert.Equal(t, int64(1), commandAttributes["process.exit.code"])
+
+	assert.Equal(t, "HTTP GET", request.GetName())
+	assert.Equal(t, otlptracepb.Span_SPAN_KIND_CLIENT, request.GetKind())
+	assert.Equal(t, step.GetSpanId(), request.GetParentSpanId())
+	requestAttributes := attributes(request.GetAttributes())
+	assert.Equal(t, int64(404), requestAttributes["http.response.status_code"])
+	assert.Equal(t, backend.URL+"/missing", requestAttributes["url.full"])
+	assert.Equal(t, otlptracepb.Status_STATUS_CODE_ERROR, request.GetStatus().GetCode())
+
+	t.Run("metrics", func(t *testing.T) {
+		require.Len(t, r.metrics, 1)
+		metrics := r.metrics[0].GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics()
+		require.Len(t, metrics, 2)
+
+		duration := metrics[0]
+		assert.Equal(t, "piper.step.duration", duration.GetName())
+		assert.Equal(t, "s", duration.GetUnit())
+		point := duration.GetHistogram().GetDataPoints()[0]
+		assert.Equal(t, uint64(1), point.GetCount())
+		assert.Equal(t, "failure", attributes(point.GetAttributes())["piper.result"])
+		assert.Equal(t, stepDurationBounds, point.GetExplicitBounds())
+		require.Len(t, point.GetBucketCounts(), len(stepDurationBounds)+1)
+		assert.Equal(t, uint64(1), point.GetBucketCounts()[0], "a fast step is counted in the first bucket")
+
+		executions := metrics[1]
+		assert.Equal(t, "piper.step.executions", executions.GetName())
+		assert.True(t, executions.GetSum().GetIsMonotonic())
+		numberPoint := executions.GetSum().GetDataPoints()[0]
+		assert.Equal(t, int64(1), numberPoint.GetAsInt())
+		assert.Equal(t, "build", attributes(numberPoint.GetAttributes())["piper.error.category"])
+	})
+}
+
+func TestTracingDisabled(t *testing.T) {
+	require.NoError(t, Start(Config{}, "mavenBuild", "Build", "id"))
+	assert.False(t, Enabled())
+	span := StartSpan("exec mvn", SpanKindInternal)
+	assert.Nil(t, span)
+	span.SetAttributes(Attr("key", "value"))
+	span.End(nil)
+	Finish(false, "undefined")
+}
+
+func TestTraceContext(t *testing.T) {
+	t.Run("from TRACEPARENT", func(t *testing.T) {
+		t.Setenv("TRACEPARENT", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		traceID, parentID := traceContext("id")
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(traceID[:]))
+		assert.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(parentID[:]))
+	})
+
+	t.Run("from correlation ID", func(t *testing.T) {
+		t.Setenv("TRACEPARENT", "invalid")
+		traceID, parentID := traceContext("id")
+		other, _ := traceContext("id")
+		assert.Equal(t, traceID, other)
+		assert.False(t, parentID.IsValid())
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://otlp.example.org")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20token, X-Scope-OrgID=team")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("OTEL_SERVICE_NAME", "my-pipeline")
+
+	assert.Equal(t, Config{
+		Endpoint:    "https://otlp.example.org",
+		Protocol:    ProtocolGRPC,
+		Headers:     map[string]string{"Authorization": "Bearer token", "X-Scope-OrgID": "team"},
+		Insecure:    true,
+		ServiceName: "my-pipeline",
+	}, ConfigFromEnv())
+}
+
+func TestNewExporters(t *testing.T) {
+	_, _, err := newExporters(Config{Endpoint: "localhost:4318"})
+	assert.EqualError(t, err, "invalid OTLP endpoint 'localhost:4318', expected URL like http://localhost:4318")
+	_, _, err = newExporters(Config{Endpoint: "http://localhost:4318", Protocol: "http/json"})
+	assert.EqualError(t, err, "unsupported OTLP protocol 'http/json', supported protocols: grpc, http/protobuf")
+}
+
+func TestGRPCExport(t *testing.T) {
+	r := &receiver{}
+	server := grpc.NewServer()
+	tracepb.RegisterTraceServiceServer(server, r)
+	metricspb.RegisterMetricsServiceServer(server, metricsService{r: r})
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	config := Config{Endpoint: listener.Addr().String(), Protocol: ProtocolGRPC, Insecure: true, Headers: map[string]string{"Authorization": "Bearer token"}}
+	require.NoError(t, Start(config, "kubernetesDeploy", "Release", "id"))
+	time.Sleep(time.Millisecond)
+	Finish(false, "undefined")
+
+	assert.Equal(t, "Bearer token", r.headers["authorization"])
+	spans, _ := r.spans(t)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "kubernetesDeploy", spans[0].GetName())
+	assert.Equal(t, otlptracepb.Status_STATUS_CODE_OK, spans[0].GetStatus().GetCode())
+	assert.Equal(t, "success", attributes(spans[0].GetAttributes())["piper.result"])
+
+	require.Len(t, r.metrics, 1)
+	point := r.metrics[0].GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics()[0].GetHistogram().GetDataPoints()[0]
+	assert.Greater(t, point.GetSum(), 0.0)
+}
@@ -0,0 +1,134 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	vaultAPI "github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/api/auth/approle"
+	"github.com/pkg/errors"
+)
+
+// Auth methods which can be used to log in to Vault
+const (
+	AuthMethodAppRole    = "approle"
+	AuthMethodKubernetes = "kubernetes"
+	AuthMethodJWT        = "jwt"
+	AuthMethodCert       = "cert"
+)
+
+// JWTEnvVar allows to provide the token for the JWT auth method if it is not issued by a supported CI system
+const JWTEnvVar = "PIPER_vaultJWT"
+
+// kubernetesServiceAccountTokenPath is the location of the token of the service account a pod runs with
+var kubernetesServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// loginAuth logs in with a login request to the mount path of an auth method
+type loginAuth struct {
+	mountPath string
+	data      func() (map[string]interface{}, error)
+}
+
+func (a *loginAuth) Login(ctx context.Context, client *vaultAPI.Client) (*vaultAPI.Secret, error) {
+	data, err := a.data()
+	if err != nil {
+		return nil, err
+	}
+	return client.Logical().WriteWithContext(ctx, path.Join("auth", a.mountPath, "login"), data)
+}
+
+// authMethod returns the auth method of the configuration, AppRole is used by default
+func (c *Client) authMethod() (vaultAPI.AuthMethod, error) {
+	method := c.cfg.AuthMethod
+	if len(method) == 0 {
+		method = AuthMethodAppRole
+	}
+	mountPath := c.cfg.AuthMountPoint
+	if len(mountPath) == 0 {
+		mountPath = method
+	}
+	switch method {
+	case AuthMethodAppRole:
+		return approle.NewAppRoleAuth(c.cfg.RoleID, &approle.SecretID{FromString: c.cfg.SecretID}, approle.WithMountPath(mountPath))
+	case AuthMethodKubernetes:
+		return &loginAuth{mountPath: mountPath, data: func() (map[string]interface{}, error) {
+			token, err := os.ReadFile(kubernetesServiceAccountTokenPath)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read Kubernetes service account token")
+			}
+			return map[string]interface{}{"role": c.cfg.Role, "jwt": strings.TrimSpace(string(token))}, nil
+		}}, nil
+	case AuthMethodJWT:
+		return &loginAuth{mountPath: mountPath, data: func() (map[string]interface{}, error) {
+			token, err := ciJWT(c.cfg.JWTAudience)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"role": c.cfg.Role, "jwt": token}, nil
+		}}, nil
+	case AuthMethodCert:
+		// the client certificate is sent during the TLS handshake, the role is optional
+		return &loginAuth{mountPath: mountPath, data: func() (map[string]interface{}, error) {
+			return map[string]interface{}{"name": c.cfg.Role}, nil
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported Vault auth method '%s'", method)
+}
+
+// ciJWT returns an OIDC token of the CI system: a token provided via PIPER_vaultJWT,
+// a token requested from GitHub Actions or the token of an Azure workload identity
+func ciJWT(audience string) (string, error) {
+	if token := os.Getenv(JWTEnvVar); len(token) > 0 {
+		return token, nil
+	}
+	if requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"); len(requestURL) > 0 {
+		log.Entry().Debug("requesting OIDC token from GitHub Actions")
+		return gitHubActionsJWT(requestURL, os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"), audience)
+	}
+	if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); len(tokenFile) > 0 {
+		log.Entry().Debug("using OIDC token of Azure workload identity")
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read Azure federated token")
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+	return "", fmt.Errorf("no OIDC token available, please provide it via %s or use the OIDC token of GitHub Actions or an Azure workload identity", JWTEnvVar)
+}
+
+// gitHubActionsJWT requests an OIDC token for the workflow run, which requires the permission id-token: write
+func gitHubActionsJWT(requestURL, requestToken, audience string) (string, error) {
+	if len(audience) > 0 {
+		u, err := url.Parse(requestURL)
+		if err != nil {
+			return "", errors.Wrap(err, "invalid ACTIONS_ID_TOKEN_REQUEST_URL")
+		}
+		query := u.Query()
+		query.Set("audience", audience)
+		u.RawQuery = query.Encode()
+		requestURL = u.String()
+	}
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRetries: 3, Token: "Bearer " + requestToken})
+	response, err := client.SendRequest(http.MethodGet, requestURL, nil, nil, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request OIDC token from GitHub Actions")
+	}
+	defer response.Body.Close()
+	var token struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil || len(token.Value) == 0 {
+		return "", errors.New("failed to request OIDC token from GitHub Actions: no token returned")
+	}
+	log.RegisterSecret(token.Value)
+	return token.Value, nil
+}
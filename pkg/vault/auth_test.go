@@ -0,0 +1,145 @@
+//go:build unit
+// +build unit
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVaultLogin answers login requests with a token and records the login data per path
+func fakeVaultLogin(t *testing.T, logins map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := map[string]interface{}{}
+		if r.ContentLength > 0 {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&data))
+		}
+		logins[r.URL.Path] = data
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]interface{}{"client_token": "hvs.loginToken", "renewable": false}})
+	}))
+}
+
+func newLoginClient(t *testing.T, address string, cfg ClientConfig) *Client {
+	cfg.Config = &api.Config{Address: address}
+	client, err := newClient(&cfg)
+	require.NoError(t, err)
+	return client
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("AppRole", func(t *testing.T) {
+		logins := map[string]map[string]interface{}{}
+		server := fakeVaultLogin(t, logins)
+		defer server.Close()
+		client := newLoginClient(t, server.URL, ClientConfig{RoleID: "roleID", SecretID: "secretID"})
+
+		_, err := client.login()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"role_id": "roleID", "secret_id": "secretID"}, logins["/v1/auth/approle/login"])
+		assert.Equal(t, "hvs.loginToken", client.vaultApiClient.Token())
+	})
+
+	t.Run("Kubernetes", func(t *testing.T) {
+		logins := map[string]map[string]interface{}{}
+		server := fakeVaultLogin(t, logins)
+		defer server.Close()
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenPath, []byte("serviceAccountToken\n"), 0600))
+		defer func(original string) { kubernetesServiceAccountTokenPath = original }(kubernetesServiceAccountTokenPath)
+		kubernetesServiceAccountTokenPath = tokenPath
+		client := newLoginClient(t, server.URL, ClientConfig{AuthMethod: AuthMethodKubernetes, AuthMountPoint: "k8s-cluster", Role: "piper"})
+
+		_, err := client.login()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"role": "piper", "jwt": "serviceAccountToken"}, logins["/v1/auth/k8s-cluster/login"])
+		assert.Equal(t, "hvs.loginToken", client.vaultApiClient.Token())
+	})
+
+	t.Run("JWT of GitHub Actions", func(t *testing.T) {
+		logins := map[string]map[string]interface{}{}
+		server := fakeVaultLogin(t, logins)
+		defer server.Close()
+		gitHub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer requestToken", r.Header.Get("Authorization"))
+			assert.Equal(t, "https://vault.example.com", r.URL.Query().Get("audience"))
+			assert.Equal(t, "1", r.URL.Query().Get("api-version"))
+			w.Write([]byte(`{"value":"gitHubJWT"}`))
+		}))
+		defer gitHub.Close()
+		t.Setenv(JWTEnvVar, "")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", gitHub.URL+"/token?api-version=1")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "requestToken")
+		client := newLoginClient(t, server.URL, ClientConfig{AuthMethod: AuthMethodJWT, Role: "piper", JWTAudience: "https://vault.example.com"})
+
+		_, err := client.login()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"role": "piper", "jwt": "gitHubJWT"}, logins["/v1/auth/jwt/login"])
+	})
+
+	t.Run("cert", func(t *testing.T) {
+		logins := map[string]map[string]interface{}{}
+		server := fakeVaultLogin(t, logins)
+		defer server.Close()
+		client := newLoginClient(t, server.URL, ClientConfig{AuthMethod: AuthMethodCert, Role: "pipeline"})
+
+		_, err := client.login()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "pipeline"}, logins["/v1/auth/cert/login"])
+	})
+
+	t.Run("unsupported auth method", func(t *testing.T) {
+		client := newLoginClient(t, "http://localhost", ClientConfig{AuthMethod: "ldap"})
+
+		_, err := client.login()
+
+		assert.EqualError(t, err, "unable to initialize auth method: unsupported Vault auth method 'ldap'")
+	})
+}
+
+func TestCIJWT(t *testing.T) {
+	t.Run("explicit token", func(t *testing.T) {
+		t.Setenv(JWTEnvVar, "explicitJWT")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "https://github.example.com")
+
+		token, err := ciJWT("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "explicitJWT", token)
+	})
+
+	t.Run("Azure workload identity", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("azureJWT"), 0600))
+		t.Setenv(JWTEnvVar, "")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+		t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+
+		token, err := ciJWT("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "azureJWT", token)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		t.Setenv(JWTEnvVar, "")
+		t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+		t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+
+		_, err := ciJWT("")
+
+		assert.ErrorContains(t, err, "no OIDC token available")
+	})
+}
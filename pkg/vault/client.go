@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"github.com/SAP/jenkins-library/pkg/log"
 	vaultAPI "github.com/hashicorp/vault/api"
-	"github.com/hashicorp/vault/api/auth/approle"
 	"github.com/pkg/errors"
 	"io"
 	"net/http"
@@ -26,6 +25,14 @@ type ClientConfig struct {
 	AppRoleMountPoint string
 	RoleID            string
 	SecretID          string
+	// AuthMethod is the method used to log in, one of approle (default), kubernetes, jwt or cert
+	AuthMethod string
+	// AuthMountPoint is the path of the auth method, it defaults to the name of the method
+	AuthMountPoint string
+	// Role is the role used to log in with the kubernetes, jwt and cert auth methods
+	Role string
+	// JWTAudience is the audience of the OIDC token requested from the CI system for the jwt auth method
+	JWTAudience string
 }
 
 // logicalClient interface for mocking
@@ -153,14 +160,14 @@ func (c *Client) manageTokenLifecycle(authResp *vaultAPI.Secret) error {
 }
 
 func (c *Client) login() (*vaultAPI.Secret, error) {
-	appRoleAuth, err := approle.NewAppRoleAuth(c.cfg.RoleID, &approle.SecretID{FromString: c.cfg.SecretID})
+	authMethod, err := c.authMethod()
 	if err != nil {
-		return nil, fmt.Errorf("unable to initialize appRole auth method: %w", err)
+		return nil, fmt.Errorf("unable to initialize auth method: %w", err)
 	}
 
-	authInfo, err := c.vaultApiClient.Auth().Login(context.Background(), appRoleAuth)
+	authInfo, err := c.vaultApiClient.Auth().Login(context.Background(), authMethod)
 	if err != nil {
-		return nil, fmt.Errorf("unable to login to appRole auth method: %w", err)
+		return nil, fmt.Errorf("unable to login to auth method: %w", err)
 	}
 	if authInfo == nil {
 		return nil, fmt.Errorf("no auth info was returned after login")
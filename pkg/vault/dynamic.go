@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/hashicorp/vault/api"
+)
+
+// Lease is a credential of a dynamic secrets engine which is valid for a limited time
+type Lease struct {
+	ID        string
+	Path      string
+	Duration  time.Duration
+	Renewable bool
+}
+
+type trackedLease struct {
+	Lease
+	logical logicalClient
+}
+
+var (
+	leases            []trackedLease
+	leasesMutex       sync.Mutex
+	registerRevokeAll sync.Once
+)
+
+// GetDynamicSecret requests credentials from a dynamic secrets engine, e.g. database/creds/<role>, aws/sts/<role>,
+// gcp/roleset/<roleset>/token, azure/creds/<role>, pki/issue/<role> or ssh/creds/<role>.
+// The path is read if data is nil, otherwise data is written to the path, which is required e.g. for issuing certificates.
+// The lease of the credentials is revoked by RevokeLeases, which is called at the end of the step or if it fails.
+func (c *Client) GetDynamicSecret(path string, data map[string]interface{}) (map[string]string, error) {
+	path = sanitizePath(path)
+	var err error
+	var secret *api.Secret
+	if data == nil {
+		secret, err = c.logical.Read(path)
+	} else {
+		secret, err = c.logical.Write(path, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no credentials returned for path %s", path)
+	}
+
+	if len(secret.LeaseID) > 0 {
+		lease := Lease{ID: secret.LeaseID, Path: path, Duration: time.Duration(secret.LeaseDuration) * time.Second, Renewable: secret.Renewable}
+		log.Entry().Debugf("obtained lease %s for %s valid for %v", lease.ID, path, lease.Duration)
+		trackLease(lease, c.logical)
+	}
+	return toStringMap(secret.Data), nil
+}
+
+// Leases returns the leases which have not been revoked yet
+func Leases() []Lease {
+	leasesMutex.Lock()
+	defer leasesMutex.Unlock()
+	result := make([]Lease, 0, len(leases))
+	for _, lease := range leases {
+		result = append(result, lease.Lease)
+	}
+	return result
+}
+
+func trackLease(lease Lease, logical logicalClient) {
+	leasesMutex.Lock()
+	defer leasesMutex.Unlock()
+	leases = append(leases, trackedLease{Lease: lease, logical: logical})
+	// the deferred functions of the step are not executed if it fails with log.Entry().Fatal
+	registerRevokeAll.Do(func() { log.DeferExitHandler(RevokeLeases) })
+}
+
+// RevokeLeases revokes all leases obtained via GetDynamicSecret, failures are only logged
+// since the leases expire anyway
+func RevokeLeases() {
+	leasesMutex.Lock()
+	defer leasesMutex.Unlock()
+	for _, lease := range leases {
+		if _, err := lease.logical.Write("sys/leases/revoke", map[string]interface{}{"lease_id": lease.ID}); err != nil {
+			log.Entry().WithError(err).Warnf("Could not revoke lease of %s, it expires after %v", lease.Path, lease.Duration)
+			continue
+		}
+		log.Entry().Debugf("revoked lease %s", lease.ID)
+	}
+	leases = nil
+}
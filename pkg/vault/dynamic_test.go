@@ -0,0 +1,89 @@
+//go:build unit
+// +build unit
+
+package vault
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/vault/mocks"
+)
+
+func TestGetDynamicSecret(t *testing.T) {
+	t.Cleanup(func() { leases = nil })
+
+	t.Run("database credentials", func(t *testing.T) {
+		leases = nil
+		vaultMock := &mocks.VaultMock{}
+		client := Client{nil, vaultMock, &ClientConfig{}}
+		vaultMock.On("Read", "database/creds/readonly").Return(&api.Secret{
+			LeaseID:       "database/creds/readonly/abc",
+			LeaseDuration: 3600,
+			Renewable:     true,
+			Data:          SecretData{"username": "v-approle-readonly", "password": "A1a-secret"},
+		}, nil)
+
+		secret, err := client.GetDynamicSecret("/database/creds/readonly/", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"username": "v-approle-readonly", "password": "A1a-secret"}, secret)
+		assert.Equal(t, []Lease{{ID: "database/creds/readonly/abc", Path: "database/creds/readonly", Duration: time.Hour, Renewable: true}}, Leases())
+	})
+
+	t.Run("certificate issuance", func(t *testing.T) {
+		leases = nil
+		vaultMock := &mocks.VaultMock{}
+		client := Client{nil, vaultMock, &ClientConfig{}}
+		vaultMock.On("Write", "pki/issue/web", map[string]interface{}{"common_name": "app.acme.corp"}).Return(&api.Secret{
+			Data: SecretData{"certificate": "-----BEGIN CERTIFICATE-----", "ca_chain": []interface{}{"ca1", "ca2"}},
+		}, nil)
+
+		secret, err := client.GetDynamicSecret("pki/issue/web", map[string]interface{}{"common_name": "app.acme.corp"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "-----BEGIN CERTIFICATE-----", secret["certificate"])
+		assert.Equal(t, `["ca1","ca2"]`, secret["ca_chain"])
+		assert.Empty(t, Leases())
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{nil, vaultMock, &ClientConfig{}}
+		vaultMock.On("Read", "aws/sts/deploy").Return(nil, nil)
+
+		_, err := client.GetDynamicSecret("aws/sts/deploy", nil)
+
+		assert.EqualError(t, err, "no credentials returned for path aws/sts/deploy")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		vaultMock := &mocks.VaultMock{}
+		client := Client{nil, vaultMock, &ClientConfig{}}
+		vaultMock.On("Read", "aws/sts/deploy").Return(nil, fmt.Errorf("permission denied"))
+
+		_, err := client.GetDynamicSecret("aws/sts/deploy", nil)
+
+		assert.EqualError(t, err, "permission denied")
+	})
+}
+
+func TestRevokeLeases(t *testing.T) {
+	t.Cleanup(func() { leases = nil })
+	vaultMock := &mocks.VaultMock{}
+	leases = []trackedLease{
+		{Lease: Lease{ID: "database/creds/readonly/abc", Path: "database/creds/readonly"}, logical: vaultMock},
+		{Lease: Lease{ID: "aws/sts/deploy/def", Path: "aws/sts/deploy"}, logical: vaultMock},
+	}
+	vaultMock.On("Write", "sys/leases/revoke", map[string]interface{}{"lease_id": "database/creds/readonly/abc"}).Return(nil, nil)
+	vaultMock.On("Write", "sys/leases/revoke", map[string]interface{}{"lease_id": "aws/sts/deploy/def"}).Return(nil, fmt.Errorf("lease not found"))
+
+	RevokeLeases()
+
+	vaultMock.AssertNumberOfCalls(t, "Write", 2)
+	assert.Empty(t, Leases())
+}
@@ -53,6 +53,11 @@ func (c *Client) GetKvSecret(path string) (map[string]string, error) {
 		return nil, fmt.Errorf("excpected 'data' field to be a map[string]interface{} but got %T instead", rawData)
 	}
 
+	return toStringMap(data), nil
+}
+
+// toStringMap converts the data of a secret, values which are not strings are encoded as JSON
+func toStringMap(data map[string]interface{}) map[string]string {
 	secretData := make(map[string]string, len(data))
 	for k, v := range data {
 		switch t := v.(type) {
@@ -70,7 +75,7 @@ func (c *Client) GetKvSecret(path string) (map[string]string, error) {
 			secretData[k] = string(jsonBytes)
 		}
 	}
-	return secretData, nil
+	return secretData
 }
 
 // WriteKvSecret writes secret to kv engine
@@ -184,8 +189,12 @@ func (c *Client) RevokeToken() error {
 }
 
 // MustRevokeToken same as RevokeToken but the program is terminated with an error if this fails.
+// Leases of dynamic secrets are revoked before.
 // Should be used in defer statements only.
 func (c *Client) MustRevokeToken() {
+	// leases can only be revoked as long as the token is valid
+	RevokeLeases()
+
 	lookupPath := "auth/token/lookup-self"
 	const serviceTokenPrefix = "hvs."
 
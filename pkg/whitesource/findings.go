@@ -0,0 +1,39 @@
+package whitesource
+
+import (
+	"github.com/SAP/jenkins-library/pkg/format"
+)
+
+// ToFindings converts alerts into scanner independent findings
+func ToFindings(alerts []Alert) []format.Finding {
+	findings := []format.Finding{}
+	for _, alert := range alerts {
+		finding := format.Finding{
+			Tool:       "Mend",
+			Rule:       alert.Vulnerability.Name,
+			CVE:        format.FindCVE(alert.Vulnerability.Name),
+			PackageURL: alert.Library.ToPackageUrl().ToString(),
+			Severity:   format.NormalizeSeverity(consolidateSeverities(alert.Vulnerability.Severity, alert.Vulnerability.CVSS3Severity)),
+			Status:     format.FindingStatusOpen,
+			AuditState: alert.Status,
+			Message:    alert.Vulnerability.Description,
+		}
+		if len(finding.Rule) == 0 {
+			finding.Rule = alert.Type
+		}
+		if score := vulnerabilityScore(alert); score > 0 {
+			finding.Severity = format.SeverityFromScore(score)
+		}
+		if alert.Status == "IGNORE" {
+			finding.Status = format.FindingStatusSuppressed
+		}
+		if alert.Assessment != nil {
+			finding.AuditState = string(alert.Assessment.Status)
+			if alert.Assessment.Status == format.NotRelevant {
+				finding.Status = format.FindingStatusSuppressed
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
@@ -0,0 +1,36 @@
+//go:build unit
+// +build unit
+
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	library := Library{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1", LibType: "MAVEN_ARTIFACT"}
+	alerts := []Alert{
+		{Type: "SECURITY_VULNERABILITY", Status: "OPEN", Library: library, Vulnerability: Vulnerability{Name: "CVE-2021-44228", Severity: "high", CVSS3Severity: "critical", CVSS3Score: 10}},
+		{Type: "SECURITY_VULNERABILITY", Status: "IGNORE", Library: library, Vulnerability: Vulnerability{Name: "WS-2021-0001", Severity: "medium"}},
+		{Type: "SECURITY_VULNERABILITY", Status: "OPEN", Library: library, Vulnerability: Vulnerability{Name: "CVE-2021-45046", Score: 5}, Assessment: &format.Assessment{Status: format.NotRelevant}},
+	}
+
+	findings := ToFindings(alerts)
+
+	if assert.Len(t, findings, 3) {
+		assert.Equal(t, "Mend", findings[0].Tool)
+		assert.Equal(t, "CVE-2021-44228", findings[0].CVE)
+		assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", findings[0].PackageURL)
+		assert.Equal(t, format.SeverityCritical, findings[0].Severity)
+		assert.Equal(t, format.FindingStatusOpen, findings[0].Status)
+		assert.Empty(t, findings[1].CVE)
+		assert.Equal(t, format.SeverityMedium, findings[1].Severity)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[1].Status)
+		assert.Equal(t, format.SeverityMedium, findings[2].Severity)
+		assert.Equal(t, format.FindingStatusSuppressed, findings[2].Status)
+		assert.Equal(t, "notRelevant", findings[2].AuditState)
+	}
+}
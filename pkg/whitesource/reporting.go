@@ -104,6 +104,7 @@ func CreateCustomVulnerabilityReport(productName string, scan *Scan, alerts *[]A
 		detailTable.Rows = append(detailTable.Rows, row)
 	}
 	scanReport.DetailTable = detailTable
+	scanReport.Findings = ToFindings(*alerts)
 
 	return scanReport
 }
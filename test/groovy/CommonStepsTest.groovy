@@ -170,6 +170,7 @@ public class CommonStepsTest extends BasePiperTest{
         'nexusUpload', //implementing new golang pattern without fields
         'piperPipelineStageArtifactDeployment', //stage without step flags
         'pipelineCreateScanSummary', //stage without step flags
+        'sarifCheckQualityGate', //implementing new golang pattern without fields
         'sonarExecuteScan', //implementing new golang pattern without fields
         'gctsCreateRepository', //implementing new golang pattern without fields
         'gctsRollback', //implementing new golang pattern without fields